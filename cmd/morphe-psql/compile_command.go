@@ -0,0 +1,272 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+
+	rcfg "github.com/kalo-build/morphe-go/pkg/registry/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+)
+
+// compileFlags holds the parsed flags of the compile command
+type compileFlags struct {
+	configFilePath string
+
+	registryDirPath   string
+	modelsDirPath     string
+	enumsDirPath      string
+	structuresDirPath string
+	entitiesDirPath   string
+
+	outputDirPath string
+
+	schema           string
+	modelsSchema     string
+	enumsSchema      string
+	structuresSchema string
+	entitiesSchema   string
+
+	modelsBigSerial     bool
+	compositeJunctions  bool
+	enumsBigSerial      bool
+	structuresBigSerial bool
+
+	enumStrategy      string
+	structureStrategy string
+	persistStructures bool
+	entityViewSuffix  string
+
+	identifierCollisions string
+	naming               string
+}
+
+var errNoRegistryDirPath = errors.New("either -registry or all of -models, -enums, -structures and -entities must be set")
+var errNoOutputDirPath = errors.New("-out must be set")
+var errConfigFileWithFlags = errors.New("-config cannot be combined with other flags")
+var errConfigFileNoOutput = errors.New("config file must set output.path or per-kind output paths")
+
+func runCompileCommand(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := compileFlags{}
+	flagSet := newCompileFlagSet(&flags, stderr)
+	if parseErr := flagSet.Parse(args); parseErr != nil {
+		if errors.Is(parseErr, flag.ErrHelp) {
+			return exitCodeSuccess
+		}
+		return exitCodeUsage
+	}
+	if flagSet.NArg() > 0 {
+		fmt.Fprintf(stderr, "morphe-psql compile: unexpected arguments: %v\n", flagSet.Args())
+		return exitCodeUsage
+	}
+
+	var config compile.MorpheCompileConfig
+	var configErr error
+	if flags.configFilePath != "" {
+		config, configErr = flags.loadCompileConfig(flagSet)
+	} else {
+		config, configErr = flags.getCompileConfig()
+	}
+	if configErr != nil {
+		fmt.Fprintf(stderr, "morphe-psql compile: %s\n", configErr)
+		return exitCodeUsage
+	}
+
+	compileErr := compile.MorpheToPSQL(config)
+	if compileErr != nil {
+		fmt.Fprintf(stderr, "morphe-psql compile: compilation failed: %s\n", compileErr)
+		return exitCodeFailure
+	}
+
+	fmt.Fprintln(stdout, "compiled registry")
+	return exitCodeSuccess
+}
+
+func (flags compileFlags) loadCompileConfig(flagSet *flag.FlagSet) (compile.MorpheCompileConfig, error) {
+	config, loadErr := flags.loadCompileConfigFile(flagSet)
+	if loadErr != nil {
+		return compile.MorpheCompileConfig{}, loadErr
+	}
+	if config.ModelWriter == nil || config.EnumWriter == nil || config.StructureWriter == nil || config.EntityWriter == nil {
+		return compile.MorpheCompileConfig{}, errConfigFileNoOutput
+	}
+
+	return config, nil
+}
+
+// loadCompileConfigFile loads the -config file, which cannot be combined with flags other than the given
+// command-specific flags
+func (flags compileFlags) loadCompileConfigFile(flagSet *flag.FlagSet, commandFlagNames ...string) (compile.MorpheCompileConfig, error) {
+	otherFlagSet := false
+	flagSet.Visit(func(f *flag.Flag) {
+		if f.Name != "config" && !slices.Contains(commandFlagNames, f.Name) {
+			otherFlagSet = true
+		}
+	})
+	if otherFlagSet {
+		return compile.MorpheCompileConfig{}, errConfigFileWithFlags
+	}
+
+	return compile.LoadMorpheCompileConfig(flags.configFilePath)
+}
+
+func newCompileFlagSet(flags *compileFlags, output io.Writer) *flag.FlagSet {
+	flagSet := flag.NewFlagSet("morphe-psql compile", flag.ContinueOnError)
+	flagSet.SetOutput(output)
+	flagSet.Usage = func() {
+		fmt.Fprintln(output, "Usage: morphe-psql compile -registry <dir> -out <dir> [flags]")
+		fmt.Fprintln(output, "       morphe-psql compile -config <file>")
+		fmt.Fprintln(output, "")
+		fmt.Fprintln(output, "Writes models/, enums/, structures/ and entities/ definition files into the output directory.")
+		fmt.Fprintln(output, "")
+		fmt.Fprintln(output, "Flags:")
+		flagSet.PrintDefaults()
+	}
+
+	flagSet.StringVar(&flags.configFilePath, "config", "", "YAML or JSON config file (e.g. "+compile.DefaultCompileConfigFileName+"), cannot be combined with other flags")
+	flagSet.StringVar(&flags.outputDirPath, "out", "", "output directory for the compiled definitions")
+	addCompileSettingFlags(flagSet, flags)
+
+	return flagSet
+}
+
+// addCompileSettingFlags adds the registry, schema and strategy flags shared by the commands compiling a registry
+func addCompileSettingFlags(flagSet *flag.FlagSet, flags *compileFlags) {
+	defaultConfig := cfg.DefaultMorpheConfig()
+
+	flagSet.StringVar(&flags.registryDirPath, "registry", "", "registry root directory containing models/, enums/, structures/ and entities/")
+	flagSet.StringVar(&flags.modelsDirPath, "models", "", "models registry directory (overrides -registry)")
+	flagSet.StringVar(&flags.enumsDirPath, "enums", "", "enums registry directory (overrides -registry)")
+	flagSet.StringVar(&flags.structuresDirPath, "structures", "", "structures registry directory (overrides -registry)")
+	flagSet.StringVar(&flags.entitiesDirPath, "entities", "", "entities registry directory (overrides -registry)")
+
+	flagSet.StringVar(&flags.schema, "schema", cfg.DefaultSchema, "schema used for all definitions")
+	flagSet.StringVar(&flags.modelsSchema, "models-schema", "", "schema for model tables (overrides -schema)")
+	flagSet.StringVar(&flags.enumsSchema, "enums-schema", "", "schema for enum tables and types (overrides -schema)")
+	flagSet.StringVar(&flags.structuresSchema, "structures-schema", "", "schema for the structures table (overrides -schema)")
+	flagSet.StringVar(&flags.entitiesSchema, "entities-schema", "", "schema for entity views (overrides -schema)")
+
+	flagSet.BoolVar(&flags.modelsBigSerial, "models-bigserial", defaultConfig.MorpheModelsConfig.UseBigSerial, "use BIGSERIAL instead of SERIAL for model auto-increment fields")
+	flagSet.BoolVar(&flags.compositeJunctions, "composite-junction-keys", defaultConfig.MorpheModelsConfig.UseCompositeJunctionKeys, "key junction tables by their (source, target) columns instead of a SERIAL id")
+	flagSet.BoolVar(&flags.enumsBigSerial, "enums-bigserial", defaultConfig.MorpheEnumsConfig.UseBigSerial, "use BIGSERIAL instead of SERIAL for enum table ids")
+	flagSet.BoolVar(&flags.structuresBigSerial, "structures-bigserial", defaultConfig.MorpheStructuresConfig.UseBigSerial, "use BIGSERIAL instead of SERIAL for the structures table id")
+
+	flagSet.StringVar(&flags.enumStrategy, "enum-strategy", string(cfg.EnumStrategyLookupTable), "enum representation: lookup_table or native_enum")
+	flagSet.StringVar(&flags.structureStrategy, "structure-strategy", string(cfg.StructureStrategyJSONTable), "structure representation: json_table, typed_table or composite_type")
+	flagSet.BoolVar(&flags.persistStructures, "persist-structures", defaultConfig.MorpheStructuresConfig.EnablePersistence, "write the structures persistence table")
+	flagSet.StringVar(&flags.entityViewSuffix, "entity-view-suffix", defaultConfig.MorpheEntitiesConfig.ViewNameSuffix, "suffix appended to entity view names")
+	flagSet.StringVar(&flags.identifierCollisions, "identifier-collisions", string(cfg.IdentifierCollisionStrategyFail), "handling of names colliding once abbreviated to 63 characters: fail or hash")
+	flagSet.StringVar(&flags.naming, "naming", string(cfg.NamingPresetMorphe), "naming of tables, constraints and indices: morphe, postgres or rails")
+}
+
+func (flags compileFlags) getCompileConfig() (compile.MorpheCompileConfig, error) {
+	config, settingsErr := flags.getCompileSettings()
+	if settingsErr != nil {
+		return compile.MorpheCompileConfig{}, settingsErr
+	}
+	if flags.outputDirPath == "" {
+		return compile.MorpheCompileConfig{}, errNoOutputDirPath
+	}
+
+	config.ModelWriter = &compile.MorpheTableFileWriter{
+		Type:           compile.MorpheTableTypeModels,
+		TargetDirPath:  filepath.Join(flags.outputDirPath, "models"),
+		NamingStrategy: config.GetNamingStrategy(),
+	}
+	config.EnumWriter = &compile.MorpheTableFileWriter{
+		Type:           compile.MorpheTableTypeEnums,
+		TargetDirPath:  filepath.Join(flags.outputDirPath, "enums"),
+		NamingStrategy: config.GetNamingStrategy(),
+	}
+	config.EnumTypeWriter = &compile.MorpheTypeFileWriter{
+		TargetDirPath: filepath.Join(flags.outputDirPath, "enums"),
+	}
+	config.StructureWriter = &compile.MorpheTableFileWriter{
+		Type:           compile.MorpheTableTypeStructures,
+		TargetDirPath:  filepath.Join(flags.outputDirPath, "structures"),
+		NamingStrategy: config.GetNamingStrategy(),
+	}
+	config.StructureTypeWriter = &compile.MorpheTypeFileWriter{
+		TargetDirPath: filepath.Join(flags.outputDirPath, "structures"),
+	}
+	config.EntityWriter = &compile.MorpheViewFileWriter{
+		TargetDirPath: filepath.Join(flags.outputDirPath, "entities"),
+	}
+	config.TypeWriter = &compile.MorpheTypeFileWriter{
+		TargetDirPath: filepath.Join(flags.outputDirPath, "types"),
+	}
+
+	validateErr := config.Validate()
+	if validateErr != nil {
+		return compile.MorpheCompileConfig{}, validateErr
+	}
+
+	return config, nil
+}
+
+// getCompileSettings returns the registry paths and Morphe settings of the flags, without any writers
+func (flags compileFlags) getCompileSettings() (compile.MorpheCompileConfig, error) {
+	registryConfig := rcfg.MorpheLoadRegistryConfig{
+		RegistryModelsDirPath:     getRegistryDirPath(flags.modelsDirPath, flags.registryDirPath, "models"),
+		RegistryEnumsDirPath:      getRegistryDirPath(flags.enumsDirPath, flags.registryDirPath, "enums"),
+		RegistryStructuresDirPath: getRegistryDirPath(flags.structuresDirPath, flags.registryDirPath, "structures"),
+		RegistryEntitiesDirPath:   getRegistryDirPath(flags.entitiesDirPath, flags.registryDirPath, "entities"),
+	}
+	if registryConfig.RegistryModelsDirPath == "" ||
+		registryConfig.RegistryEnumsDirPath == "" ||
+		registryConfig.RegistryStructuresDirPath == "" ||
+		registryConfig.RegistryEntitiesDirPath == "" {
+		return compile.MorpheCompileConfig{}, errNoRegistryDirPath
+	}
+
+	morpheConfig := cfg.MorpheConfig{
+		MorpheModelsConfig: cfg.MorpheModelsConfig{
+			Schema:                   getSchema(flags.modelsSchema, flags.schema),
+			UseBigSerial:             flags.modelsBigSerial,
+			UseCompositeJunctionKeys: flags.compositeJunctions,
+		},
+		MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+			Schema:       getSchema(flags.enumsSchema, flags.schema),
+			UseBigSerial: flags.enumsBigSerial,
+			Strategy:     cfg.EnumStrategy(flags.enumStrategy),
+		},
+		MorpheStructuresConfig: cfg.MorpheStructuresConfig{
+			Schema:            getSchema(flags.structuresSchema, flags.schema),
+			UseBigSerial:      flags.structuresBigSerial,
+			EnablePersistence: flags.persistStructures,
+			Strategy:          cfg.StructureStrategy(flags.structureStrategy),
+		},
+		MorpheEntitiesConfig: cfg.MorpheEntitiesConfig{
+			Schema:         getSchema(flags.entitiesSchema, flags.schema),
+			ViewNameSuffix: flags.entityViewSuffix,
+		},
+		IdentifierCollisions: cfg.IdentifierCollisionStrategy(flags.identifierCollisions),
+		Naming:               cfg.NamingPreset(flags.naming),
+	}
+
+	return compile.MorpheCompileConfig{
+		MorpheLoadRegistryConfig: registryConfig,
+		MorpheConfig:             morpheConfig,
+	}, nil
+}
+
+func getRegistryDirPath(dirPath string, registryDirPath string, kindDirName string) string {
+	if dirPath != "" {
+		return dirPath
+	}
+	if registryDirPath == "" {
+		return ""
+	}
+	return filepath.Join(registryDirPath, kindDirName)
+}
+
+func getSchema(schema string, defaultSchema string) string {
+	if schema != "" {
+		return schema
+	}
+	return defaultSchema
+}
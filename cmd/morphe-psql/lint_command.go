@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/lint"
+)
+
+// lintFlags holds the parsed flags of the lint command
+type lintFlags struct {
+	compileFlags
+
+	failSeverity    string
+	disabledRuleIDs string
+}
+
+func runLintCommand(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := lintFlags{}
+	flagSet := newLintFlagSet(&flags, stderr)
+	if parseErr := flagSet.Parse(args); parseErr != nil {
+		if errors.Is(parseErr, flag.ErrHelp) {
+			return exitCodeSuccess
+		}
+		return exitCodeUsage
+	}
+	if flagSet.NArg() > 0 {
+		fmt.Fprintf(stderr, "morphe-psql lint: unexpected arguments: %v\n", flagSet.Args())
+		return exitCodeUsage
+	}
+
+	config, configErr := flags.getLintConfig(flagSet)
+	if configErr != nil {
+		fmt.Fprintf(stderr, "morphe-psql lint: %s\n", configErr)
+		return exitCodeUsage
+	}
+
+	report, lintErr := lint.MorpheToPSQLLint(config)
+	if lintErr != nil {
+		fmt.Fprintf(stderr, "morphe-psql lint: lint failed: %s\n", lintErr)
+		return exitCodeFailure
+	}
+
+	for _, finding := range report.Findings {
+		fmt.Fprintln(stdout, finding)
+	}
+	failingFindings := report.GetFailingFindings()
+	if len(failingFindings) > 0 {
+		fmt.Fprintf(stderr, "morphe-psql lint: %d of %d findings at or above %s\n",
+			len(failingFindings), len(report.Findings), report.FailSeverity)
+		return exitCodeFailure
+	}
+
+	fmt.Fprintf(stdout, "linted registry: %d findings\n", len(report.Findings))
+	return exitCodeSuccess
+}
+
+func newLintFlagSet(flags *lintFlags, output io.Writer) *flag.FlagSet {
+	flagSet := flag.NewFlagSet("morphe-psql lint", flag.ContinueOnError)
+	flagSet.SetOutput(output)
+	flagSet.Usage = func() {
+		fmt.Fprintln(output, "Usage: morphe-psql lint -registry <dir> [flags]")
+		fmt.Fprintln(output, "       morphe-psql lint -config <file> [-fail-on <severity>] [-disable <rules>]")
+		fmt.Fprintln(output, "")
+		fmt.Fprintln(output, "Compiles the registry without writing files and checks the compiled tables and views.")
+		fmt.Fprintln(output, "Exits with a failure if any finding is at or above the -fail-on severity.")
+		fmt.Fprintln(output, "")
+		fmt.Fprintln(output, "Flags:")
+		flagSet.PrintDefaults()
+	}
+
+	flagSet.StringVar(&flags.configFilePath, "config", "", "YAML or JSON config file (e.g. "+compile.DefaultCompileConfigFileName+"), cannot be combined with other compile flags")
+	flagSet.StringVar(&flags.failSeverity, "fail-on", string(lint.SeverityError), "least severity failing the lint: info, warning or error")
+	flagSet.StringVar(&flags.disabledRuleIDs, "disable", "", "comma separated IDs of rules to skip")
+	addCompileSettingFlags(flagSet, &flags.compileFlags)
+
+	return flagSet
+}
+
+func (flags lintFlags) getLintConfig(flagSet *flag.FlagSet) (lint.MorpheLintConfig, error) {
+	var compileConfig compile.MorpheCompileConfig
+	var compileConfigErr error
+	if flags.configFilePath != "" {
+		compileConfig, compileConfigErr = flags.loadCompileConfigFile(flagSet, "fail-on", "disable")
+	} else {
+		compileConfig, compileConfigErr = flags.getCompileSettings()
+	}
+	if compileConfigErr != nil {
+		return lint.MorpheLintConfig{}, compileConfigErr
+	}
+
+	rules := lint.DefaultRules()
+	if flags.disabledRuleIDs != "" {
+		var rulesErr error
+		rules, rulesErr = lint.WithoutRules(rules, strings.Split(flags.disabledRuleIDs, ",")...)
+		if rulesErr != nil {
+			return lint.MorpheLintConfig{}, rulesErr
+		}
+	}
+
+	config := lint.MorpheLintConfig{
+		CompileConfig: compileConfig,
+		Rules:         rules,
+		FailSeverity:  lint.Severity(flags.failSeverity),
+	}
+	validateErr := config.Validate()
+	if validateErr != nil {
+		return lint.MorpheLintConfig{}, validateErr
+	}
+
+	return config, nil
+}
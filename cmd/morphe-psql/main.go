@@ -0,0 +1,54 @@
+// Command morphe-psql compiles Morphe registries into PostgreSQL definitions and lints the compiled definitions.
+//
+// Usage:
+//
+//	morphe-psql compile -registry ./morphe -out ./sql [flags]
+//	morphe-psql lint -registry ./morphe [flags]
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	exitCodeSuccess = 0
+	exitCodeFailure = 1
+	exitCodeUsage   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitCodeUsage
+	}
+
+	switch args[0] {
+	case "compile":
+		return runCompileCommand(args[1:], stdout, stderr)
+	case "lint":
+		return runLintCommand(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		printUsage(stdout)
+		return exitCodeSuccess
+	}
+
+	fmt.Fprintf(stderr, "morphe-psql: unknown command '%s'\n\n", args[0])
+	printUsage(stderr)
+	return exitCodeUsage
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: morphe-psql <command> [flags]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintln(w, "  compile    compile a Morphe registry into PostgreSQL definition files")
+	fmt.Fprintln(w, "  lint       check the PostgreSQL definitions compiled from a Morphe registry")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Run 'morphe-psql <command> -h' for the flags of a command.")
+}
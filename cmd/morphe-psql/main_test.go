@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kalo-build/go-util/assertfile"
+	"github.com/kalo-build/plugin-morphe-psql-types/internal/testutils"
+)
+
+type MainTestSuite struct {
+	assertfile.FileSuite
+
+	TestDirPath            string
+	TestGroundTruthDirPath string
+	RegistryDirPath        string
+}
+
+func TestMainTestSuite(t *testing.T) {
+	suite.Run(t, new(MainTestSuite))
+}
+
+func (suite *MainTestSuite) SetupTest() {
+	suite.TestDirPath = testutils.GetTestDirPath()
+	suite.TestGroundTruthDirPath = filepath.Join(suite.TestDirPath, "ground-truth", "compile-minimal")
+	suite.RegistryDirPath = filepath.Join(suite.TestDirPath, "registry", "minimal")
+}
+
+func (suite *MainTestSuite) TearDownTest() {
+	suite.TestDirPath = ""
+}
+
+func (suite *MainTestSuite) TestRun_Compile() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := run([]string{
+		"compile",
+		"-registry", suite.RegistryDirPath,
+		"-out", workingDirPath,
+		"-persist-structures",
+	}, stdout, stderr)
+
+	suite.Equal(exitCodeSuccess, exitCode, stderr.String())
+	suite.Empty(stderr.String())
+
+	allDefinitionPaths := []string{
+		"models/companies.sql",
+		"models/contact_infos.sql",
+		"models/people.sql",
+		"enums/nationalities.sql",
+		"enums/universal_numbers.sql",
+		"structures/morphe_structures.sql",
+		"entities/company_entities.sql",
+		"entities/person_entities.sql",
+	}
+	for _, definitionPath := range allDefinitionPaths {
+		suite.FileEquals(
+			filepath.Join(workingDirPath, definitionPath),
+			filepath.Join(suite.TestGroundTruthDirPath, definitionPath),
+		)
+	}
+}
+
+func (suite *MainTestSuite) TestRun_Compile_RegistryError() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := run([]string{
+		"compile",
+		"-registry", filepath.Join(suite.TestDirPath, "registry", "missing"),
+		"-out", workingDirPath,
+	}, stdout, stderr)
+
+	suite.Equal(exitCodeFailure, exitCode)
+	suite.Contains(stderr.String(), "morphe-psql compile: compilation failed:")
+}
+
+func (suite *MainTestSuite) TestRun_Compile_NoOutputDir() {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := run([]string{
+		"compile",
+		"-registry", suite.RegistryDirPath,
+	}, stdout, stderr)
+
+	suite.Equal(exitCodeUsage, exitCode)
+	suite.Contains(stderr.String(), "-out must be set")
+}
+
+func (suite *MainTestSuite) TestRun_UnknownCommand() {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := run([]string{"deploy"}, stdout, stderr)
+
+	suite.Equal(exitCodeUsage, exitCode)
+	suite.Contains(stderr.String(), "unknown command 'deploy'")
+}
+
+func (suite *MainTestSuite) TestRun_Compile_ConfigFile() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := run([]string{
+		"compile",
+		"-config", filepath.Join(suite.TestDirPath, "config", "morphe-psql.yaml"),
+	}, stdout, stderr)
+
+	suite.Equal(exitCodeSuccess, exitCode, stderr.String())
+	suite.FileExists(filepath.Join(workingDirPath, "models", "people.sql"))
+	suite.FileExists(filepath.Join(workingDirPath, "structures", "morphe_structures.sql"))
+	suite.FileExists(filepath.Join(workingDirPath, "views", "person_view.sql"))
+
+	companyViewContents, readErr := os.ReadFile(filepath.Join(workingDirPath, "views", "company_view.sql"))
+	suite.Nil(readErr)
+	suite.Contains(string(companyViewContents), "CREATE MATERIALIZED VIEW IF NOT EXISTS public.company_view AS")
+}
+
+func (suite *MainTestSuite) TestRun_Compile_ConfigFileWithFlags() {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := run([]string{
+		"compile",
+		"-config", filepath.Join(suite.TestDirPath, "config", "morphe-psql.yaml"),
+		"-schema", "app",
+	}, stdout, stderr)
+
+	suite.Equal(exitCodeUsage, exitCode)
+	suite.Contains(stderr.String(), "-config cannot be combined with other flags")
+}
+
+func (suite *MainTestSuite) TestRun_Lint() {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := run([]string{
+		"lint",
+		"-registry", suite.RegistryDirPath,
+	}, stdout, stderr)
+
+	suite.Equal(exitCodeSuccess, exitCode, stderr.String())
+	suite.Empty(stderr.String())
+	suite.Contains(stdout.String(), "warning [nullable-unique-column] public.contact_infos: unique column 'email' is nullable")
+	suite.Contains(stdout.String(), "linted registry: 4 findings")
+}
+
+func (suite *MainTestSuite) TestRun_Lint_FailOnWarning() {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := run([]string{
+		"lint",
+		"-registry", suite.RegistryDirPath,
+		"-fail-on", "warning",
+	}, stdout, stderr)
+
+	suite.Equal(exitCodeFailure, exitCode)
+	suite.Contains(stderr.String(), "morphe-psql lint: 4 of 4 findings at or above warning")
+}
+
+func (suite *MainTestSuite) TestRun_Lint_DisabledRule() {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := run([]string{
+		"lint",
+		"-config", filepath.Join(suite.TestDirPath, "config", "morphe-psql.yaml"),
+		"-fail-on", "warning",
+		"-disable", "nullable-unique-column",
+	}, stdout, stderr)
+
+	suite.Equal(exitCodeSuccess, exitCode, stderr.String())
+	suite.Equal("linted registry: 0 findings\n", stdout.String())
+}
+
+func (suite *MainTestSuite) TestRun_Lint_UnknownRule() {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	exitCode := run([]string{
+		"lint",
+		"-registry", suite.RegistryDirPath,
+		"-disable", "no-such-rule",
+	}, stdout, stderr)
+
+	suite.Equal(exitCodeUsage, exitCode)
+	suite.Contains(stderr.String(), "unknown lint rule 'no-such-rule'")
+}
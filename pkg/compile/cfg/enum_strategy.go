@@ -0,0 +1,12 @@
+package cfg
+
+// EnumStrategy determines how Morphe enums are represented in PostgreSQL
+type EnumStrategy string
+
+const (
+	// EnumStrategyLookupTable compiles enums to key/value lookup tables with seed data (default)
+	EnumStrategyLookupTable EnumStrategy = "lookup_table"
+
+	// EnumStrategyNativeEnum compiles enums to native `CREATE TYPE ... AS ENUM` types
+	EnumStrategyNativeEnum EnumStrategy = "native_enum"
+)
@@ -0,0 +1,45 @@
+package cfg
+
+// ForeignKeyConfig holds the referential actions of foreign key constraints
+type ForeignKeyConfig struct {
+	// OnDelete is the action taken when a referenced row is deleted (default: CASCADE)
+	OnDelete ReferentialAction
+
+	// OnUpdate is the action taken when a referenced key is updated (default: none, i.e. NO ACTION)
+	OnUpdate ReferentialAction
+
+	// Deferrable makes the constraints DEFERRABLE INITIALLY DEFERRED, checking them at the end of the transaction
+	Deferrable bool
+}
+
+// Validate checks if the referential actions are known
+func (config ForeignKeyConfig) Validate() error {
+	if !config.OnDelete.IsValid() {
+		return ErrUnknownReferentialAction(config.OnDelete)
+	}
+	if !config.OnUpdate.IsValid() {
+		return ErrUnknownReferentialAction(config.OnUpdate)
+	}
+	return nil
+}
+
+// WithOverrides returns the config with the actions set in the overrides replacing its own. Deferrable
+// constraints cannot be made non-deferrable by an override.
+func (config ForeignKeyConfig) WithOverrides(overrides ForeignKeyConfig) ForeignKeyConfig {
+	if overrides.OnDelete != "" {
+		config.OnDelete = overrides.OnDelete
+	}
+	if overrides.OnUpdate != "" {
+		config.OnUpdate = overrides.OnUpdate
+	}
+	config.Deferrable = config.Deferrable || overrides.Deferrable
+	return config
+}
+
+// GetOnDelete returns the ON DELETE action, falling back to the default
+func (config ForeignKeyConfig) GetOnDelete() ReferentialAction {
+	if config.OnDelete == "" {
+		return DefaultOnDelete
+	}
+	return config.OnDelete
+}
@@ -0,0 +1,26 @@
+package cfg
+
+// IdentifierCollisionStrategy determines how identifiers shared by several compiled definitions are handled, e.g. long
+// model names abbreviated to the same table name
+type IdentifierCollisionStrategy string
+
+const (
+	// IdentifierCollisionStrategyFail fails the compilation, reporting the colliding definitions (default)
+	IdentifierCollisionStrategyFail IdentifierCollisionStrategy = "fail"
+
+	// IdentifierCollisionStrategyHash suffixes colliding abbreviated identifiers with a hash of their full identifier
+	IdentifierCollisionStrategyHash IdentifierCollisionStrategy = "hash"
+)
+
+// Validate checks if the strategy is known, an empty strategy uses the default
+func (strategy IdentifierCollisionStrategy) Validate() error {
+	if strategy != "" && strategy != IdentifierCollisionStrategyFail && strategy != IdentifierCollisionStrategyHash {
+		return ErrUnknownIdentifierCollisionStrategy(strategy)
+	}
+	return nil
+}
+
+// IsHash returns true if colliding abbreviated identifiers should be told apart by hashes
+func (strategy IdentifierCollisionStrategy) IsHash() bool {
+	return strategy == IdentifierCollisionStrategyHash
+}
@@ -6,6 +6,12 @@ type MorpheConfig struct {
 	MorpheEnumsConfig
 	MorpheStructuresConfig
 	MorpheEntitiesConfig
+
+	// IdentifierCollisions determines how identifiers shared by several definitions are handled (default: fail)
+	IdentifierCollisions IdentifierCollisionStrategy
+
+	// Naming selects the built-in naming strategy for compiled identifiers (default: morphe)
+	Naming NamingPreset
 }
 
 // Default schema
@@ -36,6 +42,16 @@ func (config MorpheConfig) Validate() error {
 		return entitiesErr
 	}
 
+	identifierCollisionsErr := config.IdentifierCollisions.Validate()
+	if identifierCollisionsErr != nil {
+		return identifierCollisionsErr
+	}
+
+	namingErr := config.Naming.Validate()
+	if namingErr != nil {
+		return namingErr
+	}
+
 	return nil
 }
 
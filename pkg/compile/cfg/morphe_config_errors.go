@@ -1,8 +1,40 @@
 package cfg
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var ErrNoSchema = errors.New("schema cannot be empty")
 var ErrNoModelSchema = errors.New("model schema cannot be empty")
 var ErrNoEnumSchema = errors.New("enum schema cannot be empty")
 var ErrNoStructureSchema = errors.New("structure schema cannot be empty when persistence is enabled")
+var ErrNoStructureTypeSchema = errors.New("structure schema cannot be empty when structures compile to composite types")
+
+func ErrUnknownEnumStrategy(strategy EnumStrategy) error {
+	return fmt.Errorf("unknown enum strategy '%s'", strategy)
+}
+
+func ErrNoRelationAliasModel(modelName string, relationName string) error {
+	return fmt.Errorf("relation alias '%s' of model '%s' has no related model", relationName, modelName)
+}
+
+func ErrNoPolymorphicRelationTargets(modelName string, relationName string) error {
+	return fmt.Errorf("polymorphic relation '%s' of model '%s' has no target models", relationName, modelName)
+}
+
+func ErrUnknownReferentialAction(action ReferentialAction) error {
+	return fmt.Errorf("unknown referential action '%s'", action)
+}
+
+func ErrUnknownStructureStrategy(strategy StructureStrategy) error {
+	return fmt.Errorf("unknown structure strategy '%s'", strategy)
+}
+
+func ErrUnknownIdentifierCollisionStrategy(strategy IdentifierCollisionStrategy) error {
+	return fmt.Errorf("unknown identifier collision strategy '%s'", strategy)
+}
+
+func ErrUnknownNamingPreset(preset NamingPreset) error {
+	return fmt.Errorf("unknown naming preset '%s'", preset)
+}
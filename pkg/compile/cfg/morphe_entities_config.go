@@ -9,6 +9,19 @@ type MorpheEntitiesConfig struct {
 
 	// ViewNameSuffix is appended to view names (default: "_entities")
 	ViewNameSuffix string
+
+	// MaterializedViews maps entity names to their materialized view settings, unlisted entities compile to regular views
+	MaterializedViews map[string]MaterializedViewConfig
+}
+
+// MaterializedViewConfig holds the settings of an entity compiled to a materialized view
+type MaterializedViewConfig struct {
+	// WithNoData creates the view without populating it (WITH NO DATA), leaving the first refresh to the caller
+	WithNoData bool
+
+	// UniqueIndexFields are the entity fields of the unique index enabling concurrent refreshes
+	// (default: the fields of the entity's primary identifier)
+	UniqueIndexFields []string
 }
 
 // Validate validates the MorpheEntitiesConfig
@@ -18,3 +31,9 @@ func (c MorpheEntitiesConfig) Validate() error {
 	}
 	return nil
 }
+
+// GetMaterializedViewConfig returns the materialized view settings of the entity, if it compiles to a materialized view
+func (c MorpheEntitiesConfig) GetMaterializedViewConfig(entityName string) (MaterializedViewConfig, bool) {
+	materializedConfig, isMaterialized := c.MaterializedViews[entityName]
+	return materializedConfig, isMaterialized
+}
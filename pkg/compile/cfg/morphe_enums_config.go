@@ -7,6 +7,12 @@ type MorpheEnumsConfig struct {
 
 	// Whether to use BIGSERIAL instead of SERIAL for auto-increment fields
 	UseBigSerial bool
+
+	// Strategy used to represent enums (default: lookup tables)
+	Strategy EnumStrategy
+
+	// ForeignKeys holds the referential actions of foreign keys referencing enum lookup tables
+	ForeignKeys ForeignKeyConfig
 }
 
 // Validate checks if the models configuration is valid
@@ -14,6 +20,18 @@ func (config MorpheEnumsConfig) Validate() error {
 	if config.Schema == "" {
 		return ErrNoEnumSchema
 	}
+	if config.Strategy != "" && config.Strategy != EnumStrategyLookupTable && config.Strategy != EnumStrategyNativeEnum {
+		return ErrUnknownEnumStrategy(config.Strategy)
+	}
+	foreignKeysErr := config.ForeignKeys.Validate()
+	if foreignKeysErr != nil {
+		return foreignKeysErr
+	}
 
 	return nil
 }
+
+// IsNativeEnum returns true if enums should be compiled to native PostgreSQL enum types
+func (config MorpheEnumsConfig) IsNativeEnum() bool {
+	return config.Strategy == EnumStrategyNativeEnum
+}
@@ -7,6 +7,24 @@ type MorpheModelsConfig struct {
 
 	// Whether to use BIGSERIAL instead of SERIAL for auto-increment fields
 	UseBigSerial bool
+
+	// Whether junction tables are keyed by their (source, target) columns instead of a surrogate id
+	UseCompositeJunctionKeys bool
+
+	// ForeignKeys holds the referential actions of relation and junction table foreign keys
+	ForeignKeys ForeignKeyConfig
+
+	// RelationForeignKeys overrides the foreign key actions per relation, by model name and relation name, e.g. a
+	// Person's aliased "Manager" relation rather than its related "Person" model
+	RelationForeignKeys map[string]map[string]ForeignKeyConfig
+
+	// RelationAliases maps relations not named after their related model to that model, by model name and relation
+	// name, e.g. a Person's "Manager" relation to "Person"
+	RelationAliases map[string]map[string]string
+
+	// PolymorphicRelations lists the models a polymorphic relation (e.g. ForOnePoly) may reference, by model name
+	// and relation name
+	PolymorphicRelations map[string]map[string][]string
 }
 
 // Validate checks if the models configuration is valid
@@ -14,6 +32,51 @@ func (config MorpheModelsConfig) Validate() error {
 	if config.Schema == "" {
 		return ErrNoModelSchema
 	}
+	foreignKeysErr := config.ForeignKeys.Validate()
+	if foreignKeysErr != nil {
+		return foreignKeysErr
+	}
+	for _, relationForeignKeys := range config.RelationForeignKeys {
+		for _, relationForeignKey := range relationForeignKeys {
+			relationForeignKeyErr := relationForeignKey.Validate()
+			if relationForeignKeyErr != nil {
+				return relationForeignKeyErr
+			}
+		}
+	}
+	for modelName, relationAliases := range config.RelationAliases {
+		for relationName, relatedModelName := range relationAliases {
+			if relatedModelName == "" {
+				return ErrNoRelationAliasModel(modelName, relationName)
+			}
+		}
+	}
+	for modelName, polymorphicRelations := range config.PolymorphicRelations {
+		for relationName, targetModelNames := range polymorphicRelations {
+			if len(targetModelNames) == 0 {
+				return ErrNoPolymorphicRelationTargets(modelName, relationName)
+			}
+		}
+	}
 
 	return nil
 }
+
+// GetRelatedModelName returns the name of the model a relation relates to, which is the relation name unless aliased
+func (config MorpheModelsConfig) GetRelatedModelName(modelName string, relationName string) string {
+	relatedModelName, isAliased := config.RelationAliases[modelName][relationName]
+	if !isAliased {
+		return relationName
+	}
+	return relatedModelName
+}
+
+// GetPolymorphicRelationTargets returns the names of the models a polymorphic relation of a model may reference
+func (config MorpheModelsConfig) GetPolymorphicRelationTargets(modelName string, relationName string) []string {
+	return config.PolymorphicRelations[modelName][relationName]
+}
+
+// GetRelationForeignKeyConfig returns the foreign key actions of a model's relation by the relation's name
+func (config MorpheModelsConfig) GetRelationForeignKeyConfig(modelName string, relationName string) ForeignKeyConfig {
+	return config.ForeignKeys.WithOverrides(config.RelationForeignKeys[modelName][relationName])
+}
@@ -10,6 +10,9 @@ type MorpheStructuresConfig struct {
 
 	// Whether to enable structure persistence
 	EnablePersistence bool
+
+	// Strategy used to represent structures (default: JSON table)
+	Strategy StructureStrategy
 }
 
 // Validate checks if the structures configuration is valid
@@ -17,6 +20,22 @@ func (config MorpheStructuresConfig) Validate() error {
 	if config.EnablePersistence && config.Schema == "" {
 		return ErrNoStructureSchema
 	}
+	if config.Strategy != "" && config.Strategy != StructureStrategyJSONTable && config.Strategy != StructureStrategyTypedTable && config.Strategy != StructureStrategyCompositeType {
+		return ErrUnknownStructureStrategy(config.Strategy)
+	}
+	if config.IsCompositeType() && config.Schema == "" {
+		return ErrNoStructureTypeSchema
+	}
 
 	return nil
 }
+
+// IsCompositeType returns true if structures should be compiled to composite types
+func (config MorpheStructuresConfig) IsCompositeType() bool {
+	return config.Strategy == StructureStrategyCompositeType
+}
+
+// IsTypedTable returns true if persisted structures should be stored in one typed table per structure
+func (config MorpheStructuresConfig) IsTypedTable() bool {
+	return config.Strategy == StructureStrategyTypedTable
+}
@@ -0,0 +1,23 @@
+package cfg
+
+// NamingPreset selects a built-in naming strategy for the identifiers of compiled definitions
+type NamingPreset string
+
+const (
+	// NamingPresetMorphe names pluralized tables with fk_, uk_ and idx_ prefixed constraints and indices (default)
+	NamingPresetMorphe NamingPreset = "morphe"
+
+	// NamingPresetPostgres names singular tables with _fkey, _key and _idx suffixed constraints and indices
+	NamingPresetPostgres NamingPreset = "postgres"
+
+	// NamingPresetRails names pluralized tables with index_<table>_on_<columns> indices and hashed fk_rails_ constraints
+	NamingPresetRails NamingPreset = "rails"
+)
+
+// Validate checks if the preset is known, an empty preset uses the default
+func (preset NamingPreset) Validate() error {
+	if preset != "" && preset != NamingPresetMorphe && preset != NamingPresetPostgres && preset != NamingPresetRails {
+		return ErrUnknownNamingPreset(preset)
+	}
+	return nil
+}
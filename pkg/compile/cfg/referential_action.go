@@ -0,0 +1,30 @@
+package cfg
+
+import "slices"
+
+// ReferentialAction determines what happens to referencing rows when a referenced row is deleted or updated
+type ReferentialAction string
+
+const (
+	ReferentialActionCascade    ReferentialAction = "CASCADE"
+	ReferentialActionRestrict   ReferentialAction = "RESTRICT"
+	ReferentialActionSetNull    ReferentialAction = "SET NULL"
+	ReferentialActionSetDefault ReferentialAction = "SET DEFAULT"
+	ReferentialActionNoAction   ReferentialAction = "NO ACTION"
+)
+
+// DefaultOnDelete is the ON DELETE action of foreign keys without a configured action
+const DefaultOnDelete = ReferentialActionCascade
+
+var referentialActions = []ReferentialAction{
+	ReferentialActionCascade,
+	ReferentialActionRestrict,
+	ReferentialActionSetNull,
+	ReferentialActionSetDefault,
+	ReferentialActionNoAction,
+}
+
+// IsValid returns true if the action is empty (unset) or a known referential action
+func (action ReferentialAction) IsValid() bool {
+	return action == "" || slices.Contains(referentialActions, action)
+}
@@ -0,0 +1,15 @@
+package cfg
+
+// StructureStrategy determines how Morphe structures are represented in PostgreSQL
+type StructureStrategy string
+
+const (
+	// StructureStrategyJSONTable stores structures as JSONB rows of the generic morphe_structures table (default)
+	StructureStrategyJSONTable StructureStrategy = "json_table"
+
+	// StructureStrategyTypedTable persists each structure in its own table with typed columns
+	StructureStrategyTypedTable StructureStrategy = "typed_table"
+
+	// StructureStrategyCompositeType compiles each structure to a `CREATE TYPE ... AS (...)` composite type
+	StructureStrategyCompositeType StructureStrategy = "composite_type"
+)
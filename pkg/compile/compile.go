@@ -1,6 +1,13 @@
 package compile
 
-import "github.com/kalo-build/morphe-go/pkg/registry"
+import (
+	"slices"
+
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/write"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
 
 func MorpheToPSQL(config MorpheCompileConfig) error {
 	r, rErr := registry.LoadMorpheRegistry(config.RegistryHooks, config.MorpheLoadRegistryConfig)
@@ -8,53 +15,141 @@ func MorpheToPSQL(config MorpheCompileConfig) error {
 		return rErr
 	}
 
-	allEnumTables, compileAllEnumsErr := AllMorpheEnumsToPSQLTables(config, r)
-	if compileAllEnumsErr != nil {
-		return compileAllEnumsErr
+	// Check the writers up front, nothing is written unless the whole registry compiles
+	if config.MorpheEnumsConfig.IsNativeEnum() && config.EnumTypeWriter == nil {
+		return ErrNoEnumTypeWriter
 	}
-
-	_, writeEnumTablesErr := WriteAllEnumTableDefinitions(config, allEnumTables)
-	if writeEnumTablesErr != nil {
-		return writeEnumTablesErr
+	if config.MorpheStructuresConfig.IsCompositeType() && config.StructureTypeWriter == nil {
+		return ErrNoStructureTypeWriter
+	}
+	if config.MorpheStructuresConfig.EnablePersistence && config.StructureWriter == nil {
+		return ErrNoStructureWriter
 	}
 
-	allModelTables, compileAllModelsErr := AllMorpheModelsToPSQLTables(config, r)
-	if compileAllModelsErr != nil {
-		return compileAllModelsErr
+	definitions, definitionsErr := AllMorpheToPSQLDefinitions(config, r)
+	if definitionsErr != nil {
+		return definitionsErr
 	}
 
-	_, writeModelTablesErr := WriteAllModelTableDefinitions(config, allModelTables)
-	if writeModelTablesErr != nil {
-		return writeModelTablesErr
+	writtenTypeNames := map[string]bool{}
+	if config.MorpheEnumsConfig.IsNativeEnum() {
+		_, writeEnumTypesErr := WriteAllEnumTypeDefinitions(config, definitions.EnumTypes)
+		if writeEnumTypesErr != nil {
+			return writeEnumTypesErr
+		}
+		for _, enumType := range definitions.EnumTypes {
+			writtenTypeNames[enumType.GetSyntax()] = true
+		}
+	} else {
+		_, writeEnumTablesErr := WriteAllEnumTableDefinitions(config, definitions.EnumTables)
+		if writeEnumTablesErr != nil {
+			return writeEnumTablesErr
+		}
 	}
 
-	// Optionally compile structure table if enabled
-	if config.MorpheStructuresConfig.EnablePersistence {
-		// Check if structure writer is set
-		if config.StructureWriter == nil {
-			return ErrNoStructureWriter
+	// Composite types must exist before the model tables using them
+	if config.MorpheStructuresConfig.IsCompositeType() {
+		_, writeStructureTypesErr := WriteAllStructureTypeDefinitions(config, definitions.StructureTypes)
+		if writeStructureTypesErr != nil {
+			return writeStructureTypesErr
 		}
+		for _, structureType := range definitions.StructureTypes {
+			writtenTypeNames[structureType.GetSyntax()] = true
+		}
+	}
 
-		structureTable, compileStructureErr := MorpheStructureToPSQLTable(config)
-		if compileStructureErr != nil {
-			return compileStructureErr
+	// Custom types used by the tables (e.g. domains set by hooks) must exist before the tables using them
+	allTables := []*psqldef.Table{definitions.StructureTable}
+	for _, modelName := range core.MapKeysSorted(definitions.ModelTables) {
+		allTables = append(allTables, definitions.ModelTables[modelName]...)
+	}
+	for _, structureName := range core.MapKeysSorted(definitions.StructureTables) {
+		allTables = append(allTables, definitions.StructureTables[structureName])
+	}
+	allCustomTypes, customTypesErr := getUnwrittenCustomTypes(allTables, writtenTypeNames)
+	if customTypesErr != nil {
+		return customTypesErr
+	}
+	if len(allCustomTypes) > 0 {
+		if config.TypeWriter == nil {
+			return ErrNoTypeWriter
 		}
 
-		_, _, writeStructureErr := WriteStructureTableDefinition(config.WriteTableHooks, config.StructureWriter, structureTable)
-		if writeStructureErr != nil {
-			return writeStructureErr
+		_, writeCustomTypesErr := WriteAllCustomTypeDefinitions(config, allCustomTypes)
+		if writeCustomTypesErr != nil {
+			return writeCustomTypesErr
 		}
 	}
 
-	allEntityViews, compileAllEntityViewsErr := AllMorpheEntitiesToPSQLViews(config, r)
-	if compileAllEntityViewsErr != nil {
-		return compileAllEntityViewsErr
+	_, writeModelTablesErr := WriteAllModelTableDefinitions(config, definitions.ModelTables)
+	if writeModelTablesErr != nil {
+		return writeModelTablesErr
 	}
 
-	_, writeEntityViewsErr := WriteAllEntityViewDefinitions(config, allEntityViews)
+	if config.MorpheStructuresConfig.EnablePersistence {
+		if config.MorpheStructuresConfig.IsTypedTable() {
+			_, writeStructureTablesErr := WriteAllStructureTableDefinitions(config, definitions.StructureTables)
+			if writeStructureTablesErr != nil {
+				return writeStructureTablesErr
+			}
+		} else {
+			_, _, writeStructureErr := WriteStructureTableDefinition(config.WriteTableHooks, config.StructureWriter, definitions.StructureTable)
+			if writeStructureErr != nil {
+				return writeStructureErr
+			}
+		}
+	}
+
+	_, writeEntityViewsErr := WriteAllEntityViewDefinitions(config, definitions.EntityViews)
 	if writeEntityViewsErr != nil {
 		return writeEntityViewsErr
 	}
 
+	return flushWriters(config)
+}
+
+// flushWriters flushes each configured writer that collects its definitions until compilation finishes, writers
+// shared between definition kinds are flushed once
+func flushWriters(config MorpheCompileConfig) error {
+	allWriters := []any{
+		config.EnumWriter,
+		config.EnumTypeWriter,
+		config.StructureTypeWriter,
+		config.TypeWriter,
+		config.ModelWriter,
+		config.StructureWriter,
+		config.EntityWriter,
+	}
+
+	flushedWriters := []write.PSQLFlusher{}
+	for _, writer := range allWriters {
+		flusher, isFlusher := writer.(write.PSQLFlusher)
+		if !isFlusher || slices.Contains(flushedWriters, flusher) {
+			continue
+		}
+		_, flushErr := flusher.Flush()
+		if flushErr != nil {
+			return flushErr
+		}
+		flushedWriters = append(flushedWriters, flusher)
+	}
 	return nil
 }
+
+// getUnwrittenCustomTypes returns the dependency-ordered custom types referenced by the tables, except the already
+// written enum and structure types
+func getUnwrittenCustomTypes(allTables []*psqldef.Table, writtenTypeNames map[string]bool) ([]psqldef.PSQLType, error) {
+	allCustomTypes, customTypesErr := GetCustomTypesForTables(allTables)
+	if customTypesErr != nil {
+		return nil, customTypesErr
+	}
+
+	unwrittenCustomTypes := []psqldef.PSQLType{}
+	for _, customType := range allCustomTypes {
+		if writtenTypeNames[customType.GetSyntax()] {
+			continue
+		}
+		unwrittenCustomTypes = append(unwrittenCustomTypes, customType)
+	}
+	return unwrittenCustomTypes, nil
+}
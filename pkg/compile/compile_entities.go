@@ -5,9 +5,9 @@ import (
 	"strings"
 
 	"github.com/kalo-build/go-util/core"
-	"github.com/kalo-build/go-util/strcase"
 	"github.com/kalo-build/morphe-go/pkg/registry"
 	"github.com/kalo-build/morphe-go/pkg/yaml"
+	"github.com/kalo-build/morphe-go/pkg/yamlops"
 	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
 	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/hook"
 	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
@@ -18,6 +18,18 @@ var (
 	ErrMissingMorpheEntityField = func(entityName, fieldName string) error {
 		return fmt.Errorf("missing entity field %s in entity %s", fieldName, entityName)
 	}
+	ErrNoMorpheEntityRootModel = func(entityName string) error {
+		return fmt.Errorf("entity %s has no fields to derive a root model from", entityName)
+	}
+	ErrMissingMorpheInverseRelation = func(modelName, relatedModelName string) error {
+		return fmt.Errorf("model %s has no ForOne or ForMany relation to %s holding the foreign key of their relationship", relatedModelName, modelName)
+	}
+	ErrAmbiguousMorpheInverseRelation = func(modelName, relatedModelName string, relationNames []string) error {
+		return fmt.Errorf("model %s has several ForOne or ForMany relations to %s, cannot tell which one is the inverse relation: %s", relatedModelName, modelName, strings.Join(relationNames, ", "))
+	}
+	ErrMixedMorpheEntityRootModels = func(entityName string, rootModelNames []string) error {
+		return fmt.Errorf("entity %s has fields with different root models: %s", entityName, strings.Join(rootModelNames, ", "))
+	}
 )
 
 // AllMorpheEntitiesToPSQLViews compiles all Morphe entities to PostgreSQL views
@@ -45,7 +57,7 @@ func MorpheEntityToPSQLView(config MorpheCompileConfig, r *registry.Registry, en
 	}
 	config.MorpheConfig = morpheConfig
 
-	view, viewErr := morpheEntityToPSQLView(config.MorpheConfig, r, entity)
+	view, viewErr := morpheEntityToPSQLView(config.MorpheConfig, config.getIdentifierNames(), r, entity)
 	if viewErr != nil {
 		return nil, triggerCompileMorpheEntityFailure(config.EntityHooks, config.MorpheConfig, entity, viewErr)
 	}
@@ -58,24 +70,25 @@ func MorpheEntityToPSQLView(config MorpheCompileConfig, r *registry.Registry, en
 	return view, nil
 }
 
-func morpheEntityToPSQLView(config cfg.MorpheConfig, r *registry.Registry, entity yaml.Entity) (*psqldef.View, error) {
+func morpheEntityToPSQLView(config cfg.MorpheConfig, names identifierNames, r *registry.Registry, entity yaml.Entity) (*psqldef.View, error) {
 	validateConfigErr := config.Validate()
 	if validateConfigErr != nil {
 		return nil, validateConfigErr
 	}
 
-	validateEntityErr := entity.Validate(r.GetAllModels(), r.GetAllEnums())
+	allValidationModels := getMorpheModelsForEntityValidation(config, r)
+	validateEntityErr := entity.Validate(allValidationModels, r.GetAllEnums())
 	if validateEntityErr != nil {
 		return nil, validateEntityErr
 	}
 
-	viewName := strcase.ToSnakeCaseLower(entity.Name)
-	if config.MorpheEntitiesConfig.ViewNameSuffix != "" {
-		viewName += config.MorpheEntitiesConfig.ViewNameSuffix
-	}
+	viewName := names.getViewNameFromEntity(entity.Name, config.MorpheEntitiesConfig.ViewNameSuffix)
 
-	// TODO: Extract all "root" models from the entity fields, and use the first one as the base table name
-	tableName := Pluralize(strcase.ToSnakeCaseLower(entity.Name))
+	rootModelName, rootModelErr := getEntityRootModelName(entity)
+	if rootModelErr != nil {
+		return nil, rootModelErr
+	}
+	tableName := names.getTableNameFromModel(rootModelName)
 
 	view := &psqldef.View{
 		Schema:    config.MorpheEntitiesConfig.Schema,
@@ -85,102 +98,351 @@ func morpheEntityToPSQLView(config cfg.MorpheConfig, r *registry.Registry, entit
 		Joins:     []psqldef.JoinClause{},
 	}
 
-	// Process entity fields to set up columns and joins
-	joinTables := make(map[string]bool)
-	// rootTableRelationships := make(map[string]string)
-	joinTableRelationships := make(map[string]string)
+	rootModel, rootModelErr := r.GetModel(rootModelName)
+	if rootModelErr != nil {
+		return nil, rootModelErr
+	}
+
+	viewJoins := newEntityViewJoins(config.MorpheModelsConfig, r, names, rootModel, tableName)
 
 	fieldNames := core.MapKeysSorted(entity.Fields)
 	for _, fieldName := range fieldNames {
 		field := entity.Fields[fieldName]
-		// Convert field name to snake case for column name
-		columnName := strcase.ToSnakeCaseLower(fieldName)
+		columnName := names.getColumnNameFromField(fieldName)
 
-		// Parse the field type (e.g., "User.UUID" or "User.Child.AutoIncrement")
+		// Field types are paths from the root model over its relations to a model field (e.g. "Person.Company.Address.City")
 		fieldParts := strings.Split(string(field.Type), ".")
 		if len(fieldParts) < 2 {
 			return nil, fmt.Errorf("invalid field type format: %s", field.Type)
 		}
 
-		// Determine source reference for this column
-		var sourceRef string
-		if len(fieldParts) == 2 {
-			// Direct field from main model
-			sourceRef = fmt.Sprintf("%s.%s", tableName, strcase.ToSnakeCaseLower(fieldParts[1]))
-		} else if len(fieldParts) == 3 {
-			// Field from related model
-			relatedModelName := fieldParts[1]
-			relatedFieldName := fieldParts[2]
-			relatedTableName := Pluralize(strcase.ToSnakeCaseLower(relatedModelName))
-			sourceRef = fmt.Sprintf("%s.%s", relatedTableName, strcase.ToSnakeCaseLower(relatedFieldName))
-
-			// Record that we need a join to this table
-			joinTables[relatedTableName] = true
-
-			// Record the relationship to set up join condition
-			joinTableRelationships[relatedTableName] = relatedModelName
+		relationPath := fieldParts[1 : len(fieldParts)-1]
+		_, sourceAlias, joinErr := viewJoins.joinRelationPath(relationPath)
+		if joinErr != nil {
+			return nil, joinErr
 		}
 
-		// Add column to the view
+		sourceFieldName := fieldParts[len(fieldParts)-1]
 		column := psqldef.ViewColumn{
 			Name:      columnName,
-			SourceRef: sourceRef,
+			SourceRef: fmt.Sprintf("%s.%s", sourceAlias, names.getColumnNameFromField(sourceFieldName)),
 			Alias:     "", // No alias by default
 		}
 		view.Columns = append(view.Columns, column)
 	}
 
-	// Set up joins based on relationships
-	for joinTable, _ := range joinTables {
-		// Get related model name
-		relatedModelName := joinTableRelationships[joinTable]
-		if relatedModelName == "" {
-			continue
+	view.Joins = viewJoins.joins
+
+	materializedConfig, isMaterialized := config.MorpheEntitiesConfig.GetMaterializedViewConfig(entity.Name)
+	if isMaterialized {
+		materializeErr := materializeEntityView(view, names, entity, materializedConfig)
+		if materializeErr != nil {
+			return nil, materializeErr
 		}
+	}
 
-		// Find relationship in model
-		modelName := entity.Name
-		model, modelErr := r.GetModel(modelName)
-		if modelErr != nil {
-			return nil, modelErr
+	return view, nil
+}
+
+// getMorpheModelsForEntityValidation returns the models as Morphe validates entities. Morphe resolves the relations of
+// entity field paths by model name, so aliased relations are added under their relation name.
+func getMorpheModelsForEntityValidation(config cfg.MorpheConfig, r *registry.Registry) map[string]yaml.Model {
+	allValidationModels := map[string]yaml.Model{}
+	for modelName, model := range r.GetAllModels() {
+		allValidationModels[modelName] = getMorpheModelForValidation(config, r, model)
+	}
+	for _, modelName := range core.MapKeysSorted(config.MorpheModelsConfig.RelationAliases) {
+		relationAliases := config.MorpheModelsConfig.RelationAliases[modelName]
+		for _, relationName := range core.MapKeysSorted(relationAliases) {
+			relatedModel, relatedModelExists := allValidationModels[relationAliases[relationName]]
+			if _, nameTaken := allValidationModels[relationName]; relatedModelExists && !nameTaken {
+				allValidationModels[relationName] = relatedModel
+			}
 		}
+	}
+	return allValidationModels
+}
+
+// materializeEntityView turns the view into a materialized view with a unique index over the configured (or primary
+// identifier) fields and a function refreshing it
+func materializeEntityView(view *psqldef.View, names identifierNames, entity yaml.Entity, materializedConfig cfg.MaterializedViewConfig) error {
+	view.Materialized = true
+	view.WithNoData = materializedConfig.WithNoData
+	view.Indices = []psqldef.Index{}
+	view.RefreshFunctionName = names.getMaterializedViewRefreshFunctionName(view.Name)
+
+	uniqueIndexFields := materializedConfig.UniqueIndexFields
+	if len(uniqueIndexFields) == 0 {
+		uniqueIndexFields = entity.Identifiers["primary"].Fields
+	}
+	if len(uniqueIndexFields) == 0 {
+		return nil
+	}
 
-		_, relationshipExists := model.Related[relatedModelName]
-		if !relationshipExists {
-			return nil, fmt.Errorf("relationship %s not found in model %s", relatedModelName, modelName)
+	columnNames := []string{}
+	for _, fieldName := range uniqueIndexFields {
+		if _, fieldExists := entity.Fields[fieldName]; !fieldExists {
+			return ErrMissingMorpheEntityField(entity.Name, fieldName)
 		}
+		columnNames = append(columnNames, names.getColumnNameFromField(fieldName))
+	}
+
+	view.Indices = append(view.Indices, psqldef.Index{
+		Schema:    view.Schema,
+		Name:      names.getMaterializedViewUniqueIndexName(view.Name, columnNames...),
+		TableName: view.Name,
+		Columns:   columnNames,
+		IsUnique:  true,
+	})
+	return nil
+}
+
+// entityViewJoins builds the joins of an entity view, joining every distinct relation path exactly once
+type entityViewJoins struct {
+	config    cfg.MorpheModelsConfig
+	r         *registry.Registry
+	names     identifierNames
+	rootModel yaml.Model
+	rootAlias string
+
+	aliasesByPath map[string]string
+	modelsByPath  map[string]yaml.Model
+	usedAliases   map[string]bool
+	joins         []psqldef.JoinClause
+}
 
-		joinType := "LEFT"
-		// TODO: We can't just use uuid, we need to extract the primary identifier field from each.
+func newEntityViewJoins(config cfg.MorpheModelsConfig, r *registry.Registry, names identifierNames, rootModel yaml.Model, rootAlias string) *entityViewJoins {
+	return &entityViewJoins{
+		config:        config,
+		r:             r,
+		names:         names,
+		rootModel:     rootModel,
+		rootAlias:     rootAlias,
+		aliasesByPath: map[string]string{},
+		modelsByPath:  map[string]yaml.Model{},
+		usedAliases:   map[string]bool{rootAlias: true},
+		joins:         []psqldef.JoinClause{},
+	}
+}
+
+// joinRelationPath joins all relations along the path (if not joined yet) and returns the model and alias at its end
+func (j *entityViewJoins) joinRelationPath(relationPath []string) (yaml.Model, string, error) {
+	currentModel := j.rootModel
+	currentAlias := j.rootAlias
 
-		rootPrimaryId, rootPrimaryIdExists := model.Identifiers["primary"]
-		if !rootPrimaryIdExists {
-			return nil, fmt.Errorf("primary identifier not found in model '%s'", modelName)
+	for relationIdx, relationName := range relationPath {
+		pathKey := strings.Join(relationPath[:relationIdx+1], ".")
+		if alias, joined := j.aliasesByPath[pathKey]; joined {
+			currentModel = j.modelsByPath[pathKey]
+			currentAlias = alias
+			continue
 		}
 
-		relatedPrimaryId, relatedPrimaryIdExists := model.Identifiers["primary"]
-		if !relatedPrimaryIdExists {
-			return nil, fmt.Errorf("primary identifier not found in model '%s'", relatedModelName)
+		modelRelation, relationExists := currentModel.Related[relationName]
+		if !relationExists {
+			return yaml.Model{}, "", fmt.Errorf("relationship %s not found in model %s", relationName, currentModel.Name)
 		}
-		rootPrimaryIdName := strcase.ToSnakeCaseLower(rootPrimaryId.Fields[0])
-		relatedPrimaryIdName := strcase.ToSnakeCaseLower(relatedPrimaryId.Fields[0])
-
-		joinClause := psqldef.JoinClause{
-			Type:  joinType,
-			Table: joinTable,
-			Alias: joinTable,
-			Conditions: []psqldef.JoinCondition{
-				{
-					LeftRef:  tableName + "." + rootPrimaryIdName,
-					RightRef: joinTable + "." + relatedPrimaryIdName,
-				},
+
+		relatedModel, relatedModelErr := j.r.GetModel(j.config.GetRelatedModelName(currentModel.Name, relationName))
+		if relatedModelErr != nil {
+			return yaml.Model{}, "", relatedModelErr
+		}
+
+		relationJoins, relatedAlias, joinsErr := j.getRelationJoins(currentModel, currentAlias, relationName, modelRelation, relatedModel)
+		if joinsErr != nil {
+			return yaml.Model{}, "", joinsErr
+		}
+
+		j.joins = append(j.joins, relationJoins...)
+		j.aliasesByPath[pathKey] = relatedAlias
+		j.modelsByPath[pathKey] = relatedModel
+
+		currentModel = relatedModel
+		currentAlias = relatedAlias
+	}
+
+	return currentModel, currentAlias, nil
+}
+
+// getUniqueAlias returns the table name as alias, suffixed with a counter if the table is already joined through another path
+func (j *entityViewJoins) getUniqueAlias(tableName string) string {
+	alias := tableName
+	for aliasIdx := 2; j.usedAliases[alias]; aliasIdx++ {
+		alias = fmt.Sprintf("%s_%d", tableName, aliasIdx)
+	}
+	j.usedAliases[alias] = true
+	return alias
+}
+
+// getRelationJoins returns the joins from a model to a related model and the alias of the related model.
+//
+// The join uses the foreign key column of whichever side owns the For relation, matching the columns generated
+// for the model tables: a ForOne relation stores the foreign key on the model itself, a HasOne / HasMany relation
+// on the related model's inverse ForOne relation. ForMany relations (on either side) join through their junction table.
+// Foreign key columns and junction tables are named after the relation owning them, which may be aliased.
+func (j *entityViewJoins) getRelationJoins(model yaml.Model, modelAlias string, relationName string, modelRelation yaml.ModelRelation, relatedModel yaml.Model) ([]psqldef.JoinClause, string, error) {
+	relationType := modelRelation.Type
+
+	primaryIdNames, primaryErr := getModelPrimaryIdentifierFieldNames(model)
+	if primaryErr != nil {
+		return nil, "", primaryErr
+	}
+	relatedPrimaryIdNames, relatedPrimaryErr := getModelPrimaryIdentifierFieldNames(relatedModel)
+	if relatedPrimaryErr != nil {
+		return nil, "", relatedPrimaryErr
+	}
+	primaryColumnNames := j.names.getColumnNamesFromFields(primaryIdNames)
+	relatedPrimaryColumnNames := j.names.getColumnNamesFromFields(relatedPrimaryIdNames)
+
+	relatedTableName := j.names.getTableNameFromModel(relatedModel.Name)
+
+	if yamlops.IsRelationFor(relationType) && yamlops.IsRelationOne(relationType) {
+		relatedAlias := j.getUniqueAlias(relatedTableName)
+		return []psqldef.JoinClause{
+			{
+				Type:  "LEFT",
+				Table: relatedTableName,
+				Alias: relatedAlias,
+				Conditions: getKeyJoinConditions(
+					modelAlias, j.names.getForeignKeyColumnNames(relationName, relatedPrimaryIdNames),
+					relatedAlias, relatedPrimaryColumnNames,
+				),
 			},
+		}, relatedAlias, nil
+	}
+
+	if yamlops.IsRelationFor(relationType) && yamlops.IsRelationMany(relationType) {
+		junctionTableName := j.names.getJunctionTableName(model.Name, relationName)
+		junctionJoins, relatedAlias := j.getJunctionJoins(junctionTableName,
+			modelAlias, primaryColumnNames, j.names.getForeignKeyColumnNames(model.Name, primaryIdNames),
+			relatedModel, relatedPrimaryColumnNames, j.names.getForeignKeyColumnNames(relationName, relatedPrimaryIdNames),
+		)
+		return junctionJoins, relatedAlias, nil
+	}
+
+	if !yamlops.IsRelationHas(relationType) {
+		return nil, "", fmt.Errorf("relationship %s of model %s has unsupported type '%s' for entity joins", relationName, model.Name, relationType)
+	}
+
+	inverseRelationName, inverseErr := j.getInverseRelationName(model, relatedModel)
+	if inverseErr != nil {
+		return nil, "", inverseErr
+	}
+
+	if yamlops.IsRelationMany(relatedModel.Related[inverseRelationName].Type) {
+		junctionTableName := j.names.getJunctionTableName(relatedModel.Name, inverseRelationName)
+		junctionJoins, relatedAlias := j.getJunctionJoins(junctionTableName,
+			modelAlias, primaryColumnNames, j.names.getForeignKeyColumnNames(inverseRelationName, primaryIdNames),
+			relatedModel, relatedPrimaryColumnNames, j.names.getForeignKeyColumnNames(relatedModel.Name, relatedPrimaryIdNames),
+		)
+		return junctionJoins, relatedAlias, nil
+	}
+
+	relatedAlias := j.getUniqueAlias(relatedTableName)
+	return []psqldef.JoinClause{
+		{
+			Type:  "LEFT",
+			Table: relatedTableName,
+			Alias: relatedAlias,
+			Conditions: getKeyJoinConditions(
+				modelAlias, primaryColumnNames,
+				relatedAlias, j.names.getForeignKeyColumnNames(inverseRelationName, primaryIdNames),
+			),
+		},
+	}, relatedAlias, nil
+}
+
+// getInverseRelationName returns the name of the related model's ForOne or ForMany relation to the model, which holds
+// the foreign key of a Has relation. A relation named after the model takes precedence over aliased relations.
+func (j *entityViewJoins) getInverseRelationName(model yaml.Model, relatedModel yaml.Model) (string, error) {
+	if inverseRelation, inverseExists := relatedModel.Related[model.Name]; inverseExists && yamlops.IsRelationFor(inverseRelation.Type) &&
+		j.config.GetRelatedModelName(relatedModel.Name, model.Name) == model.Name {
+		return model.Name, nil
+	}
+
+	inverseRelationNames := []string{}
+	for _, relationName := range core.MapKeysSorted(relatedModel.Related) {
+		relation := relatedModel.Related[relationName]
+		if !yamlops.IsRelationFor(relation.Type) || isPolymorphicRelation(relation.Type) {
+			continue
+		}
+		if j.config.GetRelatedModelName(relatedModel.Name, relationName) == model.Name {
+			inverseRelationNames = append(inverseRelationNames, relationName)
 		}
+	}
+	if len(inverseRelationNames) == 0 {
+		return "", ErrMissingMorpheInverseRelation(model.Name, relatedModel.Name)
+	}
+	if len(inverseRelationNames) > 1 {
+		return "", ErrAmbiguousMorpheInverseRelation(model.Name, relatedModel.Name, inverseRelationNames)
+	}
+	return inverseRelationNames[0], nil
+}
+
+// getJunctionJoins joins the junction table of a ForMany relation followed by the related model's table, returning the
+// joins and the alias of the related model
+func (j *entityViewJoins) getJunctionJoins(
+	junctionTableName string,
+	modelAlias string, modelColumnNames []string, junctionModelColumnNames []string,
+	relatedModel yaml.Model, relatedColumnNames []string, junctionRelatedColumnNames []string,
+) ([]psqldef.JoinClause, string) {
+	junctionAlias := j.getUniqueAlias(junctionTableName)
+	relatedTableName := j.names.getTableNameFromModel(relatedModel.Name)
+	relatedAlias := j.getUniqueAlias(relatedTableName)
+
+	return []psqldef.JoinClause{
+		{
+			Type:  "LEFT",
+			Table: junctionTableName,
+			Alias: junctionAlias,
+			Conditions: getKeyJoinConditions(
+				modelAlias, modelColumnNames,
+				junctionAlias, junctionModelColumnNames,
+			),
+		},
+		{
+			Type:  "LEFT",
+			Table: relatedTableName,
+			Alias: relatedAlias,
+			Conditions: getKeyJoinConditions(
+				junctionAlias, junctionRelatedColumnNames,
+				relatedAlias, relatedColumnNames,
+			),
+		},
+	}, relatedAlias
+}
 
-		view.Joins = append(view.Joins, joinClause)
+// getKeyJoinConditions pairs up the key columns of both sides of a join, several for composite keys
+func getKeyJoinConditions(leftAlias string, leftColumnNames []string, rightAlias string, rightColumnNames []string) []psqldef.JoinCondition {
+	conditions := make([]psqldef.JoinCondition, len(leftColumnNames))
+	for columnIdx, leftColumnName := range leftColumnNames {
+		conditions[columnIdx] = psqldef.JoinCondition{
+			LeftRef:  leftAlias + "." + leftColumnName,
+			RightRef: rightAlias + "." + rightColumnNames[columnIdx],
+		}
 	}
+	return conditions
+}
 
-	return view, nil
+// getEntityRootModelName returns the model all entity field paths start from (e.g. "Person" for "Person.ID")
+func getEntityRootModelName(entity yaml.Entity) (string, error) {
+	rootModelNames := map[string]bool{}
+	for _, field := range entity.Fields {
+		fieldParts := strings.Split(string(field.Type), ".")
+		if len(fieldParts) < 2 {
+			return "", fmt.Errorf("invalid field type format: %s", field.Type)
+		}
+		rootModelNames[fieldParts[0]] = true
+	}
+
+	if len(rootModelNames) == 0 {
+		return "", ErrNoMorpheEntityRootModel(entity.Name)
+	}
+	if len(rootModelNames) > 1 {
+		return "", ErrMixedMorpheEntityRootModels(entity.Name, core.MapKeysSorted(rootModelNames))
+	}
+
+	return core.MapKeysSorted(rootModelNames)[0], nil
 }
 
 func triggerCompileMorpheEntityStart(hooks hook.CompileMorpheEntity, config cfg.MorpheConfig, entity yaml.Entity) (cfg.MorpheConfig, yaml.Entity, error) {
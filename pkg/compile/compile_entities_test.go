@@ -229,7 +229,7 @@ func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView() {
 	suite.Len(join.Conditions, 1)
 	joinCondition0 := join.Conditions[0]
 	suite.Equal("users.uuid", joinCondition0.LeftRef)
-	suite.Equal("children.uuid", joinCondition0.RightRef)
+	suite.Equal("children.user_uuid", joinCondition0.RightRef)
 }
 
 func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_AlternativeSuffix() {
@@ -536,6 +536,1005 @@ func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_EnumField() {
 	suite.Equal(0, len(view.Joins))
 }
 
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_RootModelFromFieldPaths() {
+	config := suite.getCompileConfig()
+
+	r := registry.NewRegistry()
+
+	model0 := yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+			"LastName": {
+				Type: yaml.ModelFieldTypeString,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{},
+	}
+	r.SetModel("Person", model0)
+
+	entity0 := yaml.Entity{
+		Name: "Employee",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Person.ID",
+			},
+			"LastName": {
+				Type: "Person.LastName",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Employee", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(err)
+	suite.NotNil(view)
+	suite.Equal("employee_entities", view.Name)
+	suite.Equal("people", view.FromTable)
+
+	suite.Len(view.Columns, 2)
+
+	column0 := view.Columns[0]
+	suite.Equal(column0.Name, "id")
+	suite.Equal(column0.SourceRef, "people.id")
+
+	column1 := view.Columns[1]
+	suite.Equal(column1.Name, "last_name")
+	suite.Equal(column1.SourceRef, "people.last_name")
+
+	suite.Len(view.Joins, 0)
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_MixedRootModels() {
+	config := suite.getCompileConfig()
+
+	r := registry.NewRegistry()
+
+	model0 := yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{},
+	}
+	r.SetModel("Person", model0)
+
+	model1 := yaml.Model{
+		Name: "Company",
+		Fields: map[string]yaml.ModelField{
+			"Name": {
+				Type: yaml.ModelFieldTypeString,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"Name"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{},
+	}
+	r.SetModel("Company", model1)
+
+	entity0 := yaml.Entity{
+		Name: "Employee",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Person.ID",
+			},
+			"CompanyName": {
+				Type: "Company.Name",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Employee", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(view)
+	suite.ErrorContains(err, "entity Employee has fields with different root models: Company, Person")
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_MultiHopRelations() {
+	config := suite.getCompileConfig()
+
+	r := registry.NewRegistry()
+
+	model0 := yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Company": {
+				Type: "ForOne",
+			},
+			"Address": {
+				Type: "ForOne",
+			},
+		},
+	}
+	r.SetModel("Person", model0)
+
+	model1 := yaml.Model{
+		Name: "Company",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+			"Name": {
+				Type: yaml.ModelFieldTypeString,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Person": {
+				Type: "HasMany",
+			},
+			"Address": {
+				Type: "ForOne",
+			},
+		},
+	}
+	r.SetModel("Company", model1)
+
+	model2 := yaml.Model{
+		Name: "Address",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+			"City": {
+				Type: yaml.ModelFieldTypeString,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Person": {
+				Type: "HasOne",
+			},
+			"Company": {
+				Type: "HasOne",
+			},
+		},
+	}
+	r.SetModel("Address", model2)
+
+	entity0 := yaml.Entity{
+		Name: "Person",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Person.ID",
+			},
+			"CompanyName": {
+				Type: "Person.Company.Name",
+			},
+			"City": {
+				Type: "Person.Company.Address.City",
+			},
+			"HomeCity": {
+				Type: "Person.Address.City",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Person", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(err)
+	suite.NotNil(view)
+	suite.Equal("people", view.FromTable)
+
+	suite.Equal([]psqldef.ViewColumn{
+		{Name: "city", SourceRef: "addresses.city"},
+		{Name: "company_name", SourceRef: "companies.name"},
+		{Name: "home_city", SourceRef: "addresses_2.city"},
+		{Name: "id", SourceRef: "people.id"},
+	}, view.Columns)
+
+	suite.Equal([]psqldef.JoinClause{
+		{
+			Type:  "LEFT",
+			Table: "companies",
+			Alias: "companies",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "people.company_id", RightRef: "companies.id"},
+			},
+		},
+		{
+			Type:  "LEFT",
+			Table: "addresses",
+			Alias: "addresses",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "companies.address_id", RightRef: "addresses.id"},
+			},
+		},
+		{
+			Type:  "LEFT",
+			Table: "addresses",
+			Alias: "addresses_2",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "people.address_id", RightRef: "addresses_2.id"},
+			},
+		},
+	}, view.Joins)
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_HasManyRelation() {
+	config := suite.getCompileConfig()
+
+	r := registry.NewRegistry()
+
+	model0 := yaml.Model{
+		Name: "Company",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Person": {
+				Type: "HasMany",
+			},
+		},
+	}
+	r.SetModel("Company", model0)
+
+	model1 := yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+			"LastName": {
+				Type: yaml.ModelFieldTypeString,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Company": {
+				Type: "ForOne",
+			},
+		},
+	}
+	r.SetModel("Person", model1)
+
+	entity0 := yaml.Entity{
+		Name: "Company",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Company.ID",
+			},
+			"EmployeeLastName": {
+				Type: "Company.Person.LastName",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Company", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(err)
+	suite.NotNil(view)
+	suite.Len(view.Joins, 1)
+	suite.Equal([]psqldef.JoinCondition{
+		{LeftRef: "companies.id", RightRef: "people.company_id"},
+	}, view.Joins[0].Conditions)
+}
+
+func (suite *CompileEntitiesTestSuite) getManyToManyRegistry() *registry.Registry {
+	r := registry.NewRegistry()
+
+	model0 := yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Tag": {
+				Type: "ForMany",
+			},
+		},
+	}
+	r.SetModel("Person", model0)
+
+	model1 := yaml.Model{
+		Name: "Tag",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+			"Label": {
+				Type: yaml.ModelFieldTypeString,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Person": {
+				Type: "HasMany",
+			},
+		},
+	}
+	r.SetModel("Tag", model1)
+
+	return r
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_ForManyRelation() {
+	config := suite.getCompileConfig()
+	r := suite.getManyToManyRegistry()
+
+	entity0 := yaml.Entity{
+		Name: "Person",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Person.ID",
+			},
+			"TagLabel": {
+				Type: "Person.Tag.Label",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Person", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(err)
+	suite.NotNil(view)
+	suite.Equal([]psqldef.ViewColumn{
+		{Name: "id", SourceRef: "people.id"},
+		{Name: "tag_label", SourceRef: "tags.label"},
+	}, view.Columns)
+	suite.Equal([]psqldef.JoinClause{
+		{
+			Type:  "LEFT",
+			Table: "person_tags",
+			Alias: "person_tags",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "people.id", RightRef: "person_tags.person_id"},
+			},
+		},
+		{
+			Type:  "LEFT",
+			Table: "tags",
+			Alias: "tags",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "person_tags.tag_id", RightRef: "tags.id"},
+			},
+		},
+	}, view.Joins)
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_ForOneRelation_CompositePrimaryKey() {
+	config := suite.getCompileConfig()
+	r := registry.NewRegistry()
+
+	model0 := yaml.Model{
+		Name: "Invoice",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Membership": {
+				Type: "ForOne",
+			},
+		},
+	}
+	r.SetModel("Invoice", model0)
+
+	model1 := yaml.Model{
+		Name: "Membership",
+		Fields: map[string]yaml.ModelField{
+			"TenantID": {
+				Type: yaml.ModelFieldTypeInteger,
+			},
+			"UserID": {
+				Type: yaml.ModelFieldTypeUUID,
+			},
+			"Role": {
+				Type: yaml.ModelFieldTypeString,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"TenantID", "UserID"},
+			},
+		},
+	}
+	r.SetModel("Membership", model1)
+
+	entity0 := yaml.Entity{
+		Name: "Invoice",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Invoice.ID",
+			},
+			"MembershipRole": {
+				Type: "Invoice.Membership.Role",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Invoice", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(err)
+	suite.NotNil(view)
+	suite.Equal([]psqldef.JoinClause{
+		{
+			Type:  "LEFT",
+			Table: "memberships",
+			Alias: "memberships",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "invoices.membership_tenant_id", RightRef: "memberships.tenant_id"},
+				{LeftRef: "invoices.membership_user_id", RightRef: "memberships.user_id"},
+			},
+		},
+	}, view.Joins)
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_HasManyRelation_InverseForMany() {
+	config := suite.getCompileConfig()
+	r := suite.getManyToManyRegistry()
+
+	entity0 := yaml.Entity{
+		Name: "Tag",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Tag.ID",
+			},
+			"PersonID": {
+				Type: "Tag.Person.ID",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Tag", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(err)
+	suite.NotNil(view)
+	suite.Equal([]psqldef.JoinClause{
+		{
+			Type:  "LEFT",
+			Table: "person_tags",
+			Alias: "person_tags",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "tags.id", RightRef: "person_tags.tag_id"},
+			},
+		},
+		{
+			Type:  "LEFT",
+			Table: "people",
+			Alias: "people",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "person_tags.person_id", RightRef: "people.id"},
+			},
+		},
+	}, view.Joins)
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_HasOneRelation_NoInverseRelation() {
+	config := suite.getCompileConfig()
+
+	r := registry.NewRegistry()
+
+	model0 := yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"ContactInfo": {
+				Type: "HasOne",
+			},
+		},
+	}
+	r.SetModel("Person", model0)
+
+	model1 := yaml.Model{
+		Name: "ContactInfo",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+			"Email": {
+				Type: yaml.ModelFieldTypeString,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{},
+	}
+	r.SetModel("ContactInfo", model1)
+
+	entity0 := yaml.Entity{
+		Name: "Person",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Person.ID",
+			},
+			"Email": {
+				Type: "Person.ContactInfo.Email",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Person", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(view)
+	suite.ErrorContains(err, "model ContactInfo has no ForOne or ForMany relation to Person")
+}
+
+// getAliasedRelationsRegistry returns people owning contact infos and holding tags as interests, both through
+// relations not named after their related model
+func (suite *CompileEntitiesTestSuite) getAliasedRelationsRegistry() *registry.Registry {
+	r := registry.NewRegistry()
+
+	r.SetModel("Person", yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"ContactInfo": {
+				Type: "HasOne",
+			},
+			"Interest": {
+				Type: "ForMany",
+			},
+		},
+	})
+	r.SetModel("ContactInfo", yaml.Model{
+		Name: "ContactInfo",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+			"Email": {
+				Type: yaml.ModelFieldTypeString,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Owner": {
+				Type: "ForOne",
+			},
+		},
+	})
+	r.SetModel("Tag", yaml.Model{
+		Name: "Tag",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Person": {
+				Type: "HasMany",
+			},
+		},
+	})
+	return r
+}
+
+func (suite *CompileEntitiesTestSuite) getAliasedRelationsCompileConfig() compile.MorpheCompileConfig {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.RelationAliases = map[string]map[string]string{
+		"ContactInfo": {
+			"Owner": "Person",
+		},
+		"Person": {
+			"Interest": "Tag",
+		},
+	}
+	return config
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_HasOneRelation_AliasedInverseRelation() {
+	config := suite.getAliasedRelationsCompileConfig()
+	r := suite.getAliasedRelationsRegistry()
+
+	entity0 := yaml.Entity{
+		Name: "Person",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Person.ID",
+			},
+			"Email": {
+				Type: "Person.ContactInfo.Email",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Person", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(err)
+	suite.NotNil(view)
+	suite.Equal([]psqldef.JoinClause{
+		{
+			Type:  "LEFT",
+			Table: "contact_infos",
+			Alias: "contact_infos",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "people.id", RightRef: "contact_infos.owner_id"},
+			},
+		},
+	}, view.Joins)
+
+	contactInfoTables, tablesErr := compile.MorpheModelToPSQLTables(config, r, suite.mustGetModel(r, "ContactInfo"))
+	suite.Nil(tablesErr)
+	columnNames := []string{}
+	for _, column := range contactInfoTables[0].Columns {
+		columnNames = append(columnNames, column.Name)
+	}
+	suite.Contains(columnNames, "owner_id")
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_AliasedForManyRelation() {
+	config := suite.getAliasedRelationsCompileConfig()
+	r := suite.getAliasedRelationsRegistry()
+
+	entity0 := yaml.Entity{
+		Name: "Person",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Person.ID",
+			},
+			"InterestID": {
+				Type: "Person.Interest.ID",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Person", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(err)
+	suite.NotNil(view)
+	suite.Equal([]psqldef.JoinClause{
+		{
+			Type:  "LEFT",
+			Table: "person_interests",
+			Alias: "person_interests",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "people.id", RightRef: "person_interests.person_id"},
+			},
+		},
+		{
+			Type:  "LEFT",
+			Table: "tags",
+			Alias: "tags",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "person_interests.interest_id", RightRef: "tags.id"},
+			},
+		},
+	}, view.Joins)
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_HasManyRelation_AliasedInverseForMany() {
+	config := suite.getAliasedRelationsCompileConfig()
+	r := suite.getAliasedRelationsRegistry()
+
+	entity0 := yaml.Entity{
+		Name: "Tag",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Tag.ID",
+			},
+			"PersonID": {
+				Type: "Tag.Person.ID",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Tag", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(err)
+	suite.NotNil(view)
+	suite.Equal([]psqldef.JoinClause{
+		{
+			Type:  "LEFT",
+			Table: "person_interests",
+			Alias: "person_interests",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "tags.id", RightRef: "person_interests.interest_id"},
+			},
+		},
+		{
+			Type:  "LEFT",
+			Table: "people",
+			Alias: "people",
+			Conditions: []psqldef.JoinCondition{
+				{LeftRef: "person_interests.person_id", RightRef: "people.id"},
+			},
+		},
+	}, view.Joins)
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_HasOneRelation_AmbiguousInverseRelation() {
+	config := suite.getAliasedRelationsCompileConfig()
+	config.MorpheModelsConfig.RelationAliases["ContactInfo"]["Reviewer"] = "Person"
+	r := suite.getAliasedRelationsRegistry()
+	contactInfo := suite.mustGetModel(r, "ContactInfo")
+	contactInfo.Related["Reviewer"] = yaml.ModelRelation{
+		Type: "ForOne",
+	}
+	r.SetModel("ContactInfo", contactInfo)
+
+	entity0 := yaml.Entity{
+		Name: "Person",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Person.ID",
+			},
+			"Email": {
+				Type: "Person.ContactInfo.Email",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+	r.SetEntity("Person", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(view)
+	suite.ErrorContains(err, "model ContactInfo has several ForOne or ForMany relations to Person, cannot tell which one is the inverse relation: Owner, Reviewer")
+}
+
+func (suite *CompileEntitiesTestSuite) mustGetModel(r *registry.Registry, modelName string) yaml.Model {
+	model, modelErr := r.GetModel(modelName)
+	suite.Require().Nil(modelErr)
+	return model
+}
+
+func (suite *CompileEntitiesTestSuite) getTagEntity() yaml.Entity {
+	return yaml.Entity{
+		Name: "Tag",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Tag.ID",
+			},
+			"Label": {
+				Type: "Tag.Label",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.EntityRelation{},
+	}
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_MaterializedView() {
+	config := suite.getCompileConfig()
+	config.MorpheEntitiesConfig.MaterializedViews = map[string]cfg.MaterializedViewConfig{
+		"Tag": {
+			WithNoData: true,
+		},
+	}
+	r := suite.getManyToManyRegistry()
+
+	entity0 := suite.getTagEntity()
+	r.SetEntity("Tag", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(err)
+	suite.NotNil(view)
+	suite.True(view.Materialized)
+	suite.True(view.WithNoData)
+	suite.Equal("fn_refresh_tag_entities", view.RefreshFunctionName)
+	suite.Equal([]psqldef.Index{
+		{
+			Schema:    "public",
+			Name:      "uidx_tag_entities_id",
+			TableName: "tag_entities",
+			Columns:   []string{"id"},
+			IsUnique:  true,
+		},
+	}, view.Indices)
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_MaterializedView_UniqueIndexFields() {
+	config := suite.getCompileConfig()
+	config.MorpheEntitiesConfig.MaterializedViews = map[string]cfg.MaterializedViewConfig{
+		"Tag": {
+			UniqueIndexFields: []string{"Label"},
+		},
+	}
+	r := suite.getManyToManyRegistry()
+
+	entity0 := suite.getTagEntity()
+	r.SetEntity("Tag", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(err)
+	suite.NotNil(view)
+	suite.True(view.Materialized)
+	suite.False(view.WithNoData)
+	suite.Len(view.Indices, 1)
+	suite.Equal("uidx_tag_entities_label", view.Indices[0].Name)
+	suite.Equal([]string{"label"}, view.Indices[0].Columns)
+}
+
+func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_MaterializedView_MissingUniqueIndexField() {
+	config := suite.getCompileConfig()
+	config.MorpheEntitiesConfig.MaterializedViews = map[string]cfg.MaterializedViewConfig{
+		"Tag": {
+			UniqueIndexFields: []string{"Color"},
+		},
+	}
+	r := suite.getManyToManyRegistry()
+
+	entity0 := suite.getTagEntity()
+	r.SetEntity("Tag", entity0)
+
+	view, err := compile.MorpheEntityToPSQLView(config, r, entity0)
+
+	suite.Nil(view)
+	suite.ErrorContains(err, "missing entity field Color in entity Tag")
+}
+
 func (suite *CompileEntitiesTestSuite) TestMorpheEntityToPSQLView_NoSchema() {
 	r := registry.NewRegistry()
 
@@ -4,3 +4,6 @@ import "errors"
 
 var ErrNoEnumTables = errors.New("no enum tables provided")
 var ErrNoEnumTable = errors.New("no enum table provided")
+var ErrNoEnumTypes = errors.New("no enum types provided")
+var ErrNoEnumType = errors.New("no enum type provided")
+var ErrNoEnumTypeWriter = errors.New("enum type writer must be provided when the native enum strategy is used")
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/kalo-build/go-util/core"
-	"github.com/kalo-build/go-util/strcase"
 	"github.com/kalo-build/morphe-go/pkg/registry"
 	"github.com/kalo-build/morphe-go/pkg/yaml"
 	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
@@ -31,7 +30,7 @@ func MorpheEnumToPSQLTable(config MorpheCompileConfig, enum yaml.Enum) (*psqldef
 		return nil, triggerCompileMorpheEnumFailure(config.EnumHooks, config.MorpheEnumsConfig, enum, enumStartErr)
 	}
 
-	table, createPSQLTableForEnumErr := createPSQLTableForEnum(enumsConfig, enum)
+	table, createPSQLTableForEnumErr := createPSQLTableForEnum(enumsConfig, config.getIdentifierNames(), enum)
 	if createPSQLTableForEnumErr != nil {
 		return nil, triggerCompileMorpheEnumFailure(config.EnumHooks, enumsConfig, enum, createPSQLTableForEnumErr)
 	}
@@ -44,8 +43,64 @@ func MorpheEnumToPSQLTable(config MorpheCompileConfig, enum yaml.Enum) (*psqldef
 	return table, nil
 }
 
+func AllMorpheEnumsToPSQLTypes(config MorpheCompileConfig, r *registry.Registry) (map[string]*psqldef.PSQLTypeEnum, error) {
+	allEnumTypeDefs := map[string]*psqldef.PSQLTypeEnum{}
+	for enumName, enum := range r.GetAllEnums() {
+		enumType, enumErr := MorpheEnumToPSQLType(config, enum)
+		if enumErr != nil {
+			return nil, enumErr
+		}
+		allEnumTypeDefs[enumName] = enumType
+	}
+	return allEnumTypeDefs, nil
+}
+
+// MorpheEnumToPSQLType converts a Morphe enum to a native PostgreSQL enum type
+func MorpheEnumToPSQLType(config MorpheCompileConfig, enum yaml.Enum) (*psqldef.PSQLTypeEnum, error) {
+	enumsConfig, enum, enumStartErr := triggerCompileMorpheEnumStart(config.EnumHooks, config.MorpheEnumsConfig, enum)
+	if enumStartErr != nil {
+		return nil, triggerCompileMorpheEnumFailure(config.EnumHooks, config.MorpheEnumsConfig, enum, enumStartErr)
+	}
+
+	enumType, createPSQLTypeForEnumErr := createPSQLTypeForEnum(enumsConfig, config.getIdentifierNames(), enum)
+	if createPSQLTypeForEnumErr != nil {
+		return nil, triggerCompileMorpheEnumFailure(config.EnumHooks, enumsConfig, enum, createPSQLTypeForEnumErr)
+	}
+
+	enumType, enumSuccessErr := triggerCompileMorpheEnumTypeSuccess(config.EnumHooks, enumType)
+	if enumSuccessErr != nil {
+		return nil, triggerCompileMorpheEnumFailure(config.EnumHooks, enumsConfig, enum, enumSuccessErr)
+	}
+
+	return enumType, nil
+}
+
+// createPSQLTypeForEnum creates a native PostgreSQL enum type for a Morphe enum
+func createPSQLTypeForEnum(config cfg.MorpheEnumsConfig, names identifierNames, enum yaml.Enum) (*psqldef.PSQLTypeEnum, error) {
+	validateConfigErr := config.Validate()
+	if validateConfigErr != nil {
+		return nil, validateConfigErr
+	}
+	validateMorpheErr := enum.Validate()
+	if validateMorpheErr != nil {
+		return nil, validateMorpheErr
+	}
+
+	enumType := getPSQLTypeForEnum(config.Schema, names, enum)
+	return &enumType, nil
+}
+
+// getPSQLTypeForEnum returns the native enum type for a Morphe enum, using the entry names as enum labels
+func getPSQLTypeForEnum(schema string, names identifierNames, enum yaml.Enum) psqldef.PSQLTypeEnum {
+	return psqldef.PSQLTypeEnum{
+		Schema: schema,
+		Name:   names.getEnumTypeNameFromEnum(enum.Name),
+		Values: core.MapKeysSorted(enum.Entries),
+	}
+}
+
 // createPSQLTableForEnum creates a PostgreSQL table with seed data for a Morphe enum
-func createPSQLTableForEnum(config cfg.MorpheEnumsConfig, enum yaml.Enum) (*psqldef.Table, error) {
+func createPSQLTableForEnum(config cfg.MorpheEnumsConfig, names identifierNames, enum yaml.Enum) (*psqldef.Table, error) {
 	validateConfigErr := config.Validate()
 	if validateConfigErr != nil {
 		return nil, validateConfigErr
@@ -55,8 +110,7 @@ func createPSQLTableForEnum(config cfg.MorpheEnumsConfig, enum yaml.Enum) (*psql
 		return nil, validateMorpheErr
 	}
 
-	tableName := strcase.ToSnakeCaseLower(enum.Name)
-	tableName = Pluralize(tableName)
+	tableName := names.getTableNameFromEnum(enum.Name)
 
 	serialType := psqldef.PSQLTypeSerial
 	if config.UseBigSerial {
@@ -110,7 +164,7 @@ func createPSQLTableForEnum(config cfg.MorpheEnumsConfig, enum yaml.Enum) (*psql
 		},
 		UniqueConstraints: []psqldef.UniqueConstraint{
 			{
-				Name:        "uk_" + tableName + "_key",
+				Name:        names.getUniqueConstraintName(tableName, "key"),
 				TableName:   tableName,
 				ColumnNames: []string{"key"},
 			},
@@ -146,6 +200,25 @@ func triggerCompileMorpheEnumSuccess(hooks hook.CompileMorpheEnum, table *psqlde
 	return updatedTable, nil
 }
 
+// triggerCompileMorpheEnumTypeSuccess triggers the success hook for native enum type compilation
+func triggerCompileMorpheEnumTypeSuccess(hooks hook.CompileMorpheEnum, enumType *psqldef.PSQLTypeEnum) (*psqldef.PSQLTypeEnum, error) {
+	if hooks.OnCompileMorpheEnumTypeSuccess == nil {
+		return enumType, nil
+	}
+	if enumType == nil {
+		return nil, ErrNoEnumType
+	}
+
+	enumTypeClone := enumType.DeepClone()
+
+	updatedEnumType, err := hooks.OnCompileMorpheEnumTypeSuccess(&enumTypeClone)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedEnumType, nil
+}
+
 // triggerCompileMorpheEnumFailure triggers the failure hook for enum compilation
 func triggerCompileMorpheEnumFailure(hooks hook.CompileMorpheEnum, config cfg.MorpheEnumsConfig, enum yaml.Enum, failureErr error) error {
 	if hooks.OnCompileMorpheEnumFailure == nil {
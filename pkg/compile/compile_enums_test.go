@@ -648,3 +648,116 @@ func (suite *CompileEnumsTestSuite) TestMorpheEnumToPSQLTable_FailureHook() {
 	suite.ErrorContains(enumErr, "compile enum failure hook error")
 	suite.Nil(lookupTable)
 }
+
+func (suite *CompileEnumsTestSuite) TestMorpheEnumToPSQLType_String() {
+	config := suite.getMorpheConfig()
+	config.MorpheEnumsConfig.Strategy = cfg.EnumStrategyNativeEnum
+
+	enum0 := yaml.Enum{
+		Name: "UserRole",
+		Type: yaml.EnumTypeString,
+		Entries: map[string]any{
+			"Viewer": "VIEWER",
+			"Admin":  "ADMIN",
+			"Editor": "EDITOR",
+		},
+	}
+
+	enumType, enumErr := compile.MorpheEnumToPSQLType(config, enum0)
+
+	suite.Nil(enumErr)
+	suite.NotNil(enumType)
+
+	suite.Equal(config.MorpheEnumsConfig.Schema, enumType.Schema)
+	suite.Equal("user_role", enumType.Name)
+	suite.Equal("public.user_role", enumType.GetSyntax())
+	suite.Equal([]string{"Admin", "Editor", "Viewer"}, enumType.Values)
+}
+
+func (suite *CompileEnumsTestSuite) TestMorpheEnumToPSQLType_NoEntries() {
+	config := suite.getMorpheConfig()
+	config.MorpheEnumsConfig.Strategy = cfg.EnumStrategyNativeEnum
+
+	enum0 := yaml.Enum{
+		Name:    "UserRole",
+		Type:    yaml.EnumTypeString,
+		Entries: map[string]any{},
+	}
+
+	enumType, enumErr := compile.MorpheEnumToPSQLType(config, enum0)
+
+	suite.ErrorIs(enumErr, yaml.ErrNoMorpheEnumEntries)
+	suite.Nil(enumType)
+}
+
+func (suite *CompileEnumsTestSuite) TestMorpheEnumToPSQLType_UnknownStrategy() {
+	config := suite.getMorpheConfig()
+	config.MorpheEnumsConfig.Strategy = "unknown"
+
+	enum0 := yaml.Enum{
+		Name: "UserRole",
+		Type: yaml.EnumTypeString,
+		Entries: map[string]any{
+			"Admin": "ADMIN",
+		},
+	}
+
+	enumType, enumErr := compile.MorpheEnumToPSQLType(config, enum0)
+
+	suite.ErrorContains(enumErr, "unknown enum strategy 'unknown'")
+	suite.Nil(enumType)
+}
+
+func (suite *CompileEnumsTestSuite) TestMorpheEnumToPSQLType_SuccessHook_Successful() {
+	enumHooks := hook.CompileMorpheEnum{
+		OnCompileMorpheEnumTypeSuccess: func(enumType *psqldef.PSQLTypeEnum) (*psqldef.PSQLTypeEnum, error) {
+			enumType.Name = enumType.Name + "_changed"
+			enumType.Values = append(enumType.Values, "Guest")
+			return enumType, nil
+		},
+	}
+
+	config := suite.getMorpheConfig()
+	config.MorpheEnumsConfig.Strategy = cfg.EnumStrategyNativeEnum
+	config.EnumHooks = enumHooks
+
+	enum0 := yaml.Enum{
+		Name: "UserRole",
+		Type: yaml.EnumTypeString,
+		Entries: map[string]any{
+			"Admin": "ADMIN",
+		},
+	}
+
+	enumType, enumErr := compile.MorpheEnumToPSQLType(config, enum0)
+
+	suite.Nil(enumErr)
+	suite.NotNil(enumType)
+	suite.Equal("user_role_changed", enumType.Name)
+	suite.Equal([]string{"Admin", "Guest"}, enumType.Values)
+}
+
+func (suite *CompileEnumsTestSuite) TestMorpheEnumToPSQLType_SuccessHook_Failure() {
+	enumHooks := hook.CompileMorpheEnum{
+		OnCompileMorpheEnumTypeSuccess: func(enumType *psqldef.PSQLTypeEnum) (*psqldef.PSQLTypeEnum, error) {
+			return enumType, fmt.Errorf("compile enum type success hook error")
+		},
+	}
+
+	config := suite.getMorpheConfig()
+	config.MorpheEnumsConfig.Strategy = cfg.EnumStrategyNativeEnum
+	config.EnumHooks = enumHooks
+
+	enum0 := yaml.Enum{
+		Name: "UserRole",
+		Type: yaml.EnumTypeString,
+		Entries: map[string]any{
+			"Admin": "ADMIN",
+		},
+	}
+
+	enumType, enumErr := compile.MorpheEnumToPSQLType(config, enum0)
+
+	suite.ErrorContains(enumErr, "compile enum type success hook error")
+	suite.Nil(enumType)
+}
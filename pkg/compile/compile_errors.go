@@ -3,11 +3,17 @@ package compile
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/kalo-build/morphe-go/pkg/yaml"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
 )
 
 var ErrNoRegistry = errors.New("registry not initialized")
+var ErrNoPSQLType = errors.New("no psql type provided")
+var ErrNoPSQLTable = errors.New("no psql table provided")
+var ErrNoPSQLView = errors.New("no psql view provided")
+var ErrNoTypeWriter = errors.New("no psql type writer provided for custom types referenced by tables")
 
 func ErrUnsupportedMorpheFieldType[TType yaml.ModelFieldType | yaml.StructureFieldType](unsupportedType TType) error {
 	return fmt.Errorf("unsupported morphe field type for go conversion: '%s'", unsupportedType)
@@ -16,3 +22,46 @@ func ErrUnsupportedMorpheFieldType[TType yaml.ModelFieldType | yaml.StructureFie
 func ErrMissingMorpheIdentifierField(modelName string, identifierName string, fieldName string) error {
 	return fmt.Errorf("morphe model '%s' has no field '%s' referenced in identifiers ('%s')", modelName, identifierName, fieldName)
 }
+
+func ErrUnsupportedPSQLTypeDefinition(psqlType psqldef.PSQLType) error {
+	return fmt.Errorf("unsupported psql type definition for '%s'", psqlType.GetSyntax())
+}
+
+func ErrViewDependencyCycle(viewNames []string) error {
+	return fmt.Errorf("views depend on each other in a cycle: %s", strings.Join(viewNames, ", "))
+}
+
+func ErrCustomTypeDependencyCycle(typeNames []string) error {
+	return fmt.Errorf("custom types depend on each other in a cycle: %s", strings.Join(typeNames, ", "))
+}
+
+func ErrAmbiguousJunctionColumns(modelName string, relationName string) error {
+	return fmt.Errorf("ForMany relation '%s' of model '%s' needs a relation alias to tell its junction columns apart", relationName, modelName)
+}
+
+func ErrStructureIDColumnClash(structureName string) error {
+	return fmt.Errorf("a field of structure '%s' compiles to the generated id column, an ID field must be AutoIncrement or UUID to become the primary key", structureName)
+}
+
+func ErrUnsupportedPolymorphicRelation(modelName string, relationName string, relationType string) error {
+	return fmt.Errorf("polymorphic relation '%s' of model '%s' has unsupported type '%s'", relationName, modelName, relationType)
+}
+
+func ErrPolymorphicRelationKeyMismatch(modelName string, relationName string) error {
+	return fmt.Errorf("target models of polymorphic relation '%s' of model '%s' must have single field primary identifiers of the same type", relationName, modelName)
+}
+
+func ErrCompileConfigFile(filePath string, line int, err error) error {
+	if line > 0 {
+		return fmt.Errorf("%s:%d: %w", filePath, line, err)
+	}
+	return fmt.Errorf("%s: %w", filePath, err)
+}
+
+func ErrIdentifierCollisions(collisions []IdentifierCollision) error {
+	descriptions := make([]string, len(collisions))
+	for collisionIdx, collision := range collisions {
+		descriptions[collisionIdx] = collision.String()
+	}
+	return fmt.Errorf("compiled definitions share identifiers:\n\t%s", strings.Join(descriptions, "\n\t"))
+}
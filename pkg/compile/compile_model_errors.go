@@ -6,5 +6,7 @@ var ErrNoModelTables = errors.New("no model tables provided")
 var ErrNoModelTable = errors.New("no model table provided")
 var ErrNoStructureTable = errors.New("no structure table provided")
 var ErrNoStructureWriter = errors.New("structure writer must be provided when structure persistence is enabled")
+var ErrNoStructureType = errors.New("no structure type provided")
+var ErrNoStructureTypeWriter = errors.New("structure type writer must be provided when structures compile to composite types")
 var ErrNoEntityViews = errors.New("no entity views provided")
 var ErrNoEntityView = errors.New("no entity view provided")
@@ -7,7 +7,6 @@ import (
 
 	"github.com/kalo-build/clone"
 	"github.com/kalo-build/go-util/core"
-	"github.com/kalo-build/go-util/strcase"
 	"github.com/kalo-build/morphe-go/pkg/registry"
 	"github.com/kalo-build/morphe-go/pkg/yaml"
 	"github.com/kalo-build/morphe-go/pkg/yamlops"
@@ -36,7 +35,7 @@ func MorpheModelToPSQLTables(config MorpheCompileConfig, r *registry.Registry, m
 	}
 	config.MorpheConfig = morpheConfig
 
-	allModelTables, tablesErr := morpheModelToPSQLTables(config.MorpheConfig, r, model)
+	allModelTables, tablesErr := morpheModelToPSQLTables(config.MorpheConfig, config.getIdentifierNames(), r, model)
 	if tablesErr != nil {
 		return nil, triggerCompileMorpheModelFailure(config.ModelHooks, morpheConfig, model, tablesErr)
 	}
@@ -48,19 +47,19 @@ func MorpheModelToPSQLTables(config MorpheCompileConfig, r *registry.Registry, m
 	return allModelTables, nil
 }
 
-func morpheModelToPSQLTables(config cfg.MorpheConfig, r *registry.Registry, model yaml.Model) ([]*psqldef.Table, error) {
+func morpheModelToPSQLTables(config cfg.MorpheConfig, names identifierNames, r *registry.Registry, model yaml.Model) ([]*psqldef.Table, error) {
 	validateConfigErr := config.Validate()
 	if validateConfigErr != nil {
 		return nil, validateConfigErr
 	}
-	validateMorpheErr := model.Validate(r.GetAllEnums())
+	validateMorpheErr := validateMorpheModel(config, r, model)
 	if validateMorpheErr != nil {
 		return nil, validateMorpheErr
 	}
 
 	schema := config.MorpheModelsConfig.Schema
 	modelName := model.Name
-	tableName := GetTableNameFromModel(modelName)
+	tableName := names.getTableNameFromModel(modelName)
 
 	var typeMap map[yaml.ModelFieldType]psqldef.PSQLType
 	var relatedTypeMap map[yaml.ModelFieldType]psqldef.PSQLType
@@ -78,12 +77,12 @@ func morpheModelToPSQLTables(config cfg.MorpheConfig, r *registry.Registry, mode
 		return nil, fmt.Errorf("no primary identifier set for model '%s'", model.Name)
 	}
 
-	fieldColumns, enumForeignKeys, fieldColumnsErr := getColumnsForModelFields(config, r, typeMap, tableName, primaryID, model.Fields)
+	fieldColumns, enumForeignKeys, immutableColumnNames, fieldColumnsErr := getColumnsForModelFields(config, names, r, typeMap, tableName, primaryID, model.Fields)
 	if fieldColumnsErr != nil {
 		return nil, fieldColumnsErr
 	}
 
-	relatedColumns, relatedColumnsErr := getColumnsForModelRelations(r, relatedTypeMap, model.Related)
+	relatedColumns, relatedColumnsErr := getColumnsForModelRelations(config.MorpheModelsConfig, names, modelName, r, relatedTypeMap, model.Related)
 	if relatedColumnsErr != nil {
 		return nil, relatedColumnsErr
 	}
@@ -95,23 +94,30 @@ func morpheModelToPSQLTables(config cfg.MorpheConfig, r *registry.Registry, mode
 		ForeignKeys:       enumForeignKeys,
 		Indices:           []psqldef.Index{},
 		UniqueConstraints: []psqldef.UniqueConstraint{},
+		Triggers:          getTriggersForImmutableColumns(names, schema, tableName, immutableColumnNames),
 	}
 
-	relationForeignKeys, foreignKeysErr := getForeignKeysForModelRelations(schema, tableName, r, model.Related)
+	relationForeignKeys, foreignKeysErr := getForeignKeysForModelRelations(config.MorpheModelsConfig, names, modelName, tableName, r, model.Related)
 	if foreignKeysErr != nil {
 		return nil, foreignKeysErr
 	}
 	modelTable.ForeignKeys = append(modelTable.ForeignKeys, relationForeignKeys...)
 
-	indices := getIndicesForForeignKeys(tableName, modelTable.ForeignKeys)
+	indices := getIndicesForForeignKeys(names, tableName, modelTable.ForeignKeys)
 	modelTable.Indices = indices
+	allowNullForSetNullForeignKeys(&modelTable)
+
+	polymorphicErr := addPolymorphicRelationsToTable(&modelTable, config.MorpheModelsConfig, names, r, relatedTypeMap, model)
+	if polymorphicErr != nil {
+		return nil, polymorphicErr
+	}
 
 	// Apply spec-compliant processing to the model table
-	addUniqueIndicesFromIdentifiers(&modelTable, model.Identifiers)
+	addUniqueIndicesFromIdentifiers(&modelTable, names, model.Identifiers)
 	quoteReservedColumnNames(&modelTable)
-	ensureNamedForeignKeyConstraints(&modelTable)
+	ensureNamedForeignKeyConstraints(&modelTable, names)
 
-	junctionTables, junctionTablesErr := getJunctionTablesForForManyRelations(schema, r, model)
+	junctionTables, junctionTablesErr := getJunctionTablesForForManyRelations(config.MorpheModelsConfig, names, r, typeMap, relatedTypeMap, model)
 	if junctionTablesErr != nil {
 		return nil, junctionTablesErr
 	}
@@ -119,7 +125,7 @@ func morpheModelToPSQLTables(config cfg.MorpheConfig, r *registry.Registry, mode
 	// Process junction tables as well
 	for tableIdx := range junctionTables {
 		quoteReservedColumnNames(junctionTables[tableIdx])
-		ensureNamedForeignKeyConstraints(junctionTables[tableIdx])
+		ensureNamedForeignKeyConstraints(junctionTables[tableIdx], names)
 	}
 
 	tables := []*psqldef.Table{&modelTable}
@@ -130,166 +136,294 @@ func morpheModelToPSQLTables(config cfg.MorpheConfig, r *registry.Registry, mode
 	return tables, nil
 }
 
-func getColumnsForModelFields(config cfg.MorpheConfig, r *registry.Registry, typeMap map[yaml.ModelFieldType]psqldef.PSQLType, tableName string, primaryID yaml.ModelIdentifier, modelFields map[string]yaml.ModelField) ([]psqldef.TableColumn, []psqldef.ForeignKey, error) {
+// validateMorpheModel validates the model against the registry's enums
+func validateMorpheModel(config cfg.MorpheConfig, r *registry.Registry, model yaml.Model) error {
+	return getMorpheModelForValidation(config, r, model).Validate(r.GetAllEnums())
+}
+
+// getMorpheModelForValidation returns the model as Morphe validates it. Morphe only knows enums as non-primitive
+// field types, so fields typed by a structure are validated as strings when structures compile to composite types.
+func getMorpheModelForValidation(config cfg.MorpheConfig, r *registry.Registry, model yaml.Model) yaml.Model {
+	if !config.MorpheStructuresConfig.IsCompositeType() {
+		return model
+	}
+
+	validatedModel := model.DeepClone()
+	for fieldName, field := range validatedModel.Fields {
+		if yaml.IsModelFieldTypePrimitive(field.Type) {
+			continue
+		}
+		if _, structureErr := r.GetStructure(string(field.Type)); structureErr == nil {
+			field.Type = yaml.ModelFieldTypeString
+			validatedModel.Fields[fieldName] = field
+		}
+	}
+	return validatedModel
+}
+
+func getColumnsForModelFields(config cfg.MorpheConfig, names identifierNames, r *registry.Registry, typeMap map[yaml.ModelFieldType]psqldef.PSQLType, tableName string, primaryID yaml.ModelIdentifier, modelFields map[string]yaml.ModelField) ([]psqldef.TableColumn, []psqldef.ForeignKey, []string, error) {
 	columns := []psqldef.TableColumn{}
 	enumForeignKeys := []psqldef.ForeignKey{}
+	immutableColumnNames := []string{}
 
 	modelFieldNames := core.MapKeysSorted(modelFields)
 	for _, fieldName := range modelFieldNames {
 		field := modelFields[fieldName]
-		columnName := GetColumnNameFromField(fieldName)
+		columnName := names.getColumnNameFromField(fieldName)
+		isPrimaryKey := slices.Index(primaryID.Fields, fieldName) != -1
 
 		columnType, supported := typeMap[field.Type]
 		if supported {
 			column := psqldef.TableColumn{
 				Name:       columnName,
 				Type:       columnType,
-				NotNull:    false,
-				PrimaryKey: slices.Index(primaryID.Fields, fieldName) != -1,
+				NotNull:    isMandatoryModelField(field) && !isPrimaryKey,
+				PrimaryKey: isPrimaryKey,
 				Default:    "",
 			}
 			columns = append(columns, column)
+			if isImmutableModelField(field) {
+				immutableColumnNames = append(immutableColumnNames, columnName)
+			}
 			continue
 		}
 
+		if config.MorpheStructuresConfig.IsCompositeType() {
+			structure, structureErr := r.GetStructure(string(field.Type))
+			if structureErr == nil {
+				structureType, structureTypeErr := getPSQLTypeForStructure(config, names, r, structure)
+				if structureTypeErr != nil {
+					return nil, nil, nil, structureTypeErr
+				}
+				column := psqldef.TableColumn{
+					Name:       columnName,
+					Type:       structureType,
+					NotNull:    isMandatoryModelField(field) && !isPrimaryKey,
+					PrimaryKey: isPrimaryKey,
+					Default:    "",
+				}
+				columns = append(columns, column)
+				if isImmutableModelField(field) {
+					immutableColumnNames = append(immutableColumnNames, columnName)
+				}
+				continue
+			}
+		}
+
 		enumType, enumErr := r.GetEnum(string(field.Type))
 		if enumErr != nil {
-			return nil, nil, fmt.Errorf("morphe model field '%s' has unsupported type '%s'", fieldName, field.Type)
+			return nil, nil, nil, fmt.Errorf("morphe model field '%s' has unsupported type '%s'", fieldName, field.Type)
+		}
+
+		if config.MorpheEnumsConfig.IsNativeEnum() {
+			column := psqldef.TableColumn{
+				Name:       columnName,
+				Type:       getPSQLTypeForEnum(config.MorpheEnumsConfig.Schema, names, enumType),
+				NotNull:    true,
+				PrimaryKey: isPrimaryKey,
+				Default:    "",
+			}
+			columns = append(columns, column)
+			if isImmutableModelField(field) {
+				immutableColumnNames = append(immutableColumnNames, columnName)
+			}
+			continue
 		}
 
 		columnName = columnName + "_id"
-		enumTableName := Pluralize(strcase.ToSnakeCaseLower(enumType.Name))
+		enumTableName := names.getTableNameFromEnum(enumType.Name)
 
 		foreignKey := psqldef.ForeignKey{
 			Schema:         config.MorpheModelsConfig.Schema,
-			Name:           GetForeignKeyConstraintName(tableName, columnName),
+			Name:           names.getForeignKeyConstraintName(tableName, columnName),
 			TableName:      tableName,
 			ColumnNames:    []string{columnName},
+			RefSchema:      config.MorpheEnumsConfig.Schema,
 			RefTableName:   enumTableName,
 			RefColumnNames: []string{"id"},
-			OnDelete:       "CASCADE",
-			OnUpdate:       "",
 		}
-		enumForeignKeys = append(enumForeignKeys, foreignKey)
+		enumForeignKeys = append(enumForeignKeys, withForeignKeyConfig(foreignKey, config.MorpheEnumsConfig.ForeignKeys))
 
 		column := psqldef.TableColumn{
 			Name:       columnName,
 			Type:       psqldef.PSQLTypeInteger,
 			NotNull:    true,
-			PrimaryKey: slices.Index(primaryID.Fields, fieldName) != -1,
+			PrimaryKey: isPrimaryKey,
 			Default:    "",
 		}
 		columns = append(columns, column)
+		if isImmutableModelField(field) {
+			immutableColumnNames = append(immutableColumnNames, columnName)
+		}
 	}
 
-	return columns, enumForeignKeys, nil
+	return columns, enumForeignKeys, immutableColumnNames, nil
 }
 
-func getColumnsForModelRelations(r *registry.Registry, typeMap map[yaml.ModelFieldType]psqldef.PSQLType, relatedModels map[string]yaml.ModelRelation) ([]psqldef.TableColumn, error) {
+// getTriggersForImmutableColumns creates a trigger rejecting updates that change any of the immutable columns
+func getTriggersForImmutableColumns(names identifierNames, schema string, tableName string, immutableColumnNames []string) []psqldef.Trigger {
+	if len(immutableColumnNames) == 0 {
+		return []psqldef.Trigger{}
+	}
+
+	functionBody := []string{}
+	for _, columnName := range immutableColumnNames {
+		unquotedColumnName := strings.Trim(columnName, "\"")
+		quotedColumnName := fmt.Sprintf("\"%s\"", unquotedColumnName)
+		functionBody = append(functionBody,
+			fmt.Sprintf("IF NEW.%s IS DISTINCT FROM OLD.%s THEN", quotedColumnName, quotedColumnName),
+			fmt.Sprintf("\tRAISE EXCEPTION 'column %s of table %s is immutable';", unquotedColumnName, tableName),
+			"END IF;",
+		)
+	}
+	functionBody = append(functionBody, "RETURN NEW;")
+
+	return []psqldef.Trigger{
+		{
+			Schema:       schema,
+			Name:         names.getImmutableTriggerName(tableName),
+			TableName:    tableName,
+			Timing:       "BEFORE",
+			Events:       []string{"UPDATE"},
+			FunctionName: names.getImmutableTriggerFunctionName(tableName),
+			FunctionBody: functionBody,
+		},
+	}
+}
+
+// getColumnsForModelRelations returns the foreign key columns of a model's ForOne relations, named after the relation
+func getColumnsForModelRelations(config cfg.MorpheModelsConfig, names identifierNames, modelName string, r *registry.Registry, typeMap map[yaml.ModelFieldType]psqldef.PSQLType, relatedModels map[string]yaml.ModelRelation) ([]psqldef.TableColumn, error) {
 	columns := []psqldef.TableColumn{}
 
-	relatedModelNames := core.MapKeysSorted(relatedModels)
-	for _, relatedModelName := range relatedModelNames {
-		modelRelation := relatedModels[relatedModelName]
+	relationNames := core.MapKeysSorted(relatedModels)
+	for _, relationName := range relationNames {
+		modelRelation := relatedModels[relationName]
 		relationType := modelRelation.Type
-		relatedModel, modelErr := r.GetModel(relatedModelName)
+		if isPolymorphicRelation(relationType) {
+			continue
+		}
+		relatedModel, modelErr := r.GetModel(config.GetRelatedModelName(modelName, relationName))
 		if modelErr != nil {
 			return nil, modelErr
 		}
-		primaryID, hasPrimary := relatedModel.Identifiers["primary"]
-		if !hasPrimary {
-			return nil, fmt.Errorf("related model %s has no primary identifier", relatedModelName)
-		}
-
-		if len(primaryID.Fields) != 1 {
-			return nil, fmt.Errorf("related entity %s primary identifier must have exactly one field", relatedModelName)
-		}
-
-		targetPrimaryIdName := primaryID.Fields[0]
-		targetPrimaryIdField, primaryFieldExists := relatedModel.Fields[targetPrimaryIdName]
-		if !primaryFieldExists {
-			return nil, fmt.Errorf("related entity %s primary identifier field %s not found", relatedModelName, targetPrimaryIdName)
+		targetPrimaryIdNames, primaryErr := getModelPrimaryIdentifierFieldNames(relatedModel)
+		if primaryErr != nil {
+			return nil, primaryErr
 		}
 
 		if yamlops.IsRelationFor(relationType) && yamlops.IsRelationOne(relationType) {
-			columnName := GetForeignKeyColumnName(relatedModelName, targetPrimaryIdName)
-
-			columnType, supported := typeMap[targetPrimaryIdField.Type]
-			if !supported {
-				return nil, fmt.Errorf("morphe related model field '%s' has unsupported type '%s'", targetPrimaryIdName, targetPrimaryIdField.Type)
+			relationColumns, relationColumnsErr := getForeignColumnsForModelKey(names, typeMap, relationName, relatedModel, targetPrimaryIdNames)
+			if relationColumnsErr != nil {
+				return nil, relationColumnsErr
 			}
-
-			column := psqldef.TableColumn{
-				Name:       columnName,
-				Type:       columnType,
-				NotNull:    true,
-				PrimaryKey: false,
-				Default:    "",
+			for columnIdx := range relationColumns {
+				relationColumns[columnIdx].NotNull = true
 			}
-
-			columns = append(columns, column)
+			columns = append(columns, relationColumns...)
 		}
 	}
 
 	return columns, nil
 }
 
-func getForeignKeysForModelRelations(schema string, tableName string, r *registry.Registry, relatedModels map[string]yaml.ModelRelation) ([]psqldef.ForeignKey, error) {
+// getModelPrimaryIdentifierFieldNames returns the fields of a model's primary identifier, several for composite keys
+func getModelPrimaryIdentifierFieldNames(model yaml.Model) ([]string, error) {
+	primaryID, hasPrimary := model.Identifiers["primary"]
+	if !hasPrimary || len(primaryID.Fields) == 0 {
+		return nil, fmt.Errorf("model %s has no primary identifier", model.Name)
+	}
+	return primaryID.Fields, nil
+}
+
+// getForeignColumnsForModelKey returns the columns referencing the given key fields of a model, one per field, named
+// after the relation (or the model itself for junction table source columns)
+func getForeignColumnsForModelKey(names identifierNames, relatedTypeMap map[yaml.ModelFieldType]psqldef.PSQLType, relationName string, model yaml.Model, keyFieldNames []string) ([]psqldef.TableColumn, error) {
+	columns := []psqldef.TableColumn{}
+	for _, keyFieldName := range keyFieldNames {
+		columnType, columnTypeErr := getForeignColumnTypeForModelField(relatedTypeMap, model, keyFieldName)
+		if columnTypeErr != nil {
+			return nil, columnTypeErr
+		}
+		columns = append(columns, psqldef.TableColumn{
+			Name: names.getForeignKeyColumnName(relationName, keyFieldName),
+			Type: columnType,
+		})
+	}
+	return columns, nil
+}
+
+// getForeignColumnTypeForModelField returns the type of a column referencing the given field of a related model
+func getForeignColumnTypeForModelField(relatedTypeMap map[yaml.ModelFieldType]psqldef.PSQLType, model yaml.Model, fieldName string) (psqldef.PSQLType, error) {
+	field, fieldExists := model.Fields[fieldName]
+	if !fieldExists {
+		return nil, fmt.Errorf("model %s primary identifier field %s not found", model.Name, fieldName)
+	}
+	columnType, supported := relatedTypeMap[field.Type]
+	if !supported {
+		return nil, fmt.Errorf("morphe related model field '%s' has unsupported type '%s'", fieldName, field.Type)
+	}
+	return columnType, nil
+}
+
+func getForeignKeysForModelRelations(config cfg.MorpheModelsConfig, names identifierNames, modelName string, tableName string, r *registry.Registry, relatedModels map[string]yaml.ModelRelation) ([]psqldef.ForeignKey, error) {
+	schema := config.Schema
 	foreignKeys := []psqldef.ForeignKey{}
 
-	relatedModelNames := core.MapKeysSorted(relatedModels)
-	for _, relatedModelName := range relatedModelNames {
-		modelRelation := relatedModels[relatedModelName]
+	relationNames := core.MapKeysSorted(relatedModels)
+	for _, relationName := range relationNames {
+		modelRelation := relatedModels[relationName]
 		relationType := modelRelation.Type
+		if isPolymorphicRelation(relationType) {
+			continue
+		}
+		relatedModelName := config.GetRelatedModelName(modelName, relationName)
 		relatedModel, modelErr := r.GetModel(relatedModelName)
 		if modelErr != nil {
 			return nil, modelErr
 		}
-		primaryID, hasPrimary := relatedModel.Identifiers["primary"]
-		if !hasPrimary {
-			return nil, fmt.Errorf("related model %s has no primary identifier", relatedModelName)
-		}
-
-		if len(primaryID.Fields) != 1 {
-			return nil, fmt.Errorf("related entity %s primary identifier must have exactly one field", relatedModelName)
-		}
-
-		targetPrimaryIdName := primaryID.Fields[0]
-		_, primaryFieldExists := relatedModel.Fields[targetPrimaryIdName]
-		if !primaryFieldExists {
-			return nil, fmt.Errorf("related entity %s primary identifier field %s not found", relatedModelName, targetPrimaryIdName)
+		targetPrimaryIdNames, primaryErr := getModelPrimaryIdentifierFieldNames(relatedModel)
+		if primaryErr != nil {
+			return nil, primaryErr
 		}
 
 		if yamlops.IsRelationFor(relationType) && yamlops.IsRelationOne(relationType) {
-			columnName := GetForeignKeyColumnName(relatedModelName, targetPrimaryIdName)
-			refTableName := GetTableNameFromModel(relatedModelName)
-			refColumnName := GetColumnNameFromField(targetPrimaryIdName)
+			columnNames := names.getForeignKeyColumnNames(relationName, targetPrimaryIdNames)
 
 			foreignKey := psqldef.ForeignKey{
 				Schema:         schema,
-				Name:           GetForeignKeyConstraintName(tableName, columnName),
+				Name:           names.getForeignKeyConstraintName(tableName, strings.Join(columnNames, "_")),
 				TableName:      tableName,
-				ColumnNames:    []string{columnName},
-				RefTableName:   refTableName,
-				RefColumnNames: []string{refColumnName},
-				OnDelete:       "CASCADE",
-				OnUpdate:       "",
+				ColumnNames:    columnNames,
+				RefSchema:      schema,
+				RefTableName:   names.getTableNameFromModel(relatedModelName),
+				RefColumnNames: names.getColumnNamesFromFields(targetPrimaryIdNames),
 			}
 
-			foreignKeys = append(foreignKeys, foreignKey)
+			foreignKeys = append(foreignKeys, withForeignKeyConfig(foreignKey, config.GetRelationForeignKeyConfig(modelName, relationName)))
 		}
 	}
 
 	return foreignKeys, nil
 }
 
-func getIndicesForForeignKeys(tableName string, foreignKeys []psqldef.ForeignKey) []psqldef.Index {
+func getIndicesForForeignKeys(names identifierNames, tableName string, foreignKeys []psqldef.ForeignKey) []psqldef.Index {
 	indices := []psqldef.Index{}
 
 	for _, fk := range foreignKeys {
+		// Composite foreign keys are looked up by all of their columns together
+		if len(fk.ColumnNames) > 1 {
+			indices = append(indices, psqldef.Index{
+				Name:      names.getIndexName(tableName, strings.Join(fk.ColumnNames, "_")),
+				TableName: tableName,
+				Columns:   slices.Clone(fk.ColumnNames),
+				IsUnique:  false,
+			})
+			continue
+		}
+
 		for _, columnName := range fk.ColumnNames {
 			index := psqldef.Index{
-				Name:      GetIndexName(tableName, columnName),
+				Name:      names.getIndexName(tableName, columnName),
 				TableName: tableName,
 				Columns:   []string{columnName},
 				IsUnique:  false,
@@ -339,111 +473,112 @@ func triggerCompileMorpheModelFailure(hooks hook.CompileMorpheModel, morpheConfi
 	return hooks.OnCompileMorpheModelFailure(morpheConfig, model.DeepClone(), failureErr)
 }
 
-// getJunctionTablesForForManyRelations creates junction tables for ForMany relationships
-func getJunctionTablesForForManyRelations(schema string, r *registry.Registry, model yaml.Model) ([]*psqldef.Table, error) {
+// getJunctionTablesForForManyRelations creates junction tables for ForMany relationships, typing the key columns
+// like the primary keys they reference
+func getJunctionTablesForForManyRelations(config cfg.MorpheModelsConfig, names identifierNames, r *registry.Registry, typeMap map[yaml.ModelFieldType]psqldef.PSQLType, relatedTypeMap map[yaml.ModelFieldType]psqldef.PSQLType, model yaml.Model) ([]*psqldef.Table, error) {
+	schema := config.Schema
 	junctionTables := []*psqldef.Table{}
 	modelName := model.Name
-	tableName := GetTableNameFromModel(modelName)
+	tableName := names.getTableNameFromModel(modelName)
 
-	// Get primary ID field for this model
-	primaryID, hasPrimary := model.Identifiers["primary"]
-	if !hasPrimary {
-		return nil, fmt.Errorf("model %s has no primary identifier", modelName)
+	// Get primary ID fields for this model
+	primaryIdNames, primaryErr := getModelPrimaryIdentifierFieldNames(model)
+	if primaryErr != nil {
+		return nil, primaryErr
 	}
-	if len(primaryID.Fields) != 1 {
-		return nil, fmt.Errorf("model %s primary identifier must have exactly one field", modelName)
+	sourceColumns, sourceColumnsErr := getForeignColumnsForModelKey(names, relatedTypeMap, modelName, model, primaryIdNames)
+	if sourceColumnsErr != nil {
+		return nil, sourceColumnsErr
 	}
-	primaryIdName := primaryID.Fields[0]
 
-	relatedModelNames := core.MapKeysSorted(model.Related)
-	for _, relatedModelName := range relatedModelNames {
-		modelRelation := model.Related[relatedModelName]
+	relationNames := core.MapKeysSorted(model.Related)
+	for _, relationName := range relationNames {
+		modelRelation := model.Related[relationName]
 		relationType := modelRelation.Type
 
-		if yamlops.IsRelationFor(relationType) && yamlops.IsRelationMany(relationType) {
+		if yamlops.IsRelationFor(relationType) && yamlops.IsRelationMany(relationType) && !isPolymorphicRelation(relationType) {
+			relatedModelName := config.GetRelatedModelName(modelName, relationName)
 			relatedModel, modelErr := r.GetModel(relatedModelName)
 			if modelErr != nil {
 				return nil, modelErr
 			}
 
-			// Get primary ID field for related model
-			relatedPrimaryID, hasRelatedPrimary := relatedModel.Identifiers["primary"]
-			if !hasRelatedPrimary {
-				return nil, fmt.Errorf("related model %s has no primary identifier", relatedModelName)
+			// Get primary ID fields for related model
+			relatedPrimaryIdNames, relatedPrimaryErr := getModelPrimaryIdentifierFieldNames(relatedModel)
+			if relatedPrimaryErr != nil {
+				return nil, relatedPrimaryErr
 			}
-			if len(relatedPrimaryID.Fields) != 1 {
-				return nil, fmt.Errorf("related model %s primary identifier must have exactly one field", relatedModelName)
+			targetColumns, targetColumnsErr := getForeignColumnsForModelKey(names, relatedTypeMap, relationName, relatedModel, relatedPrimaryIdNames)
+			if targetColumnsErr != nil {
+				return nil, targetColumnsErr
 			}
-			relatedPrimaryIdName := relatedPrimaryID.Fields[0]
 
 			// Create junction table
-			junctionTableName := GetJunctionTableName(modelName, relatedModelName)
+			junctionTableName := names.getJunctionTableName(modelName, relationName)
+			// Composite keys are named after all of their fields, e.g. "TenantIDUserID" as tenant_id_user_id
+			primaryIdName := strings.Join(primaryIdNames, "")
+			relatedPrimaryIdName := strings.Join(relatedPrimaryIdNames, "")
 
 			// Create column names
-			sourceColumnName := GetForeignKeyColumnName(modelName, primaryIdName)
-			targetColumnName := GetForeignKeyColumnName(relatedModelName, relatedPrimaryIdName)
+			sourceColumnNames := names.getForeignKeyColumnNames(modelName, primaryIdNames)
+			targetColumnNames := names.getForeignKeyColumnNames(relationName, relatedPrimaryIdNames)
+			if slices.ContainsFunc(targetColumnNames, func(columnName string) bool { return slices.Contains(sourceColumnNames, columnName) }) {
+				return nil, ErrAmbiguousJunctionColumns(modelName, relationName)
+			}
 
-			// Create columns
-			columns := []psqldef.TableColumn{
-				{
+			// Create columns, keyed either by a surrogate id or by the (source, target) columns
+			columns := []psqldef.TableColumn{}
+			if !config.UseCompositeJunctionKeys {
+				columns = append(columns, psqldef.TableColumn{
 					Name:       "id",
-					Type:       psqldef.PSQLTypeSerial,
+					Type:       typeMap[yaml.ModelFieldTypeAutoIncrement],
 					PrimaryKey: true,
-				},
-				{
-					Name: sourceColumnName,
-					Type: psqldef.PSQLTypeInteger,
-				},
-				{
-					Name: targetColumnName,
-					Type: psqldef.PSQLTypeInteger,
-				},
+				})
+			}
+			for _, keyColumn := range append(slices.Clone(sourceColumns), targetColumns...) {
+				keyColumn.PrimaryKey = config.UseCompositeJunctionKeys
+				columns = append(columns, keyColumn)
 			}
 
 			// Create foreign keys
+			foreignKeyConfig := config.GetRelationForeignKeyConfig(modelName, relationName)
 			foreignKeys := []psqldef.ForeignKey{
-				{
-					Schema:       schema,
-					Name:         GetJunctionTableForeignKeyConstraintName(junctionTableName, modelName, primaryIdName),
-					TableName:    junctionTableName,
-					ColumnNames:  []string{sourceColumnName},
-					RefTableName: tableName,
-					RefColumnNames: []string{
-						GetColumnNameFromField(primaryIdName),
-					},
-					OnDelete: "CASCADE",
-				},
-				{
-					Schema:       schema,
-					Name:         GetJunctionTableForeignKeyConstraintName(junctionTableName, relatedModelName, relatedPrimaryIdName),
-					TableName:    junctionTableName,
-					ColumnNames:  []string{targetColumnName},
-					RefTableName: GetTableNameFromModel(relatedModelName),
-					RefColumnNames: []string{
-						GetColumnNameFromField(relatedPrimaryIdName),
-					},
-					OnDelete: "CASCADE",
-				},
+				withForeignKeyConfig(psqldef.ForeignKey{
+					Schema:         schema,
+					Name:           names.getJunctionTableForeignKeyConstraintName(junctionTableName, modelName, primaryIdName),
+					TableName:      junctionTableName,
+					ColumnNames:    sourceColumnNames,
+					RefSchema:      schema,
+					RefTableName:   tableName,
+					RefColumnNames: names.getColumnNamesFromFields(primaryIdNames),
+				}, foreignKeyConfig),
+				withForeignKeyConfig(psqldef.ForeignKey{
+					Schema:         schema,
+					Name:           names.getJunctionTableForeignKeyConstraintName(junctionTableName, relationName, relatedPrimaryIdName),
+					TableName:      junctionTableName,
+					ColumnNames:    targetColumnNames,
+					RefSchema:      schema,
+					RefTableName:   names.getTableNameFromModel(relatedModelName),
+					RefColumnNames: names.getColumnNamesFromFields(relatedPrimaryIdNames),
+				}, foreignKeyConfig),
 			}
 
-			// Create unique constraint
-			uniqueConstraints := []psqldef.UniqueConstraint{
-				{
-					Name: GetJunctionTableUniqueConstraintName(
+			// Create unique constraint, which the composite primary key already enforces
+			uniqueConstraints := []psqldef.UniqueConstraint{}
+			if !config.UseCompositeJunctionKeys {
+				uniqueConstraints = append(uniqueConstraints, psqldef.UniqueConstraint{
+					Name: names.getJunctionTableUniqueConstraintName(
 						junctionTableName,
 						modelName, primaryIdName,
-						relatedModelName, relatedPrimaryIdName,
+						relationName, relatedPrimaryIdName,
 					),
-					TableName: junctionTableName,
-					ColumnNames: []string{
-						sourceColumnName,
-						targetColumnName,
-					},
-				},
+					TableName:   junctionTableName,
+					ColumnNames: append(slices.Clone(sourceColumnNames), targetColumnNames...),
+				})
 			}
 
 			// Create indices for foreign keys
-			indices := getIndicesForForeignKeys(junctionTableName, foreignKeys)
+			indices := getIndicesForForeignKeys(names, junctionTableName, foreignKeys)
 
 			// Create junction table
 			junctionTable := &psqldef.Table{
@@ -463,7 +598,7 @@ func getJunctionTablesForForManyRelations(schema string, r *registry.Registry, m
 }
 
 // addUniqueIndicesFromIdentifiers adds unique indices for model identifiers
-func addUniqueIndicesFromIdentifiers(table *psqldef.Table, identifiers map[string]yaml.ModelIdentifier) {
+func addUniqueIndicesFromIdentifiers(table *psqldef.Table, names identifierNames, identifiers map[string]yaml.ModelIdentifier) {
 	tableName := table.Name
 
 	// Add unique indices for identifiers
@@ -471,20 +606,10 @@ func addUniqueIndicesFromIdentifiers(table *psqldef.Table, identifiers map[strin
 		if idName == "primary" {
 			continue
 		}
-		columnNames := make([]string, len(identifier.Fields))
-		for fieldIdx, field := range identifier.Fields {
-			columnNames[fieldIdx] = GetColumnNameFromField(field)
-		}
-
-		var indexName string
-		if len(columnNames) == 1 {
-			indexName = fmt.Sprintf("idx_%s_%s", tableName, columnNames[0])
-		} else {
-			indexName = fmt.Sprintf("idx_%s_%s", tableName, strings.Join(columnNames, "_"))
-		}
+		columnNames := names.getColumnNamesFromFields(identifier.Fields)
 
 		table.Indices = append(table.Indices, psqldef.Index{
-			Name:      indexName,
+			Name:      names.getIndexName(tableName, strings.Join(columnNames, "_")),
 			TableName: tableName,
 			Columns:   columnNames,
 			IsUnique:  true,
@@ -542,18 +667,36 @@ func quoteReservedColumnNames(table *psqldef.Table) {
 	}
 }
 
-// ensureNamedForeignKeyConstraints ensures all foreign keys have proper names and CASCADE behavior
-func ensureNamedForeignKeyConstraints(table *psqldef.Table) {
+// ensureNamedForeignKeyConstraints ensures all foreign keys have proper names
+func ensureNamedForeignKeyConstraints(table *psqldef.Table, names identifierNames) {
 	for fkIdx, fk := range table.ForeignKeys {
 		if fk.Name == "" {
-			fk.Name = GetForeignKeyConstraintName(table.Name, fk.ColumnNames[0])
+			fk.Name = names.getForeignKeyConstraintName(table.Name, strings.Join(fk.ColumnNames, "_"))
 			table.ForeignKeys[fkIdx] = fk
 		}
+	}
+}
 
-		// Ensure CASCADE behavior as per spec
-		if fk.OnDelete == "" {
-			fk.OnDelete = "CASCADE"
-			table.ForeignKeys[fkIdx] = fk
+// withForeignKeyConfig returns the foreign key with the configured referential actions
+func withForeignKeyConfig(foreignKey psqldef.ForeignKey, config cfg.ForeignKeyConfig) psqldef.ForeignKey {
+	foreignKey.OnDelete = string(config.GetOnDelete())
+	foreignKey.OnUpdate = string(config.OnUpdate)
+	foreignKey.Deferrable = config.Deferrable
+	foreignKey.InitiallyDeferred = config.Deferrable
+	return foreignKey
+}
+
+// allowNullForSetNullForeignKeys drops NOT NULL from the columns of foreign keys which set them to null
+func allowNullForSetNullForeignKeys(table *psqldef.Table) {
+	setNullAction := string(cfg.ReferentialActionSetNull)
+	for _, foreignKey := range table.ForeignKeys {
+		if foreignKey.OnDelete != setNullAction && foreignKey.OnUpdate != setNullAction {
+			continue
+		}
+		for columnIdx, column := range table.Columns {
+			if slices.Contains(foreignKey.ColumnNames, column.Name) {
+				table.Columns[columnIdx].NotNull = false
+			}
 		}
 	}
 }
@@ -482,6 +482,7 @@ func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForOne(
 	suite.Len(foreignKey0.ColumnNames, 1)
 	fkColumn00 := foreignKey0.ColumnNames[0]
 	suite.Equal("basic_parent_id", fkColumn00)
+	suite.Equal("public", foreignKey0.RefSchema)
 	suite.Equal("basic_parents", foreignKey0.RefTableName)
 	suite.Len(foreignKey0.RefColumnNames, 1)
 	fkColumnRef00 := foreignKey0.RefColumnNames[0]
@@ -500,6 +501,144 @@ func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForOne(
 	suite.Len(table0.UniqueConstraints, 0)
 }
 
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForOne_ForeignKeyConfig() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.ForeignKeys = cfg.ForeignKeyConfig{
+		OnDelete: cfg.ReferentialActionRestrict,
+	}
+	config.MorpheModelsConfig.RelationForeignKeys = map[string]map[string]cfg.ForeignKeyConfig{
+		"Basic": {
+			"BasicOwner": {
+				OnDelete:   cfg.ReferentialActionSetNull,
+				OnUpdate:   cfg.ReferentialActionCascade,
+				Deferrable: true,
+			},
+		},
+	}
+
+	model0 := yaml.Model{
+		Name: "Basic",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"BasicOwner": {
+				Type: "ForOne",
+			},
+			"BasicParent": {
+				Type: "ForOne",
+			},
+		},
+	}
+	model1 := yaml.Model{
+		Name: "BasicOwner",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+	}
+	model2 := yaml.Model{
+		Name: "BasicParent",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Basic", model0)
+	r.SetModel("BasicOwner", model1)
+	r.SetModel("BasicParent", model2)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+
+	table0 := allTables[0]
+
+	columns0 := table0.Columns
+	suite.Len(columns0, 3)
+
+	columns01 := columns0[1]
+	suite.Equal("basic_owner_id", columns01.Name)
+	suite.False(columns01.NotNull)
+
+	columns02 := columns0[2]
+	suite.Equal("basic_parent_id", columns02.Name)
+	suite.True(columns02.NotNull)
+
+	suite.Len(table0.ForeignKeys, 2)
+
+	foreignKey0 := table0.ForeignKeys[0]
+	suite.Equal("fk_basics_basic_owner_id", foreignKey0.Name)
+	suite.Equal("SET NULL", foreignKey0.OnDelete)
+	suite.Equal("CASCADE", foreignKey0.OnUpdate)
+	suite.True(foreignKey0.Deferrable)
+	suite.True(foreignKey0.InitiallyDeferred)
+
+	foreignKey1 := table0.ForeignKeys[1]
+	suite.Equal("fk_basics_basic_parent_id", foreignKey1.Name)
+	suite.Equal("RESTRICT", foreignKey1.OnDelete)
+	suite.Equal("", foreignKey1.OnUpdate)
+	suite.False(foreignKey1.Deferrable)
+	suite.False(foreignKey1.InitiallyDeferred)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_UnknownReferentialAction() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.ForeignKeys = cfg.ForeignKeyConfig{
+		OnDelete: "DROP",
+	}
+
+	model0 := yaml.Model{
+		Name: "Basic",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Basic", model0)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTables)
+	suite.EqualError(allTablesErr, "unknown referential action 'DROP'")
+}
+
 func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForMany_HasOne() {
 	config := suite.getCompileConfig()
 
@@ -818,20 +957,1079 @@ func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForMany
 	suite.Equal("basic_id", index10.Columns[0])
 	suite.False(index10.IsUnique)
 
-	index11 := table1.Indices[1]
-	suite.Equal("idx_basic_basic_parents_basic_parent_id", index11.Name)
-	suite.Equal("basic_basic_parents", index11.TableName)
-	suite.Len(index11.Columns, 1)
-	suite.Equal("basic_parent_id", index11.Columns[0])
-	suite.False(index11.IsUnique)
+	index11 := table1.Indices[1]
+	suite.Equal("idx_basic_basic_parents_basic_parent_id", index11.Name)
+	suite.Equal("basic_basic_parents", index11.TableName)
+	suite.Len(index11.Columns, 1)
+	suite.Equal("basic_parent_id", index11.Columns[0])
+	suite.False(index11.IsUnique)
+
+	suite.Len(table1.UniqueConstraints, 1)
+	uniqueConstraint10 := table1.UniqueConstraints[0]
+	suite.Equal("uk_basic_basic_parents_basic_id_basic_parent_id", uniqueConstraint10.Name)
+	suite.Equal("basic_basic_parents", uniqueConstraint10.TableName)
+	suite.Len(uniqueConstraint10.ColumnNames, 2)
+	suite.Equal("basic_id", uniqueConstraint10.ColumnNames[0])
+	suite.Equal("basic_parent_id", uniqueConstraint10.ColumnNames[1])
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForMany_JunctionKeyTypes() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.UseBigSerial = true
+
+	model0 := yaml.Model{
+		Name: "Basic",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"BasicParent": {
+				Type: "ForMany",
+			},
+		},
+	}
+	model1 := yaml.Model{
+		Name: "BasicParent",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeUUID,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Basic": {
+				Type: "HasMany",
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Basic", model0)
+	r.SetModel("BasicParent", model1)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 2)
+
+	// Junction table basics <-> basic_parents
+	table1 := allTables[1]
+	suite.Equal("basic_basic_parents", table1.Name)
+
+	columns1 := table1.Columns
+	suite.Len(columns1, 3)
+
+	columns10 := columns1[0]
+	suite.Equal("id", columns10.Name)
+	suite.Equal(psqldef.PSQLTypeBigSerial, columns10.Type)
+	suite.True(columns10.PrimaryKey)
+
+	columns11 := columns1[1]
+	suite.Equal("basic_id", columns11.Name)
+	suite.Equal(psqldef.PSQLTypeBigInt, columns11.Type)
+	suite.False(columns11.PrimaryKey)
+
+	columns12 := columns1[2]
+	suite.Equal("basic_parent_id", columns12.Name)
+	suite.Equal(psqldef.PSQLTypeUUID, columns12.Type)
+	suite.False(columns12.PrimaryKey)
+
+	suite.Len(table1.UniqueConstraints, 1)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForMany_CompositeJunctionKeys() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.UseCompositeJunctionKeys = true
+
+	model0 := yaml.Model{
+		Name: "Basic",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"BasicParent": {
+				Type: "ForMany",
+			},
+		},
+	}
+	model1 := yaml.Model{
+		Name: "BasicParent",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeUUID,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Basic": {
+				Type: "HasMany",
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Basic", model0)
+	r.SetModel("BasicParent", model1)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 2)
+
+	// Junction table basics <-> basic_parents
+	table1 := allTables[1]
+	suite.Equal("basic_basic_parents", table1.Name)
+
+	columns1 := table1.Columns
+	suite.Len(columns1, 2)
+
+	columns10 := columns1[0]
+	suite.Equal("basic_id", columns10.Name)
+	suite.Equal(psqldef.PSQLTypeInteger, columns10.Type)
+	suite.True(columns10.PrimaryKey)
+
+	columns11 := columns1[1]
+	suite.Equal("basic_parent_id", columns11.Name)
+	suite.Equal(psqldef.PSQLTypeUUID, columns11.Type)
+	suite.True(columns11.PrimaryKey)
+
+	suite.Len(table1.ForeignKeys, 2)
+	suite.Len(table1.Indices, 2)
+	suite.Len(table1.UniqueConstraints, 0)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_CompositePrimaryKey() {
+	config := suite.getCompileConfig()
+
+	model0 := yaml.Model{
+		Name: "Membership",
+		Fields: map[string]yaml.ModelField{
+			"TenantID": {
+				Type: yaml.ModelFieldTypeInteger,
+			},
+			"UserID": {
+				Type: yaml.ModelFieldTypeUUID,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"TenantID",
+					"UserID",
+				},
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Membership", model0)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+
+	table0 := allTables[0]
+	suite.Equal("memberships", table0.Name)
+
+	columns0 := table0.Columns
+	suite.Len(columns0, 2)
+
+	columns00 := columns0[0]
+	suite.Equal("tenant_id", columns00.Name)
+	suite.Equal(psqldef.PSQLTypeInteger, columns00.Type)
+	suite.True(columns00.PrimaryKey)
+
+	columns01 := columns0[1]
+	suite.Equal("user_id", columns01.Name)
+	suite.Equal(psqldef.PSQLTypeUUID, columns01.Type)
+	suite.True(columns01.PrimaryKey)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForOne_CompositePrimaryKey() {
+	config := suite.getCompileConfig()
+
+	model0 := yaml.Model{
+		Name: "Invoice",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Membership": {
+				Type: "ForOne",
+			},
+		},
+	}
+	model1 := yaml.Model{
+		Name: "Membership",
+		Fields: map[string]yaml.ModelField{
+			"TenantID": {
+				Type: yaml.ModelFieldTypeInteger,
+			},
+			"UserID": {
+				Type: yaml.ModelFieldTypeUUID,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"TenantID",
+					"UserID",
+				},
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Invoice", model0)
+	r.SetModel("Membership", model1)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+
+	table0 := allTables[0]
+	suite.Equal("invoices", table0.Name)
+
+	columns0 := table0.Columns
+	suite.Len(columns0, 3)
+
+	columns01 := columns0[1]
+	suite.Equal("membership_tenant_id", columns01.Name)
+	suite.Equal(psqldef.PSQLTypeInteger, columns01.Type)
+	suite.True(columns01.NotNull)
+	suite.False(columns01.PrimaryKey)
+
+	columns02 := columns0[2]
+	suite.Equal("membership_user_id", columns02.Name)
+	suite.Equal(psqldef.PSQLTypeUUID, columns02.Type)
+	suite.True(columns02.NotNull)
+	suite.False(columns02.PrimaryKey)
+
+	suite.Len(table0.ForeignKeys, 1)
+
+	foreignKey0 := table0.ForeignKeys[0]
+	suite.Equal("fk_invoices_membership_tenant_id_membership_user_id", foreignKey0.Name)
+	suite.Equal([]string{"membership_tenant_id", "membership_user_id"}, foreignKey0.ColumnNames)
+	suite.Equal("memberships", foreignKey0.RefTableName)
+	suite.Equal([]string{"tenant_id", "user_id"}, foreignKey0.RefColumnNames)
+	suite.Equal("CASCADE", foreignKey0.OnDelete)
+
+	suite.Len(table0.Indices, 1)
+	index0 := table0.Indices[0]
+	suite.Equal("idx_invoices_membership_tenant_id_membership_user_id", index0.Name)
+	suite.Equal([]string{"membership_tenant_id", "membership_user_id"}, index0.Columns)
+	suite.False(index0.IsUnique)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForMany_CompositePrimaryKey() {
+	config := suite.getCompileConfig()
+
+	model0 := yaml.Model{
+		Name: "Invoice",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Membership": {
+				Type: "ForMany",
+			},
+		},
+	}
+	model1 := yaml.Model{
+		Name: "Membership",
+		Fields: map[string]yaml.ModelField{
+			"TenantID": {
+				Type: yaml.ModelFieldTypeInteger,
+			},
+			"UserID": {
+				Type: yaml.ModelFieldTypeUUID,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"TenantID",
+					"UserID",
+				},
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Invoice", model0)
+	r.SetModel("Membership", model1)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 2)
+
+	// Junction table invoices <-> memberships
+	table1 := allTables[1]
+	suite.Equal("invoice_memberships", table1.Name)
+
+	columns1 := table1.Columns
+	suite.Len(columns1, 4)
+	suite.Equal("id", columns1[0].Name)
+	suite.Equal("invoice_id", columns1[1].Name)
+	suite.Equal("membership_tenant_id", columns1[2].Name)
+	suite.Equal(psqldef.PSQLTypeInteger, columns1[2].Type)
+	suite.Equal("membership_user_id", columns1[3].Name)
+	suite.Equal(psqldef.PSQLTypeUUID, columns1[3].Type)
+
+	suite.Len(table1.ForeignKeys, 2)
+
+	foreignKey10 := table1.ForeignKeys[0]
+	suite.Equal([]string{"invoice_id"}, foreignKey10.ColumnNames)
+	suite.Equal([]string{"id"}, foreignKey10.RefColumnNames)
+
+	foreignKey11 := table1.ForeignKeys[1]
+	suite.Equal("fk_invoice_memberships_membership_tenant_id_user_id", foreignKey11.Name)
+	suite.Equal([]string{"membership_tenant_id", "membership_user_id"}, foreignKey11.ColumnNames)
+	suite.Equal("memberships", foreignKey11.RefTableName)
+	suite.Equal([]string{"tenant_id", "user_id"}, foreignKey11.RefColumnNames)
+
+	suite.Len(table1.UniqueConstraints, 1)
+	suite.Equal([]string{"invoice_id", "membership_tenant_id", "membership_user_id"}, table1.UniqueConstraints[0].ColumnNames)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForOnePoly() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.PolymorphicRelations = map[string]map[string][]string{
+		"Comment": {
+			"Commentable": {"Video", "Post"},
+		},
+	}
+
+	model0 := yaml.Model{
+		Name: "Comment",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Commentable": {
+				Type: "ForOnePoly",
+			},
+		},
+	}
+	model1 := yaml.Model{
+		Name: "Post",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Comment": {
+				Type: "HasManyPoly",
+			},
+		},
+	}
+	model2 := yaml.Model{
+		Name: "Video",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Comment", model0)
+	r.SetModel("Post", model1)
+	r.SetModel("Video", model2)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+
+	table0 := allTables[0]
+	suite.Equal("comments", table0.Name)
+
+	columns0 := table0.Columns
+	suite.Len(columns0, 3)
+
+	columns01 := columns0[1]
+	suite.Equal("commentable_type", columns01.Name)
+	suite.Equal(psqldef.PSQLTypeText, columns01.Type)
+	suite.True(columns01.NotNull)
+
+	columns02 := columns0[2]
+	suite.Equal("commentable_id", columns02.Name)
+	suite.Equal(psqldef.PSQLTypeInteger, columns02.Type)
+	suite.True(columns02.NotNull)
+
+	suite.Len(table0.ForeignKeys, 0)
+
+	suite.Len(table0.Indices, 1)
+	index0 := table0.Indices[0]
+	suite.Equal("idx_comments_commentable_type_commentable_id", index0.Name)
+	suite.Equal([]string{"commentable_type", "commentable_id"}, index0.Columns)
+	suite.False(index0.IsUnique)
+
+	suite.Equal([]psqldef.CheckConstraint{
+		{
+			Schema:     "public",
+			Name:       "chk_comments_commentable_type",
+			TableName:  "comments",
+			Expression: `"commentable_type" IN ('Post', 'Video')`,
+		},
+	}, table0.CheckConstraints)
+
+	suite.Equal([]psqldef.Trigger{
+		{
+			Schema:       "public",
+			Name:         "trg_comments_commentable_exists",
+			TableName:    "comments",
+			Timing:       "BEFORE",
+			Events:       []string{"INSERT", "UPDATE"},
+			FunctionName: "fn_comments_commentable_exists",
+			FunctionBody: []string{
+				`IF NEW."commentable_type" = 'Post' AND NOT EXISTS (SELECT 1 FROM public.posts WHERE "id" = NEW."commentable_id") THEN`,
+				`	RAISE EXCEPTION 'column commentable_id of table comments references missing posts row %', NEW."commentable_id";`,
+				"END IF;",
+				`IF NEW."commentable_type" = 'Video' AND NOT EXISTS (SELECT 1 FROM public.videos WHERE "id" = NEW."commentable_id") THEN`,
+				`	RAISE EXCEPTION 'column commentable_id of table comments references missing videos row %', NEW."commentable_id";`,
+				"END IF;",
+				"RETURN NEW;",
+			},
+		},
+	}, table0.Triggers)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForOnePoly_NoTargets() {
+	config := suite.getCompileConfig()
+
+	model0 := yaml.Model{
+		Name: "Comment",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Commentable": {
+				Type: "ForOnePoly",
+			},
+		},
+	}
+	model1 := yaml.Model{
+		Name: "Post",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Comment": {
+				Type: "HasManyPoly",
+			},
+		},
+	}
+	model2 := yaml.Model{
+		Name: "Video",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Comment", model0)
+	r.SetModel("Post", model1)
+	r.SetModel("Video", model2)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTables)
+	suite.EqualError(allTablesErr, "polymorphic relation 'Commentable' of model 'Comment' has no target models")
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForOnePoly_KeyMismatch() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.PolymorphicRelations = map[string]map[string][]string{
+		"Comment": {
+			"Commentable": {"Video", "Post"},
+		},
+	}
+
+	model0 := yaml.Model{
+		Name: "Comment",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Commentable": {
+				Type: "ForOnePoly",
+			},
+		},
+	}
+	model1 := yaml.Model{
+		Name: "Post",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Comment": {
+				Type: "HasManyPoly",
+			},
+		},
+	}
+	model2 := yaml.Model{
+		Name: "Video",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeUUID,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Comment", model0)
+	r.SetModel("Post", model1)
+	r.SetModel("Video", model2)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTables)
+	suite.EqualError(allTablesErr, "target models of polymorphic relation 'Commentable' of model 'Comment' must have single field primary identifiers of the same type")
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForManyPoly() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.PolymorphicRelations = map[string]map[string][]string{
+		"Comment": {
+			"Commentable": {"Video", "Post"},
+		},
+	}
+
+	model0 := yaml.Model{
+		Name: "Comment",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Commentable": {
+				Type: "ForManyPoly",
+			},
+		},
+	}
+	model1 := yaml.Model{
+		Name: "Post",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Comment": {
+				Type: "HasManyPoly",
+			},
+		},
+	}
+	model2 := yaml.Model{
+		Name: "Video",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Comment", model0)
+	r.SetModel("Post", model1)
+	r.SetModel("Video", model2)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTables)
+	suite.EqualError(allTablesErr, "polymorphic relation 'Commentable' of model 'Comment' has unsupported type 'ForManyPoly'")
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_HasManyPoly() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.PolymorphicRelations = map[string]map[string][]string{
+		"Comment": {
+			"Commentable": {"Video", "Post"},
+		},
+	}
+
+	model0 := yaml.Model{
+		Name: "Comment",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Commentable": {
+				Type: "ForOnePoly",
+			},
+		},
+	}
+	model1 := yaml.Model{
+		Name: "Post",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Comment": {
+				Type: "HasManyPoly",
+			},
+		},
+	}
+	model2 := yaml.Model{
+		Name: "Video",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Comment", model0)
+	r.SetModel("Post", model1)
+	r.SetModel("Video", model2)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model1)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+
+	table0 := allTables[0]
+	suite.Equal("posts", table0.Name)
+	suite.Len(table0.Columns, 1)
+	suite.Len(table0.ForeignKeys, 0)
+	suite.Len(table0.Triggers, 0)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForOne_Aliased() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.RelationAliases = map[string]map[string]string{
+		"Person": {
+			"Manager": "Person",
+			"Mentor":  "Person",
+		},
+	}
+
+	model0 := yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Manager": {
+				Type: "ForOne",
+			},
+			"Mentor": {
+				Type: "ForOne",
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Person", model0)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+
+	table0 := allTables[0]
+	suite.Equal("people", table0.Name)
+
+	columns0 := table0.Columns
+	suite.Len(columns0, 3)
+	suite.Equal("manager_id", columns0[1].Name)
+	suite.Equal(psqldef.PSQLTypeInteger, columns0[1].Type)
+	suite.Equal("mentor_id", columns0[2].Name)
+	suite.Equal(psqldef.PSQLTypeInteger, columns0[2].Type)
+
+	suite.Len(table0.ForeignKeys, 2)
+
+	foreignKey0 := table0.ForeignKeys[0]
+	suite.Equal("fk_people_manager_id", foreignKey0.Name)
+	suite.Equal([]string{"manager_id"}, foreignKey0.ColumnNames)
+	suite.Equal("people", foreignKey0.RefTableName)
+	suite.Equal([]string{"id"}, foreignKey0.RefColumnNames)
+
+	foreignKey1 := table0.ForeignKeys[1]
+	suite.Equal("fk_people_mentor_id", foreignKey1.Name)
+	suite.Equal([]string{"mentor_id"}, foreignKey1.ColumnNames)
+	suite.Equal("people", foreignKey1.RefTableName)
+	suite.Equal([]string{"id"}, foreignKey1.RefColumnNames)
+
+	suite.Len(table0.Indices, 2)
+	suite.Equal("idx_people_manager_id", table0.Indices[0].Name)
+	suite.Equal("idx_people_mentor_id", table0.Indices[1].Name)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForOne_Aliased_ForeignKeyConfig() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.RelationAliases = map[string]map[string]string{
+		"Person": {
+			"Manager": "Person",
+			"Mentor":  "Person",
+		},
+	}
+	// Overrides are keyed by the relation name, not the related Person model
+	config.MorpheModelsConfig.RelationForeignKeys = map[string]map[string]cfg.ForeignKeyConfig{
+		"Person": {
+			"Manager": {
+				OnDelete: cfg.ReferentialActionSetNull,
+			},
+		},
+	}
+
+	model0 := yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Manager": {
+				Type: "ForOne",
+			},
+			"Mentor": {
+				Type: "ForOne",
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Person", model0)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+
+	table0 := allTables[0]
+	suite.Len(table0.ForeignKeys, 2)
+
+	foreignKey0 := table0.ForeignKeys[0]
+	suite.Equal("fk_people_manager_id", foreignKey0.Name)
+	suite.Equal("SET NULL", foreignKey0.OnDelete)
+
+	foreignKey1 := table0.ForeignKeys[1]
+	suite.Equal("fk_people_mentor_id", foreignKey1.Name)
+	suite.Equal("CASCADE", foreignKey1.OnDelete)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForOne_SelfReference() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.RelationAliases = map[string]map[string]string{
+		"Person": {
+			"Parent": "Person",
+		},
+	}
+
+	model0 := yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Parent": {
+				Type: "ForOne",
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Person", model0)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+
+	table0 := allTables[0]
+	suite.Len(table0.Columns, 2)
+	suite.Equal("parent_id", table0.Columns[1].Name)
+
+	suite.Len(table0.ForeignKeys, 1)
+	foreignKey0 := table0.ForeignKeys[0]
+	suite.Equal("fk_people_parent_id", foreignKey0.Name)
+	suite.Equal("people", foreignKey0.TableName)
+	suite.Equal([]string{"parent_id"}, foreignKey0.ColumnNames)
+	suite.Equal("people", foreignKey0.RefTableName)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForMany_SelfReference() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.RelationAliases = map[string]map[string]string{
+		"Person": {
+			"Friend": "Person",
+		},
+	}
+
+	model0 := yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Friend": {
+				Type: "ForMany",
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Person", model0)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 2)
+
+	// Junction table people <-> people
+	table1 := allTables[1]
+	suite.Equal("person_friends", table1.Name)
+
+	columns1 := table1.Columns
+	suite.Len(columns1, 3)
+	suite.Equal("person_id", columns1[1].Name)
+	suite.Equal("friend_id", columns1[2].Name)
+
+	suite.Len(table1.ForeignKeys, 2)
+
+	foreignKey10 := table1.ForeignKeys[0]
+	suite.Equal("fk_person_friends_person_id", foreignKey10.Name)
+	suite.Equal([]string{"person_id"}, foreignKey10.ColumnNames)
+	suite.Equal("people", foreignKey10.RefTableName)
+
+	foreignKey11 := table1.ForeignKeys[1]
+	suite.Equal("fk_person_friends_friend_id", foreignKey11.Name)
+	suite.Equal([]string{"friend_id"}, foreignKey11.ColumnNames)
+	suite.Equal("people", foreignKey11.RefTableName)
+
+	suite.Len(table1.UniqueConstraints, 1)
+	suite.Equal("uk_person_friends_person_id_friend_id", table1.UniqueConstraints[0].Name)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_ForMany_SelfReference_NoAlias() {
+	config := suite.getCompileConfig()
+
+	model0 := yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Person": {
+				Type: "ForMany",
+			},
+		},
+	}
+	r := registry.NewRegistry()
+	r.SetModel("Person", model0)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
 
-	suite.Len(table1.UniqueConstraints, 1)
-	uniqueConstraint10 := table1.UniqueConstraints[0]
-	suite.Equal("uk_basic_basic_parents_basic_id_basic_parent_id", uniqueConstraint10.Name)
-	suite.Equal("basic_basic_parents", uniqueConstraint10.TableName)
-	suite.Len(uniqueConstraint10.ColumnNames, 2)
-	suite.Equal("basic_id", uniqueConstraint10.ColumnNames[0])
-	suite.Equal("basic_parent_id", uniqueConstraint10.ColumnNames[1])
+	suite.Nil(allTables)
+	suite.EqualError(allTablesErr, "ForMany relation 'Person' of model 'Person' needs a relation alias to tell its junction columns apart")
 }
 
 func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_Related_HasOne() {
@@ -1525,6 +2723,339 @@ func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_EnumField() {
 	suite.Equal(foreignKey0.Name, "fk_basics_nationality_id")
 	suite.Equal(foreignKey0.TableName, "basics")
 	suite.Equal(foreignKey0.ColumnNames, []string{"nationality_id"})
+	suite.Equal(foreignKey0.RefSchema, config.MorpheConfig.MorpheEnumsConfig.Schema)
 	suite.Equal(foreignKey0.RefTableName, "nationalities")
 	suite.Equal(foreignKey0.RefColumnNames, []string{"id"})
 }
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_EnumField_SeparateSchemas() {
+	config := suite.getCompileConfig()
+	config.MorpheModelsConfig.Schema = "app"
+	config.MorpheEnumsConfig.Schema = "lookup"
+
+	model0 := yaml.Model{
+		Name: "Basic",
+		Fields: map[string]yaml.ModelField{
+			"AutoIncrement": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+			"Nationality": {
+				Type: "Nationality",
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"AutoIncrement",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{},
+	}
+
+	r := registry.NewRegistry()
+	r.SetEnum("Nationality", yaml.Enum{
+		Name: "Nationality",
+		Type: yaml.EnumTypeString,
+		Entries: map[string]any{
+			"DE": "German",
+		},
+	})
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+	suite.Len(allTables[0].ForeignKeys, 1)
+
+	foreignKey0 := allTables[0].ForeignKeys[0]
+	suite.Equal("app", foreignKey0.Schema)
+	suite.Equal("lookup", foreignKey0.RefSchema)
+	suite.Equal("nationalities", foreignKey0.RefTableName)
+
+	tableLines, tableLinesErr := (&compile.MorpheTableFileWriter{}).GetCreateTableLines(allTables[0])
+
+	suite.Nil(tableLinesErr)
+	suite.Contains(tableLines, "\t\tREFERENCES lookup.nationalities(id)")
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_EnumField_NativeEnum() {
+	config := suite.getCompileConfig()
+	config.MorpheEnumsConfig.Strategy = cfg.EnumStrategyNativeEnum
+
+	model0 := yaml.Model{
+		Name: "Basic",
+		Fields: map[string]yaml.ModelField{
+			"AutoIncrement": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+			"Nationality": {
+				Type: "Nationality",
+			},
+			"UUID": {
+				Type: yaml.ModelFieldTypeUUID,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"UUID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{},
+	}
+
+	enum0 := yaml.Enum{
+		Name: "Nationality",
+		Type: yaml.EnumTypeString,
+		Entries: map[string]any{
+			"US": "American",
+			"DE": "German",
+			"FR": "French",
+		},
+	}
+
+	r := registry.NewRegistry()
+	r.SetEnum("Nationality", enum0)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+
+	table0 := allTables[0]
+	suite.Equal(table0.Name, "basics")
+
+	columns0 := table0.Columns
+	suite.Len(columns0, 3)
+
+	column01 := columns0[1]
+	suite.Equal("nationality", column01.Name)
+	suite.Equal(psqldef.PSQLTypeEnum{
+		Schema: "public",
+		Name:   "nationality",
+		Values: []string{"DE", "FR", "US"},
+	}, column01.Type)
+	suite.Equal("public.nationality", column01.Type.GetSyntax())
+	suite.True(column01.NotNull)
+
+	suite.Len(table0.ForeignKeys, 0)
+	suite.Len(table0.Indices, 0)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_StructureField_CompositeType() {
+	config := suite.getCompileConfig()
+	config.MorpheStructuresConfig = cfg.MorpheStructuresConfig{
+		Schema:   "public",
+		Strategy: cfg.StructureStrategyCompositeType,
+	}
+
+	model0 := yaml.Model{
+		Name: "Basic",
+		Fields: map[string]yaml.ModelField{
+			"Address": {
+				Type:       "Address",
+				Attributes: []string{"mandatory"},
+			},
+			"Nationality": {
+				Type: "Nationality",
+			},
+			"UUID": {
+				Type: yaml.ModelFieldTypeUUID,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"UUID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{},
+	}
+
+	structure0 := yaml.Structure{
+		Name: "Address",
+		Fields: map[string]yaml.StructureField{
+			"Street": {
+				Type: yaml.StructureFieldTypeString,
+			},
+			"HouseNr": {
+				Type: yaml.StructureFieldTypeInteger,
+			},
+		},
+	}
+
+	enum0 := yaml.Enum{
+		Name: "Nationality",
+		Type: yaml.EnumTypeString,
+		Entries: map[string]any{
+			"US": "American",
+			"DE": "German",
+		},
+	}
+
+	r := registry.NewRegistry()
+	r.SetStructure("Address", structure0)
+	r.SetEnum("Nationality", enum0)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+
+	columns0 := allTables[0].Columns
+	suite.Len(columns0, 3)
+
+	column00 := columns0[0]
+	suite.Equal("address", column00.Name)
+	suite.Equal(psqldef.PSQLTypeComposite{
+		Schema: "public",
+		Name:   "address",
+		Fields: map[string]psqldef.PSQLType{
+			"house_nr": psqldef.PSQLTypeInteger,
+			"street":   psqldef.PSQLTypeText,
+		},
+	}, column00.Type)
+	suite.Equal("public.address", column00.Type.GetSyntax())
+	suite.True(column00.NotNull)
+
+	suite.Equal("nationality_id", columns0[1].Name)
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_StructureField_JSONTable() {
+	config := suite.getCompileConfig()
+
+	model0 := yaml.Model{
+		Name: "Basic",
+		Fields: map[string]yaml.ModelField{
+			"Address": {
+				Type: "Address",
+			},
+			"UUID": {
+				Type: yaml.ModelFieldTypeUUID,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"UUID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{},
+	}
+
+	r := registry.NewRegistry()
+	r.SetStructure("Address", yaml.Structure{
+		Name: "Address",
+		Fields: map[string]yaml.StructureField{
+			"Street": {
+				Type: yaml.StructureFieldTypeString,
+			},
+		},
+	})
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTables)
+	suite.ErrorContains(allTablesErr, "morphe model field 'Address' has unsupported type 'Address'")
+}
+
+func (suite *CompileModelsTestSuite) TestMorpheModelToPSQLTables_FieldAttributes() {
+	config := suite.getCompileConfig()
+
+	model0 := yaml.Model{
+		Name: "Basic",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+				Attributes: []string{
+					"mandatory",
+				},
+			},
+			"Code": {
+				Type: yaml.ModelFieldTypeString,
+				Attributes: []string{
+					"mandatory",
+					"immutable",
+				},
+			},
+			"Name": {
+				Type: yaml.ModelFieldTypeString,
+			},
+			"Nationality": {
+				Type: "Nationality",
+				Attributes: []string{
+					"immutable",
+				},
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{
+					"ID",
+				},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{},
+	}
+
+	enum0 := yaml.Enum{
+		Name: "Nationality",
+		Type: yaml.EnumTypeString,
+		Entries: map[string]any{
+			"US": "American",
+		},
+	}
+
+	r := registry.NewRegistry()
+	r.SetEnum("Nationality", enum0)
+
+	allTables, allTablesErr := compile.MorpheModelToPSQLTables(config, r, model0)
+
+	suite.Nil(allTablesErr)
+	suite.Len(allTables, 1)
+
+	table0 := allTables[0]
+
+	columns0 := table0.Columns
+	suite.Len(columns0, 4)
+
+	column00 := columns0[0]
+	suite.Equal("code", column00.Name)
+	suite.True(column00.NotNull)
+
+	column01 := columns0[1]
+	suite.Equal("id", column01.Name)
+	suite.True(column01.PrimaryKey)
+	suite.False(column01.NotNull)
+
+	column02 := columns0[2]
+	suite.Equal("name", column02.Name)
+	suite.False(column02.NotNull)
+
+	column03 := columns0[3]
+	suite.Equal("nationality_id", column03.Name)
+	suite.True(column03.NotNull)
+
+	suite.Len(table0.Triggers, 1)
+
+	trigger0 := table0.Triggers[0]
+	suite.Equal("public", trigger0.Schema)
+	suite.Equal("trg_basics_immutable", trigger0.Name)
+	suite.Equal("basics", trigger0.TableName)
+	suite.Equal("BEFORE", trigger0.Timing)
+	suite.Equal([]string{"UPDATE"}, trigger0.Events)
+	suite.Equal("fn_basics_immutable", trigger0.FunctionName)
+	suite.Equal([]string{
+		`IF NEW."code" IS DISTINCT FROM OLD."code" THEN`,
+		"\tRAISE EXCEPTION 'column code of table basics is immutable';",
+		"END IF;",
+		`IF NEW."nationality_id" IS DISTINCT FROM OLD."nationality_id" THEN`,
+		"\tRAISE EXCEPTION 'column nationality_id of table basics is immutable';",
+		"END IF;",
+		"RETURN NEW;",
+	}, trigger0.FunctionBody)
+}
@@ -1,14 +1,23 @@
 package compile
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	"github.com/kalo-build/morphe-go/pkg/yaml"
 	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
 	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/hook"
 	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/write"
 	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/typemap"
 )
 
-// MorpheStructureToPSQLTable creates a standard structures table according to the spec
-func MorpheStructureToPSQLTable(config MorpheCompileConfig) (*psqldef.Table, error) {
+// MorpheStructureToPSQLTable creates a standard structures table according to the spec. With a registry, the table
+// gets one check constraint per registry structure validating the "data" of rows of that "type".
+func MorpheStructureToPSQLTable(config MorpheCompileConfig, r *registry.Registry) (*psqldef.Table, error) {
 	morpheConfig, configStartErr := triggerCompileMorpheStructureStart(config.StructureHooks, config.MorpheConfig)
 	if configStartErr != nil {
 		return nil, triggerCompileMorpheStructureFailure(config.StructureHooks, morpheConfig, configStartErr)
@@ -21,7 +30,15 @@ func MorpheStructureToPSQLTable(config MorpheCompileConfig) (*psqldef.Table, err
 	}
 
 	// Create a fixed table definition based on the spec
-	structureTable := createStandardStructureTable(morpheConfig.MorpheStructuresConfig)
+	names := config.getIdentifierNames()
+	structureTable := createStandardStructureTable(morpheConfig.MorpheStructuresConfig, names)
+	if r != nil {
+		checkConstraints, checkConstraintsErr := getCheckConstraintsForStructures(morpheConfig, names, r, structureTable)
+		if checkConstraintsErr != nil {
+			return nil, triggerCompileMorpheStructureFailure(config.StructureHooks, morpheConfig, checkConstraintsErr)
+		}
+		structureTable.CheckConstraints = checkConstraints
+	}
 
 	structureTable, structureTableErr := triggerCompileMorpheStructureSuccess(config.StructureHooks, structureTable)
 	if structureTableErr != nil {
@@ -31,13 +48,89 @@ func MorpheStructureToPSQLTable(config MorpheCompileConfig) (*psqldef.Table, err
 	return structureTable, nil
 }
 
+// AllMorpheStructuresToPSQLTypes compiles all Morphe structures to PostgreSQL composite types
+func AllMorpheStructuresToPSQLTypes(config MorpheCompileConfig, r *registry.Registry) (map[string]*psqldef.PSQLTypeComposite, error) {
+	allStructureTypeDefs := map[string]*psqldef.PSQLTypeComposite{}
+	for structureName, structure := range r.GetAllStructures() {
+		structureType, structureErr := MorpheStructureToPSQLType(config, r, structure)
+		if structureErr != nil {
+			return nil, structureErr
+		}
+		allStructureTypeDefs[structureName] = structureType
+	}
+	return allStructureTypeDefs, nil
+}
+
+// MorpheStructureToPSQLType converts a Morphe structure to a PostgreSQL composite type
+func MorpheStructureToPSQLType(config MorpheCompileConfig, r *registry.Registry, structure yaml.Structure) (*psqldef.PSQLTypeComposite, error) {
+	if r == nil {
+		return nil, triggerCompileMorpheStructureFailure(config.StructureHooks, config.MorpheConfig, ErrNoRegistry)
+	}
+
+	morpheConfig, configStartErr := triggerCompileMorpheStructureStart(config.StructureHooks, config.MorpheConfig)
+	if configStartErr != nil {
+		return nil, triggerCompileMorpheStructureFailure(config.StructureHooks, config.MorpheConfig, configStartErr)
+	}
+
+	structureType, structureTypeErr := createPSQLTypeForStructure(morpheConfig, config.getIdentifierNames(), r, structure)
+	if structureTypeErr != nil {
+		return nil, triggerCompileMorpheStructureFailure(config.StructureHooks, morpheConfig, structureTypeErr)
+	}
+
+	structureType, structureSuccessErr := triggerCompileMorpheStructureTypeSuccess(config.StructureHooks, structureType)
+	if structureSuccessErr != nil {
+		return nil, triggerCompileMorpheStructureFailure(config.StructureHooks, morpheConfig, structureSuccessErr)
+	}
+
+	return structureType, nil
+}
+
+// AllMorpheStructuresToPSQLTables compiles all Morphe structures to typed PostgreSQL tables
+func AllMorpheStructuresToPSQLTables(config MorpheCompileConfig, r *registry.Registry) (map[string]*psqldef.Table, error) {
+	allStructureTableDefs := map[string]*psqldef.Table{}
+	for structureName, structure := range r.GetAllStructures() {
+		structureTable, structureErr := MorpheStructureToPSQLTypedTable(config, r, structure)
+		if structureErr != nil {
+			return nil, structureErr
+		}
+		allStructureTableDefs[structureName] = structureTable
+	}
+	return allStructureTableDefs, nil
+}
+
+// MorpheStructureToPSQLTypedTable converts a Morphe structure to a table with one typed column per structure field
+func MorpheStructureToPSQLTypedTable(config MorpheCompileConfig, r *registry.Registry, structure yaml.Structure) (*psqldef.Table, error) {
+	if r == nil {
+		return nil, triggerCompileMorpheStructureFailure(config.StructureHooks, config.MorpheConfig, ErrNoRegistry)
+	}
+
+	morpheConfig, configStartErr := triggerCompileMorpheStructureStart(config.StructureHooks, config.MorpheConfig)
+	if configStartErr != nil {
+		return nil, triggerCompileMorpheStructureFailure(config.StructureHooks, config.MorpheConfig, configStartErr)
+	}
+
+	structureTable, structureTableErr := createTypedTableForStructure(morpheConfig, config.getIdentifierNames(), r, structure)
+	if structureTableErr != nil {
+		return nil, triggerCompileMorpheStructureFailure(config.StructureHooks, morpheConfig, structureTableErr)
+	}
+
+	structureTable, structureSuccessErr := triggerCompileMorpheStructureSuccess(config.StructureHooks, structureTable)
+	if structureSuccessErr != nil {
+		return nil, triggerCompileMorpheStructureFailure(config.StructureHooks, morpheConfig, structureSuccessErr)
+	}
+
+	return structureTable, nil
+}
+
 // WriteStructureTableDefinition writes the structure table definition
 func WriteStructureTableDefinition(hooks hook.WritePSQLTable, writer write.PSQLTableWriter, structureTable *psqldef.Table) (*psqldef.Table, []byte, error) {
 	return WriteModelTableDefinition(hooks, writer, structureTable)
 }
 
 // createStandardStructureTable creates the standard structure table
-func createStandardStructureTable(config cfg.MorpheStructuresConfig) *psqldef.Table {
+func createStandardStructureTable(config cfg.MorpheStructuresConfig, names identifierNames) *psqldef.Table {
+	tableName := "morphe_structures"
+
 	idType := psqldef.PSQLTypeSerial
 	if config.UseBigSerial {
 		idType = psqldef.PSQLTypeBigSerial
@@ -76,12 +169,12 @@ func createStandardStructureTable(config cfg.MorpheStructuresConfig) *psqldef.Ta
 	// Create indices
 	indices := []psqldef.Index{
 		{
-			Name:     "idx_morphe_structures_type",
+			Name:     names.getIndexName(tableName, "type"),
 			Columns:  []string{"\"type\""}, // Quoted to match ground truth
 			IsUnique: false,
 		},
 		{
-			Name:     "idx_morphe_structures_data",
+			Name:     names.getIndexName(tableName, "data"),
 			Columns:  []string{"\"data\""}, // Quoted to match ground truth
 			IsUnique: false,
 			Using:    "GIN",
@@ -91,12 +184,230 @@ func createStandardStructureTable(config cfg.MorpheStructuresConfig) *psqldef.Ta
 	// Create table
 	return &psqldef.Table{
 		Schema:  config.Schema,
-		Name:    "morphe_structures",
+		Name:    tableName,
 		Columns: columns,
 		Indices: indices,
 	}
 }
 
+// getCheckConstraintsForStructures returns one check constraint per registry structure, sorted by structure name
+func getCheckConstraintsForStructures(config cfg.MorpheConfig, names identifierNames, r *registry.Registry, structureTable *psqldef.Table) ([]psqldef.CheckConstraint, error) {
+	allStructures := r.GetAllStructures()
+	checkConstraints := []psqldef.CheckConstraint{}
+	for _, structureName := range core.MapKeysSorted(allStructures) {
+		structure := allStructures[structureName]
+		validateMorpheErr := structure.Validate(r.GetAllEnums())
+		if validateMorpheErr != nil {
+			return nil, validateMorpheErr
+		}
+
+		expression, expressionErr := getStructureDataCheckExpression(r, structure)
+		if expressionErr != nil {
+			return nil, expressionErr
+		}
+		checkConstraints = append(checkConstraints, psqldef.CheckConstraint{
+			Schema:     config.MorpheStructuresConfig.Schema,
+			Name:       names.getStructureCheckConstraintName(structureTable.Name, structure.Name),
+			TableName:  structureTable.Name,
+			Expression: expression,
+		})
+	}
+	return checkConstraints, nil
+}
+
+// getStructureDataCheckExpression returns the check expression for rows of one structure type: "data" must contain
+// a key per structure field, and each value must have the JSON type of its field. Values of fields that are not
+// mandatory may also be null.
+func getStructureDataCheckExpression(r *registry.Registry, structure yaml.Structure) (string, error) {
+	keys := []string{}
+	typeConditions := []string{}
+	for _, fieldName := range core.MapKeysSorted(structure.Fields) {
+		field := structure.Fields[fieldName]
+		jsonType, jsonTypeErr := getJSONTypeForStructureField(r, fieldName, field)
+		if jsonTypeErr != nil {
+			return "", jsonTypeErr
+		}
+
+		key := quoteSQLString(fieldName)
+		allowedTypes := []string{quoteSQLString(jsonType)}
+		if !isMandatoryStructureField(field) {
+			allowedTypes = append(allowedTypes, quoteSQLString("null"))
+		}
+		keys = append(keys, key)
+		typeConditions = append(typeConditions, fmt.Sprintf(`jsonb_typeof("data"->%s) IN (%s)`, key, strings.Join(allowedTypes, ", ")))
+	}
+
+	conditions := append([]string{fmt.Sprintf(`"data" ?& ARRAY[%s]`, strings.Join(keys, ", "))}, typeConditions...)
+	return fmt.Sprintf(`"type" <> %s OR (%s)`, quoteSQLString(structure.Name), strings.Join(conditions, " AND ")), nil
+}
+
+// getJSONTypeForStructureField returns the jsonb_typeof result of a structure field's values
+func getJSONTypeForStructureField(r *registry.Registry, fieldName string, field yaml.StructureField) (string, error) {
+	jsonType, supported := typemap.MorpheStructureFieldToJSONType[field.Type]
+	if supported {
+		return jsonType, nil
+	}
+
+	enumType, enumErr := r.GetEnum(string(field.Type))
+	if enumErr != nil {
+		return "", fmt.Errorf("morphe structure field '%s' has unsupported type '%s'", fieldName, field.Type)
+	}
+	jsonType, supported = typemap.MorpheEnumEntryToJSONType[enumType.Type]
+	if !supported {
+		return "", fmt.Errorf("morphe structure field '%s' has enum '%s' of unsupported type '%s'", fieldName, enumType.Name, enumType.Type)
+	}
+	return jsonType, nil
+}
+
+func quoteSQLString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// createTypedTableForStructure creates a table for a Morphe structure. Fields compile like model fields, an "ID"
+// field of type AutoIncrement or UUID becomes the primary key, otherwise a serial id is added. Every table gets
+// created_at and updated_at timestamps.
+func createTypedTableForStructure(config cfg.MorpheConfig, names identifierNames, r *registry.Registry, structure yaml.Structure) (*psqldef.Table, error) {
+	validateConfigErr := config.MorpheStructuresConfig.Validate()
+	if validateConfigErr != nil {
+		return nil, validateConfigErr
+	}
+	validateMorpheErr := structure.Validate(r.GetAllEnums())
+	if validateMorpheErr != nil {
+		return nil, validateMorpheErr
+	}
+
+	schema := config.MorpheStructuresConfig.Schema
+	tableName := names.getTableNameFromStructure(structure.Name)
+
+	typeMap := typemap.MorpheModelFieldToPSQLField
+	idType := psqldef.PSQLTypeSerial
+	if config.MorpheStructuresConfig.UseBigSerial {
+		typeMap = typemap.MorpheModelFieldToPSQLFieldBigSerial
+		idType = psqldef.PSQLTypeBigSerial
+	}
+
+	structureFields := map[string]yaml.ModelField{}
+	for fieldName, field := range structure.Fields {
+		structureFields[fieldName] = yaml.ModelField{
+			Type:       yaml.ModelFieldType(field.Type),
+			Attributes: field.Attributes,
+		}
+	}
+
+	columns := []psqldef.TableColumn{}
+	primaryID := yaml.ModelIdentifier{}
+	idField, idFieldExists := structure.Fields["ID"]
+	if idFieldExists && (idField.Type == yaml.StructureFieldTypeAutoIncrement || idField.Type == yaml.StructureFieldTypeUUID) {
+		primaryID.Fields = []string{"ID"}
+	} else {
+		columns = append(columns, psqldef.TableColumn{
+			Name:       "id",
+			Type:       idType,
+			PrimaryKey: true,
+		})
+	}
+
+	// Enum foreign keys of structure tables live in the structures schema
+	columnsConfig := config
+	columnsConfig.MorpheModelsConfig.Schema = schema
+	fieldColumns, enumForeignKeys, immutableColumnNames, fieldColumnsErr := getColumnsForModelFields(columnsConfig, names, r, typeMap, tableName, primaryID, structureFields)
+	if fieldColumnsErr != nil {
+		return nil, fieldColumnsErr
+	}
+	if len(primaryID.Fields) == 0 && slices.ContainsFunc(fieldColumns, func(column psqldef.TableColumn) bool { return column.Name == "id" }) {
+		return nil, ErrStructureIDColumnClash(structure.Name)
+	}
+	columns = append(columns, fieldColumns...)
+
+	for _, timestampColumnName := range []string{"created_at", "updated_at"} {
+		if slices.ContainsFunc(columns, func(column psqldef.TableColumn) bool { return column.Name == timestampColumnName }) {
+			continue
+		}
+		columns = append(columns, psqldef.TableColumn{
+			Name:    timestampColumnName,
+			Type:    psqldef.PSQLTypeTimestampTZ,
+			Default: "NOW()",
+		})
+	}
+
+	structureTable := &psqldef.Table{
+		Schema:            schema,
+		Name:              tableName,
+		Columns:           columns,
+		ForeignKeys:       enumForeignKeys,
+		Indices:           getIndicesForForeignKeys(names, tableName, enumForeignKeys),
+		UniqueConstraints: []psqldef.UniqueConstraint{},
+		Triggers:          getTriggersForImmutableColumns(names, schema, tableName, immutableColumnNames),
+	}
+	allowNullForSetNullForeignKeys(structureTable)
+	quoteReservedColumnNames(structureTable)
+	ensureNamedForeignKeyConstraints(structureTable, names)
+
+	return structureTable, nil
+}
+
+// createPSQLTypeForStructure creates a PostgreSQL composite type for a Morphe structure
+func createPSQLTypeForStructure(config cfg.MorpheConfig, names identifierNames, r *registry.Registry, structure yaml.Structure) (*psqldef.PSQLTypeComposite, error) {
+	validateConfigErr := config.MorpheStructuresConfig.Validate()
+	if validateConfigErr != nil {
+		return nil, validateConfigErr
+	}
+	validateMorpheErr := structure.Validate(r.GetAllEnums())
+	if validateMorpheErr != nil {
+		return nil, validateMorpheErr
+	}
+
+	structureType, structureTypeErr := getPSQLTypeForStructure(config, names, r, structure)
+	if structureTypeErr != nil {
+		return nil, structureTypeErr
+	}
+	return &structureType, nil
+}
+
+// getPSQLTypeForStructure returns the composite type for a Morphe structure, with one attribute per structure field.
+//
+// Composite types cannot hold sequences, so auto-increment fields become plain integers. Enum fields use the native
+// enum type or, with lookup tables, the enum's entry value type.
+func getPSQLTypeForStructure(config cfg.MorpheConfig, names identifierNames, r *registry.Registry, structure yaml.Structure) (psqldef.PSQLTypeComposite, error) {
+	typeMap := typemap.MorpheStructureFieldToPSQLFieldForeign
+	if config.MorpheStructuresConfig.UseBigSerial {
+		typeMap = typemap.MorpheStructureFieldToPSQLFieldBigSerialForeign
+	}
+
+	fields := map[string]psqldef.PSQLType{}
+	for fieldName, field := range structure.Fields {
+		attributeName := names.getColumnNameFromField(fieldName)
+
+		attributeType, supported := typeMap[field.Type]
+		if supported {
+			fields[attributeName] = attributeType
+			continue
+		}
+
+		enumType, enumErr := r.GetEnum(string(field.Type))
+		if enumErr != nil {
+			return psqldef.PSQLTypeComposite{}, fmt.Errorf("morphe structure field '%s' has unsupported type '%s'", fieldName, field.Type)
+		}
+
+		if config.MorpheEnumsConfig.IsNativeEnum() {
+			fields[attributeName] = getPSQLTypeForEnum(config.MorpheEnumsConfig.Schema, names, enumType)
+			continue
+		}
+
+		entryType, entryTypeSupported := typemap.MorpheEnumEntryToPSQLEntryType[enumType.Type]
+		if !entryTypeSupported {
+			return psqldef.PSQLTypeComposite{}, fmt.Errorf("morphe structure field '%s' has enum '%s' of unsupported type '%s'", fieldName, enumType.Name, enumType.Type)
+		}
+		fields[attributeName] = entryType
+	}
+
+	return psqldef.PSQLTypeComposite{
+		Schema: config.MorpheStructuresConfig.Schema,
+		Name:   names.getCompositeTypeNameFromStructure(structure.Name),
+		Fields: fields,
+	}, nil
+}
+
 func triggerCompileMorpheStructureStart(hooks hook.CompileMorpheStructure, config cfg.MorpheConfig) (cfg.MorpheConfig, error) {
 	if hooks.OnCompileMorpheStructureStart == nil {
 		return config, nil
@@ -126,6 +437,22 @@ func triggerCompileMorpheStructureSuccess(hooks hook.CompileMorpheStructure, str
 	return updatedTable, nil
 }
 
+func triggerCompileMorpheStructureTypeSuccess(hooks hook.CompileMorpheStructure, structureType *psqldef.PSQLTypeComposite) (*psqldef.PSQLTypeComposite, error) {
+	if hooks.OnCompileMorpheStructureTypeSuccess == nil {
+		return structureType, nil
+	}
+	if structureType == nil {
+		return nil, ErrNoStructureType
+	}
+
+	structureTypeClone := structureType.DeepClone()
+	updatedStructureType, successErr := hooks.OnCompileMorpheStructureTypeSuccess(&structureTypeClone)
+	if successErr != nil {
+		return nil, successErr
+	}
+	return updatedStructureType, nil
+}
+
 func triggerCompileMorpheStructureFailure(hooks hook.CompileMorpheStructure, config cfg.MorpheConfig, failureErr error) error {
 	if hooks.OnCompileMorpheStructureFailure == nil {
 		return failureErr
@@ -0,0 +1,383 @@
+package compile_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	"github.com/kalo-build/morphe-go/pkg/yaml"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/hook"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+	"github.com/stretchr/testify/suite"
+)
+
+type CompileStructuresTestSuite struct {
+	suite.Suite
+}
+
+func TestCompileStructuresTestSuite(t *testing.T) {
+	suite.Run(t, new(CompileStructuresTestSuite))
+}
+
+func (suite *CompileStructuresTestSuite) getCompileConfig() compile.MorpheCompileConfig {
+	return compile.MorpheCompileConfig{
+		MorpheConfig: cfg.MorpheConfig{
+			MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+				Schema: "public",
+			},
+			MorpheStructuresConfig: cfg.MorpheStructuresConfig{
+				Schema:   "public",
+				Strategy: cfg.StructureStrategyCompositeType,
+			},
+		},
+		StructureHooks: hook.CompileMorpheStructure{},
+	}
+}
+
+func (suite *CompileStructuresTestSuite) getAddressStructure() yaml.Structure {
+	return yaml.Structure{
+		Name: "Address",
+		Fields: map[string]yaml.StructureField{
+			"Street": {
+				Type: yaml.StructureFieldTypeString,
+			},
+			"HouseNr": {
+				Type: yaml.StructureFieldTypeInteger,
+			},
+			"ID": {
+				Type: yaml.StructureFieldTypeAutoIncrement,
+			},
+			"Country": {
+				Type: "Country",
+			},
+		},
+	}
+}
+
+func (suite *CompileStructuresTestSuite) getRegistry() *registry.Registry {
+	r := registry.NewRegistry()
+	r.SetStructure("Address", suite.getAddressStructure())
+	r.SetEnum("Country", yaml.Enum{
+		Name: "Country",
+		Type: yaml.EnumTypeString,
+		Entries: map[string]any{
+			"DE": "Germany",
+			"US": "United States",
+		},
+	})
+	return r
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLType() {
+	config := suite.getCompileConfig()
+
+	structureType, structureErr := compile.MorpheStructureToPSQLType(config, suite.getRegistry(), suite.getAddressStructure())
+
+	suite.Nil(structureErr)
+	suite.Equal(&psqldef.PSQLTypeComposite{
+		Schema: "public",
+		Name:   "address",
+		Fields: map[string]psqldef.PSQLType{
+			"country":  psqldef.PSQLTypeText,
+			"house_nr": psqldef.PSQLTypeInteger,
+			"id":       psqldef.PSQLTypeInteger,
+			"street":   psqldef.PSQLTypeText,
+		},
+	}, structureType)
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLType_NativeEnum() {
+	config := suite.getCompileConfig()
+	config.MorpheEnumsConfig.Strategy = cfg.EnumStrategyNativeEnum
+	config.MorpheStructuresConfig.UseBigSerial = true
+
+	structureType, structureErr := compile.MorpheStructureToPSQLType(config, suite.getRegistry(), suite.getAddressStructure())
+
+	suite.Nil(structureErr)
+	suite.Equal(psqldef.PSQLTypeEnum{
+		Schema: "public",
+		Name:   "country",
+		Values: []string{"DE", "US"},
+	}, structureType.Fields["country"])
+	suite.Equal(psqldef.PSQLTypeBigInt, structureType.Fields["id"])
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLType_UnknownStrategy() {
+	config := suite.getCompileConfig()
+	config.MorpheStructuresConfig.Strategy = "typed_json"
+
+	structureType, structureErr := compile.MorpheStructureToPSQLType(config, suite.getRegistry(), suite.getAddressStructure())
+
+	suite.Nil(structureType)
+	suite.ErrorContains(structureErr, "unknown structure strategy 'typed_json'")
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLType_NoFields() {
+	config := suite.getCompileConfig()
+
+	structureType, structureErr := compile.MorpheStructureToPSQLType(config, suite.getRegistry(), yaml.Structure{
+		Name: "Empty",
+	})
+
+	suite.Nil(structureType)
+	suite.ErrorIs(structureErr, yaml.ErrNoMorpheStructureFields)
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLType_SuccessHook() {
+	config := suite.getCompileConfig()
+	config.StructureHooks = hook.CompileMorpheStructure{
+		OnCompileMorpheStructureTypeSuccess: func(structureType *psqldef.PSQLTypeComposite) (*psqldef.PSQLTypeComposite, error) {
+			structureType.Name = "postal_address"
+			return structureType, nil
+		},
+	}
+
+	structureType, structureErr := compile.MorpheStructureToPSQLType(config, suite.getRegistry(), suite.getAddressStructure())
+
+	suite.Nil(structureErr)
+	suite.Equal("public.postal_address", structureType.GetSyntax())
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLType_FailureHook() {
+	config := suite.getCompileConfig()
+	config.MorpheStructuresConfig.Schema = ""
+	config.StructureHooks = hook.CompileMorpheStructure{
+		OnCompileMorpheStructureFailure: func(config cfg.MorpheConfig, compileFailure error) error {
+			return errors.New("structure hook: " + compileFailure.Error())
+		},
+	}
+
+	structureType, structureErr := compile.MorpheStructureToPSQLType(config, suite.getRegistry(), suite.getAddressStructure())
+
+	suite.Nil(structureType)
+	suite.ErrorContains(structureErr, "structure hook: structure schema cannot be empty when structures compile to composite types")
+}
+
+func (suite *CompileStructuresTestSuite) TestAllMorpheStructuresToPSQLTypes() {
+	config := suite.getCompileConfig()
+
+	allStructureTypes, allStructuresErr := compile.AllMorpheStructuresToPSQLTypes(config, suite.getRegistry())
+
+	suite.Nil(allStructuresErr)
+	suite.Len(allStructureTypes, 1)
+	suite.Equal("public.address", allStructureTypes["Address"].GetSyntax())
+}
+
+func (suite *CompileStructuresTestSuite) getTypedTableCompileConfig() compile.MorpheCompileConfig {
+	config := suite.getCompileConfig()
+	config.MorpheStructuresConfig = cfg.MorpheStructuresConfig{
+		Schema:            "public",
+		EnablePersistence: true,
+		Strategy:          cfg.StructureStrategyTypedTable,
+	}
+	return config
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLTypedTable() {
+	config := suite.getTypedTableCompileConfig()
+
+	structure0 := yaml.Structure{
+		Name: "Address",
+		Fields: map[string]yaml.StructureField{
+			"Street": {
+				Type:       yaml.StructureFieldTypeString,
+				Attributes: []string{"mandatory"},
+			},
+			"HouseNr": {
+				Type: yaml.StructureFieldTypeInteger,
+			},
+			"Country": {
+				Type: "Country",
+			},
+		},
+	}
+
+	structureTable, structureErr := compile.MorpheStructureToPSQLTypedTable(config, suite.getRegistry(), structure0)
+
+	suite.Nil(structureErr)
+	suite.Equal(&psqldef.Table{
+		Schema: "public",
+		Name:   "addresses",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "country_id", Type: psqldef.PSQLTypeInteger, NotNull: true},
+			{Name: "house_nr", Type: psqldef.PSQLTypeInteger},
+			{Name: "street", Type: psqldef.PSQLTypeText, NotNull: true},
+			{Name: "created_at", Type: psqldef.PSQLTypeTimestampTZ, Default: "NOW()"},
+			{Name: "updated_at", Type: psqldef.PSQLTypeTimestampTZ, Default: "NOW()"},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{
+				Schema:         "public",
+				Name:           "fk_addresses_country_id",
+				TableName:      "addresses",
+				ColumnNames:    []string{"country_id"},
+				RefSchema:      "public",
+				RefTableName:   "countries",
+				RefColumnNames: []string{"id"},
+				OnDelete:       "CASCADE",
+			},
+		},
+		Indices: []psqldef.Index{
+			{Name: "idx_addresses_country_id", TableName: "addresses", Columns: []string{"country_id"}},
+		},
+		UniqueConstraints: []psqldef.UniqueConstraint{},
+		Triggers:          []psqldef.Trigger{},
+	}, structureTable)
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLTypedTable_IDField() {
+	config := suite.getTypedTableCompileConfig()
+	config.MorpheStructuresConfig.UseBigSerial = true
+
+	structure0 := yaml.Structure{
+		Name: "Tag",
+		Fields: map[string]yaml.StructureField{
+			"ID": {
+				Type:       yaml.StructureFieldTypeUUID,
+				Attributes: []string{"immutable"},
+			},
+			"Label": {
+				Type: yaml.StructureFieldTypeString,
+			},
+		},
+	}
+
+	structureTable, structureErr := compile.MorpheStructureToPSQLTypedTable(config, suite.getRegistry(), structure0)
+
+	suite.Nil(structureErr)
+	suite.Equal("tags", structureTable.Name)
+	suite.Equal([]psqldef.TableColumn{
+		{Name: "id", Type: psqldef.PSQLTypeUUID, PrimaryKey: true},
+		{Name: "label", Type: psqldef.PSQLTypeText},
+		{Name: "created_at", Type: psqldef.PSQLTypeTimestampTZ, Default: "NOW()"},
+		{Name: "updated_at", Type: psqldef.PSQLTypeTimestampTZ, Default: "NOW()"},
+	}, structureTable.Columns)
+	suite.Len(structureTable.Triggers, 1)
+	suite.Equal("trg_tags_immutable", structureTable.Triggers[0].Name)
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLTypedTable_IDFieldClash() {
+	config := suite.getTypedTableCompileConfig()
+
+	structure0 := yaml.Structure{
+		Name: "Tag",
+		Fields: map[string]yaml.StructureField{
+			"ID": {
+				Type: yaml.StructureFieldTypeString,
+			},
+			"Label": {
+				Type: yaml.StructureFieldTypeString,
+			},
+		},
+	}
+
+	structureTable, structureErr := compile.MorpheStructureToPSQLTypedTable(config, suite.getRegistry(), structure0)
+
+	suite.Nil(structureTable)
+	suite.EqualError(structureErr, compile.ErrStructureIDColumnClash("Tag").Error())
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLTypedTable_SuccessHook() {
+	config := suite.getTypedTableCompileConfig()
+	config.StructureHooks = hook.CompileMorpheStructure{
+		OnCompileMorpheStructureSuccess: func(structureTable *psqldef.Table) (*psqldef.Table, error) {
+			structureTable.Name = "postal_addresses"
+			return structureTable, nil
+		},
+	}
+
+	structureTable, structureErr := compile.MorpheStructureToPSQLTypedTable(config, suite.getRegistry(), suite.getAddressStructure())
+
+	suite.Nil(structureErr)
+	suite.Equal("postal_addresses", structureTable.Name)
+}
+
+func (suite *CompileStructuresTestSuite) TestAllMorpheStructuresToPSQLTables() {
+	config := suite.getTypedTableCompileConfig()
+
+	allStructureTables, allStructuresErr := compile.AllMorpheStructuresToPSQLTables(config, suite.getRegistry())
+
+	suite.Nil(allStructuresErr)
+	suite.Len(allStructureTables, 1)
+	suite.Equal("addresses", allStructureTables["Address"].Name)
+	suite.Equal([]psqldef.TableColumn{
+		{Name: "country_id", Type: psqldef.PSQLTypeInteger, NotNull: true},
+		{Name: "house_nr", Type: psqldef.PSQLTypeInteger},
+		{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+		{Name: "street", Type: psqldef.PSQLTypeText},
+		{Name: "created_at", Type: psqldef.PSQLTypeTimestampTZ, Default: "NOW()"},
+		{Name: "updated_at", Type: psqldef.PSQLTypeTimestampTZ, Default: "NOW()"},
+	}, allStructureTables["Address"].Columns)
+}
+
+func (suite *CompileStructuresTestSuite) getJSONTableCompileConfig() compile.MorpheCompileConfig {
+	config := suite.getCompileConfig()
+	config.MorpheStructuresConfig.Strategy = cfg.StructureStrategyJSONTable
+	config.MorpheStructuresConfig.EnablePersistence = true
+	return config
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLTable_CheckConstraints() {
+	config := suite.getJSONTableCompileConfig()
+	r := suite.getRegistry()
+	r.SetStructure("Tag", yaml.Structure{
+		Name: "Tag",
+		Fields: map[string]yaml.StructureField{
+			"Label": {
+				Type:       yaml.StructureFieldTypeString,
+				Attributes: []string{"mandatory"},
+			},
+			"Active": {
+				Type: yaml.StructureFieldTypeBoolean,
+			},
+		},
+	})
+
+	structureTable, structureErr := compile.MorpheStructureToPSQLTable(config, r)
+
+	suite.Nil(structureErr)
+	suite.Equal([]psqldef.CheckConstraint{
+		{
+			Schema:    "public",
+			Name:      "chk_morphe_structures_address",
+			TableName: "morphe_structures",
+			Expression: `"type" <> 'Address' OR ("data" ?& ARRAY['Country', 'HouseNr', 'ID', 'Street']` +
+				` AND jsonb_typeof("data"->'Country') IN ('string', 'null')` +
+				` AND jsonb_typeof("data"->'HouseNr') IN ('number', 'null')` +
+				` AND jsonb_typeof("data"->'ID') IN ('number', 'null')` +
+				` AND jsonb_typeof("data"->'Street') IN ('string', 'null'))`,
+		},
+		{
+			Schema:    "public",
+			Name:      "chk_morphe_structures_tag",
+			TableName: "morphe_structures",
+			Expression: `"type" <> 'Tag' OR ("data" ?& ARRAY['Active', 'Label']` +
+				` AND jsonb_typeof("data"->'Active') IN ('boolean', 'null')` +
+				` AND jsonb_typeof("data"->'Label') IN ('string'))`,
+		},
+	}, structureTable.CheckConstraints)
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLTable_NoRegistry() {
+	config := suite.getJSONTableCompileConfig()
+
+	structureTable, structureErr := compile.MorpheStructureToPSQLTable(config, nil)
+
+	suite.Nil(structureErr)
+	suite.Equal("morphe_structures", structureTable.Name)
+	suite.Empty(structureTable.CheckConstraints)
+}
+
+func (suite *CompileStructuresTestSuite) TestMorpheStructureToPSQLTable_UnknownFieldType() {
+	config := suite.getJSONTableCompileConfig()
+	r := registry.NewRegistry()
+	r.SetStructure("Address", suite.getAddressStructure())
+
+	structureTable, structureErr := compile.MorpheStructureToPSQLTable(config, r)
+
+	suite.Nil(structureTable)
+	suite.ErrorContains(structureErr, "Country")
+}
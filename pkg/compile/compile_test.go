@@ -12,6 +12,8 @@ import (
 	"github.com/kalo-build/plugin-morphe-psql-types/internal/testutils"
 	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
 	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/hook"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
 )
 
 type CompileTestSuite struct {
@@ -157,3 +159,475 @@ func (suite *CompileTestSuite) TestMorpheToPSQL() {
 	suite.FileExists(entityPath1)
 	suite.FileEquals(entityPath1, gtEntityPath1)
 }
+
+func (suite *CompileTestSuite) TestMorpheToPSQL_NativeEnums() {
+	workingDirPath := suite.TestDirPath + "/working"
+	suite.Nil(os.Mkdir(workingDirPath, 0644))
+	defer os.RemoveAll(workingDirPath)
+
+	gtNativeEnumsDirPath := filepath.Join(suite.TestDirPath, "ground-truth", "compile-native-enums")
+
+	config := compile.MorpheCompileConfig{
+		MorpheLoadRegistryConfig: rcfg.MorpheLoadRegistryConfig{
+			RegistryEnumsDirPath:      suite.EnumsDirPath,
+			RegistryStructuresDirPath: suite.StructuresDirPath,
+			RegistryModelsDirPath:     suite.ModelsDirPath,
+			RegistryEntitiesDirPath:   suite.EntitiesDirPath,
+		},
+		MorpheConfig: cfg.MorpheConfig{
+			MorpheModelsConfig: cfg.MorpheModelsConfig{
+				Schema: "public",
+			},
+			MorpheStructuresConfig: cfg.MorpheStructuresConfig{
+				Schema: "public",
+			},
+			MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+				Schema:   "public",
+				Strategy: cfg.EnumStrategyNativeEnum,
+			},
+			MorpheEntitiesConfig: cfg.MorpheEntitiesConfig{
+				Schema:         "public",
+				ViewNameSuffix: "_entities",
+			},
+		},
+
+		ModelWriter: &compile.MorpheTableFileWriter{
+			Type:          compile.MorpheTableTypeModels,
+			TargetDirPath: workingDirPath + "/models",
+		},
+
+		EnumTypeWriter: &compile.MorpheTypeFileWriter{
+			TargetDirPath: workingDirPath + "/enums",
+		},
+
+		EntityWriter: &compile.MorpheViewFileWriter{
+			TargetDirPath: workingDirPath + "/entities",
+		},
+	}
+
+	compileErr := compile.MorpheToPSQL(config)
+
+	suite.NoError(compileErr)
+
+	modelsDirPath := workingDirPath + "/models"
+	gtModelsDirPath := gtNativeEnumsDirPath + "/models"
+	suite.DirExists(modelsDirPath)
+
+	modelPath0 := modelsDirPath + "/people.sql"
+	gtModelPath0 := gtModelsDirPath + "/people.sql"
+	suite.FileExists(modelPath0)
+	suite.FileEquals(modelPath0, gtModelPath0)
+
+	enumsDirPath := workingDirPath + "/enums"
+	gtEnumsDirPath := gtNativeEnumsDirPath + "/enums"
+	suite.DirExists(enumsDirPath)
+
+	enumPath0 := enumsDirPath + "/nationality.sql"
+	gtEnumPath0 := gtEnumsDirPath + "/nationality.sql"
+	suite.FileExists(enumPath0)
+	suite.FileEquals(enumPath0, gtEnumPath0)
+
+	enumPath1 := enumsDirPath + "/universal_number.sql"
+	gtEnumPath1 := gtEnumsDirPath + "/universal_number.sql"
+	suite.FileExists(enumPath1)
+	suite.FileEquals(enumPath1, gtEnumPath1)
+}
+
+func (suite *CompileTestSuite) TestMorpheToPSQL_NativeEnums_NoEnumTypeWriter() {
+	config := compile.MorpheCompileConfig{
+		MorpheLoadRegistryConfig: rcfg.MorpheLoadRegistryConfig{
+			RegistryEnumsDirPath:      suite.EnumsDirPath,
+			RegistryStructuresDirPath: suite.StructuresDirPath,
+			RegistryModelsDirPath:     suite.ModelsDirPath,
+			RegistryEntitiesDirPath:   suite.EntitiesDirPath,
+		},
+		MorpheConfig: cfg.MorpheConfig{
+			MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+				Schema:   "public",
+				Strategy: cfg.EnumStrategyNativeEnum,
+			},
+		},
+	}
+
+	compileErr := compile.MorpheToPSQL(config)
+
+	suite.ErrorIs(compileErr, compile.ErrNoEnumTypeWriter)
+}
+
+func (suite *CompileTestSuite) TestMorpheToPSQL_CompositeStructures() {
+	workingDirPath := suite.TestDirPath + "/working"
+	suite.Nil(os.Mkdir(workingDirPath, 0644))
+	defer os.RemoveAll(workingDirPath)
+
+	gtCompositeStructuresDirPath := filepath.Join(suite.TestDirPath, "ground-truth", "compile-composite-structures")
+
+	config := compile.MorpheCompileConfig{
+		MorpheLoadRegistryConfig: rcfg.MorpheLoadRegistryConfig{
+			RegistryEnumsDirPath:      suite.EnumsDirPath,
+			RegistryStructuresDirPath: suite.StructuresDirPath,
+			RegistryModelsDirPath:     suite.ModelsDirPath,
+			RegistryEntitiesDirPath:   suite.EntitiesDirPath,
+		},
+		MorpheConfig: cfg.MorpheConfig{
+			MorpheModelsConfig: cfg.MorpheModelsConfig{
+				Schema: "public",
+			},
+			MorpheStructuresConfig: cfg.MorpheStructuresConfig{
+				Schema:   "public",
+				Strategy: cfg.StructureStrategyCompositeType,
+			},
+			MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+				Schema: "public",
+			},
+			MorpheEntitiesConfig: cfg.MorpheEntitiesConfig{
+				Schema:         "public",
+				ViewNameSuffix: "_entities",
+			},
+		},
+
+		ModelWriter: &compile.MorpheTableFileWriter{
+			Type:          compile.MorpheTableTypeModels,
+			TargetDirPath: workingDirPath + "/models",
+		},
+
+		EnumWriter: &compile.MorpheTableFileWriter{
+			Type:          compile.MorpheTableTypeEnums,
+			TargetDirPath: workingDirPath + "/enums",
+		},
+
+		StructureTypeWriter: &compile.MorpheTypeFileWriter{
+			TargetDirPath: workingDirPath + "/structures",
+		},
+
+		EntityWriter: &compile.MorpheViewFileWriter{
+			TargetDirPath: workingDirPath + "/entities",
+		},
+	}
+
+	compileErr := compile.MorpheToPSQL(config)
+
+	suite.NoError(compileErr)
+
+	structuresDirPath := workingDirPath + "/structures"
+	gtStructuresDirPath := gtCompositeStructuresDirPath + "/structures"
+	suite.DirExists(structuresDirPath)
+
+	structurePath0 := structuresDirPath + "/address.sql"
+	gtStructurePath0 := gtStructuresDirPath + "/address.sql"
+	suite.FileExists(structurePath0)
+	suite.FileEquals(structurePath0, gtStructurePath0)
+}
+
+func (suite *CompileTestSuite) TestMorpheToPSQL_CompositeStructures_NoStructureTypeWriter() {
+	config := compile.MorpheCompileConfig{
+		MorpheLoadRegistryConfig: rcfg.MorpheLoadRegistryConfig{
+			RegistryEnumsDirPath:      suite.EnumsDirPath,
+			RegistryStructuresDirPath: suite.StructuresDirPath,
+			RegistryModelsDirPath:     suite.ModelsDirPath,
+			RegistryEntitiesDirPath:   suite.EntitiesDirPath,
+		},
+		MorpheConfig: cfg.MorpheConfig{
+			MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+				Schema: "public",
+			},
+			MorpheStructuresConfig: cfg.MorpheStructuresConfig{
+				Schema:   "public",
+				Strategy: cfg.StructureStrategyCompositeType,
+			},
+		},
+	}
+
+	compileErr := compile.MorpheToPSQL(config)
+
+	suite.ErrorIs(compileErr, compile.ErrNoStructureTypeWriter)
+}
+
+func (suite *CompileTestSuite) getCustomTypeModelHooks() hook.CompileMorpheModel {
+	personName := psqldef.PSQLTypeDomain{Schema: "public", Name: "person_name", ValueType: psqldef.PSQLTypeText}
+	shortName := psqldef.PSQLTypeDomain{Schema: "public", Name: "short_name", ValueType: personName}
+	return hook.CompileMorpheModel{
+		OnCompileMorpheModelSuccess: func(allModelTables []*psqldef.Table) ([]*psqldef.Table, error) {
+			for _, modelTable := range allModelTables {
+				for columnIdx, column := range modelTable.Columns {
+					switch column.Name {
+					case "first_name":
+						modelTable.Columns[columnIdx].Type = personName
+					case "last_name":
+						modelTable.Columns[columnIdx].Type = psqldef.PSQLTypeArray{ValueType: shortName}
+					}
+				}
+			}
+			return allModelTables, nil
+		},
+	}
+}
+
+func (suite *CompileTestSuite) TestMorpheToPSQL_CustomTypes() {
+	workingDirPath := suite.TestDirPath + "/working"
+	suite.Nil(os.Mkdir(workingDirPath, 0644))
+	defer os.RemoveAll(workingDirPath)
+
+	gtCustomTypesDirPath := filepath.Join(suite.TestDirPath, "ground-truth", "compile-custom-types")
+
+	config := compile.MorpheCompileConfig{
+		MorpheLoadRegistryConfig: rcfg.MorpheLoadRegistryConfig{
+			RegistryEnumsDirPath:      suite.EnumsDirPath,
+			RegistryStructuresDirPath: suite.StructuresDirPath,
+			RegistryModelsDirPath:     suite.ModelsDirPath,
+			RegistryEntitiesDirPath:   suite.EntitiesDirPath,
+		},
+		MorpheConfig: cfg.MorpheConfig{
+			MorpheModelsConfig: cfg.MorpheModelsConfig{
+				Schema: "public",
+			},
+			MorpheStructuresConfig: cfg.MorpheStructuresConfig{
+				Schema: "public",
+			},
+			MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+				Schema: "public",
+			},
+			MorpheEntitiesConfig: cfg.MorpheEntitiesConfig{
+				Schema:         "public",
+				ViewNameSuffix: "_entities",
+			},
+		},
+
+		ModelWriter: &compile.MorpheTableFileWriter{
+			Type:          compile.MorpheTableTypeModels,
+			TargetDirPath: workingDirPath + "/models",
+		},
+		ModelHooks: suite.getCustomTypeModelHooks(),
+
+		EnumWriter: &compile.MorpheTableFileWriter{
+			Type:          compile.MorpheTableTypeEnums,
+			TargetDirPath: workingDirPath + "/enums",
+		},
+
+		EntityWriter: &compile.MorpheViewFileWriter{
+			TargetDirPath: workingDirPath + "/entities",
+		},
+
+		TypeWriter: &compile.MorpheTypeFileWriter{
+			TargetDirPath: workingDirPath + "/types",
+		},
+	}
+
+	compileErr := compile.MorpheToPSQL(config)
+
+	suite.NoError(compileErr)
+
+	typesDirPath := workingDirPath + "/types"
+	gtTypesDirPath := gtCustomTypesDirPath + "/types"
+	suite.DirExists(typesDirPath)
+
+	typePath0 := typesDirPath + "/person_name.sql"
+	gtTypePath0 := gtTypesDirPath + "/person_name.sql"
+	suite.FileExists(typePath0)
+	suite.FileEquals(typePath0, gtTypePath0)
+
+	typePath1 := typesDirPath + "/short_name.sql"
+	gtTypePath1 := gtTypesDirPath + "/short_name.sql"
+	suite.FileExists(typePath1)
+	suite.FileEquals(typePath1, gtTypePath1)
+
+	modelPath0 := workingDirPath + "/models/people.sql"
+	gtModelPath0 := gtCustomTypesDirPath + "/models/people.sql"
+	suite.FileExists(modelPath0)
+	suite.FileEquals(modelPath0, gtModelPath0)
+}
+
+func (suite *CompileTestSuite) TestMorpheToPSQL_CustomTypes_NoTypeWriter() {
+	workingDirPath := suite.TestDirPath + "/working"
+	suite.Nil(os.Mkdir(workingDirPath, 0644))
+	defer os.RemoveAll(workingDirPath)
+
+	config := compile.MorpheCompileConfig{
+		MorpheLoadRegistryConfig: rcfg.MorpheLoadRegistryConfig{
+			RegistryEnumsDirPath:      suite.EnumsDirPath,
+			RegistryStructuresDirPath: suite.StructuresDirPath,
+			RegistryModelsDirPath:     suite.ModelsDirPath,
+			RegistryEntitiesDirPath:   suite.EntitiesDirPath,
+		},
+		MorpheConfig: cfg.MorpheConfig{
+			MorpheModelsConfig: cfg.MorpheModelsConfig{
+				Schema: "public",
+			},
+			MorpheStructuresConfig: cfg.MorpheStructuresConfig{
+				Schema: "public",
+			},
+			MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+				Schema: "public",
+			},
+			MorpheEntitiesConfig: cfg.MorpheEntitiesConfig{
+				Schema: "public",
+			},
+		},
+
+		ModelWriter: &compile.MorpheTableFileWriter{
+			Type:          compile.MorpheTableTypeModels,
+			TargetDirPath: workingDirPath + "/models",
+		},
+		ModelHooks: suite.getCustomTypeModelHooks(),
+
+		EnumWriter: &compile.MorpheTableFileWriter{
+			Type:          compile.MorpheTableTypeEnums,
+			TargetDirPath: workingDirPath + "/enums",
+		},
+	}
+
+	compileErr := compile.MorpheToPSQL(config)
+
+	suite.ErrorIs(compileErr, compile.ErrNoTypeWriter)
+	suite.NoDirExists(workingDirPath + "/models")
+}
+
+func (suite *CompileTestSuite) TestMorpheToPSQL_TypedStructureTables() {
+	workingDirPath := suite.TestDirPath + "/working"
+	suite.Nil(os.Mkdir(workingDirPath, 0644))
+	defer os.RemoveAll(workingDirPath)
+
+	gtTypedStructuresDirPath := filepath.Join(suite.TestDirPath, "ground-truth", "compile-typed-structures")
+
+	config := compile.MorpheCompileConfig{
+		MorpheLoadRegistryConfig: rcfg.MorpheLoadRegistryConfig{
+			RegistryEnumsDirPath:      suite.EnumsDirPath,
+			RegistryStructuresDirPath: suite.StructuresDirPath,
+			RegistryModelsDirPath:     suite.ModelsDirPath,
+			RegistryEntitiesDirPath:   suite.EntitiesDirPath,
+		},
+		MorpheConfig: cfg.MorpheConfig{
+			MorpheModelsConfig: cfg.MorpheModelsConfig{
+				Schema: "public",
+			},
+			MorpheStructuresConfig: cfg.MorpheStructuresConfig{
+				Schema:            "public",
+				EnablePersistence: true,
+				Strategy:          cfg.StructureStrategyTypedTable,
+			},
+			MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+				Schema: "public",
+			},
+			MorpheEntitiesConfig: cfg.MorpheEntitiesConfig{
+				Schema:         "public",
+				ViewNameSuffix: "_entities",
+			},
+		},
+
+		ModelWriter: &compile.MorpheTableFileWriter{
+			Type:          compile.MorpheTableTypeModels,
+			TargetDirPath: workingDirPath + "/models",
+		},
+
+		EnumWriter: &compile.MorpheTableFileWriter{
+			Type:          compile.MorpheTableTypeEnums,
+			TargetDirPath: workingDirPath + "/enums",
+		},
+
+		StructureWriter: &compile.MorpheTableFileWriter{
+			Type:          compile.MorpheTableTypeStructures,
+			TargetDirPath: workingDirPath + "/structures",
+		},
+
+		EntityWriter: &compile.MorpheViewFileWriter{
+			TargetDirPath: workingDirPath + "/entities",
+		},
+	}
+
+	compileErr := compile.MorpheToPSQL(config)
+
+	suite.NoError(compileErr)
+
+	structuresDirPath := workingDirPath + "/structures"
+	gtStructuresDirPath := gtTypedStructuresDirPath + "/structures"
+	suite.DirExists(structuresDirPath)
+	suite.NoFileExists(structuresDirPath + "/morphe_structures.sql")
+
+	structurePath0 := structuresDirPath + "/addresses.sql"
+	gtStructurePath0 := gtStructuresDirPath + "/addresses.sql"
+	suite.FileExists(structurePath0)
+	suite.FileEquals(structurePath0, gtStructurePath0)
+}
+
+func (suite *CompileTestSuite) TestMorpheToPSQL_FieldAttributes() {
+	workingDirPath := suite.TestDirPath + "/working"
+	suite.Nil(os.Mkdir(workingDirPath, 0644))
+	defer os.RemoveAll(workingDirPath)
+
+	gtFieldAttributesDirPath := filepath.Join(suite.TestDirPath, "ground-truth", "compile-field-attributes")
+	registryDirPath := filepath.Join(suite.TestDirPath, "registry", "field-attributes")
+
+	config := compile.MorpheCompileConfig{
+		MorpheLoadRegistryConfig: rcfg.MorpheLoadRegistryConfig{
+			RegistryEnumsDirPath:      filepath.Join(registryDirPath, "enums"),
+			RegistryStructuresDirPath: filepath.Join(registryDirPath, "structures"),
+			RegistryModelsDirPath:     filepath.Join(registryDirPath, "models"),
+			RegistryEntitiesDirPath:   filepath.Join(registryDirPath, "entities"),
+		},
+		MorpheConfig: cfg.DefaultMorpheConfig(),
+
+		ModelWriter: &compile.MorpheTableFileWriter{
+			Type:          compile.MorpheTableTypeModels,
+			TargetDirPath: workingDirPath + "/models",
+		},
+	}
+
+	compileErr := compile.MorpheToPSQL(config)
+
+	suite.NoError(compileErr)
+
+	modelPath0 := workingDirPath + "/models/contracts.sql"
+	gtModelPath0 := gtFieldAttributesDirPath + "/models/contracts.sql"
+	suite.FileExists(modelPath0)
+	suite.FileEquals(modelPath0, gtModelPath0)
+}
+
+func (suite *CompileTestSuite) TestMorpheToPSQL_SchemaBundle() {
+	workingDirPath := suite.TestDirPath + "/working"
+	suite.Nil(os.Mkdir(workingDirPath, 0644))
+	defer os.RemoveAll(workingDirPath)
+
+	gtBundleDirPath := filepath.Join(suite.TestDirPath, "ground-truth", "compile-bundle")
+
+	bundleWriter := &compile.MorpheSchemaBundleWriter{
+		TargetDirPath: workingDirPath,
+	}
+
+	config := compile.MorpheCompileConfig{
+		MorpheLoadRegistryConfig: rcfg.MorpheLoadRegistryConfig{
+			RegistryEnumsDirPath:      suite.EnumsDirPath,
+			RegistryStructuresDirPath: suite.StructuresDirPath,
+			RegistryModelsDirPath:     suite.ModelsDirPath,
+			RegistryEntitiesDirPath:   suite.EntitiesDirPath,
+		},
+		MorpheConfig: cfg.MorpheConfig{
+			MorpheModelsConfig: cfg.MorpheModelsConfig{
+				Schema: "public",
+			},
+			MorpheStructuresConfig: cfg.MorpheStructuresConfig{
+				Schema:            "public",
+				EnablePersistence: true,
+			},
+			MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+				Schema: "public",
+			},
+			MorpheEntitiesConfig: cfg.MorpheEntitiesConfig{
+				Schema:         "public",
+				ViewNameSuffix: "_entities",
+			},
+		},
+
+		ModelWriter:     bundleWriter,
+		StructureWriter: bundleWriter,
+		EnumWriter:      bundleWriter,
+		EntityWriter:    bundleWriter,
+	}
+
+	compileErr := compile.MorpheToPSQL(config)
+
+	suite.NoError(compileErr)
+
+	bundlePath := workingDirPath + "/schema.sql"
+	gtBundlePath := gtBundleDirPath + "/schema.sql"
+	suite.FileExists(bundlePath)
+	suite.FileEquals(bundlePath, gtBundlePath)
+}
@@ -0,0 +1,36 @@
+package compile
+
+import "github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+
+// CompiledMorpheTypes maps Morphe.Name -> PSQLType.Name -> CompiledType
+type CompiledMorpheTypes map[string]map[string]CompiledType
+
+func (types CompiledMorpheTypes) AddCompiledMorpheType(morpheName string, typeDef psqldef.PSQLType, typeContents []byte) {
+	if types[morpheName] == nil {
+		types[morpheName] = make(map[string]CompiledType)
+	}
+	types[morpheName][typeDef.GetSyntaxLocal()] = CompiledType{
+		Type:         typeDef,
+		TypeContents: typeContents,
+	}
+}
+
+func (types CompiledMorpheTypes) GetAllCompiledMorpheTypes(morpheName string) map[string]CompiledType {
+	morpheTypes, morpheTypesExist := types[morpheName]
+	if !morpheTypesExist {
+		return nil
+	}
+	return morpheTypes
+}
+
+func (types CompiledMorpheTypes) GetCompiledMorpheType(morpheName string, typeName string) CompiledType {
+	morpheTypes, morpheTypesExist := types[morpheName]
+	if !morpheTypesExist {
+		return CompiledType{}
+	}
+	compiledType, compiledTypeExists := morpheTypes[typeName]
+	if !compiledTypeExists {
+		return CompiledType{}
+	}
+	return compiledType
+}
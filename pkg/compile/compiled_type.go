@@ -0,0 +1,8 @@
+package compile
+
+import "github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+
+type CompiledType struct {
+	Type         psqldef.PSQLType
+	TypeContents []byte
+}
@@ -0,0 +1,138 @@
+package compile
+
+import (
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// GetCustomTypesForTables returns all custom types referenced by the columns of the tables, including types
+// referenced through arrays and the types the referenced types themselves depend on, in dependency order
+func GetCustomTypesForTables(tables []*psqldef.Table) ([]psqldef.PSQLType, error) {
+	customTypes := map[string]psqldef.PSQLType{}
+	for _, table := range tables {
+		if table == nil {
+			continue
+		}
+		for _, column := range table.Columns {
+			collectCustomTypes(customTypes, column.Type)
+		}
+	}
+
+	allCustomTypes := []psqldef.PSQLType{}
+	for _, typeName := range core.MapKeysSorted(customTypes) {
+		allCustomTypes = append(allCustomTypes, customTypes[typeName])
+	}
+	return SortCustomTypesByDependencies(allCustomTypes)
+}
+
+// SortCustomTypesByDependencies sorts the custom types so every type follows the types it depends on. Types without
+// dependencies between them are sorted by name. Dependencies on types outside the given types are ignored.
+func SortCustomTypesByDependencies(customTypes []psqldef.PSQLType) ([]psqldef.PSQLType, error) {
+	typesByName := map[string]psqldef.PSQLType{}
+	for _, customType := range customTypes {
+		typesByName[customType.GetSyntax()] = customType
+	}
+
+	sortedTypes := []psqldef.PSQLType{}
+	visitedTypeNames := map[string]bool{}
+	visitingTypeNames := []string{}
+
+	var visitType func(typeName string) error
+	visitType = func(typeName string) error {
+		if visitedTypeNames[typeName] {
+			return nil
+		}
+		for visitingIdx, visitingTypeName := range visitingTypeNames {
+			if visitingTypeName == typeName {
+				return ErrCustomTypeDependencyCycle(visitingTypeNames[visitingIdx:])
+			}
+		}
+		visitingTypeNames = append(visitingTypeNames, typeName)
+
+		for _, dependency := range getCustomTypeDependencies(typesByName[typeName]) {
+			dependencyName := dependency.GetSyntax()
+			if _, exists := typesByName[dependencyName]; !exists {
+				continue
+			}
+			dependencyErr := visitType(dependencyName)
+			if dependencyErr != nil {
+				return dependencyErr
+			}
+		}
+
+		visitingTypeNames = visitingTypeNames[:len(visitingTypeNames)-1]
+		visitedTypeNames[typeName] = true
+		sortedTypes = append(sortedTypes, typesByName[typeName])
+		return nil
+	}
+
+	for _, typeName := range core.MapKeysSorted(typesByName) {
+		visitErr := visitType(typeName)
+		if visitErr != nil {
+			return nil, visitErr
+		}
+	}
+	return sortedTypes, nil
+}
+
+// collectCustomTypes adds the custom type behind the PSQL type and all custom types it depends on
+func collectCustomTypes(customTypes map[string]psqldef.PSQLType, psqlType psqldef.PSQLType) {
+	customType := getCustomType(psqlType)
+	if customType == nil {
+		return
+	}
+	if _, exists := customTypes[customType.GetSyntax()]; exists {
+		return
+	}
+	customTypes[customType.GetSyntax()] = customType
+	for _, dependency := range getCustomTypeDependencies(customType) {
+		collectCustomTypes(customTypes, dependency)
+	}
+}
+
+// getCustomType returns the custom type behind a PSQL type, unwrapping arrays, or nil for primitive types
+func getCustomType(psqlType psqldef.PSQLType) psqldef.PSQLType {
+	switch typeDef := psqlType.(type) {
+	case psqldef.PSQLTypeArray:
+		return getCustomType(typeDef.ValueType)
+	case *psqldef.PSQLTypeArray:
+		return getCustomType(typeDef.ValueType)
+	case nil:
+		return nil
+	}
+	if psqlType.IsPrimitive() {
+		return nil
+	}
+	return psqlType
+}
+
+// getCustomTypeDependencies returns the custom types a custom type is defined in terms of
+func getCustomTypeDependencies(customType psqldef.PSQLType) []psqldef.PSQLType {
+	valueTypes := []psqldef.PSQLType{}
+	switch typeDef := customType.(type) {
+	case psqldef.PSQLTypeDomain:
+		valueTypes = append(valueTypes, typeDef.ValueType)
+	case *psqldef.PSQLTypeDomain:
+		valueTypes = append(valueTypes, typeDef.ValueType)
+	case psqldef.PSQLTypeRange:
+		valueTypes = append(valueTypes, typeDef.ValueType)
+	case *psqldef.PSQLTypeRange:
+		valueTypes = append(valueTypes, typeDef.ValueType)
+	case psqldef.PSQLTypeComposite:
+		for _, fieldName := range core.MapKeysSorted(typeDef.Fields) {
+			valueTypes = append(valueTypes, typeDef.Fields[fieldName])
+		}
+	case *psqldef.PSQLTypeComposite:
+		for _, fieldName := range core.MapKeysSorted(typeDef.Fields) {
+			valueTypes = append(valueTypes, typeDef.Fields[fieldName])
+		}
+	}
+
+	dependencies := []psqldef.PSQLType{}
+	for _, valueType := range valueTypes {
+		if dependency := getCustomType(valueType); dependency != nil {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return dependencies
+}
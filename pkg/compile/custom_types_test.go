@@ -0,0 +1,76 @@
+package compile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+type CustomTypesTestSuite struct {
+	suite.Suite
+}
+
+func TestCustomTypesTestSuite(t *testing.T) {
+	suite.Run(t, new(CustomTypesTestSuite))
+}
+
+func (suite *CustomTypesTestSuite) TestGetCustomTypesForTables() {
+	countryEnum := psqldef.PSQLTypeEnum{Schema: "public", Name: "country", Values: []string{"DE", "US"}}
+	zipCodeDomain := psqldef.PSQLTypeDomain{Schema: "public", Name: "zip_code", ValueType: psqldef.PSQLTypeText}
+	addressComposite := &psqldef.PSQLTypeComposite{
+		Schema: "public",
+		Name:   "address",
+		Fields: map[string]psqldef.PSQLType{
+			"country":  countryEnum,
+			"street":   psqldef.PSQLTypeText,
+			"zip_code": zipCodeDomain,
+		},
+	}
+	priceRange := psqldef.PSQLTypeRange{Schema: "public", Name: "price_range", ValueType: psqldef.PSQLTypeDoublePrecision}
+
+	customTypes, customTypesErr := compile.GetCustomTypesForTables([]*psqldef.Table{
+		{
+			Schema: "public",
+			Name:   "shops",
+			Columns: []psqldef.TableColumn{
+				{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+				{Name: "addresses", Type: psqldef.PSQLTypeArray{ValueType: addressComposite}},
+				{Name: "prices", Type: priceRange},
+			},
+		},
+		nil,
+	})
+
+	suite.Nil(customTypesErr)
+	suite.Equal([]psqldef.PSQLType{
+		countryEnum,
+		zipCodeDomain,
+		addressComposite,
+		priceRange,
+	}, customTypes)
+}
+
+func (suite *CustomTypesTestSuite) TestSortCustomTypesByDependencies() {
+	nameDomain := psqldef.PSQLTypeDomain{Schema: "public", Name: "name", ValueType: psqldef.PSQLTypeText}
+	aliasDomain := psqldef.PSQLTypeDomain{Schema: "public", Name: "alias", ValueType: nameDomain}
+	textEnum := psqldef.PSQLTypeEnum{Schema: "public", Name: "b_enum", Values: []string{"A"}}
+
+	sortedTypes, sortErr := compile.SortCustomTypesByDependencies([]psqldef.PSQLType{nameDomain, textEnum, aliasDomain})
+
+	suite.Nil(sortErr)
+	suite.Equal([]psqldef.PSQLType{nameDomain, aliasDomain, textEnum}, sortedTypes)
+}
+
+func (suite *CustomTypesTestSuite) TestSortCustomTypesByDependencies_Cycle() {
+	first := &psqldef.PSQLTypeComposite{Schema: "public", Name: "first", Fields: map[string]psqldef.PSQLType{}}
+	second := &psqldef.PSQLTypeComposite{Schema: "public", Name: "second", Fields: map[string]psqldef.PSQLType{"first": first}}
+	first.Fields["second"] = second
+
+	sortedTypes, sortErr := compile.SortCustomTypesByDependencies([]psqldef.PSQLType{first, second})
+
+	suite.Nil(sortedTypes)
+	suite.ErrorContains(sortErr, "custom types depend on each other in a cycle: public.first, public.second")
+}
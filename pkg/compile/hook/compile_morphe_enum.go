@@ -14,10 +14,14 @@ type CompileMorpheEnum struct {
 	// Called on successful compilation of an enum
 	OnCompileMorpheEnumSuccess OnCompileMorpheEnumSuccessHook
 
+	// Called on successful compilation of an enum to a native enum type
+	OnCompileMorpheEnumTypeSuccess OnCompileMorpheEnumTypeSuccessHook
+
 	// Called when compilation of an enum fails
 	OnCompileMorpheEnumFailure OnCompileMorpheEnumFailureHook
 }
 
 type OnCompileMorpheEnumStartHook = func(config cfg.MorpheEnumsConfig, enum yaml.Enum) (cfg.MorpheEnumsConfig, yaml.Enum, error)
 type OnCompileMorpheEnumSuccessHook = func(table *psqldef.Table) (*psqldef.Table, error)
+type OnCompileMorpheEnumTypeSuccessHook = func(enumType *psqldef.PSQLTypeEnum) (*psqldef.PSQLTypeEnum, error)
 type OnCompileMorpheEnumFailureHook = func(config cfg.MorpheEnumsConfig, enum yaml.Enum, compileFailure error) error
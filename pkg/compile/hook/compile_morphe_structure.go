@@ -8,9 +8,14 @@ import (
 type CompileMorpheStructure struct {
 	OnCompileMorpheStructureStart   OnCompileMorpheStructureStartHook
 	OnCompileMorpheStructureSuccess OnCompileMorpheStructureSuccessHook
+
+	// Called on successful compilation of a structure to a composite type
+	OnCompileMorpheStructureTypeSuccess OnCompileMorpheStructureTypeSuccessHook
+
 	OnCompileMorpheStructureFailure OnCompileMorpheStructureFailureHook
 }
 
 type OnCompileMorpheStructureStartHook = func(config cfg.MorpheConfig) (cfg.MorpheConfig, error)
 type OnCompileMorpheStructureSuccessHook = func(structureTable *psqldef.Table) (*psqldef.Table, error)
+type OnCompileMorpheStructureTypeSuccessHook = func(structureType *psqldef.PSQLTypeComposite) (*psqldef.PSQLTypeComposite, error)
 type OnCompileMorpheStructureFailureHook = func(config cfg.MorpheConfig, compileFailure error) error
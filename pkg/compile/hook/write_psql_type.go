@@ -0,0 +1,16 @@
+package hook
+
+import (
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/write"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+type WritePSQLType struct {
+	OnWritePSQLTypeStart   OnWritePSQLTypeStartHook
+	OnWritePSQLTypeSuccess OnWritePSQLTypeSuccessHook
+	OnWritePSQLTypeFailure OnWritePSQLTypeFailureHook
+}
+
+type OnWritePSQLTypeStartHook = func(writer write.PSQLTypeWriter, psqlType psqldef.PSQLType) (write.PSQLTypeWriter, psqldef.PSQLType, error)
+type OnWritePSQLTypeSuccessHook = func(psqlType psqldef.PSQLType, typeContents []byte) (psqldef.PSQLType, []byte, error)
+type OnWritePSQLTypeFailureHook = func(writer write.PSQLTypeWriter, psqlType psqldef.PSQLType, failureErr error) error
@@ -0,0 +1,220 @@
+package compile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// IdentifierCollision is an identifier shared by several compiled definitions which PostgreSQL requires to be unique
+// within their scope, e.g. two long model names abbreviated to the same table name
+type IdentifierCollision struct {
+	// Scope is where the identifier must be unique, e.g. "schema 'public'" or "columns of table 'public.people'"
+	Scope      string
+	Identifier string
+	// Definitions describes the definitions sharing the identifier, e.g. "table of model 'Person'"
+	Definitions []string
+	// AbbreviatedIdentifiers holds the full identifiers which were abbreviated to the identifier, if any
+	AbbreviatedIdentifiers []string
+}
+
+func (collision IdentifierCollision) String() string {
+	description := fmt.Sprintf("identifier '%s' in %s is used by %s",
+		collision.Identifier, collision.Scope, strings.Join(collision.Definitions, ", "))
+	if len(collision.AbbreviatedIdentifiers) > 0 {
+		description += fmt.Sprintf(" (abbreviated from '%s')", strings.Join(collision.AbbreviatedIdentifiers, "', '"))
+	}
+	return description
+}
+
+// identifierUse is a definition using an identifier, functions with the same body may share their identifier
+type identifierUse struct {
+	definition string
+	body       string
+}
+
+// identifierOwner is the table or view owning the identifiers of a scope, e.g. the columns of a table
+type identifierOwner struct {
+	scope      string
+	identifier string
+}
+
+// identifierCollisionFinder collects the identifiers of compiled definitions by the scope they must be unique in
+type identifierCollisionFinder struct {
+	usesByScope  map[string]map[string][]identifierUse
+	ownerByScope map[string]identifierOwner
+}
+
+func newIdentifierCollisionFinder() *identifierCollisionFinder {
+	return &identifierCollisionFinder{
+		usesByScope:  map[string]map[string][]identifierUse{},
+		ownerByScope: map[string]identifierOwner{},
+	}
+}
+
+// getIdentifierCollisions returns the identifier collisions among the compiled definitions of a registry, sorted by
+// scope and identifier
+func getIdentifierCollisions(definitions *RegistryDefinitions, names identifierNames) []IdentifierCollision {
+	finder := newIdentifierCollisionFinder()
+	for _, enumName := range core.MapKeysSorted(definitions.EnumTypes) {
+		enumType := definitions.EnumTypes[enumName]
+		finder.addUse(getSchemaScope(enumType.Schema), enumType.Name, getDefinitionDescription("type", "enum", enumName), "")
+	}
+	for _, structureName := range core.MapKeysSorted(definitions.StructureTypes) {
+		structureType := definitions.StructureTypes[structureName]
+		finder.addUse(getSchemaScope(structureType.Schema), structureType.Name, getDefinitionDescription("type", "structure", structureName), "")
+	}
+	for _, enumName := range core.MapKeysSorted(definitions.EnumTables) {
+		finder.addTable(definitions.EnumTables[enumName], "enum", enumName)
+	}
+	for _, modelName := range core.MapKeysSorted(definitions.ModelTables) {
+		for _, table := range definitions.ModelTables[modelName] {
+			finder.addTable(table, "model", modelName)
+		}
+	}
+	for _, structureName := range core.MapKeysSorted(definitions.StructureTables) {
+		finder.addTable(definitions.StructureTables[structureName], "structure", structureName)
+	}
+	if definitions.StructureTable != nil {
+		finder.addTable(definitions.StructureTable, "structures", "")
+	}
+	for _, entityName := range core.MapKeysSorted(definitions.EntityViews) {
+		finder.addView(definitions.EntityViews[entityName], entityName)
+	}
+	return finder.getCollisions(names)
+}
+
+// addTable adds a table and its columns, constraints, indices and triggers.
+//
+// Tables, views, types and indices (including those backing unique constraints) share a namespace per schema, while
+// column, constraint and trigger names only need to be unique per table.
+func (f *identifierCollisionFinder) addTable(table *psqldef.Table, morpheKind string, morpheName string) {
+	if table == nil {
+		return
+	}
+	schemaScope := getSchemaScope(table.Schema)
+	qualifiedTableName := getQualifiedRelationName(table.Schema, table.Name)
+
+	f.addUse(schemaScope, table.Name, getDefinitionDescription("table", morpheKind, morpheName), "")
+	owner := identifierOwner{scope: schemaScope, identifier: strings.Trim(table.Name, "\"")}
+	for _, kind := range []string{"columns", "constraints", "triggers"} {
+		f.ownerByScope[kind+" of table '"+qualifiedTableName+"'"] = owner
+	}
+	for _, column := range table.Columns {
+		f.addUse("columns of table '"+qualifiedTableName+"'", column.Name, getDefinitionDescription("column", morpheKind, morpheName), "")
+	}
+	for _, foreignKey := range table.ForeignKeys {
+		f.addUse("constraints of table '"+qualifiedTableName+"'", foreignKey.Name, getDefinitionDescription("foreign key", morpheKind, morpheName), "")
+	}
+	for _, checkConstraint := range table.CheckConstraints {
+		f.addUse("constraints of table '"+qualifiedTableName+"'", checkConstraint.Name, getDefinitionDescription("check constraint", morpheKind, morpheName), "")
+	}
+	for _, uniqueConstraint := range table.UniqueConstraints {
+		f.addUse(schemaScope, uniqueConstraint.Name, getDefinitionDescription("unique constraint", morpheKind, morpheName), "")
+	}
+	for _, index := range table.Indices {
+		f.addUse(schemaScope, index.Name, getDefinitionDescription("index", morpheKind, morpheName), "")
+	}
+	for _, trigger := range table.Triggers {
+		f.addUse("triggers of table '"+qualifiedTableName+"'", trigger.Name, getDefinitionDescription("trigger", morpheKind, morpheName), "")
+
+		functionSchema := trigger.Schema
+		if functionSchema == "" {
+			functionSchema = table.Schema
+		}
+		functionBody := strings.Join(trigger.FunctionBody, "\n")
+		f.addUse(getFunctionScope(functionSchema), trigger.FunctionName, getDefinitionDescription("function", morpheKind, morpheName), functionBody)
+	}
+}
+
+// addView adds a view and its columns, indices and refresh function
+func (f *identifierCollisionFinder) addView(view *psqldef.View, entityName string) {
+	if view == nil {
+		return
+	}
+	schemaScope := getSchemaScope(view.Schema)
+	qualifiedViewName := getQualifiedRelationName(view.Schema, view.Name)
+
+	f.addUse(schemaScope, view.Name, getDefinitionDescription("view", "entity", entityName), "")
+	f.ownerByScope["columns of view '"+qualifiedViewName+"'"] = identifierOwner{
+		scope:      schemaScope,
+		identifier: strings.Trim(view.Name, "\""),
+	}
+	for _, column := range view.Columns {
+		f.addUse("columns of view '"+qualifiedViewName+"'", column.Name, getDefinitionDescription("column", "entity", entityName), "")
+	}
+	for _, index := range view.Indices {
+		f.addUse(schemaScope, index.Name, getDefinitionDescription("index", "entity", entityName), "")
+	}
+	if view.RefreshFunctionName != "" {
+		f.addUse(getFunctionScope(view.Schema), view.RefreshFunctionName, getDefinitionDescription("function", "entity", entityName), qualifiedViewName)
+	}
+}
+
+// addUse adds a definition using the identifier, definitions without identifier are skipped
+func (f *identifierCollisionFinder) addUse(scope string, identifier string, definition string, body string) {
+	identifier = strings.Trim(identifier, "\"")
+	if identifier == "" {
+		return
+	}
+	if f.usesByScope[scope] == nil {
+		f.usesByScope[scope] = map[string][]identifierUse{}
+	}
+	for _, use := range f.usesByScope[scope][identifier] {
+		if body != "" && use.body == body {
+			return
+		}
+	}
+	f.usesByScope[scope][identifier] = append(f.usesByScope[scope][identifier], identifierUse{
+		definition: definition,
+		body:       body,
+	})
+}
+
+// getCollisions returns the collisions of all scopes. Scopes of colliding tables and views are skipped, as sharing
+// the table or view also shares its columns, which would only repeat the collision.
+func (f *identifierCollisionFinder) getCollisions(names identifierNames) []IdentifierCollision {
+	collisions := []IdentifierCollision{}
+	for _, scope := range core.MapKeysSorted(f.usesByScope) {
+		owner, hasOwner := f.ownerByScope[scope]
+		if hasOwner && len(f.usesByScope[owner.scope][owner.identifier]) > 1 {
+			continue
+		}
+		usesByIdentifier := f.usesByScope[scope]
+		for _, identifier := range core.MapKeysSorted(usesByIdentifier) {
+			uses := usesByIdentifier[identifier]
+			if len(uses) < 2 {
+				continue
+			}
+			definitions := make([]string, len(uses))
+			for useIdx, use := range uses {
+				definitions[useIdx] = use.definition
+			}
+			collisions = append(collisions, IdentifierCollision{
+				Scope:                  scope,
+				Identifier:             identifier,
+				Definitions:            definitions,
+				AbbreviatedIdentifiers: names.getAbbreviatedIdentifiers(identifier),
+			})
+		}
+	}
+	return collisions
+}
+
+func getSchemaScope(schema string) string {
+	return fmt.Sprintf("schema '%s'", schema)
+}
+
+func getFunctionScope(schema string) string {
+	return fmt.Sprintf("functions of schema '%s'", schema)
+}
+
+// getDefinitionDescription describes a compiled definition by the Morphe definition it was compiled from
+func getDefinitionDescription(kind string, morpheKind string, morpheName string) string {
+	if morpheName == "" {
+		return fmt.Sprintf("%s of %s", kind, morpheKind)
+	}
+	return fmt.Sprintf("%s of %s '%s'", kind, morpheKind, morpheName)
+}
@@ -0,0 +1,170 @@
+package compile
+
+import (
+	"github.com/kalo-build/go-util/core"
+)
+
+// identifierNames derives the identifiers of compiled definitions from Morphe names using a naming strategy.
+//
+// Identifiers are abbreviated like AbbreviateIdentifierWithAffixes with the identifier affixes of the strategy, except
+// that the abbreviations of hashed identifiers always carry a hash of the full identifier, which tells apart long
+// identifiers sharing an abbreviation. The zero value names like MorpheNamingStrategy and abbreviates without hashing
+// or recording.
+type identifierNames struct {
+	strategy NamingStrategy
+	// hashedIdentifiers holds the full identifiers whose abbreviation collided with another identifier
+	hashedIdentifiers map[string]bool
+	// abbreviations maps each abbreviated identifier to the full identifiers abbreviated to it, if recording
+	abbreviations map[string]map[string]bool
+}
+
+// newIdentifierNames returns identifier names of the naming strategy recording their abbreviations, hashing the given
+// full identifiers
+func newIdentifierNames(strategy NamingStrategy, hashedIdentifiers map[string]bool) identifierNames {
+	return identifierNames{
+		strategy:          strategy,
+		hashedIdentifiers: hashedIdentifiers,
+		abbreviations:     map[string]map[string]bool{},
+	}
+}
+
+// getAbbreviatedIdentifiers returns the sorted full identifiers which were abbreviated to the given identifier
+func (n identifierNames) getAbbreviatedIdentifiers(abbreviated string) []string {
+	return core.MapKeysSorted(n.abbreviations[abbreviated])
+}
+
+func (n identifierNames) getStrategy() NamingStrategy {
+	if n.strategy == nil {
+		return MorpheNamingStrategy{}
+	}
+	return n.strategy
+}
+
+func (n identifierNames) abbreviate(identifier string, useHash bool) string {
+	affixes := n.getStrategy().GetIdentifierAffixes()
+	abbreviated := AbbreviateIdentifierWithAffixes(identifier, useHash, affixes)
+	if n.hashedIdentifiers[identifier] {
+		_, suffix := getIdentifierAffixes(identifier, affixes)
+		abbreviated = getHashedIdentifier(identifier, abbreviated, suffix)
+	}
+	if n.abbreviations != nil && abbreviated != identifier {
+		if n.abbreviations[abbreviated] == nil {
+			n.abbreviations[abbreviated] = map[string]bool{}
+		}
+		n.abbreviations[abbreviated][identifier] = true
+	}
+	return abbreviated
+}
+
+func (n identifierNames) getTableNameFromModel(modelName string) string {
+	return n.abbreviate(n.getStrategy().GetTableNameFromModel(modelName), false)
+}
+
+func (n identifierNames) getTableNameFromStructure(structureName string) string {
+	return n.abbreviate(n.getStrategy().GetTableNameFromStructure(structureName), false)
+}
+
+func (n identifierNames) getTableNameFromEnum(enumName string) string {
+	return n.abbreviate(n.getStrategy().GetTableNameFromEnum(enumName), false)
+}
+
+func (n identifierNames) getEnumTypeNameFromEnum(enumName string) string {
+	return n.abbreviate(n.getStrategy().GetEnumTypeNameFromEnum(enumName), false)
+}
+
+func (n identifierNames) getCompositeTypeNameFromStructure(structureName string) string {
+	return n.abbreviate(n.getStrategy().GetCompositeTypeNameFromStructure(structureName), false)
+}
+
+// getViewNameFromEntity returns the view name of an entity with the view name suffix appended
+func (n identifierNames) getViewNameFromEntity(entityName string, viewNameSuffix string) string {
+	return n.abbreviate(n.getStrategy().GetViewNameFromEntity(entityName)+viewNameSuffix, false)
+}
+
+func (n identifierNames) getColumnNameFromField(fieldName string) string {
+	return n.abbreviate(n.getStrategy().GetColumnNameFromField(fieldName), false)
+}
+
+// getColumnNamesFromFields returns the column names of the given fields
+func (n identifierNames) getColumnNamesFromFields(fieldNames []string) []string {
+	columnNames := make([]string, len(fieldNames))
+	for fieldIdx, fieldName := range fieldNames {
+		columnNames[fieldIdx] = n.getColumnNameFromField(fieldName)
+	}
+	return columnNames
+}
+
+func (n identifierNames) getForeignKeyColumnName(relatedModelName, relatedFieldName string) string {
+	return n.abbreviate(n.getStrategy().GetForeignKeyColumnName(relatedModelName, relatedFieldName), false)
+}
+
+// getForeignKeyColumnNames returns the names of the columns referencing the given key fields through a relation
+func (n identifierNames) getForeignKeyColumnNames(relationName string, keyFieldNames []string) []string {
+	columnNames := make([]string, len(keyFieldNames))
+	for fieldIdx, keyFieldName := range keyFieldNames {
+		columnNames[fieldIdx] = n.getForeignKeyColumnName(relationName, keyFieldName)
+	}
+	return columnNames
+}
+
+func (n identifierNames) getForeignKeyConstraintName(tableName, columnName string) string {
+	return n.abbreviate(n.getStrategy().GetForeignKeyConstraintName(tableName, columnName), true)
+}
+
+func (n identifierNames) getJunctionTableForeignKeyConstraintName(junctionTableName, modelName, idFieldName string) string {
+	constraintName := n.getStrategy().GetJunctionTableForeignKeyConstraintName(junctionTableName, modelName, idFieldName)
+	return n.abbreviate(constraintName, true)
+}
+
+func (n identifierNames) getIndexName(tableName, columnName string) string {
+	return n.abbreviate(n.getStrategy().GetIndexName(tableName, columnName), true)
+}
+
+func (n identifierNames) getUniqueConstraintName(tableName string, columnNames ...string) string {
+	return n.abbreviate(n.getStrategy().GetUniqueConstraintName(tableName, columnNames...), true)
+}
+
+func (n identifierNames) getImmutableTriggerName(tableName string) string {
+	return n.abbreviate(n.getStrategy().GetImmutableTriggerName(tableName), true)
+}
+
+func (n identifierNames) getImmutableTriggerFunctionName(tableName string) string {
+	return n.abbreviate(n.getStrategy().GetImmutableTriggerFunctionName(tableName), true)
+}
+
+func (n identifierNames) getStructureCheckConstraintName(tableName, structureName string) string {
+	return n.abbreviate(n.getStrategy().GetStructureCheckConstraintName(tableName, structureName), true)
+}
+
+func (n identifierNames) getPolymorphicTypeCheckConstraintName(tableName, typeColumnName string) string {
+	return n.abbreviate(n.getStrategy().GetPolymorphicTypeCheckConstraintName(tableName, typeColumnName), true)
+}
+
+func (n identifierNames) getPolymorphicTriggerName(tableName, relationName string) string {
+	return n.abbreviate(n.getStrategy().GetPolymorphicTriggerName(tableName, relationName), true)
+}
+
+func (n identifierNames) getPolymorphicTriggerFunctionName(tableName, relationName string) string {
+	return n.abbreviate(n.getStrategy().GetPolymorphicTriggerFunctionName(tableName, relationName), true)
+}
+
+func (n identifierNames) getMaterializedViewUniqueIndexName(viewName string, columnNames ...string) string {
+	return n.abbreviate(n.getStrategy().GetMaterializedViewUniqueIndexName(viewName, columnNames...), true)
+}
+
+func (n identifierNames) getMaterializedViewRefreshFunctionName(viewName string) string {
+	return n.abbreviate(n.getStrategy().GetMaterializedViewRefreshFunctionName(viewName), true)
+}
+
+func (n identifierNames) getJunctionTableName(sourceModelName, targetModelName string) string {
+	return n.abbreviate(n.getStrategy().GetJunctionTableName(sourceModelName, targetModelName), false)
+}
+
+func (n identifierNames) getJunctionTableUniqueConstraintName(
+	junctionTableName string,
+	model1Name, model1IdName string,
+	model2Name, model2IdName string,
+) string {
+	constraintName := n.getStrategy().GetJunctionTableUniqueConstraintName(junctionTableName, model1Name, model1IdName, model2Name, model2IdName)
+	return n.abbreviate(constraintName, true)
+}
@@ -0,0 +1,404 @@
+package compile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	rcfg "github.com/kalo-build/morphe-go/pkg/registry/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultCompileConfigFileName is the conventional name of the compile config file
+const DefaultCompileConfigFileName = "morphe-psql.yaml"
+
+// compileConfigFile is the YAML / JSON representation of a MorpheCompileConfig
+type compileConfigFile struct {
+	Registry   compileConfigFilePaths      `yaml:"registry"`
+	Output     compileConfigFilePaths      `yaml:"output"`
+	Models     compileConfigFileModels     `yaml:"models"`
+	Enums      compileConfigFileEnums      `yaml:"enums"`
+	Structures compileConfigFileStructures `yaml:"structures"`
+	Entities   compileConfigFileEntities   `yaml:"entities"`
+
+	IdentifierCollisions string `yaml:"identifier_collisions"`
+	Naming               string `yaml:"naming"`
+}
+
+// compileConfigFilePaths holds a root directory with optional per-kind directory overrides
+type compileConfigFilePaths struct {
+	Path       string `yaml:"path"`
+	Models     string `yaml:"models"`
+	Enums      string `yaml:"enums"`
+	Structures string `yaml:"structures"`
+	Entities   string `yaml:"entities"`
+	Types      string `yaml:"types"`
+}
+
+type compileConfigFileModels struct {
+	Schema                   string                                             `yaml:"schema"`
+	UseBigSerial             bool                                               `yaml:"use_big_serial"`
+	UseCompositeJunctionKeys bool                                               `yaml:"use_composite_junction_keys"`
+	ForeignKeys              compileConfigFileForeignKeys                       `yaml:"foreign_keys"`
+	RelationForeignKeys      map[string]map[string]compileConfigFileForeignKeys `yaml:"relation_foreign_keys"`
+	RelationAliases          map[string]map[string]string                       `yaml:"relation_aliases"`
+	PolymorphicRelations     map[string]map[string][]string                     `yaml:"polymorphic_relations"`
+}
+
+type compileConfigFileEnums struct {
+	Schema       string                       `yaml:"schema"`
+	UseBigSerial bool                         `yaml:"use_big_serial"`
+	Strategy     string                       `yaml:"strategy"`
+	ForeignKeys  compileConfigFileForeignKeys `yaml:"foreign_keys"`
+}
+
+type compileConfigFileForeignKeys struct {
+	OnDelete   string `yaml:"on_delete"`
+	OnUpdate   string `yaml:"on_update"`
+	Deferrable bool   `yaml:"deferrable"`
+}
+
+type compileConfigFileStructures struct {
+	Schema            string `yaml:"schema"`
+	UseBigSerial      bool   `yaml:"use_big_serial"`
+	EnablePersistence bool   `yaml:"enable_persistence"`
+	Strategy          string `yaml:"strategy"`
+}
+
+type compileConfigFileEntities struct {
+	Schema            string                                       `yaml:"schema"`
+	ViewNameSuffix    string                                       `yaml:"view_name_suffix"`
+	MaterializedViews map[string]compileConfigFileMaterializedView `yaml:"materialized_views"`
+}
+
+type compileConfigFileMaterializedView struct {
+	WithNoData        bool     `yaml:"with_no_data"`
+	UniqueIndexFields []string `yaml:"unique_index_fields"`
+}
+
+var yamlErrorLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// LoadMorpheCompileConfig reads a YAML or JSON compile config file into a MorpheCompileConfig.
+//
+// Unset settings fall back to cfg.DefaultMorpheConfig(), relative paths are resolved against the directory of the
+// config file and the output directories are used for file writers. Errors are reported with the file path and,
+// where known, the line of the offending setting.
+func LoadMorpheCompileConfig(filePath string) (MorpheCompileConfig, error) {
+	fileContents, readErr := os.ReadFile(filePath)
+	if readErr != nil {
+		return MorpheCompileConfig{}, readErr
+	}
+
+	configFile := getDefaultCompileConfigFile()
+	decoder := yaml.NewDecoder(bytes.NewReader(fileContents))
+	decoder.KnownFields(true)
+	decodeErr := decoder.Decode(&configFile)
+	if decodeErr != nil && !errors.Is(decodeErr, io.EOF) {
+		return MorpheCompileConfig{}, getYAMLConfigFileError(filePath, decodeErr)
+	}
+
+	rootNode := yaml.Node{}
+	_ = yaml.Unmarshal(fileContents, &rootNode)
+
+	baseDirPath := filepath.Dir(filePath)
+	config := configFile.toCompileConfig(baseDirPath)
+
+	validateErr := validateCompileConfigFile(config, filePath, &rootNode)
+	if validateErr != nil {
+		return MorpheCompileConfig{}, validateErr
+	}
+
+	return config, nil
+}
+
+func getDefaultCompileConfigFile() compileConfigFile {
+	defaultConfig := cfg.DefaultMorpheConfig()
+	return compileConfigFile{
+		Models: compileConfigFileModels{
+			Schema:       defaultConfig.MorpheModelsConfig.Schema,
+			UseBigSerial: defaultConfig.MorpheModelsConfig.UseBigSerial,
+		},
+		Enums: compileConfigFileEnums{
+			Schema:       defaultConfig.MorpheEnumsConfig.Schema,
+			UseBigSerial: defaultConfig.MorpheEnumsConfig.UseBigSerial,
+			Strategy:     string(defaultConfig.MorpheEnumsConfig.Strategy),
+		},
+		Structures: compileConfigFileStructures{
+			Schema:            defaultConfig.MorpheStructuresConfig.Schema,
+			UseBigSerial:      defaultConfig.MorpheStructuresConfig.UseBigSerial,
+			EnablePersistence: defaultConfig.MorpheStructuresConfig.EnablePersistence,
+			Strategy:          string(defaultConfig.MorpheStructuresConfig.Strategy),
+		},
+		Entities: compileConfigFileEntities{
+			Schema:         defaultConfig.MorpheEntitiesConfig.Schema,
+			ViewNameSuffix: defaultConfig.MorpheEntitiesConfig.ViewNameSuffix,
+		},
+		IdentifierCollisions: string(defaultConfig.IdentifierCollisions),
+		Naming:               string(defaultConfig.Naming),
+	}
+}
+
+func (f compileConfigFile) toCompileConfig(baseDirPath string) MorpheCompileConfig {
+	config := MorpheCompileConfig{
+		MorpheLoadRegistryConfig: rcfg.MorpheLoadRegistryConfig{
+			RegistryModelsDirPath:     f.Registry.getKindDirPath(baseDirPath, f.Registry.Models, "models"),
+			RegistryEnumsDirPath:      f.Registry.getKindDirPath(baseDirPath, f.Registry.Enums, "enums"),
+			RegistryStructuresDirPath: f.Registry.getKindDirPath(baseDirPath, f.Registry.Structures, "structures"),
+			RegistryEntitiesDirPath:   f.Registry.getKindDirPath(baseDirPath, f.Registry.Entities, "entities"),
+		},
+		MorpheConfig: cfg.MorpheConfig{
+			MorpheModelsConfig: cfg.MorpheModelsConfig{
+				Schema:                   f.Models.Schema,
+				UseBigSerial:             f.Models.UseBigSerial,
+				UseCompositeJunctionKeys: f.Models.UseCompositeJunctionKeys,
+				ForeignKeys:              f.Models.ForeignKeys.toForeignKeyConfig(),
+				RelationAliases:          f.Models.RelationAliases,
+				PolymorphicRelations:     f.Models.PolymorphicRelations,
+			},
+			MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+				Schema:       f.Enums.Schema,
+				UseBigSerial: f.Enums.UseBigSerial,
+				Strategy:     cfg.EnumStrategy(f.Enums.Strategy),
+				ForeignKeys:  f.Enums.ForeignKeys.toForeignKeyConfig(),
+			},
+			MorpheStructuresConfig: cfg.MorpheStructuresConfig{
+				Schema:            f.Structures.Schema,
+				UseBigSerial:      f.Structures.UseBigSerial,
+				EnablePersistence: f.Structures.EnablePersistence,
+				Strategy:          cfg.StructureStrategy(f.Structures.Strategy),
+			},
+			MorpheEntitiesConfig: cfg.MorpheEntitiesConfig{
+				Schema:         f.Entities.Schema,
+				ViewNameSuffix: f.Entities.ViewNameSuffix,
+			},
+			IdentifierCollisions: cfg.IdentifierCollisionStrategy(f.IdentifierCollisions),
+			Naming:               cfg.NamingPreset(f.Naming),
+		},
+	}
+
+	if len(f.Models.RelationForeignKeys) > 0 {
+		config.MorpheModelsConfig.RelationForeignKeys = map[string]map[string]cfg.ForeignKeyConfig{}
+		for modelName, relationForeignKeys := range f.Models.RelationForeignKeys {
+			config.MorpheModelsConfig.RelationForeignKeys[modelName] = map[string]cfg.ForeignKeyConfig{}
+			for relationName, relationForeignKey := range relationForeignKeys {
+				config.MorpheModelsConfig.RelationForeignKeys[modelName][relationName] = relationForeignKey.toForeignKeyConfig()
+			}
+		}
+	}
+
+	if len(f.Entities.MaterializedViews) > 0 {
+		config.MorpheEntitiesConfig.MaterializedViews = map[string]cfg.MaterializedViewConfig{}
+		for entityName, materializedView := range f.Entities.MaterializedViews {
+			config.MorpheEntitiesConfig.MaterializedViews[entityName] = cfg.MaterializedViewConfig{
+				WithNoData:        materializedView.WithNoData,
+				UniqueIndexFields: materializedView.UniqueIndexFields,
+			}
+		}
+	}
+
+	if modelsDirPath := f.Output.getKindDirPath(baseDirPath, f.Output.Models, "models"); modelsDirPath != "" {
+		config.ModelWriter = &MorpheTableFileWriter{
+			Type:           MorpheTableTypeModels,
+			TargetDirPath:  modelsDirPath,
+			NamingStrategy: config.GetNamingStrategy(),
+		}
+	}
+	if enumsDirPath := f.Output.getKindDirPath(baseDirPath, f.Output.Enums, "enums"); enumsDirPath != "" {
+		config.EnumWriter = &MorpheTableFileWriter{
+			Type:           MorpheTableTypeEnums,
+			TargetDirPath:  enumsDirPath,
+			NamingStrategy: config.GetNamingStrategy(),
+		}
+		config.EnumTypeWriter = &MorpheTypeFileWriter{
+			TargetDirPath: enumsDirPath,
+		}
+	}
+	if structuresDirPath := f.Output.getKindDirPath(baseDirPath, f.Output.Structures, "structures"); structuresDirPath != "" {
+		config.StructureWriter = &MorpheTableFileWriter{
+			Type:           MorpheTableTypeStructures,
+			TargetDirPath:  structuresDirPath,
+			NamingStrategy: config.GetNamingStrategy(),
+		}
+		config.StructureTypeWriter = &MorpheTypeFileWriter{
+			TargetDirPath: structuresDirPath,
+		}
+	}
+	if entitiesDirPath := f.Output.getKindDirPath(baseDirPath, f.Output.Entities, "entities"); entitiesDirPath != "" {
+		config.EntityWriter = &MorpheViewFileWriter{
+			TargetDirPath: entitiesDirPath,
+		}
+	}
+	if typesDirPath := f.Output.getKindDirPath(baseDirPath, f.Output.Types, "types"); typesDirPath != "" {
+		config.TypeWriter = &MorpheTypeFileWriter{
+			TargetDirPath: typesDirPath,
+		}
+	}
+
+	return config
+}
+
+// getKindDirPath returns the per-kind override if set, otherwise the kind's subdirectory of the root path
+func (p compileConfigFilePaths) getKindDirPath(baseDirPath string, kindDirPath string, kindDirName string) string {
+	if kindDirPath != "" {
+		return resolveConfigFilePath(baseDirPath, kindDirPath)
+	}
+	if p.Path == "" {
+		return ""
+	}
+	return filepath.Join(resolveConfigFilePath(baseDirPath, p.Path), kindDirName)
+}
+
+// hasInvalidRelationForeignKeys returns true if the foreign key actions of any relation are unknown
+func hasInvalidRelationForeignKeys(config cfg.MorpheModelsConfig) bool {
+	for _, relationForeignKeys := range config.RelationForeignKeys {
+		for _, relationForeignKey := range relationForeignKeys {
+			if relationForeignKey.Validate() != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasEmptyRelationAliases returns true if any relation alias has no related model
+func hasEmptyRelationAliases(config cfg.MorpheModelsConfig) bool {
+	for _, relationAliases := range config.RelationAliases {
+		for _, relatedModelName := range relationAliases {
+			if relatedModelName == "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// toForeignKeyConfig converts the file's foreign key settings, accepting lowercase referential actions
+func (k compileConfigFileForeignKeys) toForeignKeyConfig() cfg.ForeignKeyConfig {
+	return cfg.ForeignKeyConfig{
+		OnDelete:   cfg.ReferentialAction(strings.ToUpper(k.OnDelete)),
+		OnUpdate:   cfg.ReferentialAction(strings.ToUpper(k.OnUpdate)),
+		Deferrable: k.Deferrable,
+	}
+}
+
+func resolveConfigFilePath(baseDirPath string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDirPath, path)
+}
+
+// validateCompileConfigFile validates each section on its own so errors can point at the section's line
+func validateCompileConfigFile(config MorpheCompileConfig, filePath string, rootNode *yaml.Node) error {
+	registryErr := config.MorpheLoadRegistryConfig.Validate()
+	if registryErr != nil {
+		return ErrCompileConfigFile(filePath, getConfigNodeLine(rootNode, "registry"), registryErr)
+	}
+
+	modelsErr := config.MorpheModelsConfig.Validate()
+	if modelsErr != nil {
+		modelsLine := getConfigNodeLine(rootNode, "models", "polymorphic_relations")
+		if config.MorpheModelsConfig.Schema == "" {
+			modelsLine = getConfigNodeLine(rootNode, "models", "schema")
+		} else if config.MorpheModelsConfig.ForeignKeys.Validate() != nil {
+			modelsLine = getConfigNodeLine(rootNode, "models", "foreign_keys")
+		} else if hasInvalidRelationForeignKeys(config.MorpheModelsConfig) {
+			modelsLine = getConfigNodeLine(rootNode, "models", "relation_foreign_keys")
+		} else if hasEmptyRelationAliases(config.MorpheModelsConfig) {
+			modelsLine = getConfigNodeLine(rootNode, "models", "relation_aliases")
+		}
+		return ErrCompileConfigFile(filePath, modelsLine, modelsErr)
+	}
+
+	enumsErr := config.MorpheEnumsConfig.Validate()
+	if enumsErr != nil {
+		enumsLine := getConfigNodeLine(rootNode, "enums", "strategy")
+		if config.MorpheEnumsConfig.Schema == "" {
+			enumsLine = getConfigNodeLine(rootNode, "enums", "schema")
+		} else if config.MorpheEnumsConfig.ForeignKeys.Validate() != nil {
+			enumsLine = getConfigNodeLine(rootNode, "enums", "foreign_keys")
+		}
+		return ErrCompileConfigFile(filePath, enumsLine, enumsErr)
+	}
+
+	structuresErr := config.MorpheStructuresConfig.Validate()
+	if structuresErr != nil {
+		structuresLine := getConfigNodeLine(rootNode, "structures", "schema")
+		if config.MorpheStructuresConfig.Schema != "" {
+			structuresLine = getConfigNodeLine(rootNode, "structures", "strategy")
+		}
+		return ErrCompileConfigFile(filePath, structuresLine, structuresErr)
+	}
+
+	entitiesErr := config.MorpheEntitiesConfig.Validate()
+	if entitiesErr != nil {
+		return ErrCompileConfigFile(filePath, getConfigNodeLine(rootNode, "entities", "schema"), entitiesErr)
+	}
+
+	identifierCollisionsErr := config.IdentifierCollisions.Validate()
+	if identifierCollisionsErr != nil {
+		return ErrCompileConfigFile(filePath, getConfigNodeLine(rootNode, "identifier_collisions"), identifierCollisionsErr)
+	}
+
+	namingErr := config.Naming.Validate()
+	if namingErr != nil {
+		return ErrCompileConfigFile(filePath, getConfigNodeLine(rootNode, "naming"), namingErr)
+	}
+
+	configErr := config.Validate()
+	if configErr != nil {
+		return ErrCompileConfigFile(filePath, 0, configErr)
+	}
+
+	return nil
+}
+
+// getConfigNodeLine returns the line of the deepest existing key along the path, or 0 if none exists
+func getConfigNodeLine(rootNode *yaml.Node, keyPath ...string) int {
+	node := rootNode
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	line := 0
+	for _, key := range keyPath {
+		if node.Kind != yaml.MappingNode {
+			return line
+		}
+		found := false
+		for contentIdx := 0; contentIdx+1 < len(node.Content); contentIdx += 2 {
+			if node.Content[contentIdx].Value == key {
+				line = node.Content[contentIdx].Line
+				node = node.Content[contentIdx+1]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return line
+		}
+	}
+	return line
+}
+
+func getYAMLConfigFileError(filePath string, decodeErr error) error {
+	errMessage := decodeErr.Error()
+	var typeErr *yaml.TypeError
+	if errors.As(decodeErr, &typeErr) && len(typeErr.Errors) > 0 {
+		errMessage = typeErr.Errors[0]
+	}
+
+	matches := yamlErrorLinePattern.FindStringSubmatch(errMessage)
+	if matches == nil {
+		return ErrCompileConfigFile(filePath, 0, decodeErr)
+	}
+
+	line, _ := strconv.Atoi(matches[1])
+	return ErrCompileConfigFile(filePath, line, errors.New(matches[2]))
+}
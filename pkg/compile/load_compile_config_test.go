@@ -0,0 +1,278 @@
+package compile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/internal/testutils"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+)
+
+type LoadCompileConfigTestSuite struct {
+	suite.Suite
+
+	TestDirPath     string
+	ConfigDirPath   string
+	RegistryDirPath string
+	WorkingDirPath  string
+}
+
+func TestLoadCompileConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(LoadCompileConfigTestSuite))
+}
+
+func (suite *LoadCompileConfigTestSuite) SetupTest() {
+	suite.TestDirPath = testutils.GetTestDirPath()
+	suite.ConfigDirPath = filepath.Join(suite.TestDirPath, "config")
+	suite.RegistryDirPath = filepath.Join(suite.TestDirPath, "registry", "minimal")
+	suite.WorkingDirPath = filepath.Join(suite.TestDirPath, "working")
+}
+
+func (suite *LoadCompileConfigTestSuite) TearDownTest() {
+	suite.TestDirPath = ""
+}
+
+func (suite *LoadCompileConfigTestSuite) writeConfigFile(fileName string, contents string) string {
+	suite.Nil(os.MkdirAll(suite.WorkingDirPath, 0755))
+	filePath := filepath.Join(suite.WorkingDirPath, fileName)
+	suite.Nil(os.WriteFile(filePath, []byte(contents), 0644))
+	return filePath
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_YAML() {
+	config, loadErr := compile.LoadMorpheCompileConfig(filepath.Join(suite.ConfigDirPath, "morphe-psql.yaml"))
+
+	suite.Nil(loadErr)
+
+	suite.Equal(filepath.Join(suite.RegistryDirPath, "models"), config.RegistryModelsDirPath)
+	suite.Equal(filepath.Join(suite.RegistryDirPath, "enums"), config.RegistryEnumsDirPath)
+	suite.Equal(filepath.Join(suite.RegistryDirPath, "structures"), config.RegistryStructuresDirPath)
+	suite.Equal(filepath.Join(suite.RegistryDirPath, "entities"), config.RegistryEntitiesDirPath)
+
+	suite.Equal(cfg.MorpheConfig{
+		MorpheModelsConfig: cfg.MorpheModelsConfig{
+			Schema:       "app",
+			UseBigSerial: true,
+		},
+		MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+			Schema: cfg.DefaultSchema,
+		},
+		MorpheStructuresConfig: cfg.MorpheStructuresConfig{
+			Schema:            cfg.DefaultSchema,
+			EnablePersistence: true,
+		},
+		MorpheEntitiesConfig: cfg.MorpheEntitiesConfig{
+			Schema:         cfg.DefaultSchema,
+			ViewNameSuffix: "_view",
+			MaterializedViews: map[string]cfg.MaterializedViewConfig{
+				"Company": {
+					WithNoData: true,
+				},
+			},
+		},
+	}, config.MorpheConfig)
+
+	modelWriter, isTableFileWriter := config.ModelWriter.(*compile.MorpheTableFileWriter)
+	suite.True(isTableFileWriter)
+	suite.Equal(filepath.Join(suite.WorkingDirPath, "models"), modelWriter.TargetDirPath)
+
+	entityWriter, isViewFileWriter := config.EntityWriter.(*compile.MorpheViewFileWriter)
+	suite.True(isViewFileWriter)
+	suite.Equal(filepath.Join(suite.WorkingDirPath, "views"), entityWriter.TargetDirPath)
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_JSON() {
+	config, loadErr := compile.LoadMorpheCompileConfig(filepath.Join(suite.ConfigDirPath, "morphe-psql.json"))
+
+	suite.Nil(loadErr)
+	suite.Equal("lookup", config.MorpheEnumsConfig.Schema)
+	suite.True(config.MorpheEnumsConfig.IsNativeEnum())
+	suite.Equal(cfg.DefaultSchema, config.MorpheModelsConfig.Schema)
+	suite.Equal("_entities", config.MorpheEntitiesConfig.ViewNameSuffix)
+
+	enumTypeWriter, isTypeFileWriter := config.EnumTypeWriter.(*compile.MorpheTypeFileWriter)
+	suite.True(isTypeFileWriter)
+	suite.Equal(filepath.Join(suite.WorkingDirPath, "enums"), enumTypeWriter.TargetDirPath)
+
+	typeWriter, isTypeWriterFileWriter := config.TypeWriter.(*compile.MorpheTypeFileWriter)
+	suite.True(isTypeWriterFileWriter)
+	suite.Equal(filepath.Join(suite.WorkingDirPath, "types"), typeWriter.TargetDirPath)
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_UnknownSetting() {
+	defer os.RemoveAll(suite.WorkingDirPath)
+
+	filePath := suite.writeConfigFile("morphe-psql.yaml", `registry:
+  path: ../registry/minimal
+models:
+  schema: public
+  use_bigserial: true
+`)
+
+	_, loadErr := compile.LoadMorpheCompileConfig(filePath)
+
+	suite.ErrorContains(loadErr, filePath+":5: field use_bigserial not found")
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_InvalidSyntax() {
+	defer os.RemoveAll(suite.WorkingDirPath)
+
+	filePath := suite.writeConfigFile("morphe-psql.yaml", `registry:
+  path: ../registry/minimal
+models:
+	schema: public
+`)
+
+	_, loadErr := compile.LoadMorpheCompileConfig(filePath)
+
+	suite.ErrorContains(loadErr, filePath+":4: ")
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_UnknownEnumStrategy() {
+	defer os.RemoveAll(suite.WorkingDirPath)
+
+	filePath := suite.writeConfigFile("morphe-psql.yaml", `registry:
+  path: ../registry/minimal
+enums:
+  schema: public
+  strategy: domain
+`)
+
+	_, loadErr := compile.LoadMorpheCompileConfig(filePath)
+
+	suite.EqualError(loadErr, filePath+":5: unknown enum strategy 'domain'")
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_IdentifierCollisions() {
+	defer os.RemoveAll(suite.WorkingDirPath)
+
+	filePath := suite.writeConfigFile("morphe-psql.yaml", `registry:
+  path: ../registry/minimal
+identifier_collisions: hash
+`)
+
+	config, loadErr := compile.LoadMorpheCompileConfig(filePath)
+
+	suite.Nil(loadErr)
+	suite.Equal(cfg.IdentifierCollisionStrategyHash, config.IdentifierCollisions)
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_UnknownIdentifierCollisionStrategy() {
+	defer os.RemoveAll(suite.WorkingDirPath)
+
+	filePath := suite.writeConfigFile("morphe-psql.yaml", `registry:
+  path: ../registry/minimal
+models:
+  schema: public
+identifier_collisions: truncate
+`)
+
+	_, loadErr := compile.LoadMorpheCompileConfig(filePath)
+
+	suite.EqualError(loadErr, filePath+":5: unknown identifier collision strategy 'truncate'")
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_Naming() {
+	defer os.RemoveAll(suite.WorkingDirPath)
+
+	filePath := suite.writeConfigFile("morphe-psql.yaml", `registry:
+  path: ../registry/minimal
+naming: postgres
+`)
+
+	config, loadErr := compile.LoadMorpheCompileConfig(filePath)
+
+	suite.Nil(loadErr)
+	suite.Equal(cfg.NamingPresetPostgres, config.Naming)
+	suite.Equal(compile.PostgresNamingStrategy{}, config.GetNamingStrategy())
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_UnknownNamingPreset() {
+	defer os.RemoveAll(suite.WorkingDirPath)
+
+	filePath := suite.writeConfigFile("morphe-psql.yaml", `registry:
+  path: ../registry/minimal
+naming: django
+`)
+
+	_, loadErr := compile.LoadMorpheCompileConfig(filePath)
+
+	suite.EqualError(loadErr, filePath+":3: unknown naming preset 'django'")
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_Relations() {
+	defer os.RemoveAll(suite.WorkingDirPath)
+
+	filePath := suite.writeConfigFile("morphe-psql.yaml", `registry:
+  path: ../registry/minimal
+models:
+  schema: public
+  foreign_keys:
+    on_delete: restrict
+  relation_foreign_keys:
+    Person:
+      Company:
+        on_delete: set null
+        deferrable: true
+      Manager:
+        on_update: cascade
+  relation_aliases:
+    Person:
+      Manager: Person
+  polymorphic_relations:
+    Comment:
+      Commentable: [Post, Video]
+enums:
+  schema: public
+  foreign_keys:
+    on_update: cascade
+`)
+
+	config, loadErr := compile.LoadMorpheCompileConfig(filePath)
+
+	suite.Nil(loadErr)
+	suite.Equal(cfg.ForeignKeyConfig{OnDelete: cfg.ReferentialActionRestrict}, config.MorpheModelsConfig.ForeignKeys)
+	suite.Equal(cfg.ForeignKeyConfig{
+		OnDelete:   cfg.ReferentialActionSetNull,
+		Deferrable: true,
+	}, config.MorpheModelsConfig.GetRelationForeignKeyConfig("Person", "Company"))
+	suite.Equal(cfg.ForeignKeyConfig{
+		OnDelete: cfg.ReferentialActionRestrict,
+		OnUpdate: cfg.ReferentialActionCascade,
+	}, config.MorpheModelsConfig.GetRelationForeignKeyConfig("Person", "Manager"))
+	suite.Equal(cfg.ForeignKeyConfig{OnDelete: cfg.ReferentialActionRestrict},
+		config.MorpheModelsConfig.GetRelationForeignKeyConfig("Person", "Person"))
+	suite.Equal(cfg.ForeignKeyConfig{OnUpdate: cfg.ReferentialActionCascade}, config.MorpheEnumsConfig.ForeignKeys)
+	suite.Equal("Person", config.MorpheModelsConfig.GetRelatedModelName("Person", "Manager"))
+	suite.Equal([]string{"Post", "Video"}, config.MorpheModelsConfig.GetPolymorphicRelationTargets("Comment", "Commentable"))
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_UnknownReferentialAction() {
+	defer os.RemoveAll(suite.WorkingDirPath)
+
+	filePath := suite.writeConfigFile("morphe-psql.yaml", `registry:
+  path: ../registry/minimal
+models:
+  schema: public
+  foreign_keys:
+    on_delete: drop
+`)
+
+	_, loadErr := compile.LoadMorpheCompileConfig(filePath)
+
+	suite.EqualError(loadErr, filePath+":5: unknown referential action 'DROP'")
+}
+
+func (suite *LoadCompileConfigTestSuite) TestLoadMorpheCompileConfig_NoRegistry() {
+	defer os.RemoveAll(suite.WorkingDirPath)
+
+	filePath := suite.writeConfigFile("morphe-psql.json", `{"models": {"schema": "public"}}`)
+
+	_, loadErr := compile.LoadMorpheCompileConfig(filePath)
+
+	suite.ErrorContains(loadErr, filePath+": ")
+}
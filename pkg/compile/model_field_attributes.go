@@ -0,0 +1,25 @@
+package compile
+
+import (
+	"slices"
+
+	"github.com/kalo-build/morphe-go/pkg/yaml"
+)
+
+// Morphe field attributes with PostgreSQL semantics
+const (
+	MorpheFieldAttributeMandatory = "mandatory"
+	MorpheFieldAttributeImmutable = "immutable"
+)
+
+func isMandatoryModelField(field yaml.ModelField) bool {
+	return slices.Contains(field.Attributes, MorpheFieldAttributeMandatory)
+}
+
+func isImmutableModelField(field yaml.ModelField) bool {
+	return slices.Contains(field.Attributes, MorpheFieldAttributeImmutable)
+}
+
+func isMandatoryStructureField(field yaml.StructureField) bool {
+	return slices.Contains(field.Attributes, MorpheFieldAttributeMandatory)
+}
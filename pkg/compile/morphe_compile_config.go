@@ -17,17 +17,46 @@ type MorpheCompileConfig struct {
 	ModelWriter write.PSQLTableWriter
 	ModelHooks  hook.CompileMorpheModel
 
-	EnumWriter write.PSQLTableWriter
-	EnumHooks  hook.CompileMorpheEnum
+	EnumWriter     write.PSQLTableWriter
+	EnumTypeWriter write.PSQLTypeWriter
+	EnumHooks      hook.CompileMorpheEnum
 
-	StructureWriter write.PSQLTableWriter
-	StructureHooks  hook.CompileMorpheStructure
+	StructureWriter     write.PSQLTableWriter
+	StructureTypeWriter write.PSQLTypeWriter
+	StructureHooks      hook.CompileMorpheStructure
 
 	EntityWriter write.PSQLViewWriter
 	EntityHooks  hook.CompileMorpheEntity
 
+	// TypeWriter writes the custom types referenced by compiled tables which are not enum or structure types
+	TypeWriter write.PSQLTypeWriter
+
 	WriteTableHooks hook.WritePSQLTable
 	WriteViewHooks  hook.WritePSQLView
+	WriteTypeHooks  hook.WritePSQLType
+
+	// NamingStrategy derives the identifiers of compiled definitions, overriding the Naming preset if set
+	NamingStrategy NamingStrategy
+
+	// identifierNames derives the identifiers of compiled definitions, set when compiling a whole registry
+	identifierNames identifierNames
+}
+
+// GetNamingStrategy returns the configured naming strategy, or that of the Naming preset
+func (config MorpheCompileConfig) GetNamingStrategy() NamingStrategy {
+	if config.NamingStrategy != nil {
+		return config.NamingStrategy
+	}
+	return GetNamingStrategyForPreset(config.Naming)
+}
+
+// getIdentifierNames returns the identifier names set when compiling a whole registry, or those of the naming strategy
+func (config MorpheCompileConfig) getIdentifierNames() identifierNames {
+	names := config.identifierNames
+	if names.strategy == nil {
+		names.strategy = config.GetNamingStrategy()
+	}
+	return names
 }
 
 func (config MorpheCompileConfig) Validate() error {
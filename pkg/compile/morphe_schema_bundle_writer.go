@@ -0,0 +1,469 @@
+package compile
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/sqlfile"
+)
+
+// SchemaBundleFileName is the name of the single file written by the MorpheSchemaBundleWriter
+const SchemaBundleFileName = "schema"
+
+// MorpheSchemaBundleWriter collects all written types, tables and views into a single schema file.
+//
+// The same writer can be used as model, enum, structure and entity writer. Definitions are ordered by their
+// dependencies: types first, then tables after the tables they reference, then views after the relations they
+// select from. Foreign keys that are part of a reference cycle are deferred to trailing ALTER TABLE statements.
+// Writes only collect the definitions, the schema file is written once by Flush after compilation finishes.
+type MorpheSchemaBundleWriter struct {
+	TargetDirPath string
+
+	tableWriter MorpheTableFileWriter
+	viewWriter  MorpheViewFileWriter
+	typeWriter  MorpheTypeFileWriter
+
+	types  map[string]psqldef.PSQLType
+	tables map[string]*psqldef.Table
+	views  map[string]*psqldef.View
+}
+
+func (w *MorpheSchemaBundleWriter) WriteType(typeDefinition psqldef.PSQLType) ([]byte, error) {
+	if typeDefinition == nil {
+		return nil, ErrNoPSQLType
+	}
+	if w.types == nil {
+		w.types = map[string]psqldef.PSQLType{}
+	}
+	w.types[typeDefinition.GetSyntax()] = psqldef.DeepClonePSQLType(typeDefinition)
+
+	typeLines, typeErr := w.typeWriter.GetCreateTypeLines(typeDefinition)
+	if typeErr != nil {
+		return nil, typeErr
+	}
+	return linesToBytes(typeLines)
+}
+
+func (w *MorpheSchemaBundleWriter) WriteTable(tableDefinition *psqldef.Table) ([]byte, error) {
+	if tableDefinition == nil {
+		return nil, ErrNoPSQLTable
+	}
+	if w.tables == nil {
+		w.tables = map[string]*psqldef.Table{}
+	}
+	tableCopy := tableDefinition.DeepClone()
+	w.tables[getQualifiedRelationName(tableCopy.Schema, tableCopy.Name)] = &tableCopy
+
+	tableLines, tableErr := w.getTableLines(&tableCopy)
+	if tableErr != nil {
+		return nil, tableErr
+	}
+	return linesToBytes(tableLines)
+}
+
+func (w *MorpheSchemaBundleWriter) WriteView(viewDefinition *psqldef.View) ([]byte, error) {
+	if viewDefinition == nil {
+		return nil, ErrNoPSQLView
+	}
+	if w.views == nil {
+		w.views = map[string]*psqldef.View{}
+	}
+	viewCopy := viewDefinition.DeepClone()
+	w.views[getQualifiedRelationName(viewCopy.Schema, viewCopy.Name)] = &viewCopy
+
+	viewLines, viewErr := w.viewWriter.GetCreateViewLines(&viewCopy)
+	if viewErr != nil {
+		return nil, viewErr
+	}
+	return linesToBytes(viewLines)
+}
+
+// Flush writes the schema file with all definitions collected so far and returns its contents
+func (w *MorpheSchemaBundleWriter) Flush() ([]byte, error) {
+	allBundleLines, allLinesErr := w.GetAllBundleLines()
+	if allLinesErr != nil {
+		return nil, allLinesErr
+	}
+
+	bundleFileContents, bundleContentsErr := core.LinesToString(allBundleLines)
+	if bundleContentsErr != nil {
+		return nil, bundleContentsErr
+	}
+
+	return sqlfile.WriteSQLDefinitionFile(w.TargetDirPath, SchemaBundleFileName, bundleFileContents)
+}
+
+// GetAllBundleLines returns the dependency-ordered lines of all definitions collected so far
+func (w *MorpheSchemaBundleWriter) GetAllBundleLines() ([]string, error) {
+	orderedTypes, typesErr := w.getOrderedTypes()
+	if typesErr != nil {
+		return nil, typesErr
+	}
+	orderedTables, deferredForeignKeys := w.getOrderedTables()
+	orderedViews, viewsErr := w.getOrderedViews()
+	if viewsErr != nil {
+		return nil, viewsErr
+	}
+
+	allBundleLines := []string{
+		"-- Schema bundle generated from Morphe definitions",
+		"",
+	}
+
+	// Create all schemas up front
+	schemas := map[string]bool{}
+	for _, typeDefinition := range w.types {
+		schemas[typeDefinition.GetSchema()] = true
+	}
+	for _, tableDefinition := range w.tables {
+		schemas[tableDefinition.Schema] = true
+	}
+	for _, viewDefinition := range w.views {
+		schemas[viewDefinition.Schema] = true
+	}
+	delete(schemas, "")
+	for _, schema := range core.MapKeysSorted(schemas) {
+		allBundleLines = append(allBundleLines, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;", schema))
+	}
+	if len(schemas) > 0 {
+		allBundleLines = append(allBundleLines, "")
+	}
+
+	for _, typeDefinition := range orderedTypes {
+		typeLines, typeErr := w.typeWriter.GetCreateTypeLines(typeDefinition)
+		if typeErr != nil {
+			return nil, typeErr
+		}
+		allBundleLines = append(allBundleLines, fmt.Sprintf("-- Type definition for %s", typeDefinition.GetSyntaxLocal()))
+		allBundleLines = append(allBundleLines, typeLines...)
+		allBundleLines = append(allBundleLines, "")
+	}
+
+	for _, tableDefinition := range orderedTables {
+		tableLines, tableErr := w.getTableLines(tableDefinition)
+		if tableErr != nil {
+			return nil, tableErr
+		}
+		allBundleLines = append(allBundleLines, tableLines...)
+	}
+
+	for _, viewDefinition := range orderedViews {
+		viewLines, viewErr := w.viewWriter.GetCreateViewLines(viewDefinition)
+		if viewErr != nil {
+			return nil, viewErr
+		}
+		allBundleLines = append(allBundleLines, fmt.Sprintf("-- View definition for %s", viewDefinition.Name))
+		allBundleLines = append(allBundleLines, viewLines...)
+		allBundleLines = append(allBundleLines, "")
+		allBundleLines = append(allBundleLines, w.viewWriter.GetMaterializedViewObjectLines(viewDefinition)...)
+	}
+
+	if len(deferredForeignKeys) > 0 {
+		allBundleLines = append(allBundleLines, "-- Deferred foreign keys")
+		for _, deferredForeignKey := range deferredForeignKeys {
+			allBundleLines = append(allBundleLines, w.getDeferredForeignKeyLines(deferredForeignKey)...)
+		}
+		allBundleLines = append(allBundleLines, "")
+	}
+
+	return allBundleLines, nil
+}
+
+func (w *MorpheSchemaBundleWriter) getTableLines(tableDefinition *psqldef.Table) ([]string, error) {
+	tableLines := []string{
+		fmt.Sprintf("-- Table definition for %s", tableDefinition.Name),
+	}
+
+	createTableLines, createErr := w.tableWriter.GetCreateTableLines(tableDefinition)
+	if createErr != nil {
+		return nil, createErr
+	}
+	tableLines = append(tableLines, createTableLines...)
+	tableLines = append(tableLines, "")
+
+	if len(tableDefinition.Indices) > 0 {
+		indexLines, indexErr := w.tableWriter.GetIndexLines(tableDefinition)
+		if indexErr != nil {
+			return nil, indexErr
+		}
+		tableLines = append(tableLines, indexLines...)
+		tableLines = append(tableLines, "")
+	}
+
+	if len(tableDefinition.Triggers) > 0 {
+		triggerLines, triggerErr := w.tableWriter.GetTriggerLines(tableDefinition)
+		if triggerErr != nil {
+			return nil, triggerErr
+		}
+		tableLines = append(tableLines, triggerLines...)
+		tableLines = append(tableLines, "")
+	}
+
+	if len(tableDefinition.SeedData) > 0 {
+		seedDataLines, seedErr := w.tableWriter.GetSeedDataLines(tableDefinition)
+		if seedErr != nil {
+			return nil, seedErr
+		}
+		tableLines = append(tableLines, seedDataLines...)
+		tableLines = append(tableLines, "")
+	}
+
+	return tableLines, nil
+}
+
+type deferredForeignKey struct {
+	table      *psqldef.Table
+	foreignKey psqldef.ForeignKey
+}
+
+// getDeferredForeignKeyLines adds the foreign key only if the constraint does not exist yet, as ALTER TABLE ... ADD
+// CONSTRAINT has no IF NOT EXISTS and the bundle must be re-runnable like its CREATE ... IF NOT EXISTS statements
+func (w *MorpheSchemaBundleWriter) getDeferredForeignKeyLines(deferredForeignKey deferredForeignKey) []string {
+	tableName := getQualifiedRelationName(deferredForeignKey.table.Schema, deferredForeignKey.table.Name)
+	constraintName := deferredForeignKey.foreignKey.Name
+	if constraintName == "" {
+		// PostgreSQL's default name for unnamed foreign key constraints
+		constraintName = fmt.Sprintf("%s_%s_fkey", deferredForeignKey.table.Name, strings.Join(deferredForeignKey.foreignKey.ColumnNames, "_"))
+	}
+
+	foreignKeyLines := w.tableWriter.GetForeignKeyConstraintLines(deferredForeignKey.foreignKey)
+	alterLines := []string{fmt.Sprintf("\t\tALTER TABLE %s ADD %s", tableName, foreignKeyLines[0])}
+	for _, foreignKeyLine := range foreignKeyLines[1:] {
+		alterLines = append(alterLines, "\t\t"+foreignKeyLine)
+	}
+	alterLines[len(alterLines)-1] += ";"
+
+	deferredLines := []string{
+		"DO $$",
+		"BEGIN",
+		fmt.Sprintf("\tIF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = '%s' AND conrelid = '%s'::regclass) THEN",
+			constraintName, tableName),
+	}
+	deferredLines = append(deferredLines, alterLines...)
+	return append(deferredLines, "\tEND IF;", "END $$;")
+}
+
+// getOrderedTypes sorts the types so every type follows the types it is defined in terms of
+func (w *MorpheSchemaBundleWriter) getOrderedTypes() ([]psqldef.PSQLType, error) {
+	allTypes := []psqldef.PSQLType{}
+	for _, typeName := range core.MapKeysSorted(w.types) {
+		allTypes = append(allTypes, w.types[typeName])
+	}
+	return SortCustomTypesByDependencies(allTypes)
+}
+
+// getOrderedTables sorts the tables so every table follows the tables it references. When every remaining table
+// waits on another one, the first table (by name) of a reference cycle nothing else is waited on from is emitted
+// with its foreign keys within that cycle deferred, which breaks the cycle.
+func (w *MorpheSchemaBundleWriter) getOrderedTables() ([]*psqldef.Table, []deferredForeignKey) {
+	orderedTables := []*psqldef.Table{}
+	deferredForeignKeys := []deferredForeignKey{}
+
+	pendingTables := map[string]*psqldef.Table{}
+	for tableName, tableDefinition := range w.tables {
+		pendingTables[tableName] = tableDefinition
+	}
+
+	for len(pendingTables) > 0 {
+		readyTableName := ""
+		for _, tableName := range core.MapKeysSorted(pendingTables) {
+			if len(w.getPendingForeignKeys(pendingTables, tableName)) == 0 {
+				readyTableName = tableName
+				break
+			}
+		}
+
+		if readyTableName != "" {
+			orderedTables = append(orderedTables, pendingTables[readyTableName])
+			delete(pendingTables, readyTableName)
+			continue
+		}
+
+		// All remaining tables are part of or depend on a cycle
+		cycleTableName := w.getSinkReferenceCycle(pendingTables)[0]
+		cycleTable := pendingTables[cycleTableName].DeepClone()
+		pendingForeignKeys := w.getPendingForeignKeys(pendingTables, cycleTableName)
+
+		inlineForeignKeys := []psqldef.ForeignKey{}
+		for _, foreignKey := range cycleTable.ForeignKeys {
+			if pendingForeignKeys[foreignKey.Name+"|"+strings.Join(foreignKey.ColumnNames, ",")] {
+				deferredForeignKeys = append(deferredForeignKeys, deferredForeignKey{
+					table:      &cycleTable,
+					foreignKey: foreignKey,
+				})
+				continue
+			}
+			inlineForeignKeys = append(inlineForeignKeys, foreignKey)
+		}
+		cycleTable.ForeignKeys = inlineForeignKeys
+
+		orderedTables = append(orderedTables, &cycleTable)
+		delete(pendingTables, cycleTableName)
+	}
+
+	return orderedTables, deferredForeignKeys
+}
+
+// getPendingForeignKeys returns the foreign keys of the table that reference other still pending tables
+func (w *MorpheSchemaBundleWriter) getPendingForeignKeys(pendingTables map[string]*psqldef.Table, tableName string) map[string]bool {
+	tableDefinition := pendingTables[tableName]
+	pendingForeignKeys := map[string]bool{}
+	for _, foreignKey := range tableDefinition.ForeignKeys {
+		refSchema := foreignKey.RefSchema
+		if refSchema == "" {
+			refSchema = tableDefinition.Schema
+		}
+		refTableName := getQualifiedRelationRef(refSchema, foreignKey.RefTableName)
+		if refTableName == tableName {
+			continue
+		}
+		if _, isPending := pendingTables[refTableName]; isPending {
+			pendingForeignKeys[foreignKey.Name+"|"+strings.Join(foreignKey.ColumnNames, ",")] = true
+		}
+	}
+	return pendingForeignKeys
+}
+
+// getPendingReferencedTableNames returns the sorted names of the other still pending tables the table references
+func (w *MorpheSchemaBundleWriter) getPendingReferencedTableNames(pendingTables map[string]*psqldef.Table, tableName string) []string {
+	tableDefinition := pendingTables[tableName]
+	referencedTableNames := map[string]bool{}
+	for _, foreignKey := range tableDefinition.ForeignKeys {
+		refSchema := foreignKey.RefSchema
+		if refSchema == "" {
+			refSchema = tableDefinition.Schema
+		}
+		refTableName := getQualifiedRelationRef(refSchema, foreignKey.RefTableName)
+		if refTableName == tableName {
+			continue
+		}
+		if _, isPending := pendingTables[refTableName]; isPending {
+			referencedTableNames[refTableName] = true
+		}
+	}
+	return core.MapKeysSorted(referencedTableNames)
+}
+
+// getSinkReferenceCycle returns the sorted names of the tables in a strongly connected component of the pending
+// tables' references that references no other component. The first component completed by Tarjan's algorithm is
+// such a sink, and as every pending table references another one, it is a cycle of at least two tables.
+func (w *MorpheSchemaBundleWriter) getSinkReferenceCycle(pendingTables map[string]*psqldef.Table) []string {
+	nextIndex := 0
+	indices := map[string]int{}
+	lowLinks := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	var sinkCycle []string
+
+	var visit func(tableName string)
+	visit = func(tableName string) {
+		indices[tableName] = nextIndex
+		lowLinks[tableName] = nextIndex
+		nextIndex++
+		stack = append(stack, tableName)
+		onStack[tableName] = true
+
+		for _, refTableName := range w.getPendingReferencedTableNames(pendingTables, tableName) {
+			if sinkCycle != nil {
+				return
+			}
+			if _, visited := indices[refTableName]; !visited {
+				visit(refTableName)
+				lowLinks[tableName] = min(lowLinks[tableName], lowLinks[refTableName])
+			} else if onStack[refTableName] {
+				lowLinks[tableName] = min(lowLinks[tableName], indices[refTableName])
+			}
+		}
+
+		if sinkCycle != nil || lowLinks[tableName] != indices[tableName] {
+			return
+		}
+		rootIdx := slices.Index(stack, tableName)
+		sinkCycle = slices.Clone(stack[rootIdx:])
+		slices.Sort(sinkCycle)
+	}
+
+	for _, tableName := range core.MapKeysSorted(pendingTables) {
+		if sinkCycle != nil {
+			break
+		}
+		if _, visited := indices[tableName]; !visited {
+			visit(tableName)
+		}
+	}
+	return sinkCycle
+}
+
+// getOrderedViews sorts the views so every view follows the views it selects from
+func (w *MorpheSchemaBundleWriter) getOrderedViews() ([]*psqldef.View, error) {
+	orderedViews := []*psqldef.View{}
+
+	pendingViews := map[string]*psqldef.View{}
+	for viewName, viewDefinition := range w.views {
+		pendingViews[viewName] = viewDefinition
+	}
+
+	for len(pendingViews) > 0 {
+		readyViewName := ""
+		for _, viewName := range core.MapKeysSorted(pendingViews) {
+			if !w.hasPendingViewDependency(pendingViews, viewName) {
+				readyViewName = viewName
+				break
+			}
+		}
+
+		if readyViewName == "" {
+			return nil, ErrViewDependencyCycle(core.MapKeysSorted(pendingViews))
+		}
+
+		orderedViews = append(orderedViews, pendingViews[readyViewName])
+		delete(pendingViews, readyViewName)
+	}
+
+	return orderedViews, nil
+}
+
+func (w *MorpheSchemaBundleWriter) hasPendingViewDependency(pendingViews map[string]*psqldef.View, viewName string) bool {
+	viewDefinition := pendingViews[viewName]
+	relationRefs := []string{viewDefinition.FromTable}
+	for _, join := range viewDefinition.Joins {
+		relationRefs = append(relationRefs, join.Table)
+	}
+
+	for _, relationRef := range relationRefs {
+		refViewName := getQualifiedRelationRef(viewDefinition.Schema, relationRef)
+		if refViewName == viewName {
+			continue
+		}
+		if _, isPending := pendingViews[refViewName]; isPending {
+			return true
+		}
+	}
+	return false
+}
+
+func linesToBytes(lines []string) ([]byte, error) {
+	contents, contentsErr := core.LinesToString(lines)
+	if contentsErr != nil {
+		return nil, contentsErr
+	}
+	return []byte(contents), nil
+}
+
+func getQualifiedRelationName(schema string, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
+
+// getQualifiedRelationRef qualifies an unqualified table or view reference with the referencing relation's schema
+func getQualifiedRelationRef(schema string, relationRef string) string {
+	if strings.Contains(relationRef, ".") {
+		return relationRef
+	}
+	return getQualifiedRelationName(schema, relationRef)
+}
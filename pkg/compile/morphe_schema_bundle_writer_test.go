@@ -0,0 +1,591 @@
+package compile_test
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/internal/testutils"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+type MorpheSchemaBundleWriterTestSuite struct {
+	suite.Suite
+
+	TestDirPath string
+}
+
+func TestMorpheSchemaBundleWriterTestSuite(t *testing.T) {
+	suite.Run(t, new(MorpheSchemaBundleWriterTestSuite))
+}
+
+func (suite *MorpheSchemaBundleWriterTestSuite) SetupTest() {
+	suite.TestDirPath = testutils.GetTestDirPath()
+}
+
+func (suite *MorpheSchemaBundleWriterTestSuite) TearDownTest() {
+	suite.TestDirPath = ""
+}
+
+func (suite *MorpheSchemaBundleWriterTestSuite) TestWriteTable_ForeignKeyCycle() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	bundleWriter := &compile.MorpheSchemaBundleWriter{
+		TargetDirPath: workingDirPath,
+	}
+
+	employeesTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "employees",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "department_id", Type: psqldef.PSQLTypeInteger},
+			{Name: "mentor_id", Type: psqldef.PSQLTypeInteger},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{
+				Name:           "fk_employees_department_id",
+				TableName:      "employees",
+				ColumnNames:    []string{"department_id"},
+				RefTableName:   "departments",
+				RefColumnNames: []string{"id"},
+			},
+			{
+				Name:           "fk_employees_mentor_id",
+				TableName:      "employees",
+				ColumnNames:    []string{"mentor_id"},
+				RefTableName:   "employees",
+				RefColumnNames: []string{"id"},
+			},
+		},
+	}
+	departmentsTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "departments",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "head_id", Type: psqldef.PSQLTypeInteger},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{
+				Name:           "fk_departments_head_id",
+				TableName:      "departments",
+				ColumnNames:    []string{"head_id"},
+				RefTableName:   "employees",
+				RefColumnNames: []string{"id"},
+				OnDelete:       "SET NULL",
+			},
+		},
+	}
+
+	_, employeesErr := bundleWriter.WriteTable(employeesTable)
+	suite.Nil(employeesErr)
+	_, departmentsErr := bundleWriter.WriteTable(departmentsTable)
+	suite.Nil(departmentsErr)
+
+	bundleContents, flushErr := bundleWriter.Flush()
+	suite.Nil(flushErr)
+
+	suite.Equal(`-- Schema bundle generated from Morphe definitions
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+-- Table definition for departments
+CREATE TABLE IF NOT EXISTS public.departments (
+	id SERIAL PRIMARY KEY,
+	head_id INTEGER
+);
+
+-- Table definition for employees
+CREATE TABLE IF NOT EXISTS public.employees (
+	id SERIAL PRIMARY KEY,
+	department_id INTEGER,
+	mentor_id INTEGER,
+	CONSTRAINT fk_employees_department_id FOREIGN KEY (department_id)
+		REFERENCES departments(id),
+	CONSTRAINT fk_employees_mentor_id FOREIGN KEY (mentor_id)
+		REFERENCES employees(id)
+);
+
+-- Deferred foreign keys
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_departments_head_id' AND conrelid = 'public.departments'::regclass) THEN
+		ALTER TABLE public.departments ADD CONSTRAINT fk_departments_head_id FOREIGN KEY (head_id)
+			REFERENCES employees(id)
+			ON DELETE SET NULL;
+	END IF;
+END $$;
+
+`, string(bundleContents))
+	suite.FileExists(workingDirPath + "/schema.sql")
+}
+
+func (suite *MorpheSchemaBundleWriterTestSuite) TestWriteTable_ForeignKeyCycle_AfterTableOutsideCycle() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	bundleWriter := &compile.MorpheSchemaBundleWriter{
+		TargetDirPath: workingDirPath,
+	}
+
+	getTable := func(tableName string, refTableName string) *psqldef.Table {
+		return &psqldef.Table{
+			Schema: "public",
+			Name:   tableName,
+			Columns: []psqldef.TableColumn{
+				{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+				{Name: "ref_id", Type: psqldef.PSQLTypeInteger},
+			},
+			ForeignKeys: []psqldef.ForeignKey{
+				{
+					Name:           "fk_" + tableName + "_ref_id",
+					TableName:      tableName,
+					ColumnNames:    []string{"ref_id"},
+					RefTableName:   refTableName,
+					RefColumnNames: []string{"id"},
+				},
+			},
+		}
+	}
+
+	// audits is first by name but only references the orders and customers cycle
+	for _, table := range []*psqldef.Table{
+		getTable("audits", "orders"),
+		getTable("orders", "customers"),
+		getTable("customers", "orders"),
+	} {
+		tableContents, tableErr := bundleWriter.WriteTable(table)
+		suite.Nil(tableErr)
+		suite.Contains(string(tableContents), "CREATE TABLE IF NOT EXISTS public."+table.Name)
+	}
+	suite.NoFileExists(workingDirPath + "/schema.sql")
+
+	bundleContents, flushErr := bundleWriter.Flush()
+	suite.Nil(flushErr)
+
+	suite.Equal(`-- Schema bundle generated from Morphe definitions
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+-- Table definition for customers
+CREATE TABLE IF NOT EXISTS public.customers (
+	id SERIAL PRIMARY KEY,
+	ref_id INTEGER
+);
+
+-- Table definition for orders
+CREATE TABLE IF NOT EXISTS public.orders (
+	id SERIAL PRIMARY KEY,
+	ref_id INTEGER,
+	CONSTRAINT fk_orders_ref_id FOREIGN KEY (ref_id)
+		REFERENCES customers(id)
+);
+
+-- Table definition for audits
+CREATE TABLE IF NOT EXISTS public.audits (
+	id SERIAL PRIMARY KEY,
+	ref_id INTEGER,
+	CONSTRAINT fk_audits_ref_id FOREIGN KEY (ref_id)
+		REFERENCES orders(id)
+);
+
+-- Deferred foreign keys
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_customers_ref_id' AND conrelid = 'public.customers'::regclass) THEN
+		ALTER TABLE public.customers ADD CONSTRAINT fk_customers_ref_id FOREIGN KEY (ref_id)
+			REFERENCES orders(id);
+	END IF;
+END $$;
+
+`, string(bundleContents))
+	suite.FileExists(workingDirPath + "/schema.sql")
+}
+
+func (suite *MorpheSchemaBundleWriterTestSuite) TestFlush_ForeignKeyCycle_Rerunnable() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	bundleWriter := &compile.MorpheSchemaBundleWriter{
+		TargetDirPath: workingDirPath,
+	}
+
+	for _, tableNames := range [][2]string{{"authors", "books"}, {"books", "authors"}} {
+		_, tableErr := bundleWriter.WriteTable(&psqldef.Table{
+			Schema: "public",
+			Name:   tableNames[0],
+			Columns: []psqldef.TableColumn{
+				{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+				{Name: "ref_id", Type: psqldef.PSQLTypeInteger},
+			},
+			ForeignKeys: []psqldef.ForeignKey{
+				{
+					TableName:      tableNames[0],
+					ColumnNames:    []string{"ref_id"},
+					RefTableName:   tableNames[1],
+					RefColumnNames: []string{"id"},
+				},
+			},
+		})
+		suite.Nil(tableErr)
+	}
+
+	bundleContents, flushErr := bundleWriter.Flush()
+	suite.Nil(flushErr)
+
+	// Applying the bundle a second time must not fail, so every statement is guarded against existing objects
+	rerunnablePrefixes := []string{
+		"--",
+		"CREATE SCHEMA IF NOT EXISTS ",
+		"CREATE TABLE IF NOT EXISTS ",
+		"DO $$",
+	}
+	for _, line := range strings.Split(string(bundleContents), "\n") {
+		if line == "" || strings.HasPrefix(line, "\t") || line == ");" || line == "BEGIN" || line == "END $$;" {
+			continue
+		}
+		suite.True(slices.ContainsFunc(rerunnablePrefixes, func(prefix string) bool {
+			return strings.HasPrefix(line, prefix)
+		}), "statement is not re-runnable: %s", line)
+	}
+	suite.Contains(string(bundleContents), "\tIF NOT EXISTS (SELECT 1 FROM pg_constraint "+
+		"WHERE conname = 'authors_ref_id_fkey' AND conrelid = 'public.authors'::regclass) THEN\n"+
+		"\t\tALTER TABLE public.authors ADD FOREIGN KEY (ref_id) REFERENCES books (id);")
+}
+
+func (suite *MorpheSchemaBundleWriterTestSuite) TestWriteTable_AfterTableInOtherSchema() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	bundleWriter := &compile.MorpheSchemaBundleWriter{
+		TargetDirPath: workingDirPath,
+	}
+
+	peopleTable := &psqldef.Table{
+		Schema: "app",
+		Name:   "people",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "nationality_id", Type: psqldef.PSQLTypeInteger},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{
+				Schema:         "app",
+				Name:           "fk_people_nationality_id",
+				TableName:      "people",
+				ColumnNames:    []string{"nationality_id"},
+				RefSchema:      "lookup",
+				RefTableName:   "nationalities",
+				RefColumnNames: []string{"id"},
+			},
+		},
+	}
+	nationalitiesTable := &psqldef.Table{
+		Schema: "lookup",
+		Name:   "nationalities",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "key", Type: psqldef.PSQLTypeText, NotNull: true},
+		},
+		UniqueConstraints: []psqldef.UniqueConstraint{
+			{Name: "uk_nationalities_key", TableName: "nationalities", ColumnNames: []string{"key"}},
+		},
+	}
+
+	_, peopleErr := bundleWriter.WriteTable(peopleTable)
+	suite.Nil(peopleErr)
+	_, nationalitiesErr := bundleWriter.WriteTable(nationalitiesTable)
+	suite.Nil(nationalitiesErr)
+
+	bundleContents, flushErr := bundleWriter.Flush()
+	suite.Nil(flushErr)
+
+	suite.Equal(`-- Schema bundle generated from Morphe definitions
+
+CREATE SCHEMA IF NOT EXISTS app;
+CREATE SCHEMA IF NOT EXISTS lookup;
+
+-- Table definition for nationalities
+CREATE TABLE IF NOT EXISTS lookup.nationalities (
+	id SERIAL PRIMARY KEY,
+	key TEXT NOT NULL,
+	CONSTRAINT uk_nationalities_key UNIQUE (key)
+);
+
+-- Table definition for people
+CREATE TABLE IF NOT EXISTS app.people (
+	id SERIAL PRIMARY KEY,
+	nationality_id INTEGER,
+	CONSTRAINT fk_people_nationality_id FOREIGN KEY (nationality_id)
+		REFERENCES lookup.nationalities(id)
+);
+
+`, string(bundleContents))
+}
+
+func (suite *MorpheSchemaBundleWriterTestSuite) TestWriteTable_ForeignKeyActions() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	bundleWriter := &compile.MorpheSchemaBundleWriter{
+		TargetDirPath: workingDirPath,
+	}
+
+	ownersTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "owners",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+		},
+	}
+	petsTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "pets",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "owner_id", Type: psqldef.PSQLTypeInteger},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{
+				Schema:            "public",
+				Name:              "fk_pets_owner_id",
+				TableName:         "pets",
+				ColumnNames:       []string{"owner_id"},
+				RefSchema:         "public",
+				RefTableName:      "owners",
+				RefColumnNames:    []string{"id"},
+				OnDelete:          "SET NULL",
+				OnUpdate:          "CASCADE",
+				Deferrable:        true,
+				InitiallyDeferred: true,
+			},
+		},
+	}
+
+	_, ownersErr := bundleWriter.WriteTable(ownersTable)
+	suite.Nil(ownersErr)
+	_, petsErr := bundleWriter.WriteTable(petsTable)
+	suite.Nil(petsErr)
+
+	bundleContents, flushErr := bundleWriter.Flush()
+	suite.Nil(flushErr)
+
+	suite.Equal(`-- Schema bundle generated from Morphe definitions
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+-- Table definition for owners
+CREATE TABLE IF NOT EXISTS public.owners (
+	id SERIAL PRIMARY KEY
+);
+
+-- Table definition for pets
+CREATE TABLE IF NOT EXISTS public.pets (
+	id SERIAL PRIMARY KEY,
+	owner_id INTEGER,
+	CONSTRAINT fk_pets_owner_id FOREIGN KEY (owner_id)
+		REFERENCES public.owners(id)
+		ON DELETE SET NULL
+		ON UPDATE CASCADE
+		DEFERRABLE INITIALLY DEFERRED
+);
+
+`, string(bundleContents))
+}
+
+func (suite *MorpheSchemaBundleWriterTestSuite) TestWriteTable_CompositePrimaryKey() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	bundleWriter := &compile.MorpheSchemaBundleWriter{
+		TargetDirPath: workingDirPath,
+	}
+
+	junctionTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "person_teams",
+		Columns: []psqldef.TableColumn{
+			{Name: "person_id", Type: psqldef.PSQLTypeInteger, PrimaryKey: true},
+			{Name: "team_id", Type: psqldef.PSQLTypeUUID, PrimaryKey: true},
+		},
+	}
+
+	_, junctionErr := bundleWriter.WriteTable(junctionTable)
+	suite.Nil(junctionErr)
+
+	bundleContents, flushErr := bundleWriter.Flush()
+	suite.Nil(flushErr)
+
+	suite.Equal(`-- Schema bundle generated from Morphe definitions
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+-- Table definition for person_teams
+CREATE TABLE IF NOT EXISTS public.person_teams (
+	person_id INTEGER,
+	team_id UUID,
+	PRIMARY KEY (person_id, team_id)
+);
+
+`, string(bundleContents))
+}
+
+func (suite *MorpheSchemaBundleWriterTestSuite) TestWriteTable_CompositeForeignKey() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	bundleWriter := &compile.MorpheSchemaBundleWriter{
+		TargetDirPath: workingDirPath,
+	}
+
+	invoicesTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "invoices",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "membership_tenant_id", Type: psqldef.PSQLTypeInteger, NotNull: true},
+			{Name: "membership_user_id", Type: psqldef.PSQLTypeUUID, NotNull: true},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{
+				Schema:         "public",
+				Name:           "fk_invoices_membership_tenant_id_membership_user_id",
+				TableName:      "invoices",
+				ColumnNames:    []string{"membership_tenant_id", "membership_user_id"},
+				RefSchema:      "public",
+				RefTableName:   "memberships",
+				RefColumnNames: []string{"tenant_id", "user_id"},
+				OnDelete:       "CASCADE",
+			},
+		},
+	}
+	membershipsTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "memberships",
+		Columns: []psqldef.TableColumn{
+			{Name: "tenant_id", Type: psqldef.PSQLTypeInteger, PrimaryKey: true},
+			{Name: "user_id", Type: psqldef.PSQLTypeUUID, PrimaryKey: true},
+		},
+	}
+
+	_, invoicesErr := bundleWriter.WriteTable(invoicesTable)
+	suite.Nil(invoicesErr)
+	_, membershipsErr := bundleWriter.WriteTable(membershipsTable)
+	suite.Nil(membershipsErr)
+
+	bundleContents, flushErr := bundleWriter.Flush()
+	suite.Nil(flushErr)
+
+	suite.Equal(`-- Schema bundle generated from Morphe definitions
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+-- Table definition for memberships
+CREATE TABLE IF NOT EXISTS public.memberships (
+	tenant_id INTEGER,
+	user_id UUID,
+	PRIMARY KEY (tenant_id, user_id)
+);
+
+-- Table definition for invoices
+CREATE TABLE IF NOT EXISTS public.invoices (
+	id SERIAL PRIMARY KEY,
+	membership_tenant_id INTEGER NOT NULL,
+	membership_user_id UUID NOT NULL,
+	CONSTRAINT fk_invoices_membership_tenant_id_membership_user_id FOREIGN KEY (membership_tenant_id, membership_user_id)
+		REFERENCES public.memberships(tenant_id, user_id)
+		ON DELETE CASCADE
+);
+
+`, string(bundleContents))
+}
+
+func (suite *MorpheSchemaBundleWriterTestSuite) TestWriteView_AfterReferencedView() {
+	bundleWriter := &compile.MorpheSchemaBundleWriter{
+		TargetDirPath: suite.TestDirPath + "/working",
+	}
+	defer os.RemoveAll(bundleWriter.TargetDirPath)
+
+	activeView := &psqldef.View{
+		Schema:    "public",
+		Name:      "active_people",
+		Columns:   []psqldef.ViewColumn{{Name: "id", SourceRef: "person_entities.id"}},
+		FromTable: "person_entities",
+	}
+	entitiesView := &psqldef.View{
+		Schema:    "public",
+		Name:      "person_entities",
+		Columns:   []psqldef.ViewColumn{{Name: "id", SourceRef: "people.id"}},
+		FromTable: "people",
+	}
+
+	_, activeErr := bundleWriter.WriteView(activeView)
+	suite.Nil(activeErr)
+	_, entitiesErr := bundleWriter.WriteView(entitiesView)
+	suite.Nil(entitiesErr)
+
+	bundleLines, bundleErr := bundleWriter.GetAllBundleLines()
+	suite.Nil(bundleErr)
+	suite.Contains(bundleLines, "-- View definition for person_entities")
+	suite.Contains(bundleLines, "-- View definition for active_people")
+
+	entitiesIdx, activeIdx := -1, -1
+	for lineIdx, line := range bundleLines {
+		switch line {
+		case "-- View definition for person_entities":
+			entitiesIdx = lineIdx
+		case "-- View definition for active_people":
+			activeIdx = lineIdx
+		}
+	}
+	suite.Less(entitiesIdx, activeIdx)
+}
+
+func (suite *MorpheSchemaBundleWriterTestSuite) TestWriteType_CompositeAfterEnum() {
+	bundleWriter := &compile.MorpheSchemaBundleWriter{
+		TargetDirPath: suite.TestDirPath + "/working",
+	}
+	defer os.RemoveAll(bundleWriter.TargetDirPath)
+
+	zoneEnum := psqldef.PSQLTypeEnum{Schema: "public", Name: "zone", Values: []string{"EU", "US"}}
+	addressComposite := psqldef.PSQLTypeComposite{
+		Schema: "public",
+		Name:   "address",
+		Fields: map[string]psqldef.PSQLType{
+			"street": psqldef.PSQLTypeText,
+			"zone":   zoneEnum,
+		},
+	}
+
+	_, addressErr := bundleWriter.WriteType(addressComposite)
+	suite.Nil(addressErr)
+	_, zoneErr := bundleWriter.WriteType(zoneEnum)
+	suite.Nil(zoneErr)
+
+	bundleLines, bundleErr := bundleWriter.GetAllBundleLines()
+	suite.Nil(bundleErr)
+	suite.Equal([]string{
+		"-- Schema bundle generated from Morphe definitions",
+		"",
+		"CREATE SCHEMA IF NOT EXISTS public;",
+		"",
+		"-- Type definition for zone",
+		"CREATE TYPE public.zone AS ENUM (",
+		"\t'EU',",
+		"\t'US'",
+		");",
+		"",
+		"-- Type definition for address",
+		"CREATE TYPE public.address AS (",
+		"\tstreet TEXT,",
+		"\tzone public.zone",
+		");",
+		"",
+	}, bundleLines)
+}
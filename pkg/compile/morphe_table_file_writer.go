@@ -12,6 +12,8 @@ import (
 type MorpheTableFileWriter struct {
 	Type          MorpheTableType
 	TargetDirPath string
+	// NamingStrategy names the indices of tables left unnamed, e.g. by hooks, MorpheNamingStrategy by default
+	NamingStrategy NamingStrategy
 }
 
 func (w *MorpheTableFileWriter) WriteTable(tableDefinition *psqldef.Table) ([]byte, error) {
@@ -42,7 +44,7 @@ func (w *MorpheTableFileWriter) getAllTableLines(tableDefinition *psqldef.Table)
 	}
 
 	// Create table
-	tableLines, tableErr := w.getCreateTableLines(tableDefinition)
+	tableLines, tableErr := w.GetCreateTableLines(tableDefinition)
 	if tableErr != nil {
 		return nil, tableErr
 	}
@@ -51,7 +53,7 @@ func (w *MorpheTableFileWriter) getAllTableLines(tableDefinition *psqldef.Table)
 
 	// Add indices
 	if len(tableDefinition.Indices) > 0 {
-		indexLines, indexErr := w.getIndexLines(tableDefinition)
+		indexLines, indexErr := w.GetIndexLines(tableDefinition)
 		if indexErr != nil {
 			return nil, indexErr
 		}
@@ -59,9 +61,19 @@ func (w *MorpheTableFileWriter) getAllTableLines(tableDefinition *psqldef.Table)
 		allTableLines = append(allTableLines, "")
 	}
 
+	// Add triggers
+	if len(tableDefinition.Triggers) > 0 {
+		triggerLines, triggerErr := w.GetTriggerLines(tableDefinition)
+		if triggerErr != nil {
+			return nil, triggerErr
+		}
+		allTableLines = append(allTableLines, triggerLines...)
+		allTableLines = append(allTableLines, "")
+	}
+
 	// Add seed data
 	if len(tableDefinition.SeedData) > 0 {
-		seedDataLines, seedErr := w.getSeedDataLines(tableDefinition)
+		seedDataLines, seedErr := w.GetSeedDataLines(tableDefinition)
 		if seedErr != nil {
 			return nil, seedErr
 		}
@@ -72,7 +84,7 @@ func (w *MorpheTableFileWriter) getAllTableLines(tableDefinition *psqldef.Table)
 	return allTableLines, nil
 }
 
-func (w *MorpheTableFileWriter) getCreateTableLines(tableDefinition *psqldef.Table) ([]string, error) {
+func (w *MorpheTableFileWriter) GetCreateTableLines(tableDefinition *psqldef.Table) ([]string, error) {
 	tableName := tableDefinition.Name
 	if tableDefinition.Schema != "" {
 		tableName = tableDefinition.Schema + "." + tableName
@@ -82,77 +94,121 @@ func (w *MorpheTableFileWriter) getCreateTableLines(tableDefinition *psqldef.Tab
 		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (", tableName),
 	}
 
-	// Add columns
-	for colIdx, column := range tableDefinition.Columns {
-		columnDef := w.formatColumnDefinition(column)
-
-		// Add comma if not the last column or if we have constraints to add
-		if colIdx < len(tableDefinition.Columns)-1 ||
-			len(tableDefinition.ForeignKeys) > 0 ||
-			len(tableDefinition.UniqueConstraints) > 0 {
-			columnDef += ","
+	// Tables keyed by several columns declare their primary key as a table constraint
+	primaryKeyColumnNames := []string{}
+	for _, column := range tableDefinition.Columns {
+		if column.PrimaryKey {
+			primaryKeyColumnNames = append(primaryKeyColumnNames, column.Name)
 		}
+	}
+	hasCompositePrimaryKey := len(primaryKeyColumnNames) > 1
 
-		tableLines = append(tableLines, "\t"+columnDef)
+	// Each entry is a column or constraint, which may span several lines
+	definitions := [][]string{}
+	for _, column := range tableDefinition.Columns {
+		if hasCompositePrimaryKey {
+			column.PrimaryKey = false
+		}
+		definitions = append(definitions, []string{w.FormatColumnDefinition(column)})
 	}
 
-	// Add unique constraints
-	for uqIdx, uniqueConstraint := range tableDefinition.UniqueConstraints {
-		constraintLine := fmt.Sprintf("\tUNIQUE (%s)", strings.Join(uniqueConstraint.ColumnNames, ", "))
+	if hasCompositePrimaryKey {
+		definitions = append(definitions, []string{fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKeyColumnNames, ", "))})
+	}
 
-		// Add comma if not the last constraint or if we have foreign keys to add
-		if uqIdx < len(tableDefinition.UniqueConstraints)-1 || len(tableDefinition.ForeignKeys) > 0 {
-			constraintLine += ","
-		}
+	for _, uniqueConstraint := range tableDefinition.UniqueConstraints {
+		definitions = append(definitions, []string{w.FormatUniqueConstraintDefinition(uniqueConstraint)})
+	}
 
-		tableLines = append(tableLines, constraintLine)
+	// Foreign key constraints span several lines for readability
+	for _, foreignKey := range tableDefinition.ForeignKeys {
+		definitions = append(definitions, w.GetForeignKeyConstraintLines(foreignKey))
 	}
 
-	// Add foreign key constraints with proper formatting
-	for fkIdx, foreignKey := range tableDefinition.ForeignKeys {
-		// Format according to the spec with named constraints
-		if foreignKey.Name != "" {
-			// Format with CONSTRAINT and multiline for readability
-			fkLine := fmt.Sprintf("\tCONSTRAINT %s FOREIGN KEY (%s)",
-				foreignKey.Name,
-				strings.Join(foreignKey.ColumnNames, ", "))
-			tableLines = append(tableLines, fkLine)
+	for _, checkConstraint := range tableDefinition.CheckConstraints {
+		definitions = append(definitions, []string{w.FormatCheckConstraintDefinition(checkConstraint)})
+	}
 
-			refLine := fmt.Sprintf("\t\tREFERENCES %s(%s)",
-				foreignKey.RefTableName,
-				strings.Join(foreignKey.RefColumnNames, ", "))
+	for definitionIdx, definitionLines := range definitions {
+		// Separate all but the last definition with a comma
+		if definitionIdx < len(definitions)-1 {
+			definitionLines[len(definitionLines)-1] += ","
+		}
+		for _, definitionLine := range definitionLines {
+			tableLines = append(tableLines, "\t"+definitionLine)
+		}
+	}
 
-			if foreignKey.OnDelete != "" {
-				refLine += fmt.Sprintf("\n\t\tON DELETE %s", foreignKey.OnDelete)
-			}
+	tableLines = append(tableLines, ");")
+	return tableLines, nil
+}
 
-			// Only add comma if not the last foreign key
-			if fkIdx < len(tableDefinition.ForeignKeys)-1 {
-				refLine += ","
-			}
+// FormatUniqueConstraintDefinition formats a unique constraint as used within a table definition
+func (w *MorpheTableFileWriter) FormatUniqueConstraintDefinition(uniqueConstraint psqldef.UniqueConstraint) string {
+	if uniqueConstraint.Name == "" {
+		return fmt.Sprintf("UNIQUE (%s)", strings.Join(uniqueConstraint.ColumnNames, ", "))
+	}
+	return fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)", uniqueConstraint.Name, strings.Join(uniqueConstraint.ColumnNames, ", "))
+}
 
-			tableLines = append(tableLines, refLine)
-		} else {
-			// Fallback to simple single-line format for unnamed constraints
-			fkLine := fmt.Sprintf("\tFOREIGN KEY (%s) REFERENCES %s (%s)",
-				strings.Join(foreignKey.ColumnNames, ", "),
-				foreignKey.RefTableName,
-				strings.Join(foreignKey.RefColumnNames, ", "))
-
-			// Only add comma if not the last foreign key
-			if fkIdx < len(tableDefinition.ForeignKeys)-1 {
-				fkLine += ","
-			}
+// FormatCheckConstraintDefinition formats a check constraint as used within a table definition
+func (w *MorpheTableFileWriter) FormatCheckConstraintDefinition(checkConstraint psqldef.CheckConstraint) string {
+	if checkConstraint.Name == "" {
+		return fmt.Sprintf("CHECK (%s)", checkConstraint.Expression)
+	}
+	return fmt.Sprintf("CONSTRAINT %s CHECK (%s)", checkConstraint.Name, checkConstraint.Expression)
+}
 
-			tableLines = append(tableLines, fkLine)
+// GetForeignKeyConstraintLines formats a foreign key constraint as used within a table definition
+func (w *MorpheTableFileWriter) GetForeignKeyConstraintLines(foreignKey psqldef.ForeignKey) []string {
+	// Fallback to simple single-line format for unnamed constraints
+	if foreignKey.Name == "" {
+		fkLine := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+			strings.Join(foreignKey.ColumnNames, ", "),
+			getQualifiedRelationName(foreignKey.RefSchema, foreignKey.RefTableName),
+			strings.Join(foreignKey.RefColumnNames, ", "))
+		for _, actionLine := range w.getForeignKeyActionLines(foreignKey) {
+			fkLine += " " + actionLine
 		}
+		return []string{fkLine}
 	}
 
-	tableLines = append(tableLines, ");")
-	return tableLines, nil
+	// Format with CONSTRAINT and multiline for readability
+	fkLines := []string{
+		fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s)",
+			foreignKey.Name,
+			strings.Join(foreignKey.ColumnNames, ", ")),
+		fmt.Sprintf("\tREFERENCES %s(%s)",
+			getQualifiedRelationName(foreignKey.RefSchema, foreignKey.RefTableName),
+			strings.Join(foreignKey.RefColumnNames, ", ")),
+	}
+
+	for _, actionLine := range w.getForeignKeyActionLines(foreignKey) {
+		fkLines = append(fkLines, "\t"+actionLine)
+	}
+
+	return fkLines
 }
 
-func (w *MorpheTableFileWriter) formatColumnDefinition(column psqldef.TableColumn) string {
+// getForeignKeyActionLines returns the referential actions and deferral of a foreign key constraint
+func (w *MorpheTableFileWriter) getForeignKeyActionLines(foreignKey psqldef.ForeignKey) []string {
+	actionLines := []string{}
+	if foreignKey.OnDelete != "" {
+		actionLines = append(actionLines, fmt.Sprintf("ON DELETE %s", foreignKey.OnDelete))
+	}
+	if foreignKey.OnUpdate != "" {
+		actionLines = append(actionLines, fmt.Sprintf("ON UPDATE %s", foreignKey.OnUpdate))
+	}
+	if foreignKey.Deferrable && foreignKey.InitiallyDeferred {
+		actionLines = append(actionLines, "DEFERRABLE INITIALLY DEFERRED")
+	} else if foreignKey.Deferrable {
+		actionLines = append(actionLines, "DEFERRABLE")
+	}
+	return actionLines
+}
+
+// FormatColumnDefinition formats a column as used within a table definition
+func (w *MorpheTableFileWriter) FormatColumnDefinition(column psqldef.TableColumn) string {
 	parts := []string{column.Name, column.Type.GetSyntax()}
 
 	if column.NotNull {
@@ -170,42 +226,112 @@ func (w *MorpheTableFileWriter) formatColumnDefinition(column psqldef.TableColum
 	return strings.Join(parts, " ")
 }
 
-func (w *MorpheTableFileWriter) getIndexLines(tableDefinition *psqldef.Table) ([]string, error) {
+func (w *MorpheTableFileWriter) GetIndexLines(tableDefinition *psqldef.Table) ([]string, error) {
 	indexLines := []string{
 		"-- Indices",
 	}
 
+	for _, index := range tableDefinition.Indices {
+		indexLines = append(indexLines, w.FormatIndexDefinition(tableDefinition, index))
+	}
+
+	return indexLines, nil
+}
+
+// FormatIndexDefinition formats the CREATE INDEX statement for an index of the table
+func (w *MorpheTableFileWriter) FormatIndexDefinition(tableDefinition *psqldef.Table, index psqldef.Index) string {
 	tableName := tableDefinition.Name
 	if tableDefinition.Schema != "" {
 		tableName = tableDefinition.Schema + "." + tableName
 	}
 
-	for _, index := range tableDefinition.Indices {
-		indexName := index.Name
-		if indexName == "" {
-			indexName = fmt.Sprintf("idx_%s_%s", tableDefinition.Name, strings.Join(index.Columns, "_"))
-		}
+	indexName := w.GetIndexName(tableDefinition, index)
 
-		indexType := ""
-		if index.Using != "" {
-			indexType = "USING " + index.Using + " "
+	indexType := ""
+	if index.Using != "" {
+		indexType = "USING " + index.Using + " "
+	}
+
+	unique := ""
+	if index.IsUnique {
+		unique = "UNIQUE "
+	}
+
+	return fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s %s(%s);",
+		unique, indexName, tableName, indexType, strings.Join(index.Columns, ", "))
+}
+
+// GetIndexName returns the name of an index of the table, naming unnamed indices by their columns with the naming
+// strategy
+func (w *MorpheTableFileWriter) GetIndexName(tableDefinition *psqldef.Table, index psqldef.Index) string {
+	if index.Name != "" {
+		return index.Name
+	}
+	names := identifierNames{strategy: w.NamingStrategy}
+	return names.getIndexName(tableDefinition.Name, strings.Join(index.Columns, "_"))
+}
+
+func (w *MorpheTableFileWriter) GetTriggerLines(tableDefinition *psqldef.Table) ([]string, error) {
+	triggerLines := []string{
+		"-- Triggers",
+	}
+
+	for triggerIdx, trigger := range tableDefinition.Triggers {
+		definitionLines, definitionErr := w.GetTriggerDefinitionLines(tableDefinition, trigger)
+		if definitionErr != nil {
+			return nil, definitionErr
 		}
 
-		unique := ""
-		if index.IsUnique {
-			unique = "UNIQUE "
+		if triggerIdx > 0 {
+			triggerLines = append(triggerLines, "")
 		}
+		triggerLines = append(triggerLines, definitionLines...)
+	}
 
-		indexLine := fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s %s(%s);",
-			unique, indexName, tableName, indexType, strings.Join(index.Columns, ", "))
+	return triggerLines, nil
+}
 
-		indexLines = append(indexLines, indexLine)
+// GetTriggerDefinitionLines formats the trigger function and CREATE TRIGGER statements for a trigger of the table
+func (w *MorpheTableFileWriter) GetTriggerDefinitionLines(tableDefinition *psqldef.Table, trigger psqldef.Trigger) ([]string, error) {
+	if trigger.Name == "" || trigger.FunctionName == "" {
+		return nil, fmt.Errorf("trigger on table '%s' has no name or function name", tableDefinition.Name)
+	}
+	if len(trigger.Events) == 0 {
+		return nil, fmt.Errorf("trigger '%s' has no events", trigger.Name)
 	}
 
-	return indexLines, nil
+	tableName := tableDefinition.Name
+	if tableDefinition.Schema != "" {
+		tableName = tableDefinition.Schema + "." + tableName
+	}
+
+	functionName := trigger.FunctionName
+	if trigger.Schema != "" {
+		functionName = trigger.Schema + "." + functionName
+	}
+
+	triggerLines := []string{
+		fmt.Sprintf("CREATE OR REPLACE FUNCTION %s()", functionName),
+		"RETURNS TRIGGER AS $$",
+		"BEGIN",
+	}
+	for _, bodyLine := range trigger.FunctionBody {
+		triggerLines = append(triggerLines, "\t"+bodyLine)
+	}
+	triggerLines = append(triggerLines,
+		"END;",
+		"$$ LANGUAGE plpgsql;",
+		"",
+		fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s;", trigger.Name, tableName),
+		fmt.Sprintf("CREATE TRIGGER %s", trigger.Name),
+		fmt.Sprintf("\t%s %s ON %s", trigger.Timing, strings.Join(trigger.Events, " OR "), tableName),
+		fmt.Sprintf("\tFOR EACH ROW EXECUTE FUNCTION %s();", functionName),
+	)
+
+	return triggerLines, nil
 }
 
-func (w *MorpheTableFileWriter) getSeedDataLines(tableDefinition *psqldef.Table) ([]string, error) {
+func (w *MorpheTableFileWriter) GetSeedDataLines(tableDefinition *psqldef.Table) ([]string, error) {
 	seedDataLines := []string{
 		"-- Seed Data",
 	}
@@ -252,7 +378,7 @@ func (w *MorpheTableFileWriter) getSeedDataLines(tableDefinition *psqldef.Table)
 					return nil, fmt.Errorf("invalid value for column '%s' (row %d): %v", colName, rowIdx, err)
 				}
 
-				formattedValues[rowIdx] = w.formatSQLValue(val, col.Type)
+				formattedValues[rowIdx] = w.FormatSQLValue(val, col.Type)
 			}
 
 			valueList := strings.Join(formattedValues, ", ")
@@ -303,8 +429,8 @@ func (w *MorpheTableFileWriter) validateValueType(value any, column psqldef.Tabl
 	return nil
 }
 
-// formatSQLValue formats a value for SQL, taking into account the column type
-func (w *MorpheTableFileWriter) formatSQLValue(value any, columnType psqldef.PSQLType) string {
+// FormatSQLValue formats a value for SQL, taking into account the column type
+func (w *MorpheTableFileWriter) FormatSQLValue(value any, columnType psqldef.PSQLType) string {
 	if value == nil {
 		return "NULL"
 	}
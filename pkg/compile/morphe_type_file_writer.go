@@ -0,0 +1,142 @@
+package compile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/sqlfile"
+)
+
+type MorpheTypeFileWriter struct {
+	TargetDirPath string
+}
+
+func (w *MorpheTypeFileWriter) WriteType(typeDefinition psqldef.PSQLType) ([]byte, error) {
+	if typeDefinition == nil {
+		return nil, ErrNoPSQLType
+	}
+
+	allTypeLines, allLinesErr := w.getAllTypeLines(typeDefinition)
+	if allLinesErr != nil {
+		return nil, allLinesErr
+	}
+
+	typeFileContents, typeContentsErr := core.LinesToString(allTypeLines)
+	if typeContentsErr != nil {
+		return nil, typeContentsErr
+	}
+
+	return sqlfile.WriteSQLDefinitionFile(w.TargetDirPath, typeDefinition.GetSyntaxLocal(), typeFileContents)
+}
+
+func (w *MorpheTypeFileWriter) getAllTypeLines(typeDefinition psqldef.PSQLType) ([]string, error) {
+	allTypeLines := []string{}
+
+	// Add header comment
+	allTypeLines = append(allTypeLines, fmt.Sprintf("-- Type definition for %s", typeDefinition.GetSyntaxLocal()))
+	allTypeLines = append(allTypeLines, "")
+
+	// Create schema if specified
+	if typeDefinition.GetSchema() != "" {
+		allTypeLines = append(allTypeLines, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;", typeDefinition.GetSchema()))
+		allTypeLines = append(allTypeLines, "")
+	}
+
+	typeLines, typeErr := w.GetCreateTypeLines(typeDefinition)
+	if typeErr != nil {
+		return nil, typeErr
+	}
+	allTypeLines = append(allTypeLines, typeLines...)
+	allTypeLines = append(allTypeLines, "")
+
+	return allTypeLines, nil
+}
+
+func (w *MorpheTypeFileWriter) GetCreateTypeLines(typeDefinition psqldef.PSQLType) ([]string, error) {
+	switch typeDef := typeDefinition.(type) {
+	case psqldef.PSQLTypeEnum:
+		return w.getCreateEnumTypeLines(typeDef)
+	case *psqldef.PSQLTypeEnum:
+		return w.getCreateEnumTypeLines(*typeDef)
+	case psqldef.PSQLTypeComposite:
+		return w.getCreateCompositeTypeLines(typeDef)
+	case *psqldef.PSQLTypeComposite:
+		return w.getCreateCompositeTypeLines(*typeDef)
+	case psqldef.PSQLTypeDomain:
+		return w.getCreateDomainTypeLines(typeDef)
+	case *psqldef.PSQLTypeDomain:
+		return w.getCreateDomainTypeLines(*typeDef)
+	case psqldef.PSQLTypeRange:
+		return w.getCreateRangeTypeLines(typeDef)
+	case *psqldef.PSQLTypeRange:
+		return w.getCreateRangeTypeLines(*typeDef)
+	}
+	return nil, ErrUnsupportedPSQLTypeDefinition(typeDefinition)
+}
+
+func (w *MorpheTypeFileWriter) getCreateEnumTypeLines(enumType psqldef.PSQLTypeEnum) ([]string, error) {
+	if len(enumType.Values) == 0 {
+		return nil, fmt.Errorf("enum type '%s' has no values", enumType.GetSyntax())
+	}
+
+	typeLines := []string{
+		fmt.Sprintf("CREATE TYPE %s AS ENUM (", enumType.GetSyntax()),
+	}
+
+	for valueIdx, value := range enumType.Values {
+		valueLine := fmt.Sprintf("\t'%s'", strings.ReplaceAll(value, "'", "''"))
+		if valueIdx < len(enumType.Values)-1 {
+			valueLine += ","
+		}
+		typeLines = append(typeLines, valueLine)
+	}
+
+	typeLines = append(typeLines, ");")
+	return typeLines, nil
+}
+
+func (w *MorpheTypeFileWriter) getCreateCompositeTypeLines(compositeType psqldef.PSQLTypeComposite) ([]string, error) {
+	if len(compositeType.Fields) == 0 {
+		return nil, fmt.Errorf("composite type '%s' has no fields", compositeType.GetSyntax())
+	}
+
+	typeLines := []string{
+		fmt.Sprintf("CREATE TYPE %s AS (", compositeType.GetSyntax()),
+	}
+
+	fieldNames := core.MapKeysSorted(compositeType.Fields)
+	for fieldIdx, fieldName := range fieldNames {
+		fieldLine := fmt.Sprintf("\t%s %s", fieldName, compositeType.Fields[fieldName].GetSyntax())
+		if fieldIdx < len(fieldNames)-1 {
+			fieldLine += ","
+		}
+		typeLines = append(typeLines, fieldLine)
+	}
+
+	typeLines = append(typeLines, ");")
+	return typeLines, nil
+}
+
+func (w *MorpheTypeFileWriter) getCreateDomainTypeLines(domainType psqldef.PSQLTypeDomain) ([]string, error) {
+	if domainType.ValueType == nil {
+		return nil, fmt.Errorf("domain type '%s' has no value type", domainType.GetSyntax())
+	}
+
+	return []string{
+		fmt.Sprintf("CREATE DOMAIN %s AS %s;", domainType.GetSyntax(), domainType.ValueType.GetSyntax()),
+	}, nil
+}
+
+func (w *MorpheTypeFileWriter) getCreateRangeTypeLines(rangeType psqldef.PSQLTypeRange) ([]string, error) {
+	if rangeType.ValueType == nil {
+		return nil, fmt.Errorf("range type '%s' has no value type", rangeType.GetSyntax())
+	}
+
+	return []string{
+		fmt.Sprintf("CREATE TYPE %s AS RANGE (", rangeType.GetSyntax()),
+		fmt.Sprintf("\tsubtype = %s", rangeType.ValueType.GetSyntax()),
+		");",
+	}, nil
+}
@@ -0,0 +1,97 @@
+package compile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+type MorpheTypeFileWriterTestSuite struct {
+	suite.Suite
+}
+
+func TestMorpheTypeFileWriterTestSuite(t *testing.T) {
+	suite.Run(t, new(MorpheTypeFileWriterTestSuite))
+}
+
+func (suite *MorpheTypeFileWriterTestSuite) TestGetCreateTypeLines_Composite() {
+	typeWriter := &compile.MorpheTypeFileWriter{}
+
+	typeLines, typeErr := typeWriter.GetCreateTypeLines(&psqldef.PSQLTypeComposite{
+		Schema: "public",
+		Name:   "address",
+		Fields: map[string]psqldef.PSQLType{
+			"street":      psqldef.PSQLTypeText,
+			"house_nr":    psqldef.PSQLTypeInteger,
+			"nationality": psqldef.PSQLTypeEnum{Schema: "public", Name: "nationality", Values: []string{"DE"}},
+		},
+	})
+
+	suite.Nil(typeErr)
+	suite.Equal([]string{
+		"CREATE TYPE public.address AS (",
+		"\thouse_nr INTEGER,",
+		"\tnationality public.nationality,",
+		"\tstreet TEXT",
+		");",
+	}, typeLines)
+}
+
+func (suite *MorpheTypeFileWriterTestSuite) TestGetCreateTypeLines_Composite_NoFields() {
+	typeWriter := &compile.MorpheTypeFileWriter{}
+
+	typeLines, typeErr := typeWriter.GetCreateTypeLines(psqldef.PSQLTypeComposite{
+		Schema: "public",
+		Name:   "address",
+	})
+
+	suite.Nil(typeLines)
+	suite.ErrorContains(typeErr, "composite type 'public.address' has no fields")
+}
+
+func (suite *MorpheTypeFileWriterTestSuite) TestGetCreateTypeLines_Domain() {
+	typeWriter := &compile.MorpheTypeFileWriter{}
+
+	typeLines, typeErr := typeWriter.GetCreateTypeLines(psqldef.PSQLTypeDomain{
+		Schema:    "public",
+		Name:      "tags",
+		ValueType: psqldef.PSQLTypeArray{ValueType: psqldef.PSQLTypeText},
+	})
+
+	suite.Nil(typeErr)
+	suite.Equal([]string{
+		"CREATE DOMAIN public.tags AS TEXT[];",
+	}, typeLines)
+}
+
+func (suite *MorpheTypeFileWriterTestSuite) TestGetCreateTypeLines_Domain_NoValueType() {
+	typeWriter := &compile.MorpheTypeFileWriter{}
+
+	typeLines, typeErr := typeWriter.GetCreateTypeLines(&psqldef.PSQLTypeDomain{
+		Schema: "public",
+		Name:   "tags",
+	})
+
+	suite.Nil(typeLines)
+	suite.ErrorContains(typeErr, "domain type 'public.tags' has no value type")
+}
+
+func (suite *MorpheTypeFileWriterTestSuite) TestGetCreateTypeLines_Range() {
+	typeWriter := &compile.MorpheTypeFileWriter{}
+
+	typeLines, typeErr := typeWriter.GetCreateTypeLines(&psqldef.PSQLTypeRange{
+		Schema:    "public",
+		Name:      "price_range",
+		ValueType: psqldef.PSQLTypeDoublePrecision,
+	})
+
+	suite.Nil(typeErr)
+	suite.Equal([]string{
+		"CREATE TYPE public.price_range AS RANGE (",
+		"\tsubtype = DOUBLE PRECISION",
+		");",
+	}, typeLines)
+}
@@ -41,17 +41,42 @@ func (w *MorpheViewFileWriter) getAllViewLines(viewDefinition *psqldef.View) ([]
 	}
 
 	// Create view
-	viewLines, viewErr := w.getCreateViewLines(viewDefinition)
+	viewLines, viewErr := w.GetCreateViewLines(viewDefinition)
 	if viewErr != nil {
 		return nil, viewErr
 	}
 	allViewLines = append(allViewLines, viewLines...)
 	allViewLines = append(allViewLines, "")
 
+	allViewLines = append(allViewLines, w.GetMaterializedViewObjectLines(viewDefinition)...)
+
 	return allViewLines, nil
 }
 
-func (w *MorpheViewFileWriter) getCreateViewLines(viewDefinition *psqldef.View) ([]string, error) {
+// GetMaterializedViewObjectLines returns the index and refresh function sections of a materialized view, each
+// followed by an empty line
+func (w *MorpheViewFileWriter) GetMaterializedViewObjectLines(viewDefinition *psqldef.View) []string {
+	objectLines := []string{}
+	if !viewDefinition.Materialized {
+		return objectLines
+	}
+
+	if len(viewDefinition.Indices) > 0 {
+		objectLines = append(objectLines, "-- Indices")
+		objectLines = append(objectLines, w.GetViewIndexLines(viewDefinition)...)
+		objectLines = append(objectLines, "")
+	}
+
+	if viewDefinition.RefreshFunctionName != "" {
+		objectLines = append(objectLines, "-- Refresh function")
+		objectLines = append(objectLines, w.GetRefreshFunctionLines(viewDefinition)...)
+		objectLines = append(objectLines, "")
+	}
+
+	return objectLines
+}
+
+func (w *MorpheViewFileWriter) GetCreateViewLines(viewDefinition *psqldef.View) ([]string, error) {
 	if len(viewDefinition.Columns) == 0 {
 		return nil, fmt.Errorf("view has no columns")
 	}
@@ -61,8 +86,13 @@ func (w *MorpheViewFileWriter) getCreateViewLines(viewDefinition *psqldef.View)
 		viewName = viewDefinition.Schema + "." + viewName
 	}
 
+	createViewLine := fmt.Sprintf("CREATE OR REPLACE VIEW %s AS", viewName)
+	if viewDefinition.Materialized {
+		createViewLine = fmt.Sprintf("CREATE MATERIALIZED VIEW IF NOT EXISTS %s AS", viewName)
+	}
+
 	viewLines := []string{
-		fmt.Sprintf("CREATE OR REPLACE VIEW %s AS", viewName),
+		createViewLine,
 		"SELECT",
 	}
 
@@ -112,7 +142,65 @@ func (w *MorpheViewFileWriter) getCreateViewLines(viewDefinition *psqldef.View)
 		viewLines = append(viewLines, fmt.Sprintf("WHERE %s", viewDefinition.WhereClause))
 	}
 
+	if viewDefinition.Materialized && viewDefinition.WithNoData {
+		viewLines = append(viewLines, "WITH NO DATA")
+	} else if viewDefinition.Materialized {
+		viewLines = append(viewLines, "WITH DATA")
+	}
+
 	viewLines[len(viewLines)-1] += ";"
 
 	return viewLines, nil
 }
+
+// GetViewIndexLines returns the CREATE INDEX statements of a materialized view
+func (w *MorpheViewFileWriter) GetViewIndexLines(viewDefinition *psqldef.View) []string {
+	viewName := getQualifiedRelationName(viewDefinition.Schema, viewDefinition.Name)
+
+	indexLines := []string{}
+	for _, index := range viewDefinition.Indices {
+		unique := ""
+		if index.IsUnique {
+			unique = "UNIQUE "
+		}
+		indexLines = append(indexLines, fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s (%s);",
+			unique, index.Name, viewName, strings.Join(index.Columns, ", ")))
+	}
+	return indexLines
+}
+
+// GetRefreshFunctionLines returns the function refreshing a materialized view.
+//
+// Views with a unique index are refreshed concurrently so readers are not blocked. Since a concurrent refresh
+// requires a populated view, views created WITH NO DATA are refreshed regularly until they are populated.
+func (w *MorpheViewFileWriter) GetRefreshFunctionLines(viewDefinition *psqldef.View) []string {
+	viewName := getQualifiedRelationName(viewDefinition.Schema, viewDefinition.Name)
+	functionName := getQualifiedRelationName(viewDefinition.Schema, viewDefinition.RefreshFunctionName)
+
+	functionLines := []string{
+		fmt.Sprintf("CREATE OR REPLACE FUNCTION %s()", functionName),
+		"RETURNS VOID AS $$",
+		"BEGIN",
+	}
+
+	switch {
+	case !viewDefinition.HasUniqueIndex():
+		functionLines = append(functionLines, fmt.Sprintf("\tREFRESH MATERIALIZED VIEW %s;", viewName))
+	case viewDefinition.WithNoData:
+		functionLines = append(functionLines,
+			fmt.Sprintf("\tIF (SELECT relispopulated FROM pg_class WHERE oid = '%s'::regclass) THEN", viewName),
+			fmt.Sprintf("\t\tREFRESH MATERIALIZED VIEW CONCURRENTLY %s;", viewName),
+			"\tELSE",
+			fmt.Sprintf("\t\tREFRESH MATERIALIZED VIEW %s;", viewName),
+			"\tEND IF;",
+		)
+	default:
+		functionLines = append(functionLines, fmt.Sprintf("\tREFRESH MATERIALIZED VIEW CONCURRENTLY %s;", viewName))
+	}
+
+	functionLines = append(functionLines,
+		"END;",
+		"$$ LANGUAGE plpgsql;",
+	)
+	return functionLines
+}
@@ -0,0 +1,122 @@
+package compile_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/internal/testutils"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+type MorpheViewFileWriterTestSuite struct {
+	suite.Suite
+
+	TestDirPath string
+}
+
+func TestMorpheViewFileWriterTestSuite(t *testing.T) {
+	suite.Run(t, new(MorpheViewFileWriterTestSuite))
+}
+
+func (suite *MorpheViewFileWriterTestSuite) SetupTest() {
+	suite.TestDirPath = testutils.GetTestDirPath()
+}
+
+func (suite *MorpheViewFileWriterTestSuite) TearDownTest() {
+	suite.TestDirPath = ""
+}
+
+func (suite *MorpheViewFileWriterTestSuite) getMaterializedView() *psqldef.View {
+	return &psqldef.View{
+		Schema: "public",
+		Name:   "tag_entities",
+		Columns: []psqldef.ViewColumn{
+			{Name: "id", SourceRef: "tags.id"},
+			{Name: "label", SourceRef: "tags.label"},
+		},
+		FromTable:    "tags",
+		Joins:        []psqldef.JoinClause{},
+		Materialized: true,
+		Indices: []psqldef.Index{
+			{
+				Schema:    "public",
+				Name:      "uidx_tag_entities_id",
+				TableName: "tag_entities",
+				Columns:   []string{"id"},
+				IsUnique:  true,
+			},
+		},
+		RefreshFunctionName: "fn_refresh_tag_entities",
+	}
+}
+
+func (suite *MorpheViewFileWriterTestSuite) TestWriteView_MaterializedView() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	viewWriter := &compile.MorpheViewFileWriter{
+		TargetDirPath: workingDirPath,
+	}
+
+	viewContents, writeErr := viewWriter.WriteView(suite.getMaterializedView())
+
+	suite.Nil(writeErr)
+	suite.Equal(`-- View definition for tag_entities
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS public.tag_entities AS
+SELECT
+	tags.id,
+	tags.label
+FROM tags
+WITH DATA;
+
+-- Indices
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_tag_entities_id ON public.tag_entities (id);
+
+-- Refresh function
+CREATE OR REPLACE FUNCTION public.fn_refresh_tag_entities()
+RETURNS VOID AS $$
+BEGIN
+	REFRESH MATERIALIZED VIEW CONCURRENTLY public.tag_entities;
+END;
+$$ LANGUAGE plpgsql;
+
+`, string(viewContents))
+	suite.FileExists(workingDirPath + "/tag_entities.sql")
+}
+
+func (suite *MorpheViewFileWriterTestSuite) TestGetRefreshFunctionLines_WithNoData() {
+	viewWriter := &compile.MorpheViewFileWriter{}
+	viewDefinition := suite.getMaterializedView()
+	viewDefinition.WithNoData = true
+
+	createViewLines, createErr := viewWriter.GetCreateViewLines(viewDefinition)
+
+	suite.Nil(createErr)
+	suite.Equal("WITH NO DATA;", createViewLines[len(createViewLines)-1])
+	suite.Equal([]string{
+		"CREATE OR REPLACE FUNCTION public.fn_refresh_tag_entities()",
+		"RETURNS VOID AS $$",
+		"BEGIN",
+		"\tIF (SELECT relispopulated FROM pg_class WHERE oid = 'public.tag_entities'::regclass) THEN",
+		"\t\tREFRESH MATERIALIZED VIEW CONCURRENTLY public.tag_entities;",
+		"\tELSE",
+		"\t\tREFRESH MATERIALIZED VIEW public.tag_entities;",
+		"\tEND IF;",
+		"END;",
+		"$$ LANGUAGE plpgsql;",
+	}, viewWriter.GetRefreshFunctionLines(viewDefinition))
+}
+
+func (suite *MorpheViewFileWriterTestSuite) TestGetRefreshFunctionLines_NoUniqueIndex() {
+	viewWriter := &compile.MorpheViewFileWriter{}
+	viewDefinition := suite.getMaterializedView()
+	viewDefinition.Indices = []psqldef.Index{}
+
+	suite.Contains(viewWriter.GetRefreshFunctionLines(viewDefinition), "\tREFRESH MATERIALIZED VIEW public.tag_entities;")
+}
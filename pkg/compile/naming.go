@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/gertd/go-pluralize"
-	"github.com/kalo-build/go-util/strcase"
 )
 
 // PostgreSQL identifier length limit
@@ -15,28 +14,42 @@ const maxIdentifierLength = 63
 // Client for pluralization, initialized once
 var pluralizeClient = pluralize.NewClient()
 
-// AbbreviateIdentifier shortens an identifier if it exceeds PostgreSQL's 63-character limit
+// IdentifierAffixes holds the prefixes and suffixes marking the kind of identifier a naming strategy names, e.g. "fk_"
+// or "_fkey", which are kept intact when an identifier is abbreviated
+type IdentifierAffixes struct {
+	Prefixes []string
+	Suffixes []string
+}
+
+// morpheIdentifierAffixes holds the kind prefixes of MorpheNamingStrategy identifiers
+var morpheIdentifierAffixes = IdentifierAffixes{
+	Prefixes: []string{"fk_", "uk_", "idx_", "trg_", "fn_"},
+}
+
+// AbbreviateIdentifier shortens an identifier if it exceeds PostgreSQL's 63-character limit, keeping the prefixes of
+// MorpheNamingStrategy identifiers intact
 func AbbreviateIdentifier(identifier string, useHash bool) string {
+	return AbbreviateIdentifierWithAffixes(identifier, useHash, morpheIdentifierAffixes)
+}
+
+// AbbreviateIdentifierWithAffixes shortens an identifier if it exceeds PostgreSQL's 63-character limit, keeping its
+// longest matching prefix and suffix of the given affixes intact
+func AbbreviateIdentifierWithAffixes(identifier string, useHash bool, affixes IdentifierAffixes) string {
 	// If the identifier is already within limits, return it as is
 	if len(identifier) <= maxIdentifierLength {
 		return identifier
 	}
 
-	// Split the identifier into parts (e.g., by underscores for snake_case)
-	parts := strings.Split(identifier, "_")
-	abbreviated := make([]string, len(parts))
+	// Affixes are typically important for identifying the type of object, so keep them as is
+	prefix, suffix := getIdentifierAffixes(identifier, affixes)
 
-	// For non-prefixes (like "fk", "uk", "idx"), keep them as is
-	// These are typically important for identifying the type of object
-	prefixParts := 0
-	if len(parts) > 0 && (parts[0] == "fk" || parts[0] == "uk" || parts[0] == "idx") {
-		abbreviated[0] = parts[0]
-		prefixParts = 1
-	}
+	// Split the rest of the identifier into parts (e.g., by underscores for snake_case)
+	parts := strings.Split(identifier[len(prefix):len(identifier)-len(suffix)], "_")
+	abbreviated := make([]string, len(parts))
 
-	// Abbreviate each remaining part to its first two letters
+	// Abbreviate each part to its first two letters
 	// If the part is already short (1-2 chars), keep it as is
-	for i := prefixParts; i < len(parts); i++ {
+	for i := range parts {
 		if len(parts[i]) <= 2 {
 			abbreviated[i] = parts[i]
 		} else {
@@ -45,86 +58,146 @@ func AbbreviateIdentifier(identifier string, useHash bool) string {
 	}
 
 	// Join the abbreviated parts
-	result := strings.Join(abbreviated, "_")
+	result := prefix + strings.Join(abbreviated, "_") + suffix
 
 	// If we still exceed the limit and hash is requested, add a short hash
 	if len(result) > maxIdentifierLength && useHash {
-		// Calculate how much space we have left for the main identifier
-		// allowing for an underscore and 8 character hash
-		mainLength := maxIdentifierLength - 9 // underscore + 8 chars for hash
-
 		// Truncate the main part and add a hash of the original to avoid collisions
-		hash := fmt.Sprintf("%x", md5.Sum([]byte(identifier)))[:8]
-		result = result[:mainLength] + "_" + hash
+		result = getHashedIdentifier(identifier, result, suffix)
 	} else if len(result) > maxIdentifierLength {
-		// If we're still over the limit and not using hash, just truncate
-		result = result[:maxIdentifierLength]
+		// If we're still over the limit and not using hash, just truncate before the suffix
+		result = result[:maxIdentifierLength-len(suffix)] + suffix
 	}
 
 	return result
 }
 
+// getIdentifierAffixes returns the longest prefix and suffix of the affixes the identifier starts and ends with,
+// leaving at least one character between them
+func getIdentifierAffixes(identifier string, affixes IdentifierAffixes) (string, string) {
+	prefix := ""
+	for _, affixPrefix := range affixes.Prefixes {
+		if len(affixPrefix) > len(prefix) && strings.HasPrefix(identifier, affixPrefix) {
+			prefix = affixPrefix
+		}
+	}
+	suffix := ""
+	for _, affixSuffix := range affixes.Suffixes {
+		if len(affixSuffix) > len(suffix) && len(prefix)+len(affixSuffix) < len(identifier) &&
+			strings.HasSuffix(identifier, affixSuffix) {
+			suffix = affixSuffix
+		}
+	}
+	return prefix, suffix
+}
+
+// getHashedIdentifier suffixes the abbreviation of an identifier with a hash of the full identifier, truncating the
+// abbreviation to leave room for an underscore and the 8 character hash. The given suffix of the abbreviation is kept
+// after the hash.
+func getHashedIdentifier(identifier string, abbreviated string, suffix string) string {
+	hash := fmt.Sprintf("%x", md5.Sum([]byte(identifier)))[:8]
+	abbreviated = strings.TrimSuffix(abbreviated, suffix)
+	return abbreviated[:min(len(abbreviated), maxIdentifierLength-9-len(suffix))] + "_" + hash + suffix
+}
+
 // GetTableNameFromModel returns the snake_case, pluralized table name for a model
 func GetTableNameFromModel(modelName string) string {
-	tableName := Pluralize(strcase.ToSnakeCaseLower(modelName))
-	return AbbreviateIdentifier(tableName, false)
+	return identifierNames{}.getTableNameFromModel(modelName)
+}
+
+// GetTableNameFromStructure returns the snake_case, pluralized table name for a typed structure table
+func GetTableNameFromStructure(structureName string) string {
+	return identifierNames{}.getTableNameFromStructure(structureName)
+}
+
+// GetTableNameFromEnum returns the snake_case, pluralized lookup table name for an enum
+func GetTableNameFromEnum(enumName string) string {
+	return identifierNames{}.getTableNameFromEnum(enumName)
+}
+
+// GetEnumTypeNameFromEnum returns the snake_case type name for a native enum
+func GetEnumTypeNameFromEnum(enumName string) string {
+	return identifierNames{}.getEnumTypeNameFromEnum(enumName)
+}
+
+// GetCompositeTypeNameFromStructure returns the snake_case type name for a structure composite type
+func GetCompositeTypeNameFromStructure(structureName string) string {
+	return identifierNames{}.getCompositeTypeNameFromStructure(structureName)
 }
 
 // GetColumnNameFromField returns the snake_case column name for a field
 func GetColumnNameFromField(fieldName string) string {
-	columnName := strcase.ToSnakeCaseLower(fieldName)
-	return AbbreviateIdentifier(columnName, false)
+	return identifierNames{}.getColumnNameFromField(fieldName)
 }
 
 // GetForeignKeyColumnName generates a column name for a foreign key
 func GetForeignKeyColumnName(relatedModelName, relatedFieldName string) string {
-	columnName := fmt.Sprintf("%s_%s",
-		strcase.ToSnakeCaseLower(relatedModelName),
-		strcase.ToSnakeCaseLower(relatedFieldName))
-	return AbbreviateIdentifier(columnName, false)
+	return identifierNames{}.getForeignKeyColumnName(relatedModelName, relatedFieldName)
 }
 
 // GetForeignKeyConstraintName generates a name for a foreign key constraint
 func GetForeignKeyConstraintName(tableName, columnName string) string {
-	constraintName := fmt.Sprintf("fk_%s_%s",
-		strcase.ToSnakeCaseLower(tableName),
-		columnName)
-	return AbbreviateIdentifier(constraintName, true)
+	return identifierNames{}.getForeignKeyConstraintName(tableName, columnName)
 }
 
 // GetJunctionTableForeignKeyConstraintName generates a name for a junction table foreign key constraint
 func GetJunctionTableForeignKeyConstraintName(junctionTableName, modelName, idFieldName string) string {
-	constraintName := fmt.Sprintf("fk_%s_%s_%s",
-		strcase.ToSnakeCaseLower(junctionTableName),
-		strcase.ToSnakeCaseLower(modelName),
-		strcase.ToSnakeCaseLower(idFieldName))
-	return AbbreviateIdentifier(constraintName, true)
+	return identifierNames{}.getJunctionTableForeignKeyConstraintName(junctionTableName, modelName, idFieldName)
 }
 
 // GetIndexName generates a name for an index
 func GetIndexName(tableName, columnName string) string {
-	indexName := fmt.Sprintf("idx_%s_%s", tableName, columnName)
-	return AbbreviateIdentifier(indexName, true)
+	return identifierNames{}.getIndexName(tableName, columnName)
 }
 
 // GetUniqueConstraintName generates a name for a unique constraint
 func GetUniqueConstraintName(tableName string, columnNames ...string) string {
-	parts := []string{tableName}
-	parts = append(parts, columnNames...)
-	constraintName := fmt.Sprintf("uk_%s", strings.Join(parts, "_"))
-	return AbbreviateIdentifier(constraintName, true)
+	return identifierNames{}.getUniqueConstraintName(tableName, columnNames...)
+}
+
+// GetImmutableTriggerName generates a name for the trigger guarding a table's immutable columns
+func GetImmutableTriggerName(tableName string) string {
+	return identifierNames{}.getImmutableTriggerName(tableName)
+}
+
+// GetImmutableTriggerFunctionName generates a name for the function backing a table's immutable column trigger
+func GetImmutableTriggerFunctionName(tableName string) string {
+	return identifierNames{}.getImmutableTriggerFunctionName(tableName)
+}
+
+// GetStructureCheckConstraintName generates a name for the check constraint validating a structure's data
+func GetStructureCheckConstraintName(tableName, structureName string) string {
+	return identifierNames{}.getStructureCheckConstraintName(tableName, structureName)
+}
+
+// GetPolymorphicTypeCheckConstraintName generates a name for the check constraint limiting a polymorphic relation's type column
+func GetPolymorphicTypeCheckConstraintName(tableName, typeColumnName string) string {
+	return identifierNames{}.getPolymorphicTypeCheckConstraintName(tableName, typeColumnName)
+}
+
+// GetPolymorphicTriggerName generates a name for the trigger verifying the row a polymorphic relation references
+func GetPolymorphicTriggerName(tableName, relationName string) string {
+	return identifierNames{}.getPolymorphicTriggerName(tableName, relationName)
+}
+
+// GetPolymorphicTriggerFunctionName generates a name for the function backing a polymorphic relation's trigger
+func GetPolymorphicTriggerFunctionName(tableName, relationName string) string {
+	return identifierNames{}.getPolymorphicTriggerFunctionName(tableName, relationName)
+}
+
+// GetMaterializedViewUniqueIndexName generates a name for the unique index of a materialized view
+func GetMaterializedViewUniqueIndexName(viewName string, columnNames ...string) string {
+	return identifierNames{}.getMaterializedViewUniqueIndexName(viewName, columnNames...)
+}
+
+// GetMaterializedViewRefreshFunctionName generates a name for the function refreshing a materialized view
+func GetMaterializedViewRefreshFunctionName(viewName string) string {
+	return identifierNames{}.getMaterializedViewRefreshFunctionName(viewName)
 }
 
 // GetJunctionTableName generates a name for a junction table
 func GetJunctionTableName(sourceModelName, targetModelName string) string {
-	// Generate the singular form of the junction table name
-	tableName := fmt.Sprintf("%s_%s",
-		strcase.ToSnakeCaseLower(sourceModelName),
-		strcase.ToSnakeCaseLower(targetModelName))
-
-	// Return the pluralized form
-	tableName = Pluralize(tableName)
-	return AbbreviateIdentifier(tableName, false)
+	return identifierNames{}.getJunctionTableName(sourceModelName, targetModelName)
 }
 
 // GetJunctionTableUniqueConstraintName generates a name for a junction table unique constraint
@@ -133,13 +206,7 @@ func GetJunctionTableUniqueConstraintName(
 	model1Name, model1IdName string,
 	model2Name, model2IdName string,
 ) string {
-	constraintName := fmt.Sprintf("uk_%s_%s_%s_%s_%s",
-		strcase.ToSnakeCaseLower(junctionTableName),
-		strcase.ToSnakeCaseLower(model1Name),
-		strcase.ToSnakeCaseLower(model1IdName),
-		strcase.ToSnakeCaseLower(model2Name),
-		strcase.ToSnakeCaseLower(model2IdName))
-	return AbbreviateIdentifier(constraintName, true)
+	return identifierNames{}.getJunctionTableUniqueConstraintName(junctionTableName, model1Name, model1IdName, model2Name, model2IdName)
 }
 
 // Pluralize a word using simple English rules
@@ -0,0 +1,331 @@
+package compile
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/kalo-build/go-util/strcase"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+)
+
+// NamingStrategy derives the identifiers of compiled definitions from Morphe names and from the identifiers of the
+// definitions they belong to.
+//
+// The compiler abbreviates the returned identifiers to PostgreSQL's 63-character limit, keeping the affixes returned
+// by GetIdentifierAffixes intact, so strategies do not need to shorten them. Custom strategies can embed one of the
+// presets and override the identifiers and affixes they name differently.
+type NamingStrategy interface {
+	GetTableNameFromModel(modelName string) string
+	GetTableNameFromStructure(structureName string) string
+	GetTableNameFromEnum(enumName string) string
+	GetEnumTypeNameFromEnum(enumName string) string
+	GetCompositeTypeNameFromStructure(structureName string) string
+	// GetViewNameFromEntity returns the view name of an entity, before the configured view name suffix is appended
+	GetViewNameFromEntity(entityName string) string
+
+	GetColumnNameFromField(fieldName string) string
+	GetForeignKeyColumnName(relatedModelName, relatedFieldName string) string
+
+	GetForeignKeyConstraintName(tableName, columnName string) string
+	GetIndexName(tableName, columnName string) string
+	GetUniqueConstraintName(tableName string, columnNames ...string) string
+	GetStructureCheckConstraintName(tableName, structureName string) string
+	GetPolymorphicTypeCheckConstraintName(tableName, typeColumnName string) string
+
+	GetImmutableTriggerName(tableName string) string
+	GetImmutableTriggerFunctionName(tableName string) string
+	GetPolymorphicTriggerName(tableName, relationName string) string
+	GetPolymorphicTriggerFunctionName(tableName, relationName string) string
+
+	GetMaterializedViewUniqueIndexName(viewName string, columnNames ...string) string
+	GetMaterializedViewRefreshFunctionName(viewName string) string
+
+	GetJunctionTableName(sourceModelName, targetModelName string) string
+	GetJunctionTableForeignKeyConstraintName(junctionTableName, modelName, idFieldName string) string
+	GetJunctionTableUniqueConstraintName(
+		junctionTableName string,
+		model1Name, model1IdName string,
+		model2Name, model2IdName string,
+	) string
+
+	// GetIdentifierAffixes returns the prefixes and suffixes marking the kinds of the strategy's identifiers
+	GetIdentifierAffixes() IdentifierAffixes
+}
+
+// GetNamingStrategyForPreset returns the naming strategy of a preset, the Morphe naming strategy by default
+func GetNamingStrategyForPreset(preset cfg.NamingPreset) NamingStrategy {
+	switch preset {
+	case cfg.NamingPresetPostgres:
+		return PostgresNamingStrategy{}
+	case cfg.NamingPresetRails:
+		return RailsNamingStrategy{}
+	}
+	return MorpheNamingStrategy{}
+}
+
+// MorpheNamingStrategy names tables after the snake_case, pluralized Morphe name and prefixes constraints, indices,
+// triggers and functions with their kind, e.g. "people", "fk_people_company_id" and "idx_people_company_id"
+type MorpheNamingStrategy struct{}
+
+func (MorpheNamingStrategy) GetTableNameFromModel(modelName string) string {
+	return Pluralize(strcase.ToSnakeCaseLower(modelName))
+}
+
+func (MorpheNamingStrategy) GetTableNameFromStructure(structureName string) string {
+	return Pluralize(strcase.ToSnakeCaseLower(structureName))
+}
+
+func (MorpheNamingStrategy) GetTableNameFromEnum(enumName string) string {
+	return Pluralize(strcase.ToSnakeCaseLower(enumName))
+}
+
+func (MorpheNamingStrategy) GetEnumTypeNameFromEnum(enumName string) string {
+	return strcase.ToSnakeCaseLower(enumName)
+}
+
+func (MorpheNamingStrategy) GetCompositeTypeNameFromStructure(structureName string) string {
+	return strcase.ToSnakeCaseLower(structureName)
+}
+
+func (MorpheNamingStrategy) GetViewNameFromEntity(entityName string) string {
+	return strcase.ToSnakeCaseLower(entityName)
+}
+
+func (MorpheNamingStrategy) GetColumnNameFromField(fieldName string) string {
+	return strcase.ToSnakeCaseLower(fieldName)
+}
+
+func (MorpheNamingStrategy) GetForeignKeyColumnName(relatedModelName, relatedFieldName string) string {
+	return fmt.Sprintf("%s_%s",
+		strcase.ToSnakeCaseLower(relatedModelName),
+		strcase.ToSnakeCaseLower(relatedFieldName))
+}
+
+func (MorpheNamingStrategy) GetForeignKeyConstraintName(tableName, columnName string) string {
+	return fmt.Sprintf("fk_%s_%s",
+		strcase.ToSnakeCaseLower(tableName),
+		columnName)
+}
+
+func (MorpheNamingStrategy) GetIndexName(tableName, columnName string) string {
+	return fmt.Sprintf("idx_%s_%s", tableName, columnName)
+}
+
+func (MorpheNamingStrategy) GetUniqueConstraintName(tableName string, columnNames ...string) string {
+	parts := []string{tableName}
+	parts = append(parts, columnNames...)
+	return fmt.Sprintf("uk_%s", strings.Join(parts, "_"))
+}
+
+func (MorpheNamingStrategy) GetStructureCheckConstraintName(tableName, structureName string) string {
+	return fmt.Sprintf("chk_%s_%s", tableName, strcase.ToSnakeCaseLower(structureName))
+}
+
+func (MorpheNamingStrategy) GetPolymorphicTypeCheckConstraintName(tableName, typeColumnName string) string {
+	return fmt.Sprintf("chk_%s_%s", tableName, typeColumnName)
+}
+
+func (MorpheNamingStrategy) GetImmutableTriggerName(tableName string) string {
+	return fmt.Sprintf("trg_%s_immutable", tableName)
+}
+
+func (MorpheNamingStrategy) GetImmutableTriggerFunctionName(tableName string) string {
+	return fmt.Sprintf("fn_%s_immutable", tableName)
+}
+
+func (MorpheNamingStrategy) GetPolymorphicTriggerName(tableName, relationName string) string {
+	return fmt.Sprintf("trg_%s_%s_exists", tableName, strcase.ToSnakeCaseLower(relationName))
+}
+
+func (MorpheNamingStrategy) GetPolymorphicTriggerFunctionName(tableName, relationName string) string {
+	return fmt.Sprintf("fn_%s_%s_exists", tableName, strcase.ToSnakeCaseLower(relationName))
+}
+
+func (MorpheNamingStrategy) GetMaterializedViewUniqueIndexName(viewName string, columnNames ...string) string {
+	return fmt.Sprintf("uidx_%s_%s", viewName, strings.Join(columnNames, "_"))
+}
+
+func (MorpheNamingStrategy) GetMaterializedViewRefreshFunctionName(viewName string) string {
+	return fmt.Sprintf("fn_refresh_%s", viewName)
+}
+
+func (MorpheNamingStrategy) GetJunctionTableName(sourceModelName, targetModelName string) string {
+	// Generate the singular form of the junction table name
+	tableName := fmt.Sprintf("%s_%s",
+		strcase.ToSnakeCaseLower(sourceModelName),
+		strcase.ToSnakeCaseLower(targetModelName))
+
+	// Return the pluralized form
+	return Pluralize(tableName)
+}
+
+func (MorpheNamingStrategy) GetJunctionTableForeignKeyConstraintName(junctionTableName, modelName, idFieldName string) string {
+	return fmt.Sprintf("fk_%s_%s_%s",
+		strcase.ToSnakeCaseLower(junctionTableName),
+		strcase.ToSnakeCaseLower(modelName),
+		strcase.ToSnakeCaseLower(idFieldName))
+}
+
+func (MorpheNamingStrategy) GetJunctionTableUniqueConstraintName(
+	junctionTableName string,
+	model1Name, model1IdName string,
+	model2Name, model2IdName string,
+) string {
+	return fmt.Sprintf("uk_%s_%s_%s_%s_%s",
+		strcase.ToSnakeCaseLower(junctionTableName),
+		strcase.ToSnakeCaseLower(model1Name),
+		strcase.ToSnakeCaseLower(model1IdName),
+		strcase.ToSnakeCaseLower(model2Name),
+		strcase.ToSnakeCaseLower(model2IdName))
+}
+
+func (MorpheNamingStrategy) GetIdentifierAffixes() IdentifierAffixes {
+	return morpheIdentifierAffixes
+}
+
+// PostgresNamingStrategy names tables after the singular snake_case Morphe name and suffixes constraints and indices
+// like PostgreSQL names them when no name is given, e.g. "person", "person_company_id_fkey", "person_email_key" and
+// "person_company_id_idx". Triggers and functions, which PostgreSQL does not name, are named like
+// MorpheNamingStrategy.
+type PostgresNamingStrategy struct {
+	MorpheNamingStrategy
+}
+
+func (PostgresNamingStrategy) GetTableNameFromModel(modelName string) string {
+	return strcase.ToSnakeCaseLower(modelName)
+}
+
+func (PostgresNamingStrategy) GetTableNameFromStructure(structureName string) string {
+	return strcase.ToSnakeCaseLower(structureName)
+}
+
+func (PostgresNamingStrategy) GetTableNameFromEnum(enumName string) string {
+	return strcase.ToSnakeCaseLower(enumName)
+}
+
+func (PostgresNamingStrategy) GetForeignKeyConstraintName(tableName, columnName string) string {
+	return fmt.Sprintf("%s_%s_fkey", tableName, columnName)
+}
+
+func (PostgresNamingStrategy) GetIndexName(tableName, columnName string) string {
+	return fmt.Sprintf("%s_%s_idx", tableName, columnName)
+}
+
+func (PostgresNamingStrategy) GetUniqueConstraintName(tableName string, columnNames ...string) string {
+	parts := []string{tableName}
+	parts = append(parts, columnNames...)
+	return fmt.Sprintf("%s_key", strings.Join(parts, "_"))
+}
+
+func (PostgresNamingStrategy) GetStructureCheckConstraintName(tableName, structureName string) string {
+	return fmt.Sprintf("%s_%s_check", tableName, strcase.ToSnakeCaseLower(structureName))
+}
+
+func (PostgresNamingStrategy) GetPolymorphicTypeCheckConstraintName(tableName, typeColumnName string) string {
+	return fmt.Sprintf("%s_%s_check", tableName, typeColumnName)
+}
+
+func (PostgresNamingStrategy) GetMaterializedViewUniqueIndexName(viewName string, columnNames ...string) string {
+	return fmt.Sprintf("%s_%s_idx", viewName, strings.Join(columnNames, "_"))
+}
+
+func (PostgresNamingStrategy) GetJunctionTableName(sourceModelName, targetModelName string) string {
+	return fmt.Sprintf("%s_%s",
+		strcase.ToSnakeCaseLower(sourceModelName),
+		strcase.ToSnakeCaseLower(targetModelName))
+}
+
+func (PostgresNamingStrategy) GetJunctionTableForeignKeyConstraintName(junctionTableName, modelName, idFieldName string) string {
+	return fmt.Sprintf("%s_%s_%s_fkey",
+		junctionTableName,
+		strcase.ToSnakeCaseLower(modelName),
+		strcase.ToSnakeCaseLower(idFieldName))
+}
+
+func (PostgresNamingStrategy) GetJunctionTableUniqueConstraintName(
+	junctionTableName string,
+	model1Name, model1IdName string,
+	model2Name, model2IdName string,
+) string {
+	return fmt.Sprintf("%s_%s_%s_%s_%s_key",
+		junctionTableName,
+		strcase.ToSnakeCaseLower(model1Name),
+		strcase.ToSnakeCaseLower(model1IdName),
+		strcase.ToSnakeCaseLower(model2Name),
+		strcase.ToSnakeCaseLower(model2IdName))
+}
+
+func (PostgresNamingStrategy) GetIdentifierAffixes() IdentifierAffixes {
+	return IdentifierAffixes{
+		Prefixes: []string{"trg_", "fn_"},
+		Suffixes: []string{"_pkey", "_fkey", "_key", "_idx", "_check"},
+	}
+}
+
+// RailsNamingStrategy names definitions like Active Record migrations: pluralized tables joined by pluralized
+// junction tables, "index_<table>_on_<columns>" indices and hashed "fk_rails_", "uniq_rails_" and "chk_rails_"
+// constraint names. Triggers and functions are named like MorpheNamingStrategy.
+type RailsNamingStrategy struct {
+	MorpheNamingStrategy
+}
+
+func (RailsNamingStrategy) GetForeignKeyConstraintName(tableName, columnName string) string {
+	return "fk_rails_" + getRailsIdentifierHash(tableName, columnName, "fk")
+}
+
+func (RailsNamingStrategy) GetIndexName(tableName, columnName string) string {
+	return fmt.Sprintf("index_%s_on_%s", tableName, columnName)
+}
+
+func (RailsNamingStrategy) GetUniqueConstraintName(tableName string, columnNames ...string) string {
+	return "uniq_rails_" + getRailsIdentifierHash(tableName, strings.Join(columnNames, "_"), "uniq")
+}
+
+func (RailsNamingStrategy) GetStructureCheckConstraintName(tableName, structureName string) string {
+	return "chk_rails_" + getRailsIdentifierHash(tableName, strcase.ToSnakeCaseLower(structureName), "chk")
+}
+
+func (RailsNamingStrategy) GetPolymorphicTypeCheckConstraintName(tableName, typeColumnName string) string {
+	return "chk_rails_" + getRailsIdentifierHash(tableName, typeColumnName, "chk")
+}
+
+func (RailsNamingStrategy) GetMaterializedViewUniqueIndexName(viewName string, columnNames ...string) string {
+	return fmt.Sprintf("index_%s_on_%s", viewName, strings.Join(columnNames, "_and_"))
+}
+
+func (RailsNamingStrategy) GetJunctionTableName(sourceModelName, targetModelName string) string {
+	return fmt.Sprintf("%s_%s",
+		Pluralize(strcase.ToSnakeCaseLower(sourceModelName)),
+		Pluralize(strcase.ToSnakeCaseLower(targetModelName)))
+}
+
+func (RailsNamingStrategy) GetJunctionTableForeignKeyConstraintName(junctionTableName, modelName, idFieldName string) string {
+	columnName := fmt.Sprintf("%s_%s", strcase.ToSnakeCaseLower(modelName), strcase.ToSnakeCaseLower(idFieldName))
+	return "fk_rails_" + getRailsIdentifierHash(junctionTableName, columnName, "fk")
+}
+
+func (RailsNamingStrategy) GetJunctionTableUniqueConstraintName(
+	junctionTableName string,
+	model1Name, model1IdName string,
+	model2Name, model2IdName string,
+) string {
+	columnNames := fmt.Sprintf("%s_%s_%s_%s",
+		strcase.ToSnakeCaseLower(model1Name),
+		strcase.ToSnakeCaseLower(model1IdName),
+		strcase.ToSnakeCaseLower(model2Name),
+		strcase.ToSnakeCaseLower(model2IdName))
+	return "uniq_rails_" + getRailsIdentifierHash(junctionTableName, columnNames, "uniq")
+}
+
+func (RailsNamingStrategy) GetIdentifierAffixes() IdentifierAffixes {
+	return IdentifierAffixes{
+		Prefixes: []string{"index_", "fk_rails_", "uniq_rails_", "chk_rails_", "trg_", "fn_"},
+	}
+}
+
+// getRailsIdentifierHash returns the 10 character hash Active Record suffixes generated constraint names with
+func getRailsIdentifierHash(tableName string, columnName string, kind string) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s_%s_%s", tableName, columnName, kind)))
+	return fmt.Sprintf("%x", hash)[:10]
+}
@@ -0,0 +1,326 @@
+package compile_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	"github.com/kalo-build/morphe-go/pkg/yaml"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+	"github.com/stretchr/testify/suite"
+)
+
+// dbaNamingStrategy names like PostgreSQL, except for "ix_" prefixed indices and "v_" prefixed views
+type dbaNamingStrategy struct {
+	compile.PostgresNamingStrategy
+}
+
+func (dbaNamingStrategy) GetIndexName(tableName, columnName string) string {
+	return fmt.Sprintf("ix_%s_%s", tableName, columnName)
+}
+
+func (dbaNamingStrategy) GetIdentifierAffixes() compile.IdentifierAffixes {
+	affixes := compile.PostgresNamingStrategy{}.GetIdentifierAffixes()
+	affixes.Prefixes = append(affixes.Prefixes, "ix_")
+	return affixes
+}
+
+func (dbaNamingStrategy) GetViewNameFromEntity(entityName string) string {
+	return "v_" + compile.PostgresNamingStrategy{}.GetViewNameFromEntity(entityName)
+}
+
+type NamingStrategyTestSuite struct {
+	suite.Suite
+}
+
+func TestNamingStrategyTestSuite(t *testing.T) {
+	suite.Run(t, new(NamingStrategyTestSuite))
+}
+
+func (suite *NamingStrategyTestSuite) getCompileConfig() compile.MorpheCompileConfig {
+	return compile.MorpheCompileConfig{
+		MorpheConfig: cfg.DefaultMorpheConfig(),
+	}
+}
+
+// getRegistry returns invoices related to one person and many tags, with people identified by their email
+func (suite *NamingStrategyTestSuite) getRegistry() *registry.Registry {
+	r := registry.NewRegistry()
+	r.SetModel("Person", yaml.Model{
+		Name: "Person",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+			"Email": {
+				Type: yaml.ModelFieldTypeString,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+			"email": {
+				Fields: []string{"Email"},
+			},
+		},
+	})
+	r.SetModel("Tag", yaml.Model{
+		Name: "Tag",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+	})
+	r.SetModel("Invoice", yaml.Model{
+		Name: "Invoice",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"Person": {
+				Type: "ForOne",
+			},
+			"Tag": {
+				Type: "ForMany",
+			},
+		},
+	})
+	r.SetEntity("Invoice", yaml.Entity{
+		Name: "Invoice",
+		Fields: map[string]yaml.EntityField{
+			"ID": {
+				Type: "Invoice.ID",
+			},
+			"PersonEmail": {
+				Type: "Invoice.Person.Email",
+			},
+		},
+		Identifiers: map[string]yaml.EntityIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+	})
+	return r
+}
+
+// getLongNameRegistry returns customs declarations identified by a broker reference and related to one brokerage
+// company, whose identifiers exceed PostgreSQL's 63-character limit
+func (suite *NamingStrategyTestSuite) getLongNameRegistry() *registry.Registry {
+	r := registry.NewRegistry()
+	r.SetModel("ResponsibleCustomsBrokerageCompany", yaml.Model{
+		Name: "ResponsibleCustomsBrokerageCompany",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+	})
+	r.SetModel("InternationalShipmentCustomsDeclaration", yaml.Model{
+		Name: "InternationalShipmentCustomsDeclaration",
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+			},
+			"ResponsibleBrokerReferenceNumber": {
+				Type: yaml.ModelFieldTypeString,
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+			"reference": {
+				Fields: []string{"ResponsibleBrokerReferenceNumber"},
+			},
+		},
+		Related: map[string]yaml.ModelRelation{
+			"ResponsibleCustomsBrokerageCompany": {
+				Type: "ForOne",
+			},
+		},
+	})
+	return r
+}
+
+func (suite *NamingStrategyTestSuite) TestGetNamingStrategyForPreset() {
+	suite.Equal(compile.MorpheNamingStrategy{}, compile.GetNamingStrategyForPreset(""))
+	suite.Equal(compile.MorpheNamingStrategy{}, compile.GetNamingStrategyForPreset(cfg.NamingPresetMorphe))
+	suite.Equal(compile.PostgresNamingStrategy{}, compile.GetNamingStrategyForPreset(cfg.NamingPresetPostgres))
+	suite.Equal(compile.RailsNamingStrategy{}, compile.GetNamingStrategyForPreset(cfg.NamingPresetRails))
+}
+
+func (suite *NamingStrategyTestSuite) TestGetNamingStrategy() {
+	config := suite.getCompileConfig()
+	suite.Equal(compile.MorpheNamingStrategy{}, config.GetNamingStrategy())
+
+	config.Naming = cfg.NamingPresetRails
+	suite.Equal(compile.RailsNamingStrategy{}, config.GetNamingStrategy())
+
+	config.NamingStrategy = dbaNamingStrategy{}
+	suite.Equal(dbaNamingStrategy{}, config.GetNamingStrategy())
+}
+
+func (suite *NamingStrategyTestSuite) TestAllMorpheToPSQLDefinitions_Morphe() {
+	config := suite.getCompileConfig()
+
+	definitions, compileErr := compile.AllMorpheToPSQLDefinitions(config, suite.getRegistry())
+
+	suite.Nil(compileErr)
+	suite.Equal("people", definitions.ModelTables["Person"][0].Name)
+	suite.Equal("idx_people_email", definitions.ModelTables["Person"][0].Indices[0].Name)
+
+	invoicesTable := definitions.ModelTables["Invoice"][0]
+	suite.Equal("invoices", invoicesTable.Name)
+	suite.Equal("fk_invoices_person_id", invoicesTable.ForeignKeys[0].Name)
+	suite.Equal("idx_invoices_person_id", invoicesTable.Indices[0].Name)
+
+	junctionTable := definitions.ModelTables["Invoice"][1]
+	suite.Equal("invoice_tags", junctionTable.Name)
+	suite.Equal("fk_invoice_tags_invoice_id", junctionTable.ForeignKeys[0].Name)
+	suite.Equal("uk_invoice_tags_invoice_id_tag_id", junctionTable.UniqueConstraints[0].Name)
+
+	suite.Equal("invoice_entities", definitions.EntityViews["Invoice"].Name)
+}
+
+func (suite *NamingStrategyTestSuite) TestAllMorpheToPSQLDefinitions_Postgres() {
+	config := suite.getCompileConfig()
+	config.Naming = cfg.NamingPresetPostgres
+
+	definitions, compileErr := compile.AllMorpheToPSQLDefinitions(config, suite.getRegistry())
+
+	suite.Nil(compileErr)
+	suite.Equal("person", definitions.ModelTables["Person"][0].Name)
+	suite.Equal("person_email_idx", definitions.ModelTables["Person"][0].Indices[0].Name)
+
+	invoiceTable := definitions.ModelTables["Invoice"][0]
+	suite.Equal("invoice", invoiceTable.Name)
+	suite.Equal("invoice_person_id_fkey", invoiceTable.ForeignKeys[0].Name)
+	suite.Equal("person", invoiceTable.ForeignKeys[0].RefTableName)
+	suite.Equal("invoice_person_id_idx", invoiceTable.Indices[0].Name)
+
+	junctionTable := definitions.ModelTables["Invoice"][1]
+	suite.Equal("invoice_tag", junctionTable.Name)
+	suite.Equal("invoice_tag_invoice_id_fkey", junctionTable.ForeignKeys[0].Name)
+	suite.Equal("invoice", junctionTable.ForeignKeys[0].RefTableName)
+	suite.Equal("invoice_tag_tag_id_fkey", junctionTable.ForeignKeys[1].Name)
+	suite.Equal("tag", junctionTable.ForeignKeys[1].RefTableName)
+	suite.Equal("invoice_tag_invoice_id_tag_id_key", junctionTable.UniqueConstraints[0].Name)
+
+	invoiceView := definitions.EntityViews["Invoice"]
+	suite.Equal("invoice_entities", invoiceView.Name)
+	suite.Equal("invoice", invoiceView.FromTable)
+}
+
+func (suite *NamingStrategyTestSuite) TestAllMorpheToPSQLDefinitions_Rails() {
+	config := suite.getCompileConfig()
+	config.Naming = cfg.NamingPresetRails
+
+	definitions, compileErr := compile.AllMorpheToPSQLDefinitions(config, suite.getRegistry())
+
+	suite.Nil(compileErr)
+	suite.Equal("people", definitions.ModelTables["Person"][0].Name)
+	suite.Equal("index_people_on_email", definitions.ModelTables["Person"][0].Indices[0].Name)
+
+	invoicesTable := definitions.ModelTables["Invoice"][0]
+	suite.Equal("invoices", invoicesTable.Name)
+	suite.Equal("fk_rails_a7ffa9f52c", invoicesTable.ForeignKeys[0].Name)
+	suite.Equal("index_invoices_on_person_id", invoicesTable.Indices[0].Name)
+
+	junctionTable := definitions.ModelTables["Invoice"][1]
+	suite.Equal("invoices_tags", junctionTable.Name)
+	suite.Equal("fk_rails_62d9295452", junctionTable.ForeignKeys[0].Name)
+	suite.Equal("fk_rails_8d63a6699c", junctionTable.ForeignKeys[1].Name)
+	suite.Equal("uniq_rails_ac9844ec80", junctionTable.UniqueConstraints[0].Name)
+}
+
+func (suite *NamingStrategyTestSuite) TestAllMorpheToPSQLDefinitions_CustomNamingStrategy() {
+	config := suite.getCompileConfig()
+	config.Naming = cfg.NamingPresetRails
+	config.NamingStrategy = dbaNamingStrategy{}
+
+	definitions, compileErr := compile.AllMorpheToPSQLDefinitions(config, suite.getRegistry())
+
+	suite.Nil(compileErr)
+	invoiceTable := definitions.ModelTables["Invoice"][0]
+	suite.Equal("invoice", invoiceTable.Name)
+	suite.Equal("invoice_person_id_fkey", invoiceTable.ForeignKeys[0].Name)
+	suite.Equal("ix_invoice_person_id", invoiceTable.Indices[0].Name)
+
+	invoiceView := definitions.EntityViews["Invoice"]
+	suite.Equal("v_invoice_entities", invoiceView.Name)
+	suite.Equal("invoice", invoiceView.FromTable)
+}
+
+func (suite *NamingStrategyTestSuite) TestAllMorpheModelsToPSQLTables_NamingStrategy() {
+	config := suite.getCompileConfig()
+	config.NamingStrategy = dbaNamingStrategy{}
+
+	allModelTables, compileErr := compile.AllMorpheModelsToPSQLTables(config, suite.getRegistry())
+
+	suite.Nil(compileErr)
+	suite.Equal("person", allModelTables["Person"][0].Name)
+	suite.Equal("ix_person_email", allModelTables["Person"][0].Indices[0].Name)
+}
+
+func (suite *NamingStrategyTestSuite) TestAllMorpheModelsToPSQLTables_Postgres_LongNames() {
+	config := suite.getCompileConfig()
+	config.Naming = cfg.NamingPresetPostgres
+
+	allModelTables, compileErr := compile.AllMorpheModelsToPSQLTables(config, suite.getLongNameRegistry())
+
+	suite.Nil(compileErr)
+	table := allModelTables["InternationalShipmentCustomsDeclaration"][0]
+	suite.Equal("international_shipment_customs_declaration", table.Name)
+	suite.Equal("in_sh_cu_de_re_cu_br_co_id_fkey", table.ForeignKeys[0].Name)
+	suite.Equal("in_sh_cu_de_re_cu_br_co_id_idx", table.Indices[0].Name)
+	suite.Equal("in_sh_cu_de_re_br_re_nu_idx", table.Indices[1].Name)
+}
+
+func (suite *NamingStrategyTestSuite) TestAllMorpheModelsToPSQLTables_Rails_LongNames() {
+	config := suite.getCompileConfig()
+	config.Naming = cfg.NamingPresetRails
+
+	allModelTables, compileErr := compile.AllMorpheModelsToPSQLTables(config, suite.getLongNameRegistry())
+
+	suite.Nil(compileErr)
+	table := allModelTables["InternationalShipmentCustomsDeclaration"][0]
+	suite.Equal("international_shipment_customs_declarations", table.Name)
+	suite.Equal("fk_rails_389ce9c3bd", table.ForeignKeys[0].Name)
+	suite.Equal("index_in_sh_cu_de_on_re_cu_br_co_id", table.Indices[0].Name)
+	suite.Equal("index_in_sh_cu_de_on_re_br_re_nu", table.Indices[1].Name)
+}
+
+func (suite *NamingStrategyTestSuite) TestAllMorpheModelsToPSQLTables_CustomNamingStrategy_LongNames() {
+	config := suite.getCompileConfig()
+	config.NamingStrategy = dbaNamingStrategy{}
+
+	allModelTables, compileErr := compile.AllMorpheModelsToPSQLTables(config, suite.getLongNameRegistry())
+
+	suite.Nil(compileErr)
+	table := allModelTables["InternationalShipmentCustomsDeclaration"][0]
+	suite.Equal("international_shipment_customs_declaration", table.Name)
+	suite.Equal("in_sh_cu_de_re_cu_br_co_id_fkey", table.ForeignKeys[0].Name)
+	suite.Equal("ix_in_sh_cu_de_re_cu_br_co_id", table.Indices[0].Name)
+	suite.Equal("ix_in_sh_cu_de_re_br_re_nu", table.Indices[1].Name)
+}
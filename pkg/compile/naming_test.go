@@ -200,6 +200,34 @@ func (suite *NamingTestSuite) TestAbbreviateIdentifier() {
 	suite.LessOrEqual(len(constraintResult), 63)
 }
 
+func (suite *NamingTestSuite) TestAbbreviateIdentifierWithAffixes() {
+	affixes := compile.IdentifierAffixes{
+		Prefixes: []string{"ix_", "index_"},
+		Suffixes: []string{"_key", "_fkey"},
+	}
+
+	// Short identifiers should remain unchanged
+	suite.Equal("orders_customer_id_fkey", compile.AbbreviateIdentifierWithAffixes("orders_customer_id_fkey", true, affixes))
+
+	// The longest matching prefix and suffix should be preserved
+	longName := "international_shipment_customs_declarations_responsible_broker_id_fkey"
+	suite.Equal("in_sh_cu_de_re_br_id_fkey", compile.AbbreviateIdentifierWithAffixes(longName, true, affixes))
+	longName = "index_international_shipment_customs_declarations_on_responsible_broker_id"
+	suite.Equal("index_in_sh_cu_de_on_re_br_id", compile.AbbreviateIdentifierWithAffixes(longName, true, affixes))
+
+	// The suffix should follow the hash of very long identifiers
+	veryLongName := "ix_" + strings.Repeat("very_long_part_", 20) + "key"
+	veryLongResult := compile.AbbreviateIdentifierWithAffixes(veryLongName, true, affixes)
+	suite.Len(veryLongResult, 63)
+	suite.True(strings.HasPrefix(veryLongResult, "ix_ve_lo_pa_"),
+		"Expected 'ix_' prefix to be preserved, got: %s", veryLongResult)
+	suite.Regexp("_[0-9a-f]{8}_key$", veryLongResult)
+
+	// The suffix should follow the truncation of very long identifiers without hash
+	suite.Equal("ix_ve_lo_pa_ve_lo_pa_ve_lo_pa_ve_lo_pa_ve_lo_pa_ve_lo_pa_ve_key",
+		compile.AbbreviateIdentifierWithAffixes(veryLongName, false, affixes))
+}
+
 func (suite *NamingTestSuite) TestGetTableNameFromModel_LongNames() {
 	veryLongModelName := "ExtremelyLongModelNameThatWouldExceedPostgreSQLIdentifierLengthLimits"
 
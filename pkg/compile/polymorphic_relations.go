@@ -0,0 +1,152 @@
+package compile
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	"github.com/kalo-build/morphe-go/pkg/yaml"
+	"github.com/kalo-build/morphe-go/pkg/yamlops"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// isPolymorphicRelation returns true for relation types referencing one of several models, e.g. "ForOnePoly"
+func isPolymorphicRelation(relationType string) bool {
+	return strings.HasSuffix(strings.ToLower(relationType), "poly")
+}
+
+// addPolymorphicRelationsToTable adds the columns and constraints of the model's polymorphic ForOne relations.
+//
+// Each relation is stored as a <relation>_type column holding the referenced model's name and a <relation>_id column
+// holding its primary key. A CHECK limits the type to the configured target models and, since a foreign key cannot
+// reference several tables, a trigger verifies that the referenced row exists.
+func addPolymorphicRelationsToTable(table *psqldef.Table, config cfg.MorpheModelsConfig, names identifierNames, r *registry.Registry, relatedTypeMap map[yaml.ModelFieldType]psqldef.PSQLType, model yaml.Model) error {
+	relationNames := core.MapKeysSorted(model.Related)
+	for _, relationName := range relationNames {
+		relationType := model.Related[relationName].Type
+		if !isPolymorphicRelation(relationType) || yamlops.IsRelationHas(relationType) {
+			continue
+		}
+		if yamlops.IsRelationMany(relationType) {
+			return ErrUnsupportedPolymorphicRelation(model.Name, relationName, relationType)
+		}
+
+		targetModels, targetsErr := getPolymorphicRelationTargetModels(config, r, model.Name, relationName)
+		if targetsErr != nil {
+			return targetsErr
+		}
+		idColumnType, idColumnTypeErr := getPolymorphicRelationIDColumnType(relatedTypeMap, model.Name, relationName, targetModels)
+		if idColumnTypeErr != nil {
+			return idColumnTypeErr
+		}
+
+		typeColumnName := names.getForeignKeyColumnName(relationName, "Type")
+		idColumnName := names.getForeignKeyColumnName(relationName, "ID")
+		table.Columns = append(table.Columns,
+			psqldef.TableColumn{
+				Name:    typeColumnName,
+				Type:    psqldef.PSQLTypeText,
+				NotNull: true,
+			},
+			psqldef.TableColumn{
+				Name:    idColumnName,
+				Type:    idColumnType,
+				NotNull: true,
+			},
+		)
+
+		table.Indices = append(table.Indices, psqldef.Index{
+			Name:      names.getIndexName(table.Name, typeColumnName+"_"+idColumnName),
+			TableName: table.Name,
+			Columns:   []string{typeColumnName, idColumnName},
+		})
+
+		targetTypeNames := []string{}
+		for _, targetModel := range targetModels {
+			targetTypeNames = append(targetTypeNames, quoteSQLString(targetModel.Name))
+		}
+		table.CheckConstraints = append(table.CheckConstraints, psqldef.CheckConstraint{
+			Schema:     table.Schema,
+			Name:       names.getPolymorphicTypeCheckConstraintName(table.Name, typeColumnName),
+			TableName:  table.Name,
+			Expression: fmt.Sprintf(`"%s" IN (%s)`, typeColumnName, strings.Join(targetTypeNames, ", ")),
+		})
+
+		table.Triggers = append(table.Triggers, psqldef.Trigger{
+			Schema:       table.Schema,
+			Name:         names.getPolymorphicTriggerName(table.Name, relationName),
+			TableName:    table.Name,
+			Timing:       "BEFORE",
+			Events:       []string{"INSERT", "UPDATE"},
+			FunctionName: names.getPolymorphicTriggerFunctionName(table.Name, relationName),
+			FunctionBody: getPolymorphicRelationTriggerBody(names, config.Schema, table.Name, typeColumnName, idColumnName, targetModels),
+		})
+	}
+
+	return nil
+}
+
+// getPolymorphicRelationTargetModels returns the configured target models of a polymorphic relation, sorted by name
+func getPolymorphicRelationTargetModels(config cfg.MorpheModelsConfig, r *registry.Registry, modelName string, relationName string) ([]yaml.Model, error) {
+	targetModelNames := slices.Clone(config.GetPolymorphicRelationTargets(modelName, relationName))
+	if len(targetModelNames) == 0 {
+		return nil, cfg.ErrNoPolymorphicRelationTargets(modelName, relationName)
+	}
+	slices.Sort(targetModelNames)
+
+	targetModels := []yaml.Model{}
+	for _, targetModelName := range slices.Compact(targetModelNames) {
+		targetModel, modelErr := r.GetModel(targetModelName)
+		if modelErr != nil {
+			return nil, modelErr
+		}
+		targetModels = append(targetModels, targetModel)
+	}
+	return targetModels, nil
+}
+
+// getPolymorphicRelationIDColumnType returns the type of the id column, which all target primary keys must share
+func getPolymorphicRelationIDColumnType(relatedTypeMap map[yaml.ModelFieldType]psqldef.PSQLType, modelName string, relationName string, targetModels []yaml.Model) (psqldef.PSQLType, error) {
+	var idColumnType psqldef.PSQLType
+	for _, targetModel := range targetModels {
+		primaryIdNames, primaryErr := getModelPrimaryIdentifierFieldNames(targetModel)
+		if primaryErr != nil {
+			return nil, primaryErr
+		}
+		if len(primaryIdNames) != 1 {
+			return nil, ErrPolymorphicRelationKeyMismatch(modelName, relationName)
+		}
+		columnType, columnTypeErr := getForeignColumnTypeForModelField(relatedTypeMap, targetModel, primaryIdNames[0])
+		if columnTypeErr != nil {
+			return nil, columnTypeErr
+		}
+		if idColumnType != nil && idColumnType.GetSyntax() != columnType.GetSyntax() {
+			return nil, ErrPolymorphicRelationKeyMismatch(modelName, relationName)
+		}
+		idColumnType = columnType
+	}
+	return idColumnType, nil
+}
+
+// getPolymorphicRelationTriggerBody returns trigger statements rejecting rows whose referenced row does not exist
+func getPolymorphicRelationTriggerBody(names identifierNames, schema string, tableName string, typeColumnName string, idColumnName string, targetModels []yaml.Model) []string {
+	functionBody := []string{}
+	for _, targetModel := range targetModels {
+		targetTableName := names.getTableNameFromModel(targetModel.Name)
+		targetPrimaryIdNames, _ := getModelPrimaryIdentifierFieldNames(targetModel)
+		targetIdColumnName := names.getColumnNameFromField(targetPrimaryIdNames[0])
+		functionBody = append(functionBody,
+			fmt.Sprintf(`IF NEW."%s" = %s AND NOT EXISTS (SELECT 1 FROM %s WHERE "%s" = NEW."%s") THEN`,
+				typeColumnName, quoteSQLString(targetModel.Name),
+				getQualifiedRelationName(schema, targetTableName), targetIdColumnName, idColumnName),
+			fmt.Sprintf(`	RAISE EXCEPTION 'column %s of table %s references missing %s row %%', NEW."%s";`,
+				idColumnName, tableName, targetTableName, idColumnName),
+			"END IF;",
+		)
+	}
+	functionBody = append(functionBody, "RETURN NEW;")
+	return functionBody
+}
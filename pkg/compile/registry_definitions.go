@@ -0,0 +1,121 @@
+package compile
+
+import (
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// RegistryDefinitions holds the PostgreSQL definitions compiled from a Morphe registry, keyed by the name of the
+// Morphe definition they were compiled from
+type RegistryDefinitions struct {
+	// EnumTypes holds native enum types, EnumTables lookup tables, depending on the enum strategy
+	EnumTypes  map[string]*psqldef.PSQLTypeEnum
+	EnumTables map[string]*psqldef.Table
+
+	StructureTypes map[string]*psqldef.PSQLTypeComposite
+	ModelTables    map[string][]*psqldef.Table
+
+	// StructureTables holds typed structure tables, StructureTable the table storing all structures as JSON
+	StructureTables map[string]*psqldef.Table
+	StructureTable  *psqldef.Table
+
+	EntityViews map[string]*psqldef.View
+}
+
+// AllMorpheToPSQLDefinitions compiles all enums, structures, models and entities of the registry.
+//
+// Identifiers must be unique among the compiled definitions, which long Morphe names can break once abbreviated to
+// PostgreSQL's 63-character limit. Collisions fail the compilation with a report of the colliding definitions, unless
+// the identifier collision strategy is "hash": the registry is then compiled a second time (running the compile hooks
+// again), suffixing the abbreviations of the colliding identifiers with a hash of their full identifier.
+func AllMorpheToPSQLDefinitions(config MorpheCompileConfig, r *registry.Registry) (*RegistryDefinitions, error) {
+	if r == nil {
+		return nil, ErrNoRegistry
+	}
+
+	config.identifierNames = newIdentifierNames(config.GetNamingStrategy(), nil)
+	definitions, compileErr := compileRegistryDefinitions(config, r)
+	if compileErr != nil {
+		return nil, compileErr
+	}
+	collisions := getIdentifierCollisions(definitions, config.identifierNames)
+	if len(collisions) == 0 {
+		return definitions, nil
+	}
+
+	hashedIdentifiers := map[string]bool{}
+	for _, collision := range collisions {
+		for _, identifier := range collision.AbbreviatedIdentifiers {
+			hashedIdentifiers[identifier] = true
+		}
+	}
+	// Hashes only tell apart identifiers which were abbreviated
+	if !config.IdentifierCollisions.IsHash() || len(hashedIdentifiers) == 0 {
+		return nil, ErrIdentifierCollisions(collisions)
+	}
+
+	config.identifierNames = newIdentifierNames(config.GetNamingStrategy(), hashedIdentifiers)
+	definitions, compileErr = compileRegistryDefinitions(config, r)
+	if compileErr != nil {
+		return nil, compileErr
+	}
+	collisions = getIdentifierCollisions(definitions, config.identifierNames)
+	if len(collisions) > 0 {
+		return nil, ErrIdentifierCollisions(collisions)
+	}
+	return definitions, nil
+}
+
+func compileRegistryDefinitions(config MorpheCompileConfig, r *registry.Registry) (*RegistryDefinitions, error) {
+	definitions := &RegistryDefinitions{}
+
+	if config.MorpheEnumsConfig.IsNativeEnum() {
+		allEnumTypes, compileAllEnumTypesErr := AllMorpheEnumsToPSQLTypes(config, r)
+		if compileAllEnumTypesErr != nil {
+			return nil, compileAllEnumTypesErr
+		}
+		definitions.EnumTypes = allEnumTypes
+	} else {
+		allEnumTables, compileAllEnumsErr := AllMorpheEnumsToPSQLTables(config, r)
+		if compileAllEnumsErr != nil {
+			return nil, compileAllEnumsErr
+		}
+		definitions.EnumTables = allEnumTables
+	}
+
+	if config.MorpheStructuresConfig.IsCompositeType() {
+		allStructureTypes, compileAllStructureTypesErr := AllMorpheStructuresToPSQLTypes(config, r)
+		if compileAllStructureTypesErr != nil {
+			return nil, compileAllStructureTypesErr
+		}
+		definitions.StructureTypes = allStructureTypes
+	}
+
+	allModelTables, compileAllModelsErr := AllMorpheModelsToPSQLTables(config, r)
+	if compileAllModelsErr != nil {
+		return nil, compileAllModelsErr
+	}
+	definitions.ModelTables = allModelTables
+
+	if config.MorpheStructuresConfig.EnablePersistence && config.MorpheStructuresConfig.IsTypedTable() {
+		allStructureTables, compileAllStructuresErr := AllMorpheStructuresToPSQLTables(config, r)
+		if compileAllStructuresErr != nil {
+			return nil, compileAllStructuresErr
+		}
+		definitions.StructureTables = allStructureTables
+	} else if config.MorpheStructuresConfig.EnablePersistence {
+		structureTable, compileStructureErr := MorpheStructureToPSQLTable(config, r)
+		if compileStructureErr != nil {
+			return nil, compileStructureErr
+		}
+		definitions.StructureTable = structureTable
+	}
+
+	allEntityViews, compileAllEntityViewsErr := AllMorpheEntitiesToPSQLViews(config, r)
+	if compileAllEntityViewsErr != nil {
+		return nil, compileAllEntityViewsErr
+	}
+	definitions.EntityViews = allEntityViews
+
+	return definitions, nil
+}
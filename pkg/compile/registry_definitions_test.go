@@ -0,0 +1,164 @@
+package compile_test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	"github.com/kalo-build/morphe-go/pkg/yaml"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	recordModelName = "CustomerSubscriptionInvoiceLineItemAdjustmentReconciliationRecord"
+	reportModelName = "CustomerSubscriptionInvoiceLineItemAdjustmentReconciliationReport"
+)
+
+type RegistryDefinitionsTestSuite struct {
+	suite.Suite
+}
+
+func TestRegistryDefinitionsTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistryDefinitionsTestSuite))
+}
+
+func (suite *RegistryDefinitionsTestSuite) getCompileConfig(collisionStrategy cfg.IdentifierCollisionStrategy) compile.MorpheCompileConfig {
+	return compile.MorpheCompileConfig{
+		MorpheConfig: cfg.MorpheConfig{
+			MorpheModelsConfig: cfg.MorpheModelsConfig{
+				Schema: "public",
+			},
+			MorpheEnumsConfig: cfg.MorpheEnumsConfig{
+				Schema: "public",
+			},
+			MorpheEntitiesConfig: cfg.MorpheEntitiesConfig{
+				Schema:         "public",
+				ViewNameSuffix: "_entities",
+			},
+			IdentifierCollisions: collisionStrategy,
+		},
+	}
+}
+
+func (suite *RegistryDefinitionsTestSuite) getModel(modelName string, related map[string]yaml.ModelRelation) yaml.Model {
+	return yaml.Model{
+		Name: modelName,
+		Fields: map[string]yaml.ModelField{
+			"ID": {
+				Type: yaml.ModelFieldTypeAutoIncrement,
+				Attributes: []string{
+					"mandatory",
+				},
+			},
+		},
+		Identifiers: map[string]yaml.ModelIdentifier{
+			"primary": {
+				Fields: []string{"ID"},
+			},
+		},
+		Related: related,
+	}
+}
+
+// getCollidingRegistry returns a registry with two long model names abbreviated to the same table name
+func (suite *RegistryDefinitionsTestSuite) getCollidingRegistry() *registry.Registry {
+	r := registry.NewRegistry()
+	r.SetModel(recordModelName, suite.getModel(recordModelName, nil))
+	r.SetModel(reportModelName, suite.getModel(reportModelName, nil))
+	r.SetModel("Invoice", suite.getModel("Invoice", map[string]yaml.ModelRelation{
+		recordModelName: {
+			Type: "ForOne",
+		},
+	}))
+	return r
+}
+
+func (suite *RegistryDefinitionsTestSuite) getHashedTableName(fullTableName string) string {
+	hash := md5.Sum([]byte(fullTableName))
+	return "cu_su_in_li_it_ad_re_re_" + hex.EncodeToString(hash[:])[:8]
+}
+
+func (suite *RegistryDefinitionsTestSuite) TestAllMorpheToPSQLDefinitions() {
+	config := suite.getCompileConfig("")
+	r := registry.NewRegistry()
+	r.SetModel("Person", suite.getModel("Person", nil))
+	r.SetModel("Invoice", suite.getModel("Invoice", map[string]yaml.ModelRelation{
+		"Person": {
+			Type: "ForOne",
+		},
+	}))
+
+	definitions, compileErr := compile.AllMorpheToPSQLDefinitions(config, r)
+
+	suite.Nil(compileErr)
+	suite.NotNil(definitions)
+	suite.Len(definitions.ModelTables, 2)
+	suite.Equal("people", definitions.ModelTables["Person"][0].Name)
+	suite.Equal("invoices", definitions.ModelTables["Invoice"][0].Name)
+	suite.Equal("people", definitions.ModelTables["Invoice"][0].ForeignKeys[0].RefTableName)
+}
+
+func (suite *RegistryDefinitionsTestSuite) TestAllMorpheToPSQLDefinitions_NoRegistry() {
+	config := suite.getCompileConfig("")
+
+	definitions, compileErr := compile.AllMorpheToPSQLDefinitions(config, nil)
+
+	suite.Nil(definitions)
+	suite.ErrorIs(compileErr, compile.ErrNoRegistry)
+}
+
+func (suite *RegistryDefinitionsTestSuite) TestAllMorpheToPSQLDefinitions_IdentifierCollision() {
+	config := suite.getCompileConfig(cfg.IdentifierCollisionStrategyFail)
+	r := suite.getCollidingRegistry()
+
+	definitions, compileErr := compile.AllMorpheToPSQLDefinitions(config, r)
+
+	suite.Nil(definitions)
+	suite.EqualError(compileErr, "compiled definitions share identifiers:\n"+
+		"\tidentifier 'cu_su_in_li_it_ad_re_re' in schema 'public' is used by "+
+		"table of model '"+recordModelName+"', table of model '"+reportModelName+"' "+
+		"(abbreviated from 'customer_subscription_invoice_line_item_adjustment_reconciliation_records', "+
+		"'customer_subscription_invoice_line_item_adjustment_reconciliation_reports')")
+}
+
+func (suite *RegistryDefinitionsTestSuite) TestAllMorpheToPSQLDefinitions_IdentifierCollision_Hash() {
+	config := suite.getCompileConfig(cfg.IdentifierCollisionStrategyHash)
+	r := suite.getCollidingRegistry()
+
+	definitions, compileErr := compile.AllMorpheToPSQLDefinitions(config, r)
+
+	suite.Nil(compileErr)
+	suite.NotNil(definitions)
+
+	recordTableName := suite.getHashedTableName("customer_subscription_invoice_line_item_adjustment_reconciliation_records")
+	reportTableName := suite.getHashedTableName("customer_subscription_invoice_line_item_adjustment_reconciliation_reports")
+	suite.NotEqual(recordTableName, reportTableName)
+	suite.Equal(recordTableName, definitions.ModelTables[recordModelName][0].Name)
+	suite.Equal(reportTableName, definitions.ModelTables[reportModelName][0].Name)
+
+	invoicesTable := definitions.ModelTables["Invoice"][0]
+	suite.Equal("invoices", invoicesTable.Name)
+	suite.Len(invoicesTable.ForeignKeys, 1)
+	suite.Equal(recordTableName, invoicesTable.ForeignKeys[0].RefTableName)
+}
+
+func (suite *RegistryDefinitionsTestSuite) TestAllMorpheToPSQLDefinitions_IdentifierCollision_HashUnabbreviated() {
+	config := suite.getCompileConfig(cfg.IdentifierCollisionStrategyHash)
+	r := registry.NewRegistry()
+	r.SetModel("Status", suite.getModel("Status", nil))
+	r.SetEnum("Status", yaml.Enum{
+		Name: "Status",
+		Type: yaml.EnumTypeString,
+		Entries: map[string]any{
+			"Active": "active",
+		},
+	})
+
+	definitions, compileErr := compile.AllMorpheToPSQLDefinitions(config, r)
+
+	suite.Nil(definitions)
+	suite.ErrorContains(compileErr, "identifier 'statuses' in schema 'public' is used by table of enum 'Status', table of model 'Status'")
+}
@@ -0,0 +1,6 @@
+package write
+
+// PSQLFlusher is implemented by writers that collect the written definitions and only write them out once flushed
+type PSQLFlusher interface {
+	Flush() ([]byte, error)
+}
@@ -0,0 +1,7 @@
+package write
+
+import "github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+
+type PSQLTypeWriter interface {
+	WriteType(psqldef.PSQLType) ([]byte, error)
+}
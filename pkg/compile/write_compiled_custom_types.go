@@ -0,0 +1,22 @@
+package compile
+
+import (
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// WriteAllCustomTypeDefinitions writes the custom types in the given (dependency) order
+func WriteAllCustomTypeDefinitions(config MorpheCompileConfig, allCustomTypeDefs []psqldef.PSQLType) (CompiledMorpheTypes, error) {
+	allWrittenCustomTypes := CompiledMorpheTypes{}
+
+	for _, customType := range allCustomTypeDefs {
+		if customType == nil {
+			return nil, ErrNoPSQLType
+		}
+		writtenCustomType, customTypeContents, writeErr := WriteTypeDefinition(config.WriteTypeHooks, config.TypeWriter, customType)
+		if writeErr != nil {
+			return nil, writeErr
+		}
+		allWrittenCustomTypes.AddCompiledMorpheType(customType.GetSyntax(), writtenCustomType, customTypeContents)
+	}
+	return allWrittenCustomTypes, nil
+}
@@ -0,0 +1,90 @@
+package compile
+
+import (
+	"github.com/kalo-build/clone"
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/hook"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/write"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+func WriteAllEnumTypeDefinitions(config MorpheCompileConfig, allEnumTypeDefs map[string]*psqldef.PSQLTypeEnum) (CompiledMorpheTypes, error) {
+	allWrittenEnumTypes := CompiledMorpheTypes{}
+
+	sortedEnumNames := core.MapKeysSorted(allEnumTypeDefs)
+	for _, enumName := range sortedEnumNames {
+		enumType := allEnumTypeDefs[enumName]
+		if enumType == nil {
+			return nil, ErrNoEnumType
+		}
+		writtenEnumType, enumTypeContents, writeErr := WriteTypeDefinition(config.WriteTypeHooks, config.EnumTypeWriter, *enumType)
+		if writeErr != nil {
+			return nil, writeErr
+		}
+		allWrittenEnumTypes.AddCompiledMorpheType(enumName, writtenEnumType, enumTypeContents)
+	}
+	return allWrittenEnumTypes, nil
+}
+
+func WriteTypeDefinition(hooks hook.WritePSQLType, writer write.PSQLTypeWriter, psqlType psqldef.PSQLType) (psqldef.PSQLType, []byte, error) {
+	writer, psqlType, writeStartErr := triggerWriteTypeStart(hooks, writer, psqlType)
+	if writeStartErr != nil {
+		return nil, nil, triggerWriteTypeFailure(hooks, writer, psqlType, writeStartErr)
+	}
+
+	typeContents, writeTypeErr := writer.WriteType(psqlType)
+	if writeTypeErr != nil {
+		return nil, nil, triggerWriteTypeFailure(hooks, writer, psqlType, writeTypeErr)
+	}
+
+	psqlType, typeContents, writeSuccessErr := triggerWriteTypeSuccess(hooks, psqlType, typeContents)
+	if writeSuccessErr != nil {
+		return nil, nil, triggerWriteTypeFailure(hooks, writer, psqlType, writeSuccessErr)
+	}
+	return psqlType, typeContents, nil
+}
+
+func triggerWriteTypeStart(hooks hook.WritePSQLType, writer write.PSQLTypeWriter, psqlType psqldef.PSQLType) (write.PSQLTypeWriter, psqldef.PSQLType, error) {
+	if hooks.OnWritePSQLTypeStart == nil {
+		return writer, psqlType, nil
+	}
+	if psqlType == nil {
+		return nil, nil, ErrNoPSQLType
+	}
+	psqlTypeClone := psqldef.DeepClonePSQLType(psqlType)
+
+	updatedWriter, updatedType, startErr := hooks.OnWritePSQLTypeStart(writer, psqlTypeClone)
+	if startErr != nil {
+		return nil, nil, startErr
+	}
+
+	return updatedWriter, updatedType, nil
+}
+
+func triggerWriteTypeSuccess(hooks hook.WritePSQLType, psqlType psqldef.PSQLType, typeContents []byte) (psqldef.PSQLType, []byte, error) {
+	if hooks.OnWritePSQLTypeSuccess == nil {
+		return psqlType, typeContents, nil
+	}
+	if psqlType == nil {
+		return nil, nil, ErrNoPSQLType
+	}
+	psqlTypeClone := psqldef.DeepClonePSQLType(psqlType)
+	typeContentsClone := clone.Slice(typeContents)
+
+	updatedType, updatedTypeContents, successErr := hooks.OnWritePSQLTypeSuccess(psqlTypeClone, typeContentsClone)
+	if successErr != nil {
+		return nil, nil, successErr
+	}
+	return updatedType, updatedTypeContents, nil
+}
+
+func triggerWriteTypeFailure(hooks hook.WritePSQLType, writer write.PSQLTypeWriter, psqlType psqldef.PSQLType, failureErr error) error {
+	if hooks.OnWritePSQLTypeFailure == nil {
+		return failureErr
+	}
+
+	if psqlType == nil {
+		return hooks.OnWritePSQLTypeFailure(writer, nil, failureErr)
+	}
+	return hooks.OnWritePSQLTypeFailure(writer, psqldef.DeepClonePSQLType(psqlType), failureErr)
+}
@@ -0,0 +1,24 @@
+package compile
+
+import (
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+func WriteAllStructureTypeDefinitions(config MorpheCompileConfig, allStructureTypeDefs map[string]*psqldef.PSQLTypeComposite) (CompiledMorpheTypes, error) {
+	allWrittenStructureTypes := CompiledMorpheTypes{}
+
+	sortedStructureNames := core.MapKeysSorted(allStructureTypeDefs)
+	for _, structureName := range sortedStructureNames {
+		structureType := allStructureTypeDefs[structureName]
+		if structureType == nil {
+			return nil, ErrNoStructureType
+		}
+		writtenStructureType, structureTypeContents, writeErr := WriteTypeDefinition(config.WriteTypeHooks, config.StructureTypeWriter, *structureType)
+		if writeErr != nil {
+			return nil, writeErr
+		}
+		allWrittenStructureTypes.AddCompiledMorpheType(structureName, writtenStructureType, structureTypeContents)
+	}
+	return allWrittenStructureTypes, nil
+}
@@ -0,0 +1,21 @@
+package compile
+
+import (
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+func WriteAllStructureTableDefinitions(config MorpheCompileConfig, allStructureTableDefs map[string]*psqldef.Table) (CompiledMorpheTables, error) {
+	allWrittenStructures := CompiledMorpheTables{}
+
+	sortedStructureNames := core.MapKeysSorted(allStructureTableDefs)
+	for _, structureName := range sortedStructureNames {
+		structureTable := allStructureTableDefs[structureName]
+		structureTable, structureTableContents, writeErr := WriteStructureTableDefinition(config.WriteTableHooks, config.StructureWriter, structureTable)
+		if writeErr != nil {
+			return nil, writeErr
+		}
+		allWrittenStructures.AddCompiledMorpheTable(structureName, structureTable, structureTableContents)
+	}
+	return allWrittenStructures, nil
+}
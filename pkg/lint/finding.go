@@ -0,0 +1,17 @@
+package lint
+
+import "fmt"
+
+// Finding is a problem a lint rule found in the compiled definitions
+type Finding struct {
+	RuleID   string
+	Severity Severity
+
+	// ObjectName is the qualified name of the table or view the finding is about
+	ObjectName string
+	Message    string
+}
+
+func (finding Finding) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", finding.Severity, finding.RuleID, finding.ObjectName, finding.Message)
+}
@@ -0,0 +1,65 @@
+package lint
+
+import (
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+)
+
+// Report holds the findings of linting a registry
+type Report struct {
+	Findings     []Finding
+	FailSeverity Severity
+}
+
+// GetFailingFindings returns the findings at or above the fail severity
+func (report Report) GetFailingFindings() []Finding {
+	failingFindings := []Finding{}
+	for _, finding := range report.Findings {
+		if finding.Severity.IsAtLeast(report.FailSeverity) {
+			failingFindings = append(failingFindings, finding)
+		}
+	}
+	return failingFindings
+}
+
+// IsFailing returns true if any finding is at or above the fail severity
+func (report Report) IsFailing() bool {
+	return len(report.GetFailingFindings()) > 0
+}
+
+// MorpheToPSQLLint compiles the registry without writing any files and runs the lint rules against the compiled
+// tables and views
+func MorpheToPSQLLint(config MorpheLintConfig) (*Report, error) {
+	validateErr := config.Validate()
+	if validateErr != nil {
+		return nil, validateErr
+	}
+
+	r, rErr := registry.LoadMorpheRegistry(config.CompileConfig.RegistryHooks, config.CompileConfig.MorpheLoadRegistryConfig)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	snapshot, snapshotErr := migrate.CompileSnapshot(config.CompileConfig, r)
+	if snapshotErr != nil {
+		return nil, snapshotErr
+	}
+
+	return &Report{
+		Findings:     LintSnapshot(snapshot, config.GetRules()),
+		FailSeverity: config.GetFailSeverity(),
+	}, nil
+}
+
+// LintSnapshot runs the rules against the snapshot and returns their findings in rule order
+func LintSnapshot(snapshot migrate.Snapshot, rules []Rule) []Finding {
+	findings := []Finding{}
+	for _, rule := range rules {
+		for _, finding := range rule.Check(snapshot) {
+			finding.RuleID = rule.ID
+			finding.Severity = rule.Severity
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,24 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNoRuleID = errors.New("lint rule ID cannot be empty")
+
+func ErrNoRuleCheck(ruleID string) error {
+	return fmt.Errorf("lint rule '%s' has no check", ruleID)
+}
+
+func ErrDuplicateRuleID(ruleID string) error {
+	return fmt.Errorf("lint rule '%s' is defined more than once", ruleID)
+}
+
+func ErrUnknownSeverity(severity Severity) error {
+	return fmt.Errorf("unknown lint severity '%s'", severity)
+}
+
+func ErrUnknownRuleID(ruleID string) error {
+	return fmt.Errorf("unknown lint rule '%s'", ruleID)
+}
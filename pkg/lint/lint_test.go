@@ -0,0 +1,399 @@
+package lint_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	rcfg "github.com/kalo-build/morphe-go/pkg/registry/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/internal/testutils"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/lint"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+type LintTestSuite struct {
+	suite.Suite
+
+	TestDirPath string
+}
+
+func TestLintTestSuite(t *testing.T) {
+	suite.Run(t, new(LintTestSuite))
+}
+
+func (suite *LintTestSuite) SetupTest() {
+	suite.TestDirPath = testutils.GetTestDirPath()
+}
+
+func (suite *LintTestSuite) TearDownTest() {
+	suite.TestDirPath = ""
+}
+
+func (suite *LintTestSuite) getLintConfig() lint.MorpheLintConfig {
+	registryDirPath := filepath.Join(suite.TestDirPath, "registry", "minimal")
+	return lint.MorpheLintConfig{
+		CompileConfig: compile.MorpheCompileConfig{
+			MorpheLoadRegistryConfig: rcfg.MorpheLoadRegistryConfig{
+				RegistryEnumsDirPath:      filepath.Join(registryDirPath, "enums"),
+				RegistryStructuresDirPath: filepath.Join(registryDirPath, "structures"),
+				RegistryModelsDirPath:     filepath.Join(registryDirPath, "models"),
+				RegistryEntitiesDirPath:   filepath.Join(registryDirPath, "entities"),
+			},
+			MorpheConfig: cfg.DefaultMorpheConfig(),
+		},
+	}
+}
+
+func (suite *LintTestSuite) getPeopleTable() *psqldef.Table {
+	return &psqldef.Table{
+		Schema: "public",
+		Name:   "people",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "email", Type: psqldef.PSQLTypeText, NotNull: true},
+		},
+		UniqueConstraints: []psqldef.UniqueConstraint{
+			{Name: "uk_people_email", TableName: "people", ColumnNames: []string{"email"}},
+		},
+	}
+}
+
+func (suite *LintTestSuite) lintTables(rule lint.Rule, tables ...*psqldef.Table) []lint.Finding {
+	return lint.LintSnapshot(migrate.Snapshot{Tables: tables}, []lint.Rule{rule})
+}
+
+func (suite *LintTestSuite) TestMorpheToPSQLLint() {
+	report, lintErr := lint.MorpheToPSQLLint(suite.getLintConfig())
+
+	suite.Nil(lintErr)
+	suite.Equal(lint.SeverityError, report.FailSeverity)
+	suite.Len(report.Findings, 4)
+	suite.Equal(lint.Finding{
+		RuleID:     lint.RuleIDNullableUniqueColumn,
+		Severity:   lint.SeverityWarning,
+		ObjectName: "public.companies",
+		Message:    "unique column 'name' is nullable, so any number of rows can hold NULL",
+	}, report.Findings[0])
+	suite.False(report.IsFailing())
+}
+
+func (suite *LintTestSuite) TestMorpheToPSQLLint_FailSeverity() {
+	config := suite.getLintConfig()
+	config.FailSeverity = lint.SeverityWarning
+
+	report, lintErr := lint.MorpheToPSQLLint(config)
+
+	suite.Nil(lintErr)
+	suite.True(report.IsFailing())
+	suite.Len(report.GetFailingFindings(), 4)
+}
+
+func (suite *LintTestSuite) TestMorpheToPSQLLint_UnknownSeverity() {
+	config := suite.getLintConfig()
+	config.FailSeverity = "fatal"
+
+	report, lintErr := lint.MorpheToPSQLLint(config)
+
+	suite.Nil(report)
+	suite.EqualError(lintErr, "unknown lint severity 'fatal'")
+}
+
+func (suite *LintTestSuite) TestMorpheToPSQLLint_RuleWithoutCheck() {
+	config := suite.getLintConfig()
+	config.Rules = []lint.Rule{{ID: "custom", Severity: lint.SeverityInfo}}
+
+	report, lintErr := lint.MorpheToPSQLLint(config)
+
+	suite.Nil(report)
+	suite.EqualError(lintErr, "lint rule 'custom' has no check")
+}
+
+func (suite *LintTestSuite) TestLintSnapshot_CustomRule() {
+	rule := lint.Rule{
+		ID:       "table-prefix",
+		Severity: lint.SeverityInfo,
+		Check: func(snapshot migrate.Snapshot) []lint.Finding {
+			return []lint.Finding{{ObjectName: snapshot.Tables[0].Name, Message: "table has no prefix"}}
+		},
+	}
+
+	findings := suite.lintTables(rule, suite.getPeopleTable())
+
+	suite.Equal([]lint.Finding{
+		{RuleID: "table-prefix", Severity: lint.SeverityInfo, ObjectName: "people", Message: "table has no prefix"},
+	}, findings)
+	suite.Equal("info [table-prefix] people: table has no prefix", findings[0].String())
+}
+
+func (suite *LintTestSuite) TestMissingPrimaryKeyRule() {
+	logsTable := &psqldef.Table{
+		Schema:  "public",
+		Name:    "logs",
+		Columns: []psqldef.TableColumn{{Name: "message", Type: psqldef.PSQLTypeText}},
+	}
+
+	findings := suite.lintTables(lint.MissingPrimaryKeyRule(), suite.getPeopleTable(), logsTable)
+
+	suite.Len(findings, 1)
+	suite.Equal("warning [missing-primary-key] public.logs: table has no primary key", findings[0].String())
+}
+
+func (suite *LintTestSuite) TestUnindexedForeignKeyRule() {
+	contactsTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "contacts",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "person_id", Type: psqldef.PSQLTypeInteger},
+			{Name: "manager_id", Type: psqldef.PSQLTypeInteger},
+		},
+		Indices: []psqldef.Index{
+			{Name: "idx_contacts_person_id_manager_id", Columns: []string{"person_id", "manager_id"}},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{Name: "fk_contacts_person_id", ColumnNames: []string{"person_id"}, RefTableName: "people", RefColumnNames: []string{"id"}},
+			{Name: "fk_contacts_manager_id", ColumnNames: []string{"manager_id"}, RefTableName: "people", RefColumnNames: []string{"id"}},
+		},
+	}
+
+	findings := suite.lintTables(lint.UnindexedForeignKeyRule(), suite.getPeopleTable(), contactsTable)
+
+	suite.Len(findings, 1)
+	suite.Equal("foreign key 'fk_contacts_manager_id' columns (manager_id) do not lead any index", findings[0].Message)
+}
+
+func (suite *LintTestSuite) TestForeignKeyMissingReferenceRule() {
+	contactsTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "contacts",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "person_email", Type: psqldef.PSQLTypeText},
+			{Name: "company_id", Type: psqldef.PSQLTypeInteger},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{Name: "fk_contacts_person_email", ColumnNames: []string{"person_email"}, RefSchema: "public", RefTableName: "people", RefColumnNames: []string{"email"}},
+			{Name: "fk_contacts_person_name", ColumnNames: []string{"person_name"}, RefSchema: "public", RefTableName: "people", RefColumnNames: []string{"name"}},
+			{Name: "fk_contacts_company_id", ColumnNames: []string{"company_id"}, RefSchema: "public", RefTableName: "companies", RefColumnNames: []string{"id"}},
+		},
+	}
+
+	findings := suite.lintTables(lint.ForeignKeyMissingReferenceRule(), suite.getPeopleTable(), contactsTable)
+
+	suite.Equal([]string{
+		"foreign key 'fk_contacts_person_name' uses missing column 'person_name'",
+		"foreign key 'fk_contacts_person_name' references missing column 'name' of table 'public.people'",
+		"foreign key 'fk_contacts_company_id' references missing table 'public.companies'",
+	}, suite.getMessages(findings))
+}
+
+func (suite *LintTestSuite) TestForeignKeyMissingReferenceRule_NotUnique() {
+	peopleTable := suite.getPeopleTable()
+	peopleTable.UniqueConstraints = nil
+	contactsTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "contacts",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "person_email", Type: psqldef.PSQLTypeText},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{Name: "fk_contacts_person_email", ColumnNames: []string{"person_email"}, RefSchema: "public", RefTableName: "people", RefColumnNames: []string{"email"}},
+		},
+	}
+
+	findings := suite.lintTables(lint.ForeignKeyMissingReferenceRule(), peopleTable, contactsTable)
+
+	suite.Equal([]string{
+		"foreign key 'fk_contacts_person_email' references columns (email) of table 'public.people' which are neither its primary key nor unique",
+	}, suite.getMessages(findings))
+}
+
+func (suite *LintTestSuite) TestForeignKeyTypeMismatchRule() {
+	contactsTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "contacts",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "person_id", Type: psqldef.PSQLTypeInteger},
+			{Name: "backup_person_id", Type: psqldef.PSQLTypeUUID},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{Name: "fk_contacts_person_id", ColumnNames: []string{"person_id"}, RefSchema: "public", RefTableName: "people", RefColumnNames: []string{"id"}},
+			{Name: "fk_contacts_backup_person_id", ColumnNames: []string{"backup_person_id"}, RefSchema: "public", RefTableName: "people"},
+		},
+	}
+
+	findings := suite.lintTables(lint.ForeignKeyTypeMismatchRule(), suite.getPeopleTable(), contactsTable)
+
+	suite.Equal([]string{
+		"foreign key 'fk_contacts_backup_person_id' column 'backup_person_id' has type UUID, but references column 'id' of table 'public.people' with type INTEGER",
+	}, suite.getMessages(findings))
+}
+
+func (suite *LintTestSuite) TestIdentifierCollisionRule() {
+	longPrefix := "customer_subscription_invoice_line_item_adjustment_history_entr"
+	peopleTable := suite.getPeopleTable()
+	peopleTable.Indices = []psqldef.Index{
+		{Name: "idx_people_email", Columns: []string{"email"}},
+	}
+	peopleTable.CheckConstraints = []psqldef.CheckConstraint{
+		{Name: "uk_people_email", Expression: "email <> ''"},
+	}
+	contactsTable := &psqldef.Table{
+		Schema: "public",
+		Name:   longPrefix + "ies",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+		},
+		Indices: []psqldef.Index{
+			{Name: "idx_people_email", Columns: []string{"id"}},
+		},
+	}
+	otherContactsTable := &psqldef.Table{
+		Schema: "public",
+		Name:   longPrefix + "y_details",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "id", Type: psqldef.PSQLTypeInteger},
+		},
+	}
+
+	findings := suite.lintTables(lint.IdentifierCollisionRule(), peopleTable, contactsTable, otherContactsTable)
+
+	suite.Equal([]lint.Finding{
+		{
+			RuleID:     lint.RuleIDIdentifierCollision,
+			Severity:   lint.SeverityError,
+			ObjectName: "public",
+			Message:    "identifier 'idx_people_email' is used by index idx_people_email on public.people, index idx_people_email on public." + longPrefix + "ies",
+		},
+		{
+			RuleID:     lint.RuleIDIdentifierCollision,
+			Severity:   lint.SeverityError,
+			ObjectName: "public",
+			Message: "identifier '" + longPrefix + "' is used by table public." + longPrefix + "ies, table public." + longPrefix +
+				"y_details once truncated to 63 characters",
+		},
+		{
+			RuleID:     lint.RuleIDIdentifierCollision,
+			Severity:   lint.SeverityError,
+			ObjectName: "public.people",
+			Message:    "identifier 'uk_people_email' is used by unique constraint uk_people_email, check constraint uk_people_email",
+		},
+		{
+			RuleID:     lint.RuleIDIdentifierCollision,
+			Severity:   lint.SeverityError,
+			ObjectName: "public." + longPrefix + "y_details",
+			Message:    "identifier 'id' is used by column id, column id",
+		},
+	}, findings)
+}
+
+func (suite *LintTestSuite) TestIdentifierCollisionRule_SharedTriggerFunction() {
+	trigger := psqldef.Trigger{
+		Schema:       "public",
+		Name:         "trg_touch",
+		Timing:       "BEFORE",
+		Events:       []string{"UPDATE"},
+		FunctionName: "fn_touch",
+		FunctionBody: []string{"NEW.updated_at = now();", "RETURN NEW;"},
+	}
+	peopleTable := suite.getPeopleTable()
+	peopleTable.Triggers = []psqldef.Trigger{trigger}
+	companiesTable := &psqldef.Table{
+		Schema:   "public",
+		Name:     "companies",
+		Columns:  []psqldef.TableColumn{{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true}},
+		Triggers: []psqldef.Trigger{trigger},
+	}
+
+	suite.Empty(suite.lintTables(lint.IdentifierCollisionRule(), peopleTable, companiesTable))
+
+	companiesTable.Triggers[0].FunctionBody = []string{"RETURN NEW;"}
+	findings := suite.lintTables(lint.IdentifierCollisionRule(), peopleTable, companiesTable)
+
+	suite.Equal([]string{
+		"identifier 'fn_touch' is used by function fn_touch of trigger trg_touch on public.people, function fn_touch of trigger trg_touch on public.companies",
+	}, suite.getMessages(findings))
+}
+
+func (suite *LintTestSuite) TestNullableUniqueColumnRule() {
+	peopleTable := suite.getPeopleTable()
+	peopleTable.Columns = append(peopleTable.Columns,
+		psqldef.TableColumn{Name: "first_name", Type: psqldef.PSQLTypeText},
+		psqldef.TableColumn{Name: "last_name", Type: psqldef.PSQLTypeText, NotNull: true},
+	)
+	peopleTable.Indices = []psqldef.Index{
+		{Name: "idx_people_first_name_last_name", Columns: []string{"first_name", "last_name"}, IsUnique: true},
+		{Name: "idx_people_first_name", Columns: []string{"first_name"}},
+	}
+
+	findings := suite.lintTables(lint.NullableUniqueColumnRule(), peopleTable)
+
+	suite.Equal([]string{
+		"unique column 'first_name' is nullable, so any number of rows can hold NULL",
+	}, suite.getMessages(findings))
+}
+
+func (suite *LintTestSuite) TestUnquotedReservedWordRule() {
+	peopleTable := suite.getPeopleTable()
+	peopleTable.Columns = append(peopleTable.Columns,
+		psqldef.TableColumn{Name: "user", Type: psqldef.PSQLTypeText},
+		psqldef.TableColumn{Name: `"order"`, Type: psqldef.PSQLTypeInteger},
+	)
+	peopleTable.Indices = []psqldef.Index{
+		{Name: "idx_people_order", Columns: []string{"order"}},
+	}
+	snapshot := migrate.Snapshot{
+		Tables: []*psqldef.Table{peopleTable},
+		Views: []*psqldef.View{
+			{
+				Schema:    "public",
+				Name:      "person_entities",
+				Columns:   []psqldef.ViewColumn{{Name: "limit", SourceRef: "people.limit"}},
+				FromTable: "people",
+			},
+		},
+	}
+
+	findings := lint.LintSnapshot(snapshot, []lint.Rule{lint.UnquotedReservedWordRule()})
+
+	suite.Equal([]string{
+		"error [unquoted-reserved-word] public.people: column 'user' is a reserved word and must be quoted",
+		"error [unquoted-reserved-word] public.people: index 'idx_people_order' column 'order' is a reserved word and must be quoted",
+		"error [unquoted-reserved-word] public.person_entities: column 'limit' is a reserved word and must be quoted",
+	}, suite.getFindingStrings(findings))
+}
+
+func (suite *LintTestSuite) TestWithoutRules() {
+	rules, rulesErr := lint.WithoutRules(lint.DefaultRules(), lint.RuleIDMissingPrimaryKey, lint.RuleIDUnquotedReservedWord)
+
+	suite.Nil(rulesErr)
+	suite.Len(rules, len(lint.DefaultRules())-2)
+	suite.Equal(lint.RuleIDUnindexedForeignKey, rules[0].ID)
+
+	_, unknownErr := lint.WithoutRules(lint.DefaultRules(), "no-such-rule")
+
+	suite.EqualError(unknownErr, "unknown lint rule 'no-such-rule'")
+}
+
+func (suite *LintTestSuite) getMessages(findings []lint.Finding) []string {
+	messages := []string{}
+	for _, finding := range findings {
+		messages = append(messages, finding.Message)
+	}
+	return messages
+}
+
+func (suite *LintTestSuite) getFindingStrings(findings []lint.Finding) []string {
+	findingStrings := []string{}
+	for _, finding := range findings {
+		findingStrings = append(findingStrings, finding.String())
+	}
+	return findingStrings
+}
@@ -0,0 +1,68 @@
+package lint
+
+import (
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+)
+
+type MorpheLintConfig struct {
+	// CompileConfig provides the registry paths, compilation settings and hooks of the linted registry,
+	// its writers are ignored.
+	CompileConfig compile.MorpheCompileConfig
+
+	// Rules run against the compiled definitions (default: DefaultRules)
+	Rules []Rule
+
+	// FailSeverity is the least serious severity of findings failing the lint (default: error)
+	FailSeverity Severity
+}
+
+func (config MorpheLintConfig) Validate() error {
+	registryErr := config.CompileConfig.MorpheLoadRegistryConfig.Validate()
+	if registryErr != nil {
+		return registryErr
+	}
+
+	morpheCfgErr := config.CompileConfig.MorpheConfig.Validate()
+	if morpheCfgErr != nil {
+		return morpheCfgErr
+	}
+
+	if config.FailSeverity != "" && !config.FailSeverity.IsValid() {
+		return ErrUnknownSeverity(config.FailSeverity)
+	}
+
+	ruleIDs := map[string]bool{}
+	for _, rule := range config.Rules {
+		if rule.ID == "" {
+			return ErrNoRuleID
+		}
+		if ruleIDs[rule.ID] {
+			return ErrDuplicateRuleID(rule.ID)
+		}
+		ruleIDs[rule.ID] = true
+		if rule.Check == nil {
+			return ErrNoRuleCheck(rule.ID)
+		}
+		if !rule.Severity.IsValid() {
+			return ErrUnknownSeverity(rule.Severity)
+		}
+	}
+
+	return nil
+}
+
+// GetRules returns the configured rules, or the default rules if none are configured
+func (config MorpheLintConfig) GetRules() []Rule {
+	if config.Rules == nil {
+		return DefaultRules()
+	}
+	return config.Rules
+}
+
+// GetFailSeverity returns the configured fail severity, defaulting to error
+func (config MorpheLintConfig) GetFailSeverity() Severity {
+	if config.FailSeverity == "" {
+		return SeverityError
+	}
+	return config.FailSeverity
+}
@@ -0,0 +1,16 @@
+package lint
+
+import "github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+
+// Rule checks the compiled definitions of a registry for one kind of problem
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+
+	Check CheckRuleFunc
+}
+
+// CheckRuleFunc returns the findings of a rule for the snapshot. The rule ID and severity of the findings are set
+// from the rule.
+type CheckRuleFunc = func(snapshot migrate.Snapshot) []Finding
@@ -0,0 +1,79 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+)
+
+// ForeignKeyMissingReferenceRule finds foreign keys whose columns, referenced table or referenced columns are not
+// defined, or whose referenced columns are not unique, all of which PostgreSQL rejects
+func ForeignKeyMissingReferenceRule() Rule {
+	return Rule{
+		ID:          RuleIDForeignKeyMissingReference,
+		Severity:    SeverityError,
+		Description: "foreign keys must reference the primary key or unique columns of a defined table",
+		Check:       checkForeignKeyMissingReference,
+	}
+}
+
+func checkForeignKeyMissingReference(snapshot migrate.Snapshot) []Finding {
+	findings := []Finding{}
+	for _, table := range snapshot.Tables {
+		objectName := getQualifiedName(table.Schema, table.Name)
+		for _, foreignKey := range table.ForeignKeys {
+			for _, columnName := range foreignKey.ColumnNames {
+				if findColumn(table, columnName) == nil {
+					findings = append(findings, Finding{
+						ObjectName: objectName,
+						Message:    fmt.Sprintf("foreign key '%s' uses missing column '%s'", foreignKey.Name, columnName),
+					})
+				}
+			}
+
+			refTableName := getQualifiedName(foreignKey.RefSchema, foreignKey.RefTableName)
+			refTable := findTable(snapshot, foreignKey.RefSchema, foreignKey.RefTableName)
+			if refTable == nil {
+				findings = append(findings, Finding{
+					ObjectName: objectName,
+					Message:    fmt.Sprintf("foreign key '%s' references missing table '%s'", foreignKey.Name, refTableName),
+				})
+				continue
+			}
+
+			hasMissingRefColumn := false
+			for _, refColumnName := range foreignKey.RefColumnNames {
+				if findColumn(refTable, refColumnName) != nil {
+					continue
+				}
+				hasMissingRefColumn = true
+				findings = append(findings, Finding{
+					ObjectName: objectName,
+					Message: fmt.Sprintf("foreign key '%s' references missing column '%s' of table '%s'",
+						foreignKey.Name, refColumnName, refTableName),
+				})
+			}
+			// Foreign keys without referenced columns reference the primary key
+			if hasMissingRefColumn || len(foreignKey.RefColumnNames) == 0 ||
+				isUniqueColumnSet(getUniqueColumnSets(refTable), foreignKey.RefColumnNames) {
+				continue
+			}
+			findings = append(findings, Finding{
+				ObjectName: objectName,
+				Message: fmt.Sprintf("foreign key '%s' references columns (%s) of table '%s' which are neither its primary key nor unique",
+					foreignKey.Name, strings.Join(foreignKey.RefColumnNames, ", "), refTableName),
+			})
+		}
+	}
+	return findings
+}
+
+func isUniqueColumnSet(uniqueColumnSets [][]string, columnNames []string) bool {
+	for _, uniqueColumnNames := range uniqueColumnSets {
+		if isSameColumnSet(unquoteIdentifiers(columnNames), uniqueColumnNames) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// serialTypeSyntaxes maps the serial types to the integer types of the columns they are stored in
+var serialTypeSyntaxes = map[string]string{
+	"SMALLSERIAL": "SMALLINT",
+	"SERIAL":      "INTEGER",
+	"BIGSERIAL":   "BIGINT",
+}
+
+// ForeignKeyTypeMismatchRule finds foreign key columns whose type differs from the column they reference
+func ForeignKeyTypeMismatchRule() Rule {
+	return Rule{
+		ID:          RuleIDForeignKeyTypeMismatch,
+		Severity:    SeverityError,
+		Description: "foreign key columns should have the type of the columns they reference",
+		Check:       checkForeignKeyTypeMismatch,
+	}
+}
+
+func checkForeignKeyTypeMismatch(snapshot migrate.Snapshot) []Finding {
+	findings := []Finding{}
+	for _, table := range snapshot.Tables {
+		for _, foreignKey := range table.ForeignKeys {
+			refTable := findTable(snapshot, foreignKey.RefSchema, foreignKey.RefTableName)
+			if refTable == nil {
+				continue
+			}
+			refColumnNames := foreignKey.RefColumnNames
+			if len(refColumnNames) == 0 {
+				refColumnNames = getPrimaryKeyColumnNames(refTable)
+			}
+
+			for columnIdx, columnName := range foreignKey.ColumnNames {
+				column := findColumn(table, columnName)
+				if column == nil || columnIdx >= len(refColumnNames) {
+					continue
+				}
+				refColumn := findColumn(refTable, refColumnNames[columnIdx])
+				if refColumn == nil {
+					continue
+				}
+				columnTypeSyntax := getStoredTypeSyntax(column.Type)
+				refColumnTypeSyntax := getStoredTypeSyntax(refColumn.Type)
+				if columnTypeSyntax == refColumnTypeSyntax {
+					continue
+				}
+				findings = append(findings, Finding{
+					ObjectName: getQualifiedName(table.Schema, table.Name),
+					Message: fmt.Sprintf("foreign key '%s' column '%s' has type %s, but references column '%s' of table '%s' with type %s",
+						foreignKey.Name, columnName, columnTypeSyntax,
+						refColumnNames[columnIdx], getQualifiedName(foreignKey.RefSchema, foreignKey.RefTableName), refColumnTypeSyntax),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// getStoredTypeSyntax returns the syntax of the type a column stores, where serial columns store integers
+func getStoredTypeSyntax(columnType psqldef.PSQLType) string {
+	typeSyntax := strings.ToUpper(columnType.GetSyntax())
+	if storedTypeSyntax, isSerial := serialTypeSyntaxes[typeSyntax]; isSerial {
+		return storedTypeSyntax
+	}
+	return typeSyntax
+}
@@ -0,0 +1,193 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// PostgreSQL truncates identifiers to this length
+const maxIdentifierLength = 63
+
+// IdentifierCollisionRule finds objects sharing a name within their namespace, including names which only collide
+// once PostgreSQL truncates them to 63 characters. Tables, views, indices and unique constraints share a namespace
+// per schema, as do functions, while constraints, columns and triggers share a namespace per table.
+func IdentifierCollisionRule() Rule {
+	return Rule{
+		ID:          RuleIDIdentifierCollision,
+		Severity:    SeverityError,
+		Description: "identifiers must be unique within their namespace",
+		Check:       checkIdentifierCollision,
+	}
+}
+
+// identifierOwner is an object named by an identifier
+type identifierOwner struct {
+	name        string
+	description string
+	// definition tells apart objects which replace each other, e.g. functions created with CREATE OR REPLACE
+	definition string
+}
+
+// identifierNamespace holds the owners of the identifiers within a schema, table or view
+type identifierNamespace struct {
+	objectName  string
+	identifiers []string
+	owners      map[string][]identifierOwner
+}
+
+func (namespace *identifierNamespace) add(name string, description string, definition string) {
+	if name == "" {
+		return
+	}
+	identifier := getStoredIdentifier(name)
+	if _, exists := namespace.owners[identifier]; !exists {
+		namespace.identifiers = append(namespace.identifiers, identifier)
+	}
+	namespace.owners[identifier] = append(namespace.owners[identifier], identifierOwner{
+		name:        name,
+		description: description,
+		definition:  definition,
+	})
+}
+
+// identifierNamespaces keeps namespaces in the order they were first used
+type identifierNamespaces struct {
+	namespaces []*identifierNamespace
+}
+
+func (n *identifierNamespaces) get(objectName string) *identifierNamespace {
+	for _, namespace := range n.namespaces {
+		if namespace.objectName == objectName {
+			return namespace
+		}
+	}
+	namespace := &identifierNamespace{
+		objectName: objectName,
+		owners:     map[string][]identifierOwner{},
+	}
+	n.namespaces = append(n.namespaces, namespace)
+	return namespace
+}
+
+func checkIdentifierCollision(snapshot migrate.Snapshot) []Finding {
+	schemaNamespaces := &identifierNamespaces{}
+	functionNamespaces := &identifierNamespaces{}
+	tableNamespaces := &identifierNamespaces{}
+
+	for _, table := range snapshot.Tables {
+		addTableIdentifiers(table, schemaNamespaces, functionNamespaces, tableNamespaces)
+	}
+	for _, view := range snapshot.Views {
+		viewName := getQualifiedName(view.Schema, view.Name)
+		schemaNamespace := schemaNamespaces.get(view.Schema)
+		schemaNamespace.add(view.Name, "view "+viewName, "")
+		for _, index := range view.Indices {
+			schemaNamespace.add(index.Name, fmt.Sprintf("index %s on %s", index.Name, viewName), "")
+		}
+		if view.RefreshFunctionName != "" {
+			functionNamespaces.get(view.Schema).add(view.RefreshFunctionName,
+				fmt.Sprintf("function %s refreshing %s", view.RefreshFunctionName, viewName), "refresh "+viewName)
+		}
+
+		viewNamespace := tableNamespaces.get(viewName)
+		for _, column := range view.Columns {
+			viewNamespace.add(column.Name, "column "+column.Name, "")
+		}
+	}
+
+	findings := []Finding{}
+	for _, namespaces := range []*identifierNamespaces{schemaNamespaces, functionNamespaces, tableNamespaces} {
+		for _, namespace := range namespaces.namespaces {
+			findings = append(findings, getCollisionFindings(namespace)...)
+		}
+	}
+	return findings
+}
+
+func addTableIdentifiers(table *psqldef.Table, schemaNamespaces *identifierNamespaces, functionNamespaces *identifierNamespaces, tableNamespaces *identifierNamespaces) {
+	tableName := getQualifiedName(table.Schema, table.Name)
+	schemaNamespace := schemaNamespaces.get(table.Schema)
+	tableNamespace := tableNamespaces.get(tableName)
+
+	schemaNamespace.add(table.Name, "table "+tableName, "")
+	for _, column := range table.Columns {
+		tableNamespace.add(column.Name, "column "+column.Name, "")
+	}
+	for _, index := range table.Indices {
+		schemaNamespace.add(index.Name, fmt.Sprintf("index %s on %s", index.Name, tableName), "")
+	}
+	for _, uniqueConstraint := range table.UniqueConstraints {
+		// Unique constraints are backed by an index of the same name
+		schemaNamespace.add(uniqueConstraint.Name, fmt.Sprintf("unique constraint %s on %s", uniqueConstraint.Name, tableName), "")
+		tableNamespace.add(uniqueConstraint.Name, "unique constraint "+uniqueConstraint.Name, "unique")
+	}
+	for _, foreignKey := range table.ForeignKeys {
+		tableNamespace.add(foreignKey.Name, "foreign key "+foreignKey.Name, "")
+	}
+	for _, checkConstraint := range table.CheckConstraints {
+		tableNamespace.add(checkConstraint.Name, "check constraint "+checkConstraint.Name, "")
+	}
+	for _, trigger := range table.Triggers {
+		tableNamespace.add(trigger.Name, "trigger "+trigger.Name, "")
+		functionSchema := trigger.Schema
+		if functionSchema == "" {
+			functionSchema = table.Schema
+		}
+		functionNamespaces.get(functionSchema).add(trigger.FunctionName,
+			fmt.Sprintf("function %s of trigger %s on %s", trigger.FunctionName, trigger.Name, tableName),
+			strings.Join(trigger.FunctionBody, "\n"))
+	}
+}
+
+func getCollisionFindings(namespace *identifierNamespace) []Finding {
+	findings := []Finding{}
+	for _, identifier := range namespace.identifiers {
+		owners := namespace.owners[identifier]
+		if !isCollision(owners) {
+			continue
+		}
+
+		ownerDescriptions := []string{}
+		isTruncated := false
+		for _, owner := range owners {
+			ownerDescriptions = append(ownerDescriptions, owner.description)
+			isTruncated = isTruncated || unquoteIdentifier(owner.name) != identifier
+		}
+		message := fmt.Sprintf("identifier '%s' is used by %s", identifier, strings.Join(ownerDescriptions, ", "))
+		if isTruncated {
+			message += fmt.Sprintf(" once truncated to %d characters", maxIdentifierLength)
+		}
+		findings = append(findings, Finding{
+			ObjectName: namespace.objectName,
+			Message:    message,
+		})
+	}
+	return findings
+}
+
+// isCollision returns true if differently defined objects share an identifier. Objects with the same definition,
+// such as a function shared by several triggers, do not collide, and neither do unique constraints within their
+// table, which already collide as indices within their schema.
+func isCollision(owners []identifierOwner) bool {
+	if len(owners) < 2 {
+		return false
+	}
+	for _, owner := range owners[1:] {
+		if owner.definition != owners[0].definition {
+			return true
+		}
+	}
+	return owners[0].definition == ""
+}
+
+// getStoredIdentifier returns the identifier as PostgreSQL stores it, unquoted and truncated
+func getStoredIdentifier(name string) string {
+	identifier := unquoteIdentifier(name)
+	if len(identifier) > maxIdentifierLength {
+		return identifier[:maxIdentifierLength]
+	}
+	return identifier
+}
@@ -0,0 +1,27 @@
+package lint
+
+import "github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+
+// MissingPrimaryKeyRule finds tables without a primary key, whose rows cannot be referenced or replicated reliably
+func MissingPrimaryKeyRule() Rule {
+	return Rule{
+		ID:          RuleIDMissingPrimaryKey,
+		Severity:    SeverityWarning,
+		Description: "tables should have a primary key",
+		Check:       checkMissingPrimaryKey,
+	}
+}
+
+func checkMissingPrimaryKey(snapshot migrate.Snapshot) []Finding {
+	findings := []Finding{}
+	for _, table := range snapshot.Tables {
+		if len(getPrimaryKeyColumnNames(table)) > 0 {
+			continue
+		}
+		findings = append(findings, Finding{
+			ObjectName: getQualifiedName(table.Schema, table.Name),
+			Message:    "table has no primary key",
+		})
+	}
+	return findings
+}
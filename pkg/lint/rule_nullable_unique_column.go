@@ -0,0 +1,39 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+)
+
+// NullableUniqueColumnRule finds nullable columns of unique constraints and unique indices, which accept any
+// number of rows holding NULL
+func NullableUniqueColumnRule() Rule {
+	return Rule{
+		ID:          RuleIDNullableUniqueColumn,
+		Severity:    SeverityWarning,
+		Description: "columns of unique constraints and unique indices should be NOT NULL",
+		Check:       checkNullableUniqueColumn,
+	}
+}
+
+func checkNullableUniqueColumn(snapshot migrate.Snapshot) []Finding {
+	findings := []Finding{}
+	for _, table := range snapshot.Tables {
+		reportedColumnNames := map[string]bool{}
+		for _, uniqueColumnNames := range getUniqueColumnSets(table) {
+			for _, columnName := range uniqueColumnNames {
+				column := findColumn(table, columnName)
+				if column == nil || column.NotNull || column.PrimaryKey || reportedColumnNames[columnName] {
+					continue
+				}
+				reportedColumnNames[columnName] = true
+				findings = append(findings, Finding{
+					ObjectName: getQualifiedName(table.Schema, table.Name),
+					Message:    fmt.Sprintf("unique column '%s' is nullable, so any number of rows can hold NULL", columnName),
+				})
+			}
+		}
+	}
+	return findings
+}
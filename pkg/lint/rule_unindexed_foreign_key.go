@@ -0,0 +1,57 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// UnindexedForeignKeyRule finds foreign keys whose columns do not lead any index. PostgreSQL does not index
+// referencing columns, so deleting a referenced row scans the whole referencing table.
+func UnindexedForeignKeyRule() Rule {
+	return Rule{
+		ID:          RuleIDUnindexedForeignKey,
+		Severity:    SeverityWarning,
+		Description: "foreign key columns should lead an index, unique constraint or the primary key",
+		Check:       checkUnindexedForeignKey,
+	}
+}
+
+func checkUnindexedForeignKey(snapshot migrate.Snapshot) []Finding {
+	findings := []Finding{}
+	for _, table := range snapshot.Tables {
+		for _, foreignKey := range table.ForeignKeys {
+			if isForeignKeyIndexed(table, foreignKey) {
+				continue
+			}
+			findings = append(findings, Finding{
+				ObjectName: getQualifiedName(table.Schema, table.Name),
+				Message: fmt.Sprintf("foreign key '%s' columns (%s) do not lead any index",
+					foreignKey.Name, strings.Join(foreignKey.ColumnNames, ", ")),
+			})
+		}
+	}
+	return findings
+}
+
+// isForeignKeyIndexed returns true if the foreign key columns, in any order, are the leading columns of an index,
+// unique constraint or the primary key
+func isForeignKeyIndexed(table *psqldef.Table, foreignKey psqldef.ForeignKey) bool {
+	foreignKeyColumnNames := unquoteIdentifiers(foreignKey.ColumnNames)
+	indexedColumnSets := getUniqueColumnSets(table)
+	for _, index := range table.Indices {
+		indexedColumnSets = append(indexedColumnSets, unquoteIdentifiers(index.Columns))
+	}
+
+	for _, indexedColumnNames := range indexedColumnSets {
+		if len(indexedColumnNames) < len(foreignKeyColumnNames) {
+			continue
+		}
+		if isSameColumnSet(foreignKeyColumnNames, indexedColumnNames[:len(foreignKeyColumnNames)]) {
+			return true
+		}
+	}
+	return false
+}
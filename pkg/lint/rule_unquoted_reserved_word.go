@@ -0,0 +1,82 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+)
+
+// reservedWords holds the PostgreSQL key words which cannot name tables or columns without quoting, including
+// the key words reserved except as function or type names
+var reservedWords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true, "as": true,
+	"asc": true, "asymmetric": true, "authorization": true, "binary": true, "both": true, "case": true,
+	"cast": true, "check": true, "collate": true, "collation": true, "column": true, "concurrently": true,
+	"constraint": true, "create": true, "cross": true, "current_catalog": true, "current_date": true,
+	"current_role": true, "current_schema": true, "current_time": true, "current_timestamp": true,
+	"current_user": true, "default": true, "deferrable": true, "desc": true, "distinct": true, "do": true,
+	"else": true, "end": true, "except": true, "false": true, "fetch": true, "for": true, "foreign": true,
+	"freeze": true, "from": true, "full": true, "grant": true, "group": true, "having": true, "ilike": true,
+	"in": true, "initially": true, "inner": true, "intersect": true, "into": true, "is": true, "isnull": true,
+	"join": true, "lateral": true, "leading": true, "left": true, "like": true, "limit": true,
+	"localtime": true, "localtimestamp": true, "natural": true, "not": true, "notnull": true, "null": true,
+	"offset": true, "on": true, "only": true, "or": true, "order": true, "outer": true, "overlaps": true,
+	"placing": true, "primary": true, "references": true, "returning": true, "right": true, "select": true,
+	"session_user": true, "similar": true, "some": true, "symmetric": true, "system_user": true, "table": true,
+	"tablesample": true, "then": true, "to": true, "trailing": true, "true": true, "union": true, "unique": true,
+	"user": true, "using": true, "variadic": true, "verbose": true, "when": true, "where": true, "window": true,
+	"with": true,
+}
+
+// UnquotedReservedWordRule finds tables, views, columns and index columns named with an unquoted reserved word,
+// which makes their DDL fail to parse
+func UnquotedReservedWordRule() Rule {
+	return Rule{
+		ID:          RuleIDUnquotedReservedWord,
+		Severity:    SeverityError,
+		Description: "identifiers which are PostgreSQL reserved words must be quoted",
+		Check:       checkUnquotedReservedWord,
+	}
+}
+
+func checkUnquotedReservedWord(snapshot migrate.Snapshot) []Finding {
+	findings := []Finding{}
+	addFinding := func(schema string, objectName string, identifierKind string, identifier string) {
+		if !isUnquotedReservedWord(identifier) {
+			return
+		}
+		findings = append(findings, Finding{
+			ObjectName: getQualifiedName(schema, objectName),
+			Message:    fmt.Sprintf("%s '%s' is a reserved word and must be quoted", identifierKind, identifier),
+		})
+	}
+
+	for _, table := range snapshot.Tables {
+		addFinding(table.Schema, table.Name, "table name", table.Name)
+		for _, column := range table.Columns {
+			addFinding(table.Schema, table.Name, "column", column.Name)
+		}
+		for _, index := range table.Indices {
+			for _, columnName := range index.Columns {
+				addFinding(table.Schema, table.Name, fmt.Sprintf("index '%s' column", index.Name), columnName)
+			}
+		}
+	}
+	for _, view := range snapshot.Views {
+		addFinding(view.Schema, view.Name, "view name", view.Name)
+		for _, column := range view.Columns {
+			addFinding(view.Schema, view.Name, "column", column.Name)
+		}
+		for _, index := range view.Indices {
+			for _, columnName := range index.Columns {
+				addFinding(view.Schema, view.Name, fmt.Sprintf("index '%s' column", index.Name), columnName)
+			}
+		}
+	}
+	return findings
+}
+
+func isUnquotedReservedWord(identifier string) bool {
+	return !isQuotedIdentifier(identifier) && reservedWords[strings.ToLower(identifier)]
+}
@@ -0,0 +1,38 @@
+package lint
+
+import "slices"
+
+const (
+	RuleIDMissingPrimaryKey          = "missing-primary-key"
+	RuleIDUnindexedForeignKey        = "unindexed-foreign-key"
+	RuleIDForeignKeyMissingReference = "foreign-key-missing-reference"
+	RuleIDForeignKeyTypeMismatch     = "foreign-key-type-mismatch"
+	RuleIDIdentifierCollision        = "identifier-collision"
+	RuleIDNullableUniqueColumn       = "nullable-unique-column"
+	RuleIDUnquotedReservedWord       = "unquoted-reserved-word"
+)
+
+// DefaultRules returns all built-in rules with their default severities
+func DefaultRules() []Rule {
+	return []Rule{
+		MissingPrimaryKeyRule(),
+		UnindexedForeignKeyRule(),
+		ForeignKeyMissingReferenceRule(),
+		ForeignKeyTypeMismatchRule(),
+		IdentifierCollisionRule(),
+		NullableUniqueColumnRule(),
+		UnquotedReservedWordRule(),
+	}
+}
+
+// WithoutRules returns the rules except the rules with the given IDs
+func WithoutRules(rules []Rule, ruleIDs ...string) ([]Rule, error) {
+	for _, ruleID := range ruleIDs {
+		if !slices.ContainsFunc(rules, func(rule Rule) bool { return rule.ID == ruleID }) {
+			return nil, ErrUnknownRuleID(ruleID)
+		}
+	}
+	return slices.DeleteFunc(slices.Clone(rules), func(rule Rule) bool {
+		return slices.Contains(ruleIDs, rule.ID)
+	}), nil
+}
@@ -0,0 +1,29 @@
+package lint
+
+import "slices"
+
+// Severity ranks how serious a lint finding is
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// severities lists the severities from least to most serious
+var severities = []Severity{
+	SeverityInfo,
+	SeverityWarning,
+	SeverityError,
+}
+
+// IsValid returns true if the severity is a known severity
+func (severity Severity) IsValid() bool {
+	return slices.Contains(severities, severity)
+}
+
+// IsAtLeast returns true if the severity is as serious as or more serious than the other severity
+func (severity Severity) IsAtLeast(otherSeverity Severity) bool {
+	return slices.Index(severities, severity) >= slices.Index(severities, otherSeverity)
+}
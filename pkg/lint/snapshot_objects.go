@@ -0,0 +1,94 @@
+package lint
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+func getQualifiedName(schema string, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
+
+// findTable returns the snapshot table, where an empty schema matches any schema
+func findTable(snapshot migrate.Snapshot, schema string, tableName string) *psqldef.Table {
+	for _, table := range snapshot.Tables {
+		if table.Name == tableName && (schema == "" || table.Schema == "" || table.Schema == schema) {
+			return table
+		}
+	}
+	return nil
+}
+
+func findColumn(table *psqldef.Table, columnName string) *psqldef.TableColumn {
+	for columnIdx := range table.Columns {
+		if unquoteIdentifier(table.Columns[columnIdx].Name) == unquoteIdentifier(columnName) {
+			return &table.Columns[columnIdx]
+		}
+	}
+	return nil
+}
+
+func getPrimaryKeyColumnNames(table *psqldef.Table) []string {
+	columnNames := []string{}
+	for _, column := range table.Columns {
+		if column.PrimaryKey {
+			columnNames = append(columnNames, unquoteIdentifier(column.Name))
+		}
+	}
+	return columnNames
+}
+
+// getUniqueColumnSets returns the column lists of the primary key, unique constraints and unique indices
+func getUniqueColumnSets(table *psqldef.Table) [][]string {
+	columnSets := [][]string{}
+	if primaryKeyColumnNames := getPrimaryKeyColumnNames(table); len(primaryKeyColumnNames) > 0 {
+		columnSets = append(columnSets, primaryKeyColumnNames)
+	}
+	for _, uniqueConstraint := range table.UniqueConstraints {
+		columnSets = append(columnSets, unquoteIdentifiers(uniqueConstraint.ColumnNames))
+	}
+	for _, index := range table.Indices {
+		if index.IsUnique {
+			columnSets = append(columnSets, unquoteIdentifiers(index.Columns))
+		}
+	}
+	return columnSets
+}
+
+// isSameColumnSet returns true if both lists hold the same columns, in any order
+func isSameColumnSet(columnNames []string, otherColumnNames []string) bool {
+	if len(columnNames) != len(otherColumnNames) {
+		return false
+	}
+	for _, columnName := range columnNames {
+		if !slices.Contains(otherColumnNames, columnName) {
+			return false
+		}
+	}
+	return true
+}
+
+func isQuotedIdentifier(identifier string) bool {
+	return len(identifier) >= 2 && strings.HasPrefix(identifier, `"`) && strings.HasSuffix(identifier, `"`)
+}
+
+func unquoteIdentifier(identifier string) string {
+	if isQuotedIdentifier(identifier) {
+		return strings.ReplaceAll(identifier[1:len(identifier)-1], `""`, `"`)
+	}
+	return identifier
+}
+
+func unquoteIdentifiers(identifiers []string) []string {
+	unquotedIdentifiers := []string{}
+	for _, identifier := range identifiers {
+		unquotedIdentifiers = append(unquotedIdentifiers, unquoteIdentifier(identifier))
+	}
+	return unquotedIdentifiers
+}
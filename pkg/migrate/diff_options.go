@@ -0,0 +1,103 @@
+package migrate
+
+import (
+	"strings"
+
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+)
+
+// DiffOptions resolves changes between snapshots that cannot be told apart from dropping and adding definitions
+type DiffOptions struct {
+	// TableRenames maps qualified names of renamed tables to their new qualified names, e.g. "public.people" to
+	// "public.persons". Renamed tables keep their rows and cannot move between schemas.
+	TableRenames map[string]string
+
+	// ColumnRenames maps qualified table names, after table renames, to their renamed columns from the previous
+	// to the new column name
+	ColumnRenames map[string]map[string]string
+
+	// ColumnBackfills maps qualified table names, after table renames, to SQL expressions by column name, which fill
+	// added NOT NULL columns without a default for existing rows
+	ColumnBackfills map[string]map[string]string
+
+	// DownColumnBackfills maps qualified table names, after table renames, to SQL expressions by column name, which
+	// fill dropped NOT NULL columns without a default when the down migration adds them back. Columns without a
+	// down backfill are added back as nullable.
+	DownColumnBackfills map[string]map[string]string
+
+	// NamingStrategy names the definitions left unnamed in the snapshots, e.g. indices added by hooks, like the
+	// compiler names them. Defaults to MorpheNamingStrategy.
+	NamingStrategy compile.NamingStrategy
+
+	// AllowDropAndAdd permits dropping tables or columns while others are added in their place, losing the data of
+	// the dropped ones. Without it, such changes fail as likely unhinted renames.
+	AllowDropAndAdd bool
+
+	// nullableWithoutBackfill adds NOT NULL columns without a default or backfill as nullable instead of failing
+	nullableWithoutBackfill bool
+}
+
+func (options DiffOptions) Validate() error {
+	for _, fromTableName := range core.MapKeysSorted(options.TableRenames) {
+		toTableName := options.TableRenames[fromTableName]
+		if getSchemaName(fromTableName) != getSchemaName(toTableName) {
+			return ErrTableRenameAcrossSchemas(fromTableName, toTableName)
+		}
+	}
+	return nil
+}
+
+// getInverse returns the options for diffing the snapshots in the opposite direction
+func (options DiffOptions) getInverse() DiffOptions {
+	inverse := DiffOptions{
+		NamingStrategy:          options.NamingStrategy,
+		AllowDropAndAdd:         options.AllowDropAndAdd,
+		nullableWithoutBackfill: !options.nullableWithoutBackfill,
+	}
+
+	if len(options.TableRenames) > 0 {
+		inverse.TableRenames = map[string]string{}
+		for fromTableName, toTableName := range options.TableRenames {
+			inverse.TableRenames[toTableName] = fromTableName
+		}
+	}
+
+	inverseColumnRenames := map[string]map[string]string{}
+	for tableName, columnRenames := range options.ColumnRenames {
+		inverseColumnRenames[tableName] = map[string]string{}
+		for fromColumnName, toColumnName := range columnRenames {
+			inverseColumnRenames[tableName][toColumnName] = fromColumnName
+		}
+	}
+	inverse.ColumnRenames = renameTableKeys(inverseColumnRenames, inverse.TableRenames)
+
+	// Backfills add columns in one direction only, the down backfills are the inverse's backfills
+	inverse.ColumnBackfills = renameTableKeys(options.DownColumnBackfills, inverse.TableRenames)
+	inverse.DownColumnBackfills = renameTableKeys(options.ColumnBackfills, inverse.TableRenames)
+
+	return inverse
+}
+
+// renameTableKeys returns the values keyed by qualified table names with the renamed table names replaced
+func renameTableKeys[T any](valuesByTableName map[string]T, tableRenames map[string]string) map[string]T {
+	if len(valuesByTableName) == 0 {
+		return nil
+	}
+	renamedValues := map[string]T{}
+	for tableName, value := range valuesByTableName {
+		if renamedTableName, renamed := tableRenames[tableName]; renamed {
+			tableName = renamedTableName
+		}
+		renamedValues[tableName] = value
+	}
+	return renamedValues
+}
+
+func getSchemaName(qualifiedName string) string {
+	nameParts := strings.Split(qualifiedName, ".")
+	if len(nameParts) < 2 {
+		return ""
+	}
+	return nameParts[0]
+}
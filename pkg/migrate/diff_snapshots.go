@@ -0,0 +1,1066 @@
+package migrate
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// snapshotDiff collects the statements of a migration grouped by the phase they need to run in
+type snapshotDiff struct {
+	tableWriter *compile.MorpheTableFileWriter
+	viewWriter  *compile.MorpheViewFileWriter
+	typeWriter  *compile.MorpheTypeFileWriter
+
+	options  DiffOptions
+	toTables map[string]*psqldef.Table
+	// primaryKeyChangedTables holds the qualified names of kept tables whose primary key is dropped and added again
+	primaryKeyChangedTables map[string]bool
+
+	dropViews       []string
+	renames         []string
+	dropConstraints []string
+	dropPrimaryKeys []string
+	createSchemas   []string
+	createTypes     []string
+	createTables    []string
+	alterColumns    []string
+	dropColumns     []string
+	dropTables      []string
+	dropTypes       []string
+	addConstraints  []string
+	seedData        []string
+	createViews     []string
+}
+
+// DiffSnapshots returns the ordered statements required to migrate a database from one snapshot to another
+//
+// Statements run in phases: views are dropped first, tables and columns are renamed, followed by outdated
+// constraints and primary keys, then types, tables and columns are created or altered, removed tables and types
+// are dropped, and finally constraints, seed data and views are (re)created. Foreign keys of new tables are added
+// after all tables exist, so no creation order between tables is required.
+//
+// Only the renames hinted in the options keep the data of tables and columns. Other changes that drop tables or
+// columns while adding new ones fail, unless the options allow dropping their data.
+func DiffSnapshots(from Snapshot, to Snapshot, options DiffOptions) ([]string, error) {
+	optionsErr := options.Validate()
+	if optionsErr != nil {
+		return nil, optionsErr
+	}
+
+	fromTables := getTablesByName(from.Tables)
+	toTables := getTablesByName(to.Tables)
+
+	renamesErr := validateRenames(options, fromTables, toTables)
+	if renamesErr != nil {
+		return nil, renamesErr
+	}
+
+	diff := snapshotDiff{
+		tableWriter: &compile.MorpheTableFileWriter{NamingStrategy: options.NamingStrategy},
+		viewWriter:  &compile.MorpheViewFileWriter{},
+		typeWriter:  &compile.MorpheTypeFileWriter{},
+		options:     options,
+		toTables:    toTables,
+	}
+	diff.primaryKeyChangedTables = diff.getPrimaryKeyChangedTables(fromTables)
+
+	diff.diffSchemas(from, to)
+
+	typesErr := diff.diffTypes(from, to)
+	if typesErr != nil {
+		return nil, typesErr
+	}
+
+	changedTableNames := map[string]bool{}
+	renamedTableNames := map[string]bool{}
+	droppedTables := []*psqldef.Table{}
+	for _, tableName := range core.MapKeysSorted(fromTables) {
+		fromTable := fromTables[tableName]
+		toTableName := diff.getRenamedTableName(tableName)
+		toTable, exists := toTables[toTableName]
+		if !exists {
+			diff.dropTable(fromTable)
+			changedTableNames[fromTable.Name] = true
+			droppedTables = append(droppedTables, fromTable)
+			continue
+		}
+		if toTableName != tableName {
+			diff.renames = append(diff.renames, fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", tableName, toTable.Name))
+			renamedTableNames[toTableName] = true
+			changedTableNames[toTable.Name] = true
+		}
+		if reflect.DeepEqual(fromTable, toTable) && !diff.hasForeignKeyOnChangedPrimaryKey(fromTable) {
+			continue
+		}
+		tableErr := diff.diffTable(fromTable, toTable)
+		if tableErr != nil {
+			return nil, tableErr
+		}
+		changedTableNames[fromTable.Name] = true
+	}
+
+	addedTables := []*psqldef.Table{}
+	for _, tableName := range core.MapKeysSorted(toTables) {
+		if _, exists := fromTables[tableName]; exists || renamedTableNames[tableName] {
+			continue
+		}
+		tableErr := diff.createTable(toTables[tableName])
+		if tableErr != nil {
+			return nil, tableErr
+		}
+		addedTables = append(addedTables, toTables[tableName])
+	}
+
+	if !options.AllowDropAndAdd {
+		dropAndAddErr := validateDroppedAndAddedTables(droppedTables, addedTables)
+		if dropAndAddErr != nil {
+			return nil, dropAndAddErr
+		}
+	}
+
+	viewsErr := diff.diffViews(from.Views, to.Views, changedTableNames)
+	if viewsErr != nil {
+		return nil, viewsErr
+	}
+
+	return diff.getStatements(), nil
+}
+
+func (d *snapshotDiff) getStatements() []string {
+	allStatements := []string{}
+	allStatements = append(allStatements, d.dropViews...)
+	allStatements = append(allStatements, d.renames...)
+	allStatements = append(allStatements, d.dropConstraints...)
+	allStatements = append(allStatements, d.dropPrimaryKeys...)
+	allStatements = append(allStatements, d.createSchemas...)
+	allStatements = append(allStatements, d.createTypes...)
+	allStatements = append(allStatements, d.createTables...)
+	allStatements = append(allStatements, d.alterColumns...)
+	allStatements = append(allStatements, d.dropColumns...)
+	allStatements = append(allStatements, d.dropTables...)
+	allStatements = append(allStatements, d.dropTypes...)
+	allStatements = append(allStatements, d.addConstraints...)
+	allStatements = append(allStatements, d.seedData...)
+	allStatements = append(allStatements, d.createViews...)
+	return allStatements
+}
+
+func (d *snapshotDiff) diffSchemas(from Snapshot, to Snapshot) {
+	fromSchemas := getSnapshotSchemas(from)
+	toSchemas := getSnapshotSchemas(to)
+	for _, schema := range core.MapKeysSorted(toSchemas) {
+		if fromSchemas[schema] {
+			continue
+		}
+		d.createSchemas = append(d.createSchemas, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;", schema))
+	}
+}
+
+func (d *snapshotDiff) diffTypes(from Snapshot, to Snapshot) error {
+	fromTypes := getTypesByName(from.Types)
+	toTypes := getTypesByName(to.Types)
+
+	// Types are dropped before and created after the types they are defined in terms of
+	fromTypeNames, fromTypeNamesErr := getOrderedTypeNames(fromTypes)
+	if fromTypeNamesErr != nil {
+		return fromTypeNamesErr
+	}
+	toTypeNames, toTypeNamesErr := getOrderedTypeNames(toTypes)
+	if toTypeNamesErr != nil {
+		return toTypeNamesErr
+	}
+
+	slices.Reverse(fromTypeNames)
+	for _, typeName := range fromTypeNames {
+		if _, exists := toTypes[typeName]; exists {
+			continue
+		}
+		d.dropTypes = append(d.dropTypes, getDropTypeStatement(fromTypes[typeName]))
+	}
+
+	for _, typeName := range toTypeNames {
+		toType := toTypes[typeName]
+		fromType, exists := fromTypes[typeName]
+		if !exists {
+			createErr := d.createType(toType)
+			if createErr != nil {
+				return createErr
+			}
+			continue
+		}
+		if reflect.DeepEqual(fromType, toType) {
+			continue
+		}
+
+		fromEnum, fromIsEnum := getEnumType(fromType)
+		toEnum, toIsEnum := getEnumType(toType)
+		if fromIsEnum && toIsEnum {
+			d.diffEnumType(fromEnum, toEnum, to.Tables)
+			continue
+		}
+
+		fromComposite, fromIsComposite := getCompositeType(fromType)
+		toComposite, toIsComposite := getCompositeType(toType)
+		if fromIsComposite && toIsComposite {
+			d.diffCompositeType(fromComposite, toComposite)
+			continue
+		}
+
+		d.dropTypes = append(d.dropTypes, getDropTypeStatement(fromType))
+		createErr := d.createType(toType)
+		if createErr != nil {
+			return createErr
+		}
+	}
+
+	return nil
+}
+
+func (d *snapshotDiff) createType(psqlType psqldef.PSQLType) error {
+	typeLines, typeErr := d.typeWriter.GetCreateTypeLines(psqlType)
+	if typeErr != nil {
+		return typeErr
+	}
+	d.createTypes = append(d.createTypes, strings.Join(typeLines, "\n"))
+	return nil
+}
+
+// diffCompositeType alters the attributes of a composite type in place, keeping the columns using the type intact
+func (d *snapshotDiff) diffCompositeType(fromComposite psqldef.PSQLTypeComposite, toComposite psqldef.PSQLTypeComposite) {
+	typeName := toComposite.GetSyntax()
+
+	for _, fieldName := range core.MapKeysSorted(fromComposite.Fields) {
+		if _, exists := toComposite.Fields[fieldName]; exists {
+			continue
+		}
+		d.createTypes = append(d.createTypes, fmt.Sprintf("ALTER TYPE %s DROP ATTRIBUTE IF EXISTS %s;", typeName, fieldName))
+	}
+
+	for _, fieldName := range core.MapKeysSorted(toComposite.Fields) {
+		toFieldType := toComposite.Fields[fieldName]
+		fromFieldType, exists := fromComposite.Fields[fieldName]
+		if !exists {
+			d.createTypes = append(d.createTypes, fmt.Sprintf("ALTER TYPE %s ADD ATTRIBUTE %s %s;",
+				typeName, fieldName, toFieldType.GetSyntax()))
+			continue
+		}
+		if fromFieldType.GetSyntax() != toFieldType.GetSyntax() {
+			d.createTypes = append(d.createTypes, fmt.Sprintf("ALTER TYPE %s ALTER ATTRIBUTE %s TYPE %s;",
+				typeName, fieldName, toFieldType.GetSyntax()))
+		}
+	}
+}
+
+// diffEnumType adds new enum values in place, but recreates the type when values were removed since
+// PostgreSQL cannot drop values from an existing enum type
+func (d *snapshotDiff) diffEnumType(fromEnum psqldef.PSQLTypeEnum, toEnum psqldef.PSQLTypeEnum, toTables []*psqldef.Table) {
+	removedValue := false
+	for _, value := range fromEnum.Values {
+		if !slices.Contains(toEnum.Values, value) {
+			removedValue = true
+			break
+		}
+	}
+
+	if !removedValue {
+		for _, value := range toEnum.Values {
+			if slices.Contains(fromEnum.Values, value) {
+				continue
+			}
+			d.createTypes = append(d.createTypes, fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS '%s';",
+				toEnum.GetSyntax(), strings.ReplaceAll(value, "'", "''")))
+		}
+		return
+	}
+
+	previousName := toEnum.Name + "_previous"
+	previousEnum := psqldef.PSQLTypeEnum{Schema: toEnum.Schema, Name: previousName}
+	typeLines, _ := d.typeWriter.GetCreateTypeLines(toEnum)
+	d.createTypes = append(d.createTypes,
+		fmt.Sprintf("ALTER TYPE %s RENAME TO %s;", toEnum.GetSyntax(), previousName),
+		strings.Join(typeLines, "\n"),
+	)
+
+	for _, table := range toTables {
+		for _, column := range table.Columns {
+			if column.Type == nil || column.Type.GetSyntax() != toEnum.GetSyntax() {
+				continue
+			}
+			d.alterColumns = append(d.alterColumns, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::text::%s;",
+				getQualifiedTableName(table), column.Name, toEnum.GetSyntax(), column.Name, toEnum.GetSyntax()))
+		}
+	}
+
+	d.dropTypes = append(d.dropTypes, fmt.Sprintf("DROP TYPE IF EXISTS %s;", previousEnum.GetSyntax()))
+}
+
+func (d *snapshotDiff) createTable(table *psqldef.Table) error {
+	tableWithoutForeignKeys := table.DeepClone()
+	tableWithoutForeignKeys.ForeignKeys = nil
+
+	tableLines, tableErr := d.tableWriter.GetCreateTableLines(&tableWithoutForeignKeys)
+	if tableErr != nil {
+		return tableErr
+	}
+	d.createTables = append(d.createTables, strings.Join(tableLines, "\n"))
+
+	for _, foreignKey := range table.ForeignKeys {
+		d.addConstraints = append(d.addConstraints, d.getAddForeignKeyStatement(table, foreignKey))
+	}
+	for _, index := range table.Indices {
+		d.addConstraints = append(d.addConstraints, d.tableWriter.FormatIndexDefinition(table, index))
+	}
+	for _, trigger := range table.Triggers {
+		triggerLines, triggerErr := d.tableWriter.GetTriggerDefinitionLines(table, trigger)
+		if triggerErr != nil {
+			return triggerErr
+		}
+		d.addConstraints = append(d.addConstraints, strings.Join(triggerLines, "\n"))
+	}
+
+	if len(table.SeedData) > 0 {
+		seedDataLines, seedErr := d.tableWriter.GetSeedDataLines(table)
+		if seedErr != nil {
+			return seedErr
+		}
+		// Skip the section comment
+		d.seedData = append(d.seedData, seedDataLines[1:]...)
+	}
+
+	return nil
+}
+
+func (d *snapshotDiff) dropTable(table *psqldef.Table) {
+	tableName := getQualifiedTableName(table)
+	for _, foreignKey := range table.ForeignKeys {
+		d.dropConstraints = append(d.dropConstraints, d.getDropForeignKeyStatement(tableName, table, foreignKey))
+	}
+	d.dropTables = append(d.dropTables, fmt.Sprintf("DROP TABLE IF EXISTS %s;", tableName))
+}
+
+func (d *snapshotDiff) diffTable(fromTable *psqldef.Table, toTable *psqldef.Table) error {
+	tableName := getQualifiedTableName(toTable)
+
+	// Foreign keys, those on a primary key that is dropped and added again are recreated around it
+	for _, foreignKey := range fromTable.ForeignKeys {
+		if containsEqual(toTable.ForeignKeys, foreignKey) && !d.referencesChangedPrimaryKey(fromTable, foreignKey) {
+			continue
+		}
+		d.dropConstraints = append(d.dropConstraints, d.getDropForeignKeyStatement(tableName, fromTable, foreignKey))
+	}
+	for _, foreignKey := range toTable.ForeignKeys {
+		if containsEqual(fromTable.ForeignKeys, foreignKey) && !d.referencesChangedPrimaryKey(fromTable, foreignKey) {
+			continue
+		}
+		d.addConstraints = append(d.addConstraints, d.getAddForeignKeyStatement(toTable, foreignKey))
+	}
+
+	// Unique constraints
+	for _, uniqueConstraint := range fromTable.UniqueConstraints {
+		if containsEqual(toTable.UniqueConstraints, uniqueConstraint) {
+			continue
+		}
+		d.dropConstraints = append(d.dropConstraints, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;",
+			tableName, getUniqueConstraintName(fromTable, uniqueConstraint)))
+	}
+	for _, uniqueConstraint := range toTable.UniqueConstraints {
+		if containsEqual(fromTable.UniqueConstraints, uniqueConstraint) {
+			continue
+		}
+		namedUniqueConstraint := uniqueConstraint.DeepClone()
+		namedUniqueConstraint.Name = getUniqueConstraintName(toTable, uniqueConstraint)
+		d.addConstraints = append(d.addConstraints, fmt.Sprintf("ALTER TABLE %s ADD %s;",
+			tableName, d.tableWriter.FormatUniqueConstraintDefinition(namedUniqueConstraint)))
+	}
+
+	// Check constraints, changed constraints are dropped and added again
+	for _, checkConstraint := range fromTable.CheckConstraints {
+		if containsEqual(toTable.CheckConstraints, checkConstraint) {
+			continue
+		}
+		d.dropConstraints = append(d.dropConstraints, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;",
+			tableName, checkConstraint.Name))
+	}
+	for _, checkConstraint := range toTable.CheckConstraints {
+		if containsEqual(fromTable.CheckConstraints, checkConstraint) {
+			continue
+		}
+		d.addConstraints = append(d.addConstraints, fmt.Sprintf("ALTER TABLE %s ADD %s;",
+			tableName, d.tableWriter.FormatCheckConstraintDefinition(checkConstraint)))
+	}
+
+	// Indices
+	for _, index := range fromTable.Indices {
+		if containsEqual(toTable.Indices, index) {
+			continue
+		}
+		d.dropConstraints = append(d.dropConstraints, fmt.Sprintf("DROP INDEX IF EXISTS %s;", d.getQualifiedIndexName(fromTable, index)))
+	}
+	for _, index := range toTable.Indices {
+		if containsEqual(fromTable.Indices, index) {
+			continue
+		}
+		d.addConstraints = append(d.addConstraints, d.tableWriter.FormatIndexDefinition(toTable, index))
+	}
+
+	// Triggers, changed triggers are replaced in place by their definition
+	for _, trigger := range fromTable.Triggers {
+		if containsTriggerName(toTable.Triggers, trigger.Name) {
+			continue
+		}
+		d.dropConstraints = append(d.dropConstraints, fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s;", trigger.Name, tableName))
+		d.dropTables = append(d.dropTables, fmt.Sprintf("DROP FUNCTION IF EXISTS %s();", getQualifiedFunctionName(trigger)))
+	}
+	for _, trigger := range toTable.Triggers {
+		if containsEqual(fromTable.Triggers, trigger) {
+			continue
+		}
+		triggerLines, triggerErr := d.tableWriter.GetTriggerDefinitionLines(toTable, trigger)
+		if triggerErr != nil {
+			return triggerErr
+		}
+		d.addConstraints = append(d.addConstraints, strings.Join(triggerLines, "\n"))
+	}
+
+	columnsErr := d.diffColumns(fromTable, toTable)
+	if columnsErr != nil {
+		return columnsErr
+	}
+
+	return d.diffSeedData(fromTable, toTable)
+}
+
+func (d *snapshotDiff) diffColumns(fromTable *psqldef.Table, toTable *psqldef.Table) error {
+	tableName := getQualifiedTableName(toTable)
+	columnRenames := d.options.ColumnRenames[tableName]
+
+	primaryKeyChanged := d.primaryKeyChangedTables[getQualifiedTableName(fromTable)]
+	if primaryKeyChanged && len(getPrimaryKeyColumnNames(fromTable)) > 0 {
+		d.dropPrimaryKeys = append(d.dropPrimaryKeys, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s_pkey;",
+			tableName, fromTable.Name))
+	}
+
+	fromColumns := map[string]psqldef.TableColumn{}
+	for _, column := range fromTable.Columns {
+		fromColumns[column.Name] = column
+	}
+	toColumns := map[string]psqldef.TableColumn{}
+	for _, column := range toTable.Columns {
+		toColumns[column.Name] = column
+	}
+	previousColumnNames := map[string]string{}
+	for fromColumnName, toColumnName := range columnRenames {
+		previousColumnNames[toColumnName] = fromColumnName
+	}
+
+	addedColumnNames := []string{}
+	for _, toColumn := range toTable.Columns {
+		fromColumnName, renamed := previousColumnNames[toColumn.Name]
+		if !renamed {
+			fromColumnName = toColumn.Name
+		}
+		fromColumn, exists := fromColumns[fromColumnName]
+		if !exists {
+			addErr := d.addColumn(tableName, toColumn)
+			if addErr != nil {
+				return addErr
+			}
+			addedColumnNames = append(addedColumnNames, toColumn.Name)
+			continue
+		}
+		if renamed {
+			d.renames = append(d.renames, fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;",
+				tableName, fromColumnName, toColumn.Name))
+		}
+		d.alterColumns = append(d.alterColumns, getAlterColumnStatements(tableName, fromColumn, toColumn)...)
+	}
+
+	droppedColumnNames := []string{}
+	for _, fromColumn := range fromTable.Columns {
+		if _, exists := toColumns[fromColumn.Name]; exists {
+			continue
+		}
+		if _, renamed := columnRenames[fromColumn.Name]; renamed {
+			continue
+		}
+		d.dropColumns = append(d.dropColumns, fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS %s;", tableName, fromColumn.Name))
+		droppedColumnNames = append(droppedColumnNames, fromColumn.Name)
+	}
+
+	if len(droppedColumnNames) > 0 && len(addedColumnNames) > 0 && !d.options.AllowDropAndAdd {
+		return ErrPossibleColumnRename(tableName, droppedColumnNames, addedColumnNames)
+	}
+
+	toPrimaryKeys := getPrimaryKeyColumnNames(toTable)
+	if primaryKeyChanged && len(toPrimaryKeys) > 0 {
+		d.alterColumns = append(d.alterColumns, fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);",
+			tableName, strings.Join(toPrimaryKeys, ", ")))
+	}
+
+	return nil
+}
+
+// addColumn adds a column to an existing table. NOT NULL columns without a default have no value for existing
+// rows, so they are added as nullable, backfilled and only then set to NOT NULL. Down migrations add such columns
+// back as nullable unless a down backfill is given.
+func (d *snapshotDiff) addColumn(tableName string, column psqldef.TableColumn) error {
+	addedColumn := column
+	addedColumn.PrimaryKey = false
+	if !column.NotNull || column.Default != "" || isSerialColumn(column) {
+		d.alterColumns = append(d.alterColumns, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;",
+			tableName, d.tableWriter.FormatColumnDefinition(addedColumn)))
+		return nil
+	}
+
+	addedColumn.NotNull = false
+	backfill, hasBackfill := d.options.ColumnBackfills[tableName][column.Name]
+	if !hasBackfill && d.options.nullableWithoutBackfill {
+		d.alterColumns = append(d.alterColumns, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;",
+			tableName, d.tableWriter.FormatColumnDefinition(addedColumn)))
+		return nil
+	}
+	if !hasBackfill {
+		return ErrNotNullColumnWithoutDefault(tableName, column.Name)
+	}
+	d.alterColumns = append(d.alterColumns,
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;", tableName, d.tableWriter.FormatColumnDefinition(addedColumn)),
+		fmt.Sprintf("UPDATE %s SET %s = %s;", tableName, column.Name, backfill),
+		fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", tableName, column.Name),
+	)
+	return nil
+}
+
+func getAlterColumnStatements(tableName string, fromColumn psqldef.TableColumn, toColumn psqldef.TableColumn) []string {
+	alterStatements := []string{}
+	alterPrefix := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", tableName, toColumn.Name)
+
+	fromTypeSyntax := getColumnTypeSyntax(fromColumn)
+	toTypeSyntax := getColumnTypeSyntax(toColumn)
+	if fromTypeSyntax != toTypeSyntax {
+		alterStatements = append(alterStatements, fmt.Sprintf("%s TYPE %s USING %s::%s;",
+			alterPrefix, toTypeSyntax, toColumn.Name, toTypeSyntax))
+	}
+
+	if fromColumn.NotNull != toColumn.NotNull {
+		if toColumn.NotNull {
+			alterStatements = append(alterStatements, alterPrefix+" SET NOT NULL;")
+		} else {
+			alterStatements = append(alterStatements, alterPrefix+" DROP NOT NULL;")
+		}
+	}
+
+	if fromColumn.Default != toColumn.Default {
+		if toColumn.Default != "" {
+			alterStatements = append(alterStatements, fmt.Sprintf("%s SET DEFAULT %s;", alterPrefix, toColumn.Default))
+		} else {
+			alterStatements = append(alterStatements, alterPrefix+" DROP DEFAULT;")
+		}
+	}
+
+	return alterStatements
+}
+
+func (d *snapshotDiff) diffSeedData(fromTable *psqldef.Table, toTable *psqldef.Table) error {
+	fromRows := getSeedDataRows(fromTable)
+	toRows := getSeedDataRows(toTable)
+	tableName := getQualifiedTableName(toTable)
+
+	columnTypes := map[string]psqldef.PSQLType{}
+	for _, column := range fromTable.Columns {
+		columnTypes[column.Name] = column.Type
+	}
+	referencedConditions, referencingTableNames := d.getReferencedRowConditions(toTable)
+
+	for _, rowKey := range core.MapKeysSorted(fromRows) {
+		if _, exists := toRows[rowKey]; exists {
+			continue
+		}
+		row := fromRows[rowKey]
+		conditions := make([]string, len(row.columns))
+		for colIdx, columnName := range row.columns {
+			value := row.values[colIdx]
+			if value == nil {
+				conditions[colIdx] = columnName + " IS NULL"
+				continue
+			}
+			conditions[colIdx] = fmt.Sprintf("%s = %s", columnName, d.tableWriter.FormatSQLValue(value, columnTypes[columnName]))
+		}
+		rowCondition := strings.Join(conditions, " AND ")
+		if len(referencedConditions) > 0 {
+			// Deleting a referenced row would cascade to or fail on the referencing rows, so the migration stops first
+			d.seedData = append(d.seedData, strings.Join([]string{
+				"DO $$",
+				"BEGIN",
+				fmt.Sprintf("\tIF EXISTS (SELECT 1 FROM %s WHERE %s AND (%s)) THEN",
+					tableName, rowCondition, strings.Join(referencedConditions, " OR ")),
+				fmt.Sprintf("\t\tRAISE EXCEPTION 'removed seed data of table %s is still referenced by %s';",
+					tableName, strings.Join(referencingTableNames, ", ")),
+				"\tEND IF;",
+				"END $$;",
+			}, "\n"))
+		}
+		d.seedData = append(d.seedData, fmt.Sprintf("DELETE FROM %s WHERE %s;", tableName, rowCondition))
+	}
+
+	addedRowsTable := toTable.DeepClone()
+	addedRowsTable.SeedData = nil
+	for _, rowKey := range core.MapKeysSorted(toRows) {
+		if _, exists := fromRows[rowKey]; exists {
+			continue
+		}
+		row := toRows[rowKey]
+		addedRowsTable.SeedData = append(addedRowsTable.SeedData, psqldef.InsertStatement{
+			Schema:    row.schema,
+			TableName: toTable.Name,
+			Columns:   row.columns,
+			Values:    [][]any{row.values},
+		})
+	}
+	if len(addedRowsTable.SeedData) == 0 {
+		return nil
+	}
+
+	seedDataLines, seedErr := d.tableWriter.GetSeedDataLines(&addedRowsTable)
+	if seedErr != nil {
+		return seedErr
+	}
+	// Skip the section comment
+	d.seedData = append(d.seedData, seedDataLines[1:]...)
+	return nil
+}
+
+func (d *snapshotDiff) diffViews(fromViews []*psqldef.View, toViews []*psqldef.View, changedTableNames map[string]bool) error {
+	fromViewsByName := getViewsByName(fromViews)
+	toViewsByName := getViewsByName(toViews)
+
+	recreateViewNames := map[string]bool{}
+	for _, viewName := range core.MapKeysSorted(fromViewsByName) {
+		fromView := fromViewsByName[viewName]
+		toView, exists := toViewsByName[viewName]
+		if exists && reflect.DeepEqual(fromView, toView) && !isViewOnChangedTable(fromView, changedTableNames) {
+			continue
+		}
+		if fromView.Materialized {
+			d.dropViews = append(d.dropViews, fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s;", viewName))
+		} else {
+			d.dropViews = append(d.dropViews, fmt.Sprintf("DROP VIEW IF EXISTS %s;", viewName))
+		}
+		if fromView.RefreshFunctionName != "" && (!exists || toView.RefreshFunctionName != fromView.RefreshFunctionName) {
+			d.dropViews = append(d.dropViews, fmt.Sprintf("DROP FUNCTION IF EXISTS %s();",
+				getQualifiedRefreshFunctionName(fromView)))
+		}
+		recreateViewNames[viewName] = true
+	}
+
+	for _, viewName := range core.MapKeysSorted(toViewsByName) {
+		if _, exists := fromViewsByName[viewName]; exists && !recreateViewNames[viewName] {
+			continue
+		}
+		toView := toViewsByName[viewName]
+		viewLines, viewErr := d.viewWriter.GetCreateViewLines(toView)
+		if viewErr != nil {
+			return viewErr
+		}
+		d.createViews = append(d.createViews, strings.Join(viewLines, "\n"))
+		if !toView.Materialized {
+			continue
+		}
+		d.createViews = append(d.createViews, d.viewWriter.GetViewIndexLines(toView)...)
+		if toView.RefreshFunctionName != "" {
+			d.createViews = append(d.createViews, strings.Join(d.viewWriter.GetRefreshFunctionLines(toView), "\n"))
+		}
+	}
+
+	return nil
+}
+
+func (d *snapshotDiff) getAddForeignKeyStatement(table *psqldef.Table, foreignKey psqldef.ForeignKey) string {
+	foreignKeyLines := d.tableWriter.GetForeignKeyConstraintLines(foreignKey)
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", getQualifiedTableName(table), strings.Join(foreignKeyLines, "\n"))
+}
+
+// getDropForeignKeyStatement drops a foreign key of the table from the table name, which differs from the table's
+// own name once the table is renamed
+func (d *snapshotDiff) getDropForeignKeyStatement(tableName string, table *psqldef.Table, foreignKey psqldef.ForeignKey) string {
+	constraintName := foreignKey.Name
+	if constraintName == "" {
+		// PostgreSQL's default name for unnamed foreign key constraints
+		constraintName = fmt.Sprintf("%s_%s_fkey", table.Name, strings.Join(foreignKey.ColumnNames, "_"))
+	}
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", tableName, constraintName)
+}
+
+// getReferencedRowConditions returns conditions matching the rows of the table that a foreign key of the target
+// snapshot references, along with the sorted names of the referencing tables
+func (d *snapshotDiff) getReferencedRowConditions(table *psqldef.Table) ([]string, []string) {
+	tableName := getQualifiedTableName(table)
+	conditions := []string{}
+	referencingTableNames := []string{}
+	for _, referencingTableName := range core.MapKeysSorted(d.toTables) {
+		referencingTable := d.toTables[referencingTableName]
+		for _, foreignKey := range referencingTable.ForeignKeys {
+			if getReferencedTableName(referencingTable, foreignKey) != tableName {
+				continue
+			}
+			columnMatches := make([]string, len(foreignKey.ColumnNames))
+			for colIdx, columnName := range foreignKey.ColumnNames {
+				columnMatches[colIdx] = fmt.Sprintf("referencing.%s = %s.%s",
+					columnName, table.Name, foreignKey.RefColumnNames[colIdx])
+			}
+			conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM %s referencing WHERE %s)",
+				referencingTableName, strings.Join(columnMatches, " AND ")))
+			if !slices.Contains(referencingTableNames, referencingTableName) {
+				referencingTableNames = append(referencingTableNames, referencingTableName)
+			}
+		}
+	}
+	return conditions, referencingTableNames
+}
+
+func (d *snapshotDiff) getRenamedTableName(tableName string) string {
+	if renamedTableName, renamed := d.options.TableRenames[tableName]; renamed {
+		return renamedTableName
+	}
+	return tableName
+}
+
+// getPrimaryKeyChangedTables returns the qualified names of kept tables whose primary key columns change
+func (d *snapshotDiff) getPrimaryKeyChangedTables(fromTables map[string]*psqldef.Table) map[string]bool {
+	changedTableNames := map[string]bool{}
+	for fromTableName, fromTable := range fromTables {
+		toTable, exists := d.toTables[d.getRenamedTableName(fromTableName)]
+		if !exists {
+			continue
+		}
+		columnRenames := d.options.ColumnRenames[getQualifiedTableName(toTable)]
+		fromPrimaryKeys := getPrimaryKeyColumnNames(fromTable)
+		for keyIdx, columnName := range fromPrimaryKeys {
+			if renamedColumnName, renamed := columnRenames[columnName]; renamed {
+				fromPrimaryKeys[keyIdx] = renamedColumnName
+			}
+		}
+		if !slices.Equal(fromPrimaryKeys, getPrimaryKeyColumnNames(toTable)) {
+			changedTableNames[fromTableName] = true
+		}
+	}
+	return changedTableNames
+}
+
+func (d *snapshotDiff) referencesChangedPrimaryKey(table *psqldef.Table, foreignKey psqldef.ForeignKey) bool {
+	return d.primaryKeyChangedTables[getReferencedTableName(table, foreignKey)]
+}
+
+func (d *snapshotDiff) hasForeignKeyOnChangedPrimaryKey(table *psqldef.Table) bool {
+	for _, foreignKey := range table.ForeignKeys {
+		if d.referencesChangedPrimaryKey(table, foreignKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRenames checks that every hinted rename replaces a table or column of the one snapshot by the other
+func validateRenames(options DiffOptions, fromTables map[string]*psqldef.Table, toTables map[string]*psqldef.Table) error {
+	for _, fromTableName := range core.MapKeysSorted(options.TableRenames) {
+		toTableName := options.TableRenames[fromTableName]
+		_, fromExists := fromTables[fromTableName]
+		_, fromKept := toTables[fromTableName]
+		_, toExists := toTables[toTableName]
+		_, toExisted := fromTables[toTableName]
+		if !fromExists || fromKept || !toExists || toExisted {
+			return ErrInvalidTableRename(fromTableName, toTableName)
+		}
+	}
+
+	previousTableNames := options.getInverse().TableRenames
+	for _, tableName := range core.MapKeysSorted(options.ColumnRenames) {
+		fromTableName, renamed := previousTableNames[tableName]
+		if !renamed {
+			fromTableName = tableName
+		}
+		fromTable, fromExists := fromTables[fromTableName]
+		toTable, toExists := toTables[tableName]
+		columnRenames := options.ColumnRenames[tableName]
+		for _, fromColumnName := range core.MapKeysSorted(columnRenames) {
+			toColumnName := columnRenames[fromColumnName]
+			if !fromExists || !toExists ||
+				!hasColumn(fromTable, fromColumnName) || hasColumn(toTable, fromColumnName) ||
+				!hasColumn(toTable, toColumnName) || hasColumn(fromTable, toColumnName) {
+				return ErrInvalidColumnRename(tableName, fromColumnName, toColumnName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateDroppedAndAddedTables fails when tables are dropped while others are added to the same schema, as the
+// added tables may be unhinted renames of the dropped ones
+func validateDroppedAndAddedTables(droppedTables []*psqldef.Table, addedTables []*psqldef.Table) error {
+	addedSchemas := map[string]bool{}
+	for _, table := range addedTables {
+		addedSchemas[table.Schema] = true
+	}
+
+	droppedSchemas := map[string]bool{}
+	droppedTableNames := []string{}
+	for _, table := range droppedTables {
+		if !addedSchemas[table.Schema] {
+			continue
+		}
+		droppedSchemas[table.Schema] = true
+		droppedTableNames = append(droppedTableNames, getQualifiedTableName(table))
+	}
+	if len(droppedTableNames) == 0 {
+		return nil
+	}
+
+	addedTableNames := []string{}
+	for _, table := range addedTables {
+		if droppedSchemas[table.Schema] {
+			addedTableNames = append(addedTableNames, getQualifiedTableName(table))
+		}
+	}
+	return ErrPossibleTableRename(droppedTableNames, addedTableNames)
+}
+
+type seedDataRow struct {
+	schema  string
+	columns []string
+	values  []any
+}
+
+func getSeedDataRows(table *psqldef.Table) map[string]seedDataRow {
+	rows := map[string]seedDataRow{}
+	for _, insertStmt := range table.SeedData {
+		for _, valueRow := range insertStmt.Values {
+			rowKey := fmt.Sprintf("%v=%#v", insertStmt.Columns, valueRow)
+			rows[rowKey] = seedDataRow{
+				schema:  insertStmt.Schema,
+				columns: insertStmt.Columns,
+				values:  valueRow,
+			}
+		}
+	}
+	return rows
+}
+
+func isViewOnChangedTable(view *psqldef.View, changedTableNames map[string]bool) bool {
+	if changedTableNames[getUnqualifiedName(view.FromTable)] {
+		return true
+	}
+	for _, join := range view.Joins {
+		if changedTableNames[getUnqualifiedName(join.Table)] {
+			return true
+		}
+	}
+	return false
+}
+
+func getSnapshotSchemas(snapshot Snapshot) map[string]bool {
+	schemas := map[string]bool{}
+	for _, psqlType := range snapshot.Types {
+		if psqlType.GetSchema() != "" {
+			schemas[psqlType.GetSchema()] = true
+		}
+	}
+	for _, table := range snapshot.Tables {
+		if table.Schema != "" {
+			schemas[table.Schema] = true
+		}
+	}
+	for _, view := range snapshot.Views {
+		if view.Schema != "" {
+			schemas[view.Schema] = true
+		}
+	}
+	return schemas
+}
+
+func getTypesByName(allTypes []psqldef.PSQLType) map[string]psqldef.PSQLType {
+	typesByName := map[string]psqldef.PSQLType{}
+	for _, psqlType := range allTypes {
+		typesByName[psqlType.GetSyntax()] = psqlType
+	}
+	return typesByName
+}
+
+func getTablesByName(allTables []*psqldef.Table) map[string]*psqldef.Table {
+	tablesByName := map[string]*psqldef.Table{}
+	for _, table := range allTables {
+		tablesByName[getQualifiedTableName(table)] = table
+	}
+	return tablesByName
+}
+
+func getViewsByName(allViews []*psqldef.View) map[string]*psqldef.View {
+	viewsByName := map[string]*psqldef.View{}
+	for _, view := range allViews {
+		viewName := view.Name
+		if view.Schema != "" {
+			viewName = view.Schema + "." + viewName
+		}
+		viewsByName[viewName] = view
+	}
+	return viewsByName
+}
+
+func getEnumType(psqlType psqldef.PSQLType) (psqldef.PSQLTypeEnum, bool) {
+	switch enumType := psqlType.(type) {
+	case psqldef.PSQLTypeEnum:
+		return enumType, true
+	case *psqldef.PSQLTypeEnum:
+		return *enumType, true
+	}
+	return psqldef.PSQLTypeEnum{}, false
+}
+
+func getCompositeType(psqlType psqldef.PSQLType) (psqldef.PSQLTypeComposite, bool) {
+	switch compositeType := psqlType.(type) {
+	case psqldef.PSQLTypeComposite:
+		return compositeType, true
+	case *psqldef.PSQLTypeComposite:
+		return *compositeType, true
+	}
+	return psqldef.PSQLTypeComposite{}, false
+}
+
+// getOrderedTypeNames returns the type names sorted so every type follows the types it depends on
+func getOrderedTypeNames(typesByName map[string]psqldef.PSQLType) ([]string, error) {
+	allTypes := []psqldef.PSQLType{}
+	for _, typeName := range core.MapKeysSorted(typesByName) {
+		allTypes = append(allTypes, typesByName[typeName])
+	}
+	orderedTypes, orderErr := compile.SortCustomTypesByDependencies(allTypes)
+	if orderErr != nil {
+		return nil, orderErr
+	}
+
+	typeNames := []string{}
+	for _, orderedType := range orderedTypes {
+		typeNames = append(typeNames, orderedType.GetSyntax())
+	}
+	return typeNames, nil
+}
+
+func getDropTypeStatement(psqlType psqldef.PSQLType) string {
+	if psqlType.IsDomain() {
+		return fmt.Sprintf("DROP DOMAIN IF EXISTS %s;", psqlType.GetSyntax())
+	}
+	return fmt.Sprintf("DROP TYPE IF EXISTS %s;", psqlType.GetSyntax())
+}
+
+func getQualifiedTableName(table *psqldef.Table) string {
+	if table.Schema != "" {
+		return table.Schema + "." + table.Name
+	}
+	return table.Name
+}
+
+// getReferencedTableName returns the qualified name of the table a foreign key of the table references
+func getReferencedTableName(table *psqldef.Table, foreignKey psqldef.ForeignKey) string {
+	refSchema := foreignKey.RefSchema
+	if refSchema == "" {
+		refSchema = table.Schema
+	}
+	return getQualifiedTableName(&psqldef.Table{Schema: refSchema, Name: foreignKey.RefTableName})
+}
+
+// getQualifiedIndexName returns the schema qualified name of an index, named like the table writer names it
+func (d *snapshotDiff) getQualifiedIndexName(table *psqldef.Table, index psqldef.Index) string {
+	indexName := d.tableWriter.GetIndexName(table, index)
+	if table.Schema != "" {
+		return table.Schema + "." + indexName
+	}
+	return indexName
+}
+
+func getQualifiedFunctionName(trigger psqldef.Trigger) string {
+	if trigger.Schema != "" {
+		return trigger.Schema + "." + trigger.FunctionName
+	}
+	return trigger.FunctionName
+}
+
+func getQualifiedRefreshFunctionName(view *psqldef.View) string {
+	if view.Schema != "" {
+		return view.Schema + "." + view.RefreshFunctionName
+	}
+	return view.RefreshFunctionName
+}
+
+func getUniqueConstraintName(table *psqldef.Table, uniqueConstraint psqldef.UniqueConstraint) string {
+	if uniqueConstraint.Name != "" {
+		return uniqueConstraint.Name
+	}
+	// PostgreSQL's default name for unnamed unique constraints
+	return fmt.Sprintf("%s_%s_key", table.Name, strings.Join(uniqueConstraint.ColumnNames, "_"))
+}
+
+func getUnqualifiedName(name string) string {
+	nameParts := strings.Split(name, ".")
+	return nameParts[len(nameParts)-1]
+}
+
+func getPrimaryKeyColumnNames(table *psqldef.Table) []string {
+	primaryKeys := []string{}
+	for _, column := range table.Columns {
+		if column.PrimaryKey {
+			primaryKeys = append(primaryKeys, column.Name)
+		}
+	}
+	return primaryKeys
+}
+
+// getColumnTypeSyntax returns the column type usable in ALTER COLUMN ... TYPE, where serial pseudo-types are not allowed
+func getColumnTypeSyntax(column psqldef.TableColumn) string {
+	if column.Type == nil {
+		return ""
+	}
+	typeSyntax := column.Type.GetSyntax()
+	switch typeSyntax {
+	case psqldef.PSQLTypeSerial.GetSyntax():
+		return psqldef.PSQLTypeInteger.GetSyntax()
+	case psqldef.PSQLTypeBigSerial.GetSyntax():
+		return psqldef.PSQLTypeBigInt.GetSyntax()
+	}
+	return typeSyntax
+}
+
+// isSerialColumn reports whether the column has a serial pseudo-type, which fills in values on its own
+func isSerialColumn(column psqldef.TableColumn) bool {
+	return column.Type != nil && getColumnTypeSyntax(column) != column.Type.GetSyntax()
+}
+
+func hasColumn(table *psqldef.Table, columnName string) bool {
+	for _, column := range table.Columns {
+		if column.Name == columnName {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTriggerName(triggers []psqldef.Trigger, triggerName string) bool {
+	for _, trigger := range triggers {
+		if trigger.Name == triggerName {
+			return true
+		}
+	}
+	return false
+}
+
+func containsEqual[T any](items []T, item T) bool {
+	for _, candidate := range items {
+		if reflect.DeepEqual(candidate, item) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,601 @@
+package migrate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+type DiffSnapshotsTestSuite struct {
+	suite.Suite
+}
+
+func TestDiffSnapshotsTestSuite(t *testing.T) {
+	suite.Run(t, new(DiffSnapshotsTestSuite))
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_NewTables_ForeignKeysAddedLast() {
+	ownersTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "owners",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+		},
+	}
+	petsTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "pets",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "owner_id", Type: psqldef.PSQLTypeInteger, NotNull: true},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{
+				Schema:         "public",
+				Name:           "fk_pets_owner_id",
+				TableName:      "pets",
+				ColumnNames:    []string{"owner_id"},
+				RefTableName:   "owners",
+				RefColumnNames: []string{"id"},
+				OnDelete:       "CASCADE",
+			},
+		},
+	}
+
+	statements, diffErr := migrate.DiffSnapshots(migrate.Snapshot{}, migrate.Snapshot{
+		Tables: []*psqldef.Table{petsTable, ownersTable},
+	}, migrate.DiffOptions{})
+
+	suite.Nil(diffErr)
+	suite.Equal([]string{
+		"CREATE SCHEMA IF NOT EXISTS public;",
+		"CREATE TABLE IF NOT EXISTS public.owners (\n\tid SERIAL PRIMARY KEY\n);",
+		"CREATE TABLE IF NOT EXISTS public.pets (\n\tid SERIAL PRIMARY KEY,\n\towner_id INTEGER NOT NULL\n);",
+		"ALTER TABLE public.pets ADD CONSTRAINT fk_pets_owner_id FOREIGN KEY (owner_id)\n\tREFERENCES owners(id)\n\tON DELETE CASCADE;",
+	}, statements)
+
+	downStatements, downErr := migrate.DiffSnapshots(migrate.Snapshot{
+		Tables: []*psqldef.Table{petsTable, ownersTable},
+	}, migrate.Snapshot{}, migrate.DiffOptions{})
+
+	suite.Nil(downErr)
+	suite.Equal([]string{
+		"ALTER TABLE public.pets DROP CONSTRAINT IF EXISTS fk_pets_owner_id;",
+		"DROP TABLE IF EXISTS public.owners;",
+		"DROP TABLE IF EXISTS public.pets;",
+	}, downStatements)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_EnumValueAdded() {
+	fromEnum := &psqldef.PSQLTypeEnum{Schema: "public", Name: "nationality", Values: []string{"DE", "US"}}
+	toEnum := &psqldef.PSQLTypeEnum{Schema: "public", Name: "nationality", Values: []string{"DE", "IT", "US"}}
+
+	statements, diffErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Types: []psqldef.PSQLType{fromEnum}},
+		migrate.Snapshot{Types: []psqldef.PSQLType{toEnum}},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(diffErr)
+	suite.Equal([]string{
+		"ALTER TYPE public.nationality ADD VALUE IF NOT EXISTS 'IT';",
+	}, statements)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_EnumValueRemoved() {
+	fromEnum := &psqldef.PSQLTypeEnum{Schema: "public", Name: "nationality", Values: []string{"DE", "FR", "US"}}
+	toEnum := &psqldef.PSQLTypeEnum{Schema: "public", Name: "nationality", Values: []string{"DE", "US"}}
+	peopleTable := func(enumType *psqldef.PSQLTypeEnum) *psqldef.Table {
+		return &psqldef.Table{
+			Schema: "public",
+			Name:   "people",
+			Columns: []psqldef.TableColumn{
+				{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+				{Name: "nationality", Type: enumType, NotNull: true},
+			},
+		}
+	}
+
+	statements, diffErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Types: []psqldef.PSQLType{fromEnum}, Tables: []*psqldef.Table{peopleTable(fromEnum)}},
+		migrate.Snapshot{Types: []psqldef.PSQLType{toEnum}, Tables: []*psqldef.Table{peopleTable(toEnum)}},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(diffErr)
+	suite.Equal([]string{
+		"ALTER TYPE public.nationality RENAME TO nationality_previous;",
+		"CREATE TYPE public.nationality AS ENUM (\n\t'DE',\n\t'US'\n);",
+		"ALTER TABLE public.people ALTER COLUMN nationality TYPE public.nationality USING nationality::text::public.nationality;",
+		"DROP TYPE IF EXISTS public.nationality_previous;",
+	}, statements)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_ViewMaterialized() {
+	fromView := &psqldef.View{
+		Schema:    "public",
+		Name:      "tag_entities",
+		Columns:   []psqldef.ViewColumn{{Name: "id", SourceRef: "tags.id"}},
+		FromTable: "tags",
+	}
+	toView := &psqldef.View{
+		Schema:       "public",
+		Name:         "tag_entities",
+		Columns:      []psqldef.ViewColumn{{Name: "id", SourceRef: "tags.id"}},
+		FromTable:    "tags",
+		Materialized: true,
+		Indices: []psqldef.Index{
+			{Schema: "public", Name: "uidx_tag_entities_id", TableName: "tag_entities", Columns: []string{"id"}, IsUnique: true},
+		},
+		RefreshFunctionName: "fn_refresh_tag_entities",
+	}
+
+	statements, diffErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Views: []*psqldef.View{fromView}},
+		migrate.Snapshot{Views: []*psqldef.View{toView}},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(diffErr)
+	suite.Equal([]string{
+		"DROP VIEW IF EXISTS public.tag_entities;",
+		"CREATE MATERIALIZED VIEW IF NOT EXISTS public.tag_entities AS\nSELECT\n\ttags.id\nFROM tags\nWITH DATA;",
+		"CREATE UNIQUE INDEX IF NOT EXISTS uidx_tag_entities_id ON public.tag_entities (id);",
+		"CREATE OR REPLACE FUNCTION public.fn_refresh_tag_entities()\nRETURNS VOID AS $$\nBEGIN\n\tREFRESH MATERIALIZED VIEW CONCURRENTLY public.tag_entities;\nEND;\n$$ LANGUAGE plpgsql;",
+	}, statements)
+
+	downStatements, downErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Views: []*psqldef.View{toView}},
+		migrate.Snapshot{Views: []*psqldef.View{fromView}},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(downErr)
+	suite.Equal([]string{
+		"DROP MATERIALIZED VIEW IF EXISTS public.tag_entities;",
+		"DROP FUNCTION IF EXISTS public.fn_refresh_tag_entities();",
+		"CREATE OR REPLACE VIEW public.tag_entities AS\nSELECT\n\ttags.id\nFROM tags;",
+	}, downStatements)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_CompositeTypeChanged() {
+	nationalityEnum := &psqldef.PSQLTypeEnum{Schema: "public", Name: "nationality", Values: []string{"DE", "US"}}
+	fromComposite := &psqldef.PSQLTypeComposite{
+		Schema: "public",
+		Name:   "address",
+		Fields: map[string]psqldef.PSQLType{
+			"house_nr": psqldef.PSQLTypeText,
+			"street":   psqldef.PSQLTypeText,
+			"zip":      psqldef.PSQLTypeText,
+		},
+	}
+	toComposite := &psqldef.PSQLTypeComposite{
+		Schema: "public",
+		Name:   "address",
+		Fields: map[string]psqldef.PSQLType{
+			"country":  *nationalityEnum,
+			"house_nr": psqldef.PSQLTypeInteger,
+			"street":   psqldef.PSQLTypeText,
+		},
+	}
+
+	statements, diffErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Types: []psqldef.PSQLType{fromComposite}},
+		migrate.Snapshot{Types: []psqldef.PSQLType{toComposite, nationalityEnum}},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(diffErr)
+	suite.Equal([]string{
+		"CREATE TYPE public.nationality AS ENUM (\n\t'DE',\n\t'US'\n);",
+		"ALTER TYPE public.address DROP ATTRIBUTE IF EXISTS zip;",
+		"ALTER TYPE public.address ADD ATTRIBUTE country public.nationality;",
+		"ALTER TYPE public.address ALTER ATTRIBUTE house_nr TYPE INTEGER;",
+	}, statements)
+
+	downStatements, downErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Types: []psqldef.PSQLType{toComposite, nationalityEnum}},
+		migrate.Snapshot{},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(downErr)
+	suite.Equal([]string{
+		"DROP TYPE IF EXISTS public.address;",
+		"DROP TYPE IF EXISTS public.nationality;",
+	}, downStatements)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_CheckConstraintChanged() {
+	fromTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "morphe_structures",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: `"type"`, Type: psqldef.PSQLTypeText, NotNull: true},
+			{Name: `"data"`, Type: psqldef.PSQLTypeJSONB, NotNull: true},
+		},
+		CheckConstraints: []psqldef.CheckConstraint{
+			{Schema: "public", Name: "chk_morphe_structures_address", TableName: "morphe_structures", Expression: `"type" <> 'Address' OR "data" ? 'City'`},
+			{Schema: "public", Name: "chk_morphe_structures_tag", TableName: "morphe_structures", Expression: `"type" <> 'Tag' OR "data" ? 'Label'`},
+		},
+	}
+	toTable := fromTable.DeepClone()
+	toTable.CheckConstraints = []psqldef.CheckConstraint{
+		{Schema: "public", Name: "chk_morphe_structures_address", TableName: "morphe_structures", Expression: `"type" <> 'Address' OR "data" ?& ARRAY['City', 'Street']`},
+	}
+
+	statements, diffErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Tables: []*psqldef.Table{fromTable}},
+		migrate.Snapshot{Tables: []*psqldef.Table{&toTable}},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(diffErr)
+	suite.Equal([]string{
+		"ALTER TABLE public.morphe_structures DROP CONSTRAINT IF EXISTS chk_morphe_structures_address;",
+		"ALTER TABLE public.morphe_structures DROP CONSTRAINT IF EXISTS chk_morphe_structures_tag;",
+		`ALTER TABLE public.morphe_structures ADD CONSTRAINT chk_morphe_structures_address CHECK ("type" <> 'Address' OR "data" ?& ARRAY['City', 'Street']);`,
+	}, statements)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_UnnamedIndexChanged_NamingStrategy() {
+	fromTable := suite.getOwnersTable(
+		psqldef.TableColumn{Name: "name", Type: psqldef.PSQLTypeText},
+		psqldef.TableColumn{Name: "email", Type: psqldef.PSQLTypeText},
+	)
+	fromTable.Indices = []psqldef.Index{{TableName: "owners", Columns: []string{"name"}}}
+	toTable := fromTable.DeepClone()
+	toTable.Indices = []psqldef.Index{{TableName: "owners", Columns: []string{"email"}}}
+
+	statements, diffErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Tables: []*psqldef.Table{fromTable}},
+		migrate.Snapshot{Tables: []*psqldef.Table{&toTable}},
+		migrate.DiffOptions{NamingStrategy: compile.PostgresNamingStrategy{}},
+	)
+
+	suite.Nil(diffErr)
+	suite.Equal([]string{
+		"DROP INDEX IF EXISTS public.owners_name_idx;",
+		"CREATE INDEX IF NOT EXISTS owners_email_idx ON public.owners (email);",
+	}, statements)
+
+	migration, migrationErr := migrate.SnapshotsToMigration(
+		migrate.Snapshot{Tables: []*psqldef.Table{fromTable}},
+		migrate.Snapshot{Tables: []*psqldef.Table{&toTable}},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(migrationErr)
+	suite.Equal([]string{
+		"DROP INDEX IF EXISTS public.idx_owners_email;",
+		"CREATE INDEX IF NOT EXISTS idx_owners_name ON public.owners (name);",
+	}, migration.Down)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_DomainTypesInDependencyOrder() {
+	nameDomain := psqldef.PSQLTypeDomain{Schema: "public", Name: "name", ValueType: psqldef.PSQLTypeText}
+	aliasDomain := psqldef.PSQLTypeDomain{Schema: "public", Name: "alias", ValueType: nameDomain}
+	snapshot := migrate.Snapshot{
+		Types: []psqldef.PSQLType{aliasDomain, nameDomain},
+	}
+
+	statements, diffErr := migrate.DiffSnapshots(migrate.Snapshot{}, snapshot, migrate.DiffOptions{})
+
+	suite.Nil(diffErr)
+	suite.Equal([]string{
+		"CREATE SCHEMA IF NOT EXISTS public;",
+		"CREATE DOMAIN public.name AS TEXT;",
+		"CREATE DOMAIN public.alias AS public.name;",
+	}, statements)
+
+	downStatements, downErr := migrate.DiffSnapshots(snapshot, migrate.Snapshot{}, migrate.DiffOptions{})
+
+	suite.Nil(downErr)
+	suite.Equal([]string{
+		"DROP DOMAIN IF EXISTS public.alias;",
+		"DROP DOMAIN IF EXISTS public.name;",
+	}, downStatements)
+}
+
+func (suite *DiffSnapshotsTestSuite) getOwnersTable(columns ...psqldef.TableColumn) *psqldef.Table {
+	return &psqldef.Table{
+		Schema: "public",
+		Name:   "owners",
+		Columns: append([]psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+		}, columns...),
+	}
+}
+
+func (suite *DiffSnapshotsTestSuite) getPetsTable(ownersTableName string) *psqldef.Table {
+	return &psqldef.Table{
+		Schema: "public",
+		Name:   "pets",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "owner_id", Type: psqldef.PSQLTypeInteger, NotNull: true},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{
+				Schema:         "public",
+				Name:           "fk_pets_owner_id",
+				TableName:      "pets",
+				ColumnNames:    []string{"owner_id"},
+				RefTableName:   ownersTableName,
+				RefColumnNames: []string{"id"},
+				OnDelete:       "CASCADE",
+			},
+		},
+	}
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_ColumnDroppedAndAdded() {
+	from := migrate.Snapshot{Tables: []*psqldef.Table{
+		suite.getOwnersTable(psqldef.TableColumn{Name: "name", Type: psqldef.PSQLTypeText}),
+	}}
+	to := migrate.Snapshot{Tables: []*psqldef.Table{
+		suite.getOwnersTable(psqldef.TableColumn{Name: "full_name", Type: psqldef.PSQLTypeText}),
+	}}
+
+	statements, diffErr := migrate.DiffSnapshots(from, to, migrate.DiffOptions{})
+
+	suite.Nil(statements)
+	suite.EqualError(diffErr, migrate.ErrPossibleColumnRename("public.owners", []string{"name"}, []string{"full_name"}).Error())
+
+	allowedStatements, allowedErr := migrate.DiffSnapshots(from, to, migrate.DiffOptions{AllowDropAndAdd: true})
+
+	suite.Nil(allowedErr)
+	suite.Equal([]string{
+		"ALTER TABLE public.owners ADD COLUMN IF NOT EXISTS full_name TEXT;",
+		"ALTER TABLE public.owners DROP COLUMN IF EXISTS name;",
+	}, allowedStatements)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_ColumnRenamed() {
+	from := migrate.Snapshot{Tables: []*psqldef.Table{
+		suite.getOwnersTable(psqldef.TableColumn{Name: "name", Type: psqldef.PSQLTypeText}),
+	}}
+	to := migrate.Snapshot{Tables: []*psqldef.Table{
+		suite.getOwnersTable(psqldef.TableColumn{Name: "full_name", Type: psqldef.PSQLTypeText, NotNull: true}),
+	}}
+
+	migration, migrationErr := migrate.SnapshotsToMigration(from, to, migrate.DiffOptions{
+		ColumnRenames: map[string]map[string]string{
+			"public.owners": {"name": "full_name"},
+		},
+	})
+
+	suite.Nil(migrationErr)
+	suite.Equal([]string{
+		"ALTER TABLE public.owners RENAME COLUMN name TO full_name;",
+		"ALTER TABLE public.owners ALTER COLUMN full_name SET NOT NULL;",
+	}, migration.Up)
+	suite.Equal([]string{
+		"ALTER TABLE public.owners RENAME COLUMN full_name TO name;",
+		"ALTER TABLE public.owners ALTER COLUMN name DROP NOT NULL;",
+	}, migration.Down)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_ColumnRenamed_Unknown() {
+	from := migrate.Snapshot{Tables: []*psqldef.Table{suite.getOwnersTable()}}
+	to := migrate.Snapshot{Tables: []*psqldef.Table{suite.getOwnersTable()}}
+
+	statements, diffErr := migrate.DiffSnapshots(from, to, migrate.DiffOptions{
+		ColumnRenames: map[string]map[string]string{
+			"public.owners": {"name": "full_name"},
+		},
+	})
+
+	suite.Nil(statements)
+	suite.EqualError(diffErr, migrate.ErrInvalidColumnRename("public.owners", "name", "full_name").Error())
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_TableDroppedAndAdded() {
+	ownersTable := suite.getOwnersTable()
+	keepersTable := suite.getOwnersTable()
+	keepersTable.Name = "keepers"
+
+	statements, diffErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Tables: []*psqldef.Table{ownersTable}},
+		migrate.Snapshot{Tables: []*psqldef.Table{keepersTable}},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(statements)
+	suite.EqualError(diffErr, migrate.ErrPossibleTableRename([]string{"public.owners"}, []string{"public.keepers"}).Error())
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_TableRenamed() {
+	keepersTable := suite.getOwnersTable()
+	keepersTable.Name = "keepers"
+	from := migrate.Snapshot{Tables: []*psqldef.Table{suite.getOwnersTable(), suite.getPetsTable("owners")}}
+	to := migrate.Snapshot{Tables: []*psqldef.Table{keepersTable, suite.getPetsTable("keepers")}}
+
+	migration, migrationErr := migrate.SnapshotsToMigration(from, to, migrate.DiffOptions{
+		TableRenames: map[string]string{"public.owners": "public.keepers"},
+	})
+
+	suite.Nil(migrationErr)
+	suite.Equal([]string{
+		"ALTER TABLE public.owners RENAME TO keepers;",
+		"ALTER TABLE public.pets DROP CONSTRAINT IF EXISTS fk_pets_owner_id;",
+		"ALTER TABLE public.pets ADD CONSTRAINT fk_pets_owner_id FOREIGN KEY (owner_id)\n\tREFERENCES keepers(id)\n\tON DELETE CASCADE;",
+	}, migration.Up)
+	suite.Equal([]string{
+		"ALTER TABLE public.keepers RENAME TO owners;",
+		"ALTER TABLE public.pets DROP CONSTRAINT IF EXISTS fk_pets_owner_id;",
+		"ALTER TABLE public.pets ADD CONSTRAINT fk_pets_owner_id FOREIGN KEY (owner_id)\n\tREFERENCES owners(id)\n\tON DELETE CASCADE;",
+	}, migration.Down)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_TableRenamed_NotNullColumnDropped() {
+	keepersTable := suite.getOwnersTable()
+	keepersTable.Name = "keepers"
+	from := migrate.Snapshot{Tables: []*psqldef.Table{
+		suite.getOwnersTable(psqldef.TableColumn{Name: "name", Type: psqldef.PSQLTypeText, NotNull: true}),
+	}}
+	to := migrate.Snapshot{Tables: []*psqldef.Table{keepersTable}}
+	options := migrate.DiffOptions{
+		TableRenames: map[string]string{"public.owners": "public.keepers"},
+	}
+
+	migration, migrationErr := migrate.SnapshotsToMigration(from, to, options)
+
+	suite.Nil(migrationErr)
+	suite.Equal([]string{
+		"ALTER TABLE public.owners RENAME TO keepers;",
+		"ALTER TABLE public.keepers DROP COLUMN IF EXISTS name;",
+	}, migration.Up)
+	suite.Equal([]string{
+		"ALTER TABLE public.keepers RENAME TO owners;",
+		"ALTER TABLE public.owners ADD COLUMN IF NOT EXISTS name TEXT;",
+	}, migration.Down)
+
+	options.DownColumnBackfills = map[string]map[string]string{
+		"public.keepers": {"name": "'unknown'"},
+	}
+
+	backfilledMigration, backfilledErr := migrate.SnapshotsToMigration(from, to, options)
+
+	suite.Nil(backfilledErr)
+	suite.Equal(migration.Up, backfilledMigration.Up)
+	suite.Equal([]string{
+		"ALTER TABLE public.keepers RENAME TO owners;",
+		"ALTER TABLE public.owners ADD COLUMN IF NOT EXISTS name TEXT;",
+		"UPDATE public.owners SET name = 'unknown';",
+		"ALTER TABLE public.owners ALTER COLUMN name SET NOT NULL;",
+	}, backfilledMigration.Down)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_PrimaryKeyChanged_ReferencingForeignKeysRecreated() {
+	fromOwnersTable := suite.getOwnersTable(psqldef.TableColumn{Name: "code", Type: psqldef.PSQLTypeText, NotNull: true})
+	toOwnersTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "owners",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial},
+			{Name: "code", Type: psqldef.PSQLTypeText, NotNull: true, PrimaryKey: true},
+		},
+		UniqueConstraints: []psqldef.UniqueConstraint{
+			{Name: "uq_owners_id", TableName: "owners", ColumnNames: []string{"id"}},
+		},
+	}
+
+	statements, diffErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Tables: []*psqldef.Table{fromOwnersTable, suite.getPetsTable("owners")}},
+		migrate.Snapshot{Tables: []*psqldef.Table{toOwnersTable, suite.getPetsTable("owners")}},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(diffErr)
+	suite.Equal([]string{
+		"ALTER TABLE public.pets DROP CONSTRAINT IF EXISTS fk_pets_owner_id;",
+		"ALTER TABLE public.owners DROP CONSTRAINT IF EXISTS owners_pkey;",
+		"ALTER TABLE public.owners ADD PRIMARY KEY (code);",
+		"ALTER TABLE public.owners ADD CONSTRAINT uq_owners_id UNIQUE (id);",
+		"ALTER TABLE public.pets ADD CONSTRAINT fk_pets_owner_id FOREIGN KEY (owner_id)\n\tREFERENCES owners(id)\n\tON DELETE CASCADE;",
+	}, statements)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_NotNullColumnAdded() {
+	from := migrate.Snapshot{Tables: []*psqldef.Table{suite.getOwnersTable()}}
+	to := migrate.Snapshot{Tables: []*psqldef.Table{
+		suite.getOwnersTable(psqldef.TableColumn{Name: "name", Type: psqldef.PSQLTypeText, NotNull: true}),
+	}}
+
+	statements, diffErr := migrate.DiffSnapshots(from, to, migrate.DiffOptions{})
+
+	suite.Nil(statements)
+	suite.EqualError(diffErr, migrate.ErrNotNullColumnWithoutDefault("public.owners", "name").Error())
+
+	backfilledStatements, backfilledErr := migrate.DiffSnapshots(from, to, migrate.DiffOptions{
+		ColumnBackfills: map[string]map[string]string{
+			"public.owners": {"name": "'unknown'"},
+		},
+	})
+
+	suite.Nil(backfilledErr)
+	suite.Equal([]string{
+		"ALTER TABLE public.owners ADD COLUMN IF NOT EXISTS name TEXT;",
+		"UPDATE public.owners SET name = 'unknown';",
+		"ALTER TABLE public.owners ALTER COLUMN name SET NOT NULL;",
+	}, backfilledStatements)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_NotNullColumnAdded_Default() {
+	statements, diffErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Tables: []*psqldef.Table{suite.getOwnersTable()}},
+		migrate.Snapshot{Tables: []*psqldef.Table{
+			suite.getOwnersTable(psqldef.TableColumn{Name: "name", Type: psqldef.PSQLTypeText, NotNull: true, Default: "''"}),
+		}},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(diffErr)
+	suite.Equal([]string{
+		"ALTER TABLE public.owners ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '';",
+	}, statements)
+}
+
+func (suite *DiffSnapshotsTestSuite) TestDiffSnapshots_SeedDataRemoved_FailsWhileReferenced() {
+	nationalitiesTable := func(keys ...string) *psqldef.Table {
+		table := &psqldef.Table{
+			Schema: "public",
+			Name:   "nationalities",
+			Columns: []psqldef.TableColumn{
+				{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+				{Name: "key", Type: psqldef.PSQLTypeText, NotNull: true},
+			},
+		}
+		for _, key := range keys {
+			table.SeedData = append(table.SeedData, psqldef.InsertStatement{
+				Schema:    "public",
+				TableName: "nationalities",
+				Columns:   []string{"key"},
+				Values:    [][]any{{key}},
+			})
+		}
+		return table
+	}
+	peopleTable := &psqldef.Table{
+		Schema: "public",
+		Name:   "people",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "nationality_id", Type: psqldef.PSQLTypeInteger, NotNull: true},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{
+				Schema:         "public",
+				Name:           "fk_people_nationality_id",
+				TableName:      "people",
+				ColumnNames:    []string{"nationality_id"},
+				RefSchema:      "public",
+				RefTableName:   "nationalities",
+				RefColumnNames: []string{"id"},
+				OnDelete:       "CASCADE",
+			},
+		},
+	}
+
+	statements, diffErr := migrate.DiffSnapshots(
+		migrate.Snapshot{Tables: []*psqldef.Table{nationalitiesTable("DE", "FR"), peopleTable}},
+		migrate.Snapshot{Tables: []*psqldef.Table{nationalitiesTable("DE"), peopleTable}},
+		migrate.DiffOptions{},
+	)
+
+	suite.Nil(diffErr)
+	suite.Equal([]string{
+		"DO $$\nBEGIN\n" +
+			"\tIF EXISTS (SELECT 1 FROM public.nationalities WHERE key = 'FR' AND " +
+			"(EXISTS (SELECT 1 FROM public.people referencing WHERE referencing.nationality_id = nationalities.id))) THEN\n" +
+			"\t\tRAISE EXCEPTION 'removed seed data of table public.nationalities is still referenced by public.people';\n" +
+			"\tEND IF;\nEND $$;",
+		"DELETE FROM public.nationalities WHERE key = 'FR';",
+	}, statements)
+}
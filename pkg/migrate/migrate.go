@@ -0,0 +1,83 @@
+package migrate
+
+import (
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	rcfg "github.com/kalo-build/morphe-go/pkg/registry/cfg"
+)
+
+// MorpheToPSQLMigration compiles both registry snapshots and writes the next versioned migration files
+// into the migrations directory. No files are written if the snapshots produce an empty migration.
+func MorpheToPSQLMigration(config MorpheMigrateConfig) (*Migration, error) {
+	validateErr := config.Validate()
+	if validateErr != nil {
+		return nil, validateErr
+	}
+
+	fromSnapshot, fromErr := loadSnapshot(config, config.FromRegistryConfig)
+	if fromErr != nil {
+		return nil, fromErr
+	}
+
+	toSnapshot, toErr := loadSnapshot(config, config.ToRegistryConfig)
+	if toErr != nil {
+		return nil, toErr
+	}
+
+	diffOptions := config.DiffOptions
+	if diffOptions.NamingStrategy == nil {
+		diffOptions.NamingStrategy = config.CompileConfig.GetNamingStrategy()
+	}
+
+	migration, migrationErr := SnapshotsToMigration(fromSnapshot, toSnapshot, diffOptions)
+	if migrationErr != nil {
+		return nil, migrationErr
+	}
+	migration.Description = config.Description
+
+	if migration.IsEmpty() {
+		return migration, nil
+	}
+
+	version, versionErr := GetNextMigrationVersion(config.MigrationsDirPath)
+	if versionErr != nil {
+		return nil, versionErr
+	}
+	migration.Version = version
+
+	_, _, writeErr := WriteMigrationFiles(config.MigrationsDirPath, migration)
+	if writeErr != nil {
+		return nil, writeErr
+	}
+
+	return migration, nil
+}
+
+// SnapshotsToMigration diffs the snapshots in both directions into the up and down statements of a migration,
+// the down statements revert the renames hinted in the options
+func SnapshotsToMigration(fromSnapshot Snapshot, toSnapshot Snapshot, options DiffOptions) (*Migration, error) {
+	upStatements, upErr := DiffSnapshots(fromSnapshot, toSnapshot, options)
+	if upErr != nil {
+		return nil, upErr
+	}
+
+	downStatements, downErr := DiffSnapshots(toSnapshot, fromSnapshot, options.getInverse())
+	if downErr != nil {
+		return nil, downErr
+	}
+
+	return &Migration{
+		Up:   upStatements,
+		Down: downStatements,
+	}, nil
+}
+
+func loadSnapshot(config MorpheMigrateConfig, registryConfig rcfg.MorpheLoadRegistryConfig) (Snapshot, error) {
+	r, rErr := registry.LoadMorpheRegistry(config.CompileConfig.RegistryHooks, registryConfig)
+	if rErr != nil {
+		return Snapshot{}, rErr
+	}
+
+	compileConfig := config.CompileConfig
+	compileConfig.MorpheLoadRegistryConfig = registryConfig
+	return CompileSnapshot(compileConfig, r)
+}
@@ -0,0 +1,43 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrNoMigrationsDirPath = errors.New("migrations directory path cannot be empty")
+var ErrNoMigrationDescription = errors.New("migration description cannot be empty")
+var ErrNoMigration = errors.New("no migration provided")
+
+func ErrInvalidMigrationFileName(fileName string) error {
+	return fmt.Errorf("invalid migration file name '%s'", fileName)
+}
+
+func ErrTableRenameAcrossSchemas(fromTableName string, toTableName string) error {
+	return fmt.Errorf("cannot rename table '%s' to '%s' in another schema", fromTableName, toTableName)
+}
+
+func ErrInvalidTableRename(fromTableName string, toTableName string) error {
+	return fmt.Errorf("cannot rename table '%s' to '%s', the snapshots do not replace the one with the other", fromTableName, toTableName)
+}
+
+func ErrInvalidColumnRename(tableName string, fromColumnName string, toColumnName string) error {
+	return fmt.Errorf("cannot rename column '%s' of table '%s' to '%s', the snapshots do not replace the one with the other",
+		fromColumnName, tableName, toColumnName)
+}
+
+func ErrPossibleTableRename(droppedTableNames []string, addedTableNames []string) error {
+	return fmt.Errorf("tables %s are dropped while tables %s are added, hint renamed tables in TableRenames or allow dropping their data with AllowDropAndAdd",
+		strings.Join(droppedTableNames, ", "), strings.Join(addedTableNames, ", "))
+}
+
+func ErrPossibleColumnRename(tableName string, droppedColumnNames []string, addedColumnNames []string) error {
+	return fmt.Errorf("columns %s of table '%s' are dropped while columns %s are added, hint renamed columns in ColumnRenames or allow dropping their data with AllowDropAndAdd",
+		strings.Join(droppedColumnNames, ", "), tableName, strings.Join(addedColumnNames, ", "))
+}
+
+func ErrNotNullColumnWithoutDefault(tableName string, columnName string) error {
+	return fmt.Errorf("column '%s' of table '%s' is added as NOT NULL without a default, provide a default or a ColumnBackfills expression for existing rows",
+		columnName, tableName)
+}
@@ -0,0 +1,136 @@
+package migrate_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kalo-build/go-util/assertfile"
+	rcfg "github.com/kalo-build/morphe-go/pkg/registry/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/internal/testutils"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+)
+
+type MigrateTestSuite struct {
+	assertfile.FileSuite
+
+	TestDirPath            string
+	TestGroundTruthDirPath string
+}
+
+func TestMigrateTestSuite(t *testing.T) {
+	suite.Run(t, new(MigrateTestSuite))
+}
+
+func (suite *MigrateTestSuite) SetupTest() {
+	suite.TestDirPath = testutils.GetTestDirPath()
+	suite.TestGroundTruthDirPath = filepath.Join(suite.TestDirPath, "ground-truth", "migrate-minimal")
+}
+
+func (suite *MigrateTestSuite) TearDownTest() {
+	suite.TestDirPath = ""
+}
+
+func (suite *MigrateTestSuite) getRegistryConfig(registryName string) rcfg.MorpheLoadRegistryConfig {
+	registryDirPath := filepath.Join(suite.TestDirPath, "registry", registryName)
+	return rcfg.MorpheLoadRegistryConfig{
+		RegistryEnumsDirPath:      filepath.Join(registryDirPath, "enums"),
+		RegistryStructuresDirPath: filepath.Join(registryDirPath, "structures"),
+		RegistryModelsDirPath:     filepath.Join(registryDirPath, "models"),
+		RegistryEntitiesDirPath:   filepath.Join(registryDirPath, "entities"),
+	}
+}
+
+func (suite *MigrateTestSuite) getMigrateConfig(migrationsDirPath string) migrate.MorpheMigrateConfig {
+	return migrate.MorpheMigrateConfig{
+		CompileConfig: compile.MorpheCompileConfig{
+			MorpheConfig: cfg.DefaultMorpheConfig(),
+		},
+		FromRegistryConfig: suite.getRegistryConfig("minimal"),
+		ToRegistryConfig:   suite.getRegistryConfig("minimal-v2"),
+		MigrationsDirPath:  migrationsDirPath,
+		Description:        "Minimal v2",
+	}
+}
+
+func (suite *MigrateTestSuite) TestMorpheToPSQLMigration() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	config := suite.getMigrateConfig(workingDirPath)
+
+	migration, migrationErr := migrate.MorpheToPSQLMigration(config)
+
+	suite.Nil(migrationErr)
+	suite.NotNil(migration)
+	suite.Equal(1, migration.Version)
+	suite.Equal("0001_minimal_v2", migration.GetFileBaseName())
+
+	suite.FileEquals(
+		filepath.Join(workingDirPath, "0001_minimal_v2.up.sql"),
+		filepath.Join(suite.TestGroundTruthDirPath, "0001_minimal_v2.up.sql"),
+	)
+	suite.FileEquals(
+		filepath.Join(workingDirPath, "0001_minimal_v2.down.sql"),
+		filepath.Join(suite.TestGroundTruthDirPath, "0001_minimal_v2.down.sql"),
+	)
+}
+
+func (suite *MigrateTestSuite) TestMorpheToPSQLMigration_NextVersion() {
+	workingDirPath := suite.TestDirPath + "/working"
+	suite.Nil(os.MkdirAll(workingDirPath, 0755))
+	defer os.RemoveAll(workingDirPath)
+
+	suite.Nil(os.WriteFile(filepath.Join(workingDirPath, "0007_initial.up.sql"), []byte(""), 0644))
+	suite.Nil(os.WriteFile(filepath.Join(workingDirPath, "0007_initial.down.sql"), []byte(""), 0644))
+	suite.Nil(os.WriteFile(filepath.Join(workingDirPath, "README.md"), []byte(""), 0644))
+
+	config := suite.getMigrateConfig(workingDirPath)
+
+	migration, migrationErr := migrate.MorpheToPSQLMigration(config)
+
+	suite.Nil(migrationErr)
+	suite.NotNil(migration)
+	suite.Equal(8, migration.Version)
+	suite.FileExists(filepath.Join(workingDirPath, "0008_minimal_v2.up.sql"))
+	suite.FileExists(filepath.Join(workingDirPath, "0008_minimal_v2.down.sql"))
+}
+
+func (suite *MigrateTestSuite) TestMorpheToPSQLMigration_NoChanges() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	config := suite.getMigrateConfig(workingDirPath)
+	config.ToRegistryConfig = config.FromRegistryConfig
+
+	migration, migrationErr := migrate.MorpheToPSQLMigration(config)
+
+	suite.Nil(migrationErr)
+	suite.NotNil(migration)
+	suite.True(migration.IsEmpty())
+	suite.NoDirExists(workingDirPath)
+}
+
+func (suite *MigrateTestSuite) TestMorpheToPSQLMigration_NoDescription() {
+	config := suite.getMigrateConfig(suite.TestDirPath + "/working")
+	config.Description = ""
+
+	migration, migrationErr := migrate.MorpheToPSQLMigration(config)
+
+	suite.ErrorIs(migrationErr, migrate.ErrNoMigrationDescription)
+	suite.Nil(migration)
+}
+
+func (suite *MigrateTestSuite) TestMorpheToPSQLMigration_TableRenameAcrossSchemas() {
+	config := suite.getMigrateConfig(suite.TestDirPath + "/working")
+	config.DiffOptions.TableRenames = map[string]string{"public.people": "archive.people"}
+
+	migration, migrationErr := migrate.MorpheToPSQLMigration(config)
+
+	suite.EqualError(migrationErr, migrate.ErrTableRenameAcrossSchemas("public.people", "archive.people").Error())
+	suite.Nil(migration)
+}
@@ -0,0 +1,42 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Migration is a versioned set of statements moving a database between two registry snapshots
+type Migration struct {
+	Version     int
+	Description string
+	Up          []string
+	Down        []string
+}
+
+// IsEmpty reports whether the migration contains no statements
+func (m Migration) IsEmpty() bool {
+	return len(m.Up) == 0 && len(m.Down) == 0
+}
+
+// GetFileBaseName returns the file name shared by the up and down migration files, e.g. "0002_add_nickname"
+func (m Migration) GetFileBaseName() string {
+	return fmt.Sprintf("%04d_%s", m.Version, getMigrationDescriptionSlug(m.Description))
+}
+
+func getMigrationDescriptionSlug(description string) string {
+	slug := strings.Builder{}
+	lastUnderscore := true
+	for _, char := range strings.ToLower(description) {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) {
+			slug.WriteRune(char)
+			lastUnderscore = false
+			continue
+		}
+		if !lastUnderscore {
+			slug.WriteRune('_')
+			lastUnderscore = true
+		}
+	}
+	return strings.TrimSuffix(slug.String(), "_")
+}
@@ -0,0 +1,53 @@
+package migrate
+
+import (
+	rcfg "github.com/kalo-build/morphe-go/pkg/registry/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+)
+
+type MorpheMigrateConfig struct {
+	// CompileConfig provides the compilation settings and hooks used for both registry snapshots,
+	// its registry paths and writers are ignored.
+	CompileConfig compile.MorpheCompileConfig
+
+	FromRegistryConfig rcfg.MorpheLoadRegistryConfig
+	ToRegistryConfig   rcfg.MorpheLoadRegistryConfig
+
+	// DiffOptions hints renamed tables and columns, backfills added NOT NULL columns and opts into dropping data
+	DiffOptions DiffOptions
+
+	MigrationsDirPath string
+	Description       string
+}
+
+func (config MorpheMigrateConfig) Validate() error {
+	fromRegistryErr := config.FromRegistryConfig.Validate()
+	if fromRegistryErr != nil {
+		return fromRegistryErr
+	}
+
+	toRegistryErr := config.ToRegistryConfig.Validate()
+	if toRegistryErr != nil {
+		return toRegistryErr
+	}
+
+	morpheCfgErr := config.CompileConfig.MorpheConfig.Validate()
+	if morpheCfgErr != nil {
+		return morpheCfgErr
+	}
+
+	diffOptionsErr := config.DiffOptions.Validate()
+	if diffOptionsErr != nil {
+		return diffOptionsErr
+	}
+
+	if config.MigrationsDirPath == "" {
+		return ErrNoMigrationsDirPath
+	}
+
+	if config.Description == "" {
+		return ErrNoMigrationDescription
+	}
+
+	return nil
+}
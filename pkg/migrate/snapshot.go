@@ -0,0 +1,69 @@
+package migrate
+
+import (
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// Snapshot holds every PostgreSQL definition compiled from a single Morphe registry
+type Snapshot struct {
+	Types  []psqldef.PSQLType
+	Tables []*psqldef.Table
+	Views  []*psqldef.View
+}
+
+// CompileSnapshot compiles all enums, models, structures and entities of the registry into a snapshot
+func CompileSnapshot(config compile.MorpheCompileConfig, r *registry.Registry) (Snapshot, error) {
+	if r == nil {
+		return Snapshot{}, compile.ErrNoRegistry
+	}
+
+	definitions, definitionsErr := compile.AllMorpheToPSQLDefinitions(config, r)
+	if definitionsErr != nil {
+		return Snapshot{}, definitionsErr
+	}
+
+	snapshot := Snapshot{}
+	for _, enumName := range core.MapKeysSorted(definitions.EnumTypes) {
+		snapshot.Types = append(snapshot.Types, definitions.EnumTypes[enumName])
+	}
+	for _, enumName := range core.MapKeysSorted(definitions.EnumTables) {
+		snapshot.Tables = append(snapshot.Tables, definitions.EnumTables[enumName])
+	}
+	for _, structureName := range core.MapKeysSorted(definitions.StructureTypes) {
+		snapshot.Types = append(snapshot.Types, definitions.StructureTypes[structureName])
+	}
+	for _, modelName := range core.MapKeysSorted(definitions.ModelTables) {
+		snapshot.Tables = append(snapshot.Tables, definitions.ModelTables[modelName]...)
+	}
+	for _, structureName := range core.MapKeysSorted(definitions.StructureTables) {
+		snapshot.Tables = append(snapshot.Tables, definitions.StructureTables[structureName])
+	}
+	if definitions.StructureTable != nil {
+		snapshot.Tables = append(snapshot.Tables, definitions.StructureTable)
+	}
+
+	// Custom types referenced by the tables but not compiled from the registry, e.g. domains set by hooks
+	snapshotTypeNames := map[string]bool{}
+	for _, snapshotType := range snapshot.Types {
+		snapshotTypeNames[snapshotType.GetSyntax()] = true
+	}
+	allCustomTypes, customTypesErr := compile.GetCustomTypesForTables(snapshot.Tables)
+	if customTypesErr != nil {
+		return Snapshot{}, customTypesErr
+	}
+	for _, customType := range allCustomTypes {
+		if snapshotTypeNames[customType.GetSyntax()] {
+			continue
+		}
+		snapshot.Types = append(snapshot.Types, customType)
+	}
+
+	for _, entityName := range core.MapKeysSorted(definitions.EntityViews) {
+		snapshot.Views = append(snapshot.Views, definitions.EntityViews[entityName])
+	}
+
+	return snapshot, nil
+}
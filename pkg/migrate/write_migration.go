@@ -0,0 +1,86 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var migrationFileNamePattern = regexp.MustCompile(`^(\d+)_.*\.(up|down)\.sql$`)
+
+// GetNextMigrationVersion returns the version following the highest migration file version found in the directory
+func GetNextMigrationVersion(dirPath string) (int, error) {
+	dirEntries, readErr := os.ReadDir(dirPath)
+	if readErr != nil {
+		if os.IsNotExist(readErr) {
+			return 1, nil
+		}
+		return 0, readErr
+	}
+
+	latestVersion := 0
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+		matches := migrationFileNamePattern.FindStringSubmatch(dirEntry.Name())
+		if matches == nil {
+			continue
+		}
+		version, versionErr := strconv.Atoi(matches[1])
+		if versionErr != nil {
+			return 0, ErrInvalidMigrationFileName(dirEntry.Name())
+		}
+		if version > latestVersion {
+			latestVersion = version
+		}
+	}
+
+	return latestVersion + 1, nil
+}
+
+// WriteMigrationFiles writes the up and down files of the migration into the directory
+func WriteMigrationFiles(dirPath string, migration *Migration) ([]byte, []byte, error) {
+	if migration == nil {
+		return nil, nil, ErrNoMigration
+	}
+
+	mkDirErr := os.MkdirAll(dirPath, 0755)
+	if mkDirErr != nil {
+		return nil, nil, mkDirErr
+	}
+
+	fileBaseName := migration.GetFileBaseName()
+
+	upContents := getMigrationFileContents(migration, "up", migration.Up)
+	upFilePath := filepath.Join(dirPath, fileBaseName+".up.sql")
+	upWriteErr := os.WriteFile(upFilePath, upContents, 0644)
+	if upWriteErr != nil {
+		return nil, nil, upWriteErr
+	}
+
+	downContents := getMigrationFileContents(migration, "down", migration.Down)
+	downFilePath := filepath.Join(dirPath, fileBaseName+".down.sql")
+	downWriteErr := os.WriteFile(downFilePath, downContents, 0644)
+	if downWriteErr != nil {
+		return nil, nil, downWriteErr
+	}
+
+	return upContents, downContents, nil
+}
+
+func getMigrationFileContents(migration *Migration, direction string, statements []string) []byte {
+	allLines := []string{
+		fmt.Sprintf("-- Migration %04d (%s): %s", migration.Version, direction, migration.Description),
+		"",
+	}
+
+	for _, statement := range statements {
+		allLines = append(allLines, statement, "")
+	}
+
+	return []byte(strings.Join(allLines, "\n"))
+}
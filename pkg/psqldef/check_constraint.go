@@ -0,0 +1,21 @@
+package psqldef
+
+// CheckConstraint represents a check constraint in a PSQL table
+type CheckConstraint struct {
+	Schema     string
+	Name       string
+	TableName  string
+	Expression string
+}
+
+// DeepClone creates a deep copy of the CheckConstraint
+func (c CheckConstraint) DeepClone() CheckConstraint {
+	constraintCopy := CheckConstraint{
+		Schema:     c.Schema,
+		Name:       c.Name,
+		TableName:  c.TableName,
+		Expression: c.Expression,
+	}
+
+	return constraintCopy
+}
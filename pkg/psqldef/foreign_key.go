@@ -8,10 +8,14 @@ type ForeignKey struct {
 	Name           string
 	TableName      string
 	ColumnNames    []string
+	RefSchema      string
 	RefTableName   string
 	RefColumnNames []string
 	OnDelete       string // e.g., "CASCADE", "SET NULL"
 	OnUpdate       string // e.g., "CASCADE", "SET NULL"
+
+	Deferrable        bool
+	InitiallyDeferred bool
 }
 
 // DeepClone creates a deep copy of the ForeignKey
@@ -21,10 +25,14 @@ func (fk ForeignKey) DeepClone() ForeignKey {
 		Name:           fk.Name,
 		TableName:      fk.TableName,
 		ColumnNames:    clone.Slice(fk.ColumnNames),
+		RefSchema:      fk.RefSchema,
 		RefTableName:   fk.RefTableName,
 		RefColumnNames: clone.Slice(fk.RefColumnNames),
 		OnDelete:       fk.OnDelete,
 		OnUpdate:       fk.OnUpdate,
+
+		Deferrable:        fk.Deferrable,
+		InitiallyDeferred: fk.InitiallyDeferred,
 	}
 
 	return foreignKeyCopy
@@ -10,7 +10,9 @@ type Table struct {
 	Indices           []Index
 	ForeignKeys       []ForeignKey
 	UniqueConstraints []UniqueConstraint
+	CheckConstraints  []CheckConstraint
 	SeedData          []InsertStatement
+	Triggers          []Trigger
 }
 
 // DeepClone creates a deep copy of the Table
@@ -22,7 +24,9 @@ func (t Table) DeepClone() Table {
 		Indices:           clone.DeepCloneSlice(t.Indices),
 		ForeignKeys:       clone.DeepCloneSlice(t.ForeignKeys),
 		UniqueConstraints: clone.DeepCloneSlice(t.UniqueConstraints),
+		CheckConstraints:  clone.DeepCloneSlice(t.CheckConstraints),
 		SeedData:          clone.DeepCloneSlice(t.SeedData),
+		Triggers:          clone.DeepCloneSlice(t.Triggers),
 	}
 
 	return tableCopy
@@ -0,0 +1,29 @@
+package psqldef
+
+import "github.com/kalo-build/clone"
+
+// Trigger represents a PL/pgSQL trigger function and the trigger executing it on a PSQL table
+type Trigger struct {
+	Schema       string
+	Name         string
+	TableName    string
+	Timing       string   // e.g., "BEFORE", "AFTER"
+	Events       []string // e.g., "INSERT", "UPDATE"
+	FunctionName string
+	FunctionBody []string // PL/pgSQL statements between BEGIN and END
+}
+
+// DeepClone creates a deep copy of the Trigger
+func (t Trigger) DeepClone() Trigger {
+	triggerCopy := Trigger{
+		Schema:       t.Schema,
+		Name:         t.Name,
+		TableName:    t.TableName,
+		Timing:       t.Timing,
+		Events:       clone.Slice(t.Events),
+		FunctionName: t.FunctionName,
+		FunctionBody: clone.Slice(t.FunctionBody),
+	}
+
+	return triggerCopy
+}
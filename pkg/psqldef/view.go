@@ -10,18 +10,40 @@ type View struct {
 	FromTable   string
 	Joins       []JoinClause
 	WhereClause string
+
+	// Materialized views are created WITH DATA unless WithNoData is set
+	Materialized bool
+	WithNoData   bool
+
+	// Indices and RefreshFunctionName only apply to materialized views
+	Indices             []Index
+	RefreshFunctionName string
 }
 
 // DeepClone creates a deep copy of the View
 func (v View) DeepClone() View {
 	viewCopy := View{
-		Schema:      v.Schema,
-		Name:        v.Name,
-		Columns:     clone.DeepCloneSlice(v.Columns),
-		FromTable:   v.FromTable,
-		Joins:       clone.DeepCloneSlice(v.Joins),
-		WhereClause: v.WhereClause,
+		Schema:              v.Schema,
+		Name:                v.Name,
+		Columns:             clone.DeepCloneSlice(v.Columns),
+		FromTable:           v.FromTable,
+		Joins:               clone.DeepCloneSlice(v.Joins),
+		WhereClause:         v.WhereClause,
+		Materialized:        v.Materialized,
+		WithNoData:          v.WithNoData,
+		Indices:             clone.DeepCloneSlice(v.Indices),
+		RefreshFunctionName: v.RefreshFunctionName,
 	}
 
 	return viewCopy
 }
+
+// HasUniqueIndex returns true if the view has a unique index, as required by REFRESH MATERIALIZED VIEW CONCURRENTLY
+func (v View) HasUniqueIndex() bool {
+	for _, index := range v.Indices {
+		if index.IsUnique {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package reverse
+
+type MorpheReverseConfig struct {
+	// SQLFilePath is the DDL to reverse-engineer, e.g. the output of pg_dump --schema-only
+	SQLFilePath string
+	// OutputDirPath receives the models/ and enums/ registry directories
+	OutputDirPath string
+}
+
+func (config MorpheReverseConfig) Validate() error {
+	if config.SQLFilePath == "" {
+		return ErrNoSQLFilePath
+	}
+
+	if config.OutputDirPath == "" {
+		return ErrNoOutputDirPath
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package reverse
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// NormalizeTable returns a copy of the table reduced to what its DDL expresses, so a written and parsed back
+// table equals its definition.
+//
+// Column types are compared by syntax, identifiers are unquoted, referential actions and index methods take their
+// canonical spelling and the schema and table names of the table's own objects are taken from the table. Constraint
+// names PostgreSQL gives unnamed constraints, as ParsePSQL reads them, are cleared. Seed rows are merged per column
+// list with their numbers widened to int64 or float64.
+func NormalizeTable(table psqldef.Table) psqldef.Table {
+	normalizedTable := table.DeepClone()
+
+	for columnIdx, column := range normalizedTable.Columns {
+		normalizedTable.Columns[columnIdx].Name = unquoteIdentifier(column.Name)
+		normalizedTable.Columns[columnIdx].Type = psqldef.PSQLTypePrimitive{
+			Syntax: column.Type.GetSyntax(),
+		}
+	}
+	normalizedTable.Indices = normalizeIndices(normalizedTable.Indices, table.Name)
+
+	for foreignKeyIdx, foreignKey := range normalizedTable.ForeignKeys {
+		foreignKey.Schema = ""
+		foreignKey.TableName = table.Name
+		foreignKey.ColumnNames = unquoteIdentifiers(foreignKey.ColumnNames)
+		foreignKey.RefColumnNames = unquoteIdentifiers(foreignKey.RefColumnNames)
+		foreignKey.OnDelete = normalizeReferentialAction(foreignKey.OnDelete)
+		foreignKey.OnUpdate = normalizeReferentialAction(foreignKey.OnUpdate)
+		if foreignKey.Name == getImplicitForeignKeyName(table.Name, foreignKey.ColumnNames) {
+			foreignKey.Name = ""
+		}
+		normalizedTable.ForeignKeys[foreignKeyIdx] = foreignKey
+	}
+	normalizedTable.ForeignKeys = nilIfEmpty(normalizedTable.ForeignKeys)
+
+	for constraintIdx, uniqueConstraint := range normalizedTable.UniqueConstraints {
+		uniqueConstraint.Schema = ""
+		uniqueConstraint.TableName = table.Name
+		uniqueConstraint.ColumnNames = unquoteIdentifiers(uniqueConstraint.ColumnNames)
+		if uniqueConstraint.Name == getImplicitUniqueConstraintName(table.Name, uniqueConstraint.ColumnNames) {
+			uniqueConstraint.Name = ""
+		}
+		normalizedTable.UniqueConstraints[constraintIdx] = uniqueConstraint
+	}
+	normalizedTable.UniqueConstraints = nilIfEmpty(normalizedTable.UniqueConstraints)
+
+	for constraintIdx, checkConstraint := range normalizedTable.CheckConstraints {
+		checkConstraint.Schema = ""
+		checkConstraint.TableName = table.Name
+		if checkConstraint.Name == getImplicitCheckConstraintName(table.Name) {
+			checkConstraint.Name = ""
+		}
+		normalizedTable.CheckConstraints[constraintIdx] = checkConstraint
+	}
+	normalizedTable.CheckConstraints = nilIfEmpty(normalizedTable.CheckConstraints)
+
+	for triggerIdx, trigger := range normalizedTable.Triggers {
+		trigger.TableName = table.Name
+		trigger.Timing = strings.ToUpper(trigger.Timing)
+		for eventIdx, event := range trigger.Events {
+			trigger.Events[eventIdx] = strings.ToUpper(event)
+		}
+		normalizedTable.Triggers[triggerIdx] = trigger
+	}
+	normalizedTable.Triggers = nilIfEmpty(normalizedTable.Triggers)
+
+	normalizedTable.SeedData = normalizeSeedData(normalizedTable.SeedData, table.Name)
+	return normalizedTable
+}
+
+// NormalizeView returns a copy of the view reduced to what its DDL expresses, so a written and parsed back view
+// equals its definition
+func NormalizeView(view psqldef.View) psqldef.View {
+	normalizedView := view.DeepClone()
+
+	for columnIdx := range normalizedView.Columns {
+		// Aliased columns are written as "<source> AS <name>", which the name already expresses
+		normalizedView.Columns[columnIdx].Alias = ""
+	}
+
+	for joinIdx, join := range normalizedView.Joins {
+		join.Type = strings.TrimSuffix(strings.ToUpper(join.Type), " OUTER")
+		if join.Type == "" {
+			join.Type = "INNER"
+		}
+		if join.Alias == join.Table {
+			join.Alias = ""
+		}
+		join.Conditions = nilIfEmpty(join.Conditions)
+		normalizedView.Joins[joinIdx] = join
+	}
+	normalizedView.Joins = nilIfEmpty(normalizedView.Joins)
+
+	normalizedView.Indices = normalizeIndices(normalizedView.Indices, view.Name)
+	if !normalizedView.Materialized {
+		normalizedView.WithNoData = false
+		normalizedView.Indices = nil
+		normalizedView.RefreshFunctionName = ""
+	}
+	return normalizedView
+}
+
+func normalizeIndices(indices []psqldef.Index, relationName string) []psqldef.Index {
+	for indexIdx, index := range indices {
+		index.Schema = ""
+		index.TableName = relationName
+		index.Columns = unquoteIdentifiers(index.Columns)
+		index.Using = strings.ToLower(index.Using)
+		if index.Using == "btree" {
+			index.Using = ""
+		}
+		indices[indexIdx] = index
+	}
+	return nilIfEmpty(indices)
+}
+
+// normalizeReferentialAction returns the spelling of a referential action, where NO ACTION is the default
+func normalizeReferentialAction(action string) string {
+	action = strings.ToUpper(action)
+	if action == "NO ACTION" {
+		return ""
+	}
+	return action
+}
+
+// normalizeSeedData merges consecutive inserts into the same columns, which are written one row per statement
+func normalizeSeedData(seedData []psqldef.InsertStatement, tableName string) []psqldef.InsertStatement {
+	normalizedSeedData := []psqldef.InsertStatement{}
+	for _, insert := range seedData {
+		columnNames := unquoteIdentifiers(insert.Columns)
+		rows := [][]any{}
+		for _, row := range insert.Values {
+			normalizedRow := []any{}
+			for _, value := range row {
+				normalizedRow = append(normalizedRow, normalizeSeedValue(value))
+			}
+			rows = append(rows, normalizedRow)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		seedCount := len(normalizedSeedData)
+		if seedCount > 0 && normalizedSeedData[seedCount-1].Schema == insert.Schema &&
+			slices.Equal(normalizedSeedData[seedCount-1].Columns, columnNames) {
+			normalizedSeedData[seedCount-1].Values = append(normalizedSeedData[seedCount-1].Values, rows...)
+			continue
+		}
+		normalizedSeedData = append(normalizedSeedData, psqldef.InsertStatement{
+			Schema:    insert.Schema,
+			TableName: tableName,
+			Columns:   columnNames,
+			Values:    rows,
+		})
+	}
+	return nilIfEmpty(normalizedSeedData)
+}
+
+// normalizeSeedValue widens numbers to int64, or float64 for fractions, as parsed from their SQL constants
+func normalizeSeedValue(value any) any {
+	switch typedValue := value.(type) {
+	case int:
+		return int64(typedValue)
+	case int8:
+		return int64(typedValue)
+	case int16:
+		return int64(typedValue)
+	case int32:
+		return int64(typedValue)
+	case uint:
+		return int64(typedValue)
+	case uint8:
+		return int64(typedValue)
+	case uint16:
+		return int64(typedValue)
+	case uint32:
+		return int64(typedValue)
+	case uint64:
+		return int64(typedValue)
+	case float32:
+		return normalizeSeedValue(float64(typedValue))
+	case float64:
+		if typedValue == math.Trunc(typedValue) && math.Abs(typedValue) < math.MaxInt64 {
+			return int64(typedValue)
+		}
+	}
+	return value
+}
+
+func unquoteIdentifiers(identifiers []string) []string {
+	unquotedIdentifiers := []string{}
+	for _, identifier := range identifiers {
+		unquotedIdentifiers = append(unquotedIdentifiers, unquoteIdentifier(identifier))
+	}
+	return nilIfEmpty(unquotedIdentifiers)
+}
+
+func nilIfEmpty[T any](values []T) []T {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
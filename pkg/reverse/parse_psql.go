@@ -0,0 +1,827 @@
+package reverse
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// PSQLDefinitions holds the definitions parsed from PostgreSQL DDL
+type PSQLDefinitions struct {
+	Tables    []*psqldef.Table
+	Views     []*psqldef.View
+	EnumTypes []psqldef.PSQLTypeEnum
+
+	// functionBodies holds the bodies of parsed functions by qualified name, for the triggers executing them
+	functionBodies map[string]string
+}
+
+// columnConstraintKeywords end a column type or default expression
+var columnConstraintKeywords = []string{
+	"constraint", "not", "null", "default", "primary", "unique", "references", "check", "generated", "collate",
+}
+
+// viewClauseKeywords end a join condition or where clause of a view query
+var viewClauseKeywords = []string{
+	"join", "left", "right", "full", "inner", "cross", "where", "group", "order", "limit", "with",
+}
+
+// ParsePSQLFile parses the DDL in a SQL file, e.g. the output of pg_dump --schema-only
+func ParsePSQLFile(sqlFilePath string) (*PSQLDefinitions, error) {
+	sqlBytes, readErr := os.ReadFile(sqlFilePath)
+	if readErr != nil {
+		return nil, readErr
+	}
+	return ParsePSQL(string(sqlBytes))
+}
+
+// ParsePSQL parses tables, views and enum types from PostgreSQL DDL.
+//
+// Constraints, indices, sequence defaults and identity columns added by later ALTER TABLE statements are applied
+// to their tables, so pg_dump output parses to the same definitions as inline DDL. Seed rows are read from INSERT
+// statements and COPY ... FROM stdin blocks. Triggers are read together with the body of their function, and
+// functions refreshing a materialized view become the view's refresh function. Statements that do not define
+// tables, views or enum types are ignored.
+func ParsePSQL(sql string) (*PSQLDefinitions, error) {
+	statements, splitErr := splitSQLStatements(sql)
+	if splitErr != nil {
+		return nil, splitErr
+	}
+
+	definitions := &PSQLDefinitions{}
+	for _, statement := range statements {
+		statementErr := definitions.parseStatement(statement)
+		if statementErr != nil {
+			return nil, statementErr
+		}
+	}
+	return definitions, nil
+}
+
+func (d *PSQLDefinitions) parseStatement(statement sqlStatement) error {
+	p := newSQLParser(statement)
+	switch {
+	case p.acceptKeyword("create"):
+		p.acceptKeyword("or", "replace")
+		p.acceptKeyword("unlogged")
+		switch {
+		case p.acceptKeyword("table"):
+			return d.parseCreateTable(p)
+		case p.isKeyword("unique") || p.isKeyword("index"):
+			return d.parseCreateIndex(p)
+		case p.acceptKeyword("materialized", "view"):
+			return d.parseCreateView(p, true)
+		case p.acceptKeyword("view"):
+			return d.parseCreateView(p, false)
+		case p.acceptKeyword("type"):
+			return d.parseCreateType(p)
+		case p.acceptKeyword("function"):
+			return d.parseCreateFunction(p)
+		case p.acceptKeyword("trigger"):
+			return d.parseCreateTrigger(p)
+		}
+	case p.acceptKeyword("alter", "table"):
+		return d.parseAlterTable(p)
+	case p.acceptKeyword("insert", "into"):
+		return d.parseInsert(p)
+	case p.acceptKeyword("copy"):
+		return d.parseCopy(p)
+	}
+	return nil
+}
+
+func (d *PSQLDefinitions) parseCreateTable(p *sqlParser) error {
+	p.acceptKeyword("if", "not", "exists")
+	schema, tableName, nameErr := p.parseQualifiedName()
+	if nameErr != nil {
+		return nameErr
+	}
+	table := &psqldef.Table{
+		Schema: schema,
+		Name:   tableName,
+	}
+
+	openErr := p.expectSymbol("(")
+	if openErr != nil {
+		return openErr
+	}
+	for !p.acceptSymbol(")") {
+		var definitionErr error
+		if isTableConstraintStart(p) {
+			definitionErr = d.parseTableConstraint(p, table)
+		} else {
+			definitionErr = d.parseColumnDefinition(p, table)
+		}
+		if definitionErr != nil {
+			return definitionErr
+		}
+		if !p.isSymbol(")") {
+			commaErr := p.expectSymbol(",")
+			if commaErr != nil {
+				return commaErr
+			}
+		}
+	}
+
+	d.Tables = append(d.Tables, table)
+	return nil
+}
+
+func isTableConstraintStart(p *sqlParser) bool {
+	return p.isKeyword("constraint") ||
+		p.isKeyword("primary", "key") ||
+		p.isKeyword("unique") ||
+		p.isKeyword("foreign", "key") ||
+		p.isKeyword("check") ||
+		p.isKeyword("exclude")
+}
+
+func (d *PSQLDefinitions) parseColumnDefinition(p *sqlParser, table *psqldef.Table) error {
+	columnName, nameErr := p.parseIdentifier()
+	if nameErr != nil {
+		return nameErr
+	}
+	typeStart, typeEnd := p.skipExpression(columnConstraintKeywords...)
+	if typeStart == typeEnd {
+		return p.errUnexpected("column type")
+	}
+	column := psqldef.TableColumn{
+		Name: columnName,
+		Type: getPSQLTypeFromSyntax(p.statement.getRawText(typeStart, typeEnd), d.EnumTypes),
+	}
+
+	for !p.isDone() && !p.isSymbol(",") && !p.isSymbol(")") {
+		constraintName := ""
+		if p.acceptKeyword("constraint") {
+			var constraintNameErr error
+			constraintName, constraintNameErr = p.parseIdentifier()
+			if constraintNameErr != nil {
+				return constraintNameErr
+			}
+		}
+
+		var constraintErr error
+		switch {
+		case p.acceptKeyword("not", "null"):
+			column.NotNull = true
+		case p.acceptKeyword("null"):
+			column.NotNull = false
+		case p.acceptKeyword("default"):
+			column.Default = p.parseExpression(columnConstraintKeywords...)
+			applySequenceDefault(&column)
+		case p.acceptKeyword("primary", "key"):
+			column.PrimaryKey = true
+		case p.acceptKeyword("unique"):
+			table.UniqueConstraints = append(table.UniqueConstraints, getUniqueConstraint(table, constraintName, []string{columnName}))
+		case p.acceptKeyword("references"):
+			constraintErr = parseForeignKeyReference(p, table, constraintName, []string{columnName})
+		case p.acceptKeyword("check"):
+			constraintErr = parseCheckConstraint(p, table, constraintName)
+		case p.acceptKeyword("generated"):
+			constraintErr = parseGeneratedColumn(p, &column)
+		case p.acceptKeyword("collate"):
+			_, _, constraintErr = p.parseQualifiedName()
+		default:
+			p.skipExpression()
+		}
+		if constraintErr != nil {
+			return constraintErr
+		}
+	}
+
+	table.Columns = append(table.Columns, column)
+	return nil
+}
+
+// parseGeneratedColumn parses GENERATED ... AS IDENTITY, stored as a SERIAL column, and GENERATED ... AS (...) STORED
+func parseGeneratedColumn(p *sqlParser, column *psqldef.TableColumn) error {
+	if !p.acceptKeyword("always") {
+		p.acceptKeyword("by", "default")
+	}
+	asErr := p.expectKeyword("as")
+	if asErr != nil {
+		return asErr
+	}
+	if p.acceptKeyword("identity") {
+		if serialType, isSerial := getSerialType(column.Type); isSerial {
+			column.Type = serialType
+		}
+		return p.skipParenthesized()
+	}
+	_, expressionErr := p.parseParenthesizedExpression()
+	if expressionErr != nil {
+		return expressionErr
+	}
+	p.acceptKeyword("stored")
+	return nil
+}
+
+// applySequenceDefault turns integer columns defaulting to the next sequence value into SERIAL columns
+func applySequenceDefault(column *psqldef.TableColumn) {
+	if !strings.HasPrefix(strings.ToLower(column.Default), "nextval(") {
+		return
+	}
+	serialType, isSerial := getSerialType(column.Type)
+	if !isSerial {
+		return
+	}
+	column.Type = serialType
+	column.Default = ""
+}
+
+func (d *PSQLDefinitions) parseTableConstraint(p *sqlParser, table *psqldef.Table) error {
+	constraintName := ""
+	if p.acceptKeyword("constraint") {
+		var nameErr error
+		constraintName, nameErr = p.parseIdentifier()
+		if nameErr != nil {
+			return nameErr
+		}
+	}
+
+	switch {
+	case p.acceptKeyword("primary", "key"):
+		columnNames, columnsErr := p.parseIdentifierList()
+		if columnsErr != nil {
+			return columnsErr
+		}
+		for _, columnName := range columnNames {
+			column, columnErr := getTableColumn(table, columnName)
+			if columnErr != nil {
+				return columnErr
+			}
+			column.PrimaryKey = true
+		}
+	case p.acceptKeyword("unique"):
+		p.acceptKeyword("nulls", "not", "distinct")
+		columnNames, columnsErr := p.parseIdentifierList()
+		if columnsErr != nil {
+			return columnsErr
+		}
+		table.UniqueConstraints = append(table.UniqueConstraints, getUniqueConstraint(table, constraintName, columnNames))
+	case p.acceptKeyword("foreign", "key"):
+		columnNames, columnsErr := p.parseIdentifierList()
+		if columnsErr != nil {
+			return columnsErr
+		}
+		referencesErr := p.expectKeyword("references")
+		if referencesErr != nil {
+			return referencesErr
+		}
+		foreignKeyErr := parseForeignKeyReference(p, table, constraintName, columnNames)
+		if foreignKeyErr != nil {
+			return foreignKeyErr
+		}
+	case p.acceptKeyword("check"):
+		checkErr := parseCheckConstraint(p, table, constraintName)
+		if checkErr != nil {
+			return checkErr
+		}
+	}
+
+	// Skip trailing options such as NOT VALID and unsupported constraints such as EXCLUDE
+	p.skipExpression()
+	return nil
+}
+
+func getUniqueConstraint(table *psqldef.Table, constraintName string, columnNames []string) psqldef.UniqueConstraint {
+	if constraintName == "" {
+		constraintName = getImplicitUniqueConstraintName(table.Name, columnNames)
+	}
+	return psqldef.UniqueConstraint{
+		Schema:      table.Schema,
+		Name:        constraintName,
+		TableName:   table.Name,
+		ColumnNames: columnNames,
+	}
+}
+
+// parseForeignKeyReference parses the REFERENCES clause of a foreign key, following the REFERENCES keyword
+func parseForeignKeyReference(p *sqlParser, table *psqldef.Table, constraintName string, columnNames []string) error {
+	refSchema, refTableName, refNameErr := p.parseQualifiedName()
+	if refNameErr != nil {
+		return refNameErr
+	}
+	var refColumnNames []string
+	if p.isSymbol("(") {
+		var refColumnsErr error
+		refColumnNames, refColumnsErr = p.parseIdentifierList()
+		if refColumnsErr != nil {
+			return refColumnsErr
+		}
+	}
+	if constraintName == "" {
+		constraintName = getImplicitForeignKeyName(table.Name, columnNames)
+	}
+
+	foreignKey := psqldef.ForeignKey{
+		Schema:         table.Schema,
+		Name:           constraintName,
+		TableName:      table.Name,
+		ColumnNames:    columnNames,
+		RefSchema:      refSchema,
+		RefTableName:   refTableName,
+		RefColumnNames: refColumnNames,
+	}
+	for {
+		var actionErr error
+		switch {
+		case p.acceptKeyword("match"):
+			_, actionErr = p.parseIdentifier()
+		case p.acceptKeyword("on", "delete"):
+			foreignKey.OnDelete, actionErr = parseReferentialAction(p)
+		case p.acceptKeyword("on", "update"):
+			foreignKey.OnUpdate, actionErr = parseReferentialAction(p)
+		case p.acceptKeyword("not", "deferrable"):
+			foreignKey.Deferrable = false
+		case p.acceptKeyword("deferrable"):
+			foreignKey.Deferrable = true
+		case p.acceptKeyword("initially", "deferred"):
+			foreignKey.InitiallyDeferred = true
+		case p.acceptKeyword("initially", "immediate"):
+			foreignKey.InitiallyDeferred = false
+		default:
+			table.ForeignKeys = append(table.ForeignKeys, foreignKey)
+			return nil
+		}
+		if actionErr != nil {
+			return actionErr
+		}
+	}
+}
+
+func parseReferentialAction(p *sqlParser) (string, error) {
+	for _, action := range [][]string{{"cascade"}, {"restrict"}, {"no", "action"}, {"set", "null"}, {"set", "default"}} {
+		if p.acceptKeyword(action...) {
+			// PostgreSQL 15 allows limiting SET NULL and SET DEFAULT to some of the key columns
+			return strings.ToUpper(strings.Join(action, " ")), p.skipParenthesized()
+		}
+	}
+	return "", p.errUnexpected("referential action")
+}
+
+// parseCheckConstraint parses a CHECK constraint expression, following the CHECK keyword
+func parseCheckConstraint(p *sqlParser, table *psqldef.Table, constraintName string) error {
+	expression, expressionErr := p.parseParenthesizedExpression()
+	if expressionErr != nil {
+		return expressionErr
+	}
+	if constraintName == "" {
+		constraintName = getImplicitCheckConstraintName(table.Name)
+	}
+	table.CheckConstraints = append(table.CheckConstraints, psqldef.CheckConstraint{
+		Schema:     table.Schema,
+		Name:       constraintName,
+		TableName:  table.Name,
+		Expression: expression,
+	})
+	return nil
+}
+
+// getImplicitUniqueConstraintName returns the name PostgreSQL gives an unnamed unique constraint
+func getImplicitUniqueConstraintName(tableName string, columnNames []string) string {
+	return tableName + "_" + strings.Join(columnNames, "_") + "_key"
+}
+
+// getImplicitForeignKeyName returns the name PostgreSQL gives an unnamed foreign key
+func getImplicitForeignKeyName(tableName string, columnNames []string) string {
+	return tableName + "_" + strings.Join(columnNames, "_") + "_fkey"
+}
+
+// getImplicitCheckConstraintName returns the name PostgreSQL gives an unnamed table check constraint
+func getImplicitCheckConstraintName(tableName string) string {
+	return tableName + "_check"
+}
+
+func (d *PSQLDefinitions) parseAlterTable(p *sqlParser) error {
+	p.acceptKeyword("if", "exists")
+	p.acceptKeyword("only")
+	schema, tableName, nameErr := p.parseQualifiedName()
+	if nameErr != nil {
+		return nameErr
+	}
+
+	for {
+		actionErr := d.parseAlterTableAction(p, schema, tableName)
+		if actionErr != nil {
+			return actionErr
+		}
+		if !p.acceptSymbol(",") {
+			return nil
+		}
+	}
+}
+
+// parseAlterTableAction applies an added constraint or column, or a column default, nullability or identity
+// change. Other actions, e.g. OWNER TO, are skipped, so their table does not have to be known.
+func (d *PSQLDefinitions) parseAlterTableAction(p *sqlParser, schema string, tableName string) error {
+	switch {
+	case p.acceptKeyword("add"):
+		table, tableErr := d.getTable(schema, tableName)
+		if tableErr != nil {
+			return tableErr
+		}
+		if isTableConstraintStart(p) {
+			return d.parseTableConstraint(p, table)
+		}
+		p.acceptKeyword("column")
+		p.acceptKeyword("if", "not", "exists")
+		return d.parseColumnDefinition(p, table)
+	case p.acceptKeyword("alter"):
+		p.acceptKeyword("column")
+		columnName, columnNameErr := p.parseIdentifier()
+		if columnNameErr != nil {
+			return columnNameErr
+		}
+		table, tableErr := d.getTable(schema, tableName)
+		if tableErr != nil {
+			return tableErr
+		}
+		column, columnErr := getTableColumn(table, columnName)
+		if columnErr != nil {
+			return columnErr
+		}
+		switch {
+		case p.acceptKeyword("set", "default"):
+			column.Default = p.parseExpression()
+			applySequenceDefault(column)
+		case p.acceptKeyword("drop", "default"):
+			column.Default = ""
+		case p.acceptKeyword("set", "not", "null"):
+			column.NotNull = true
+		case p.acceptKeyword("drop", "not", "null"):
+			column.NotNull = false
+		case p.acceptKeyword("add", "generated"):
+			generatedErr := parseGeneratedColumn(p, column)
+			if generatedErr != nil {
+				return generatedErr
+			}
+		}
+	}
+
+	p.skipExpression()
+	return nil
+}
+
+func (d *PSQLDefinitions) parseCreateIndex(p *sqlParser) error {
+	isUnique := p.acceptKeyword("unique")
+	indexErr := p.expectKeyword("index")
+	if indexErr != nil {
+		return indexErr
+	}
+	p.acceptKeyword("concurrently")
+	p.acceptKeyword("if", "not", "exists")
+
+	indexName := ""
+	if !p.isKeyword("on") {
+		var indexNameErr error
+		indexName, indexNameErr = p.parseIdentifier()
+		if indexNameErr != nil {
+			return indexNameErr
+		}
+	}
+	onErr := p.expectKeyword("on")
+	if onErr != nil {
+		return onErr
+	}
+	p.acceptKeyword("only")
+	schema, tableName, tableNameErr := p.parseQualifiedName()
+	if tableNameErr != nil {
+		return tableNameErr
+	}
+
+	using := ""
+	if p.acceptKeyword("using") {
+		var usingErr error
+		using, usingErr = p.parseIdentifier()
+		if usingErr != nil {
+			return usingErr
+		}
+	}
+
+	openErr := p.expectSymbol("(")
+	if openErr != nil {
+		return openErr
+	}
+	columnNames := []string{}
+	for {
+		columnStart, columnEnd := p.skipExpression()
+		if columnStart == columnEnd {
+			return p.errUnexpected("index column")
+		}
+		columnNames = append(columnNames, getIndexColumnName(p.statement, columnStart, columnEnd))
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	closeErr := p.expectSymbol(")")
+	if closeErr != nil {
+		return closeErr
+	}
+
+	index := psqldef.Index{
+		Schema:    schema,
+		Name:      indexName,
+		TableName: tableName,
+		Columns:   columnNames,
+		IsUnique:  isUnique,
+		Using:     using,
+	}
+	if view := d.findView(schema, tableName); view != nil {
+		view.Indices = append(view.Indices, index)
+		return nil
+	}
+	table, tableErr := d.getTable(schema, tableName)
+	if tableErr != nil {
+		return tableErr
+	}
+	table.Indices = append(table.Indices, index)
+	return nil
+}
+
+// getIndexColumnName returns the column of a plain index element, ignoring its sort order, or the raw text of
+// expression elements
+func getIndexColumnName(statement sqlStatement, start int, end int) string {
+	firstToken := statement.Tokens[start]
+	isColumn := firstToken.Kind == sqlTokenWord || firstToken.Kind == sqlTokenQuotedIdentifier
+	for _, token := range statement.Tokens[start+1 : end] {
+		if !slices.ContainsFunc([]string{"asc", "desc", "nulls", "first", "last"}, func(keyword string) bool {
+			return isWordToken(token, keyword)
+		}) {
+			isColumn = false
+		}
+	}
+	if isColumn {
+		return getIdentifierFromToken(firstToken)
+	}
+	return statement.getRawText(start, end)
+}
+
+func (d *PSQLDefinitions) parseCreateType(p *sqlParser) error {
+	schema, typeName, nameErr := p.parseQualifiedName()
+	if nameErr != nil {
+		return nameErr
+	}
+	if !p.acceptKeyword("as", "enum") {
+		return nil
+	}
+
+	openErr := p.expectSymbol("(")
+	if openErr != nil {
+		return openErr
+	}
+	values := []string{}
+	for !p.acceptSymbol(")") {
+		value, valueErr := p.parseStringLiteral()
+		if valueErr != nil {
+			return valueErr
+		}
+		values = append(values, value)
+		if !p.isSymbol(")") {
+			commaErr := p.expectSymbol(",")
+			if commaErr != nil {
+				return commaErr
+			}
+		}
+	}
+
+	d.EnumTypes = append(d.EnumTypes, psqldef.PSQLTypeEnum{
+		Schema: schema,
+		Name:   typeName,
+		Values: values,
+	})
+	return nil
+}
+
+func (d *PSQLDefinitions) parseInsert(p *sqlParser) error {
+	schema, tableName, nameErr := p.parseQualifiedName()
+	if nameErr != nil {
+		return nameErr
+	}
+	table, tableErr := d.getTable(schema, tableName)
+	if tableErr != nil {
+		return tableErr
+	}
+
+	columnNames := getColumnNames(table)
+	if p.isSymbol("(") {
+		var columnsErr error
+		columnNames, columnsErr = p.parseIdentifierList()
+		if columnsErr != nil {
+			return columnsErr
+		}
+	}
+	valuesErr := p.expectKeyword("values")
+	if valuesErr != nil {
+		return valuesErr
+	}
+
+	rows := [][]any{}
+	for {
+		row, rowErr := parseValuesRow(p)
+		if rowErr != nil {
+			return rowErr
+		}
+		rows = append(rows, row)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	addSeedRows(table, schema, columnNames, rows)
+	return nil
+}
+
+func parseValuesRow(p *sqlParser) ([]any, error) {
+	openErr := p.expectSymbol("(")
+	if openErr != nil {
+		return nil, openErr
+	}
+	row := []any{}
+	for {
+		valueStart, valueEnd := p.skipExpression()
+		if valueStart == valueEnd {
+			return nil, p.errUnexpected("value")
+		}
+		row = append(row, getLiteralValue(p.statement, valueStart, valueEnd))
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	closeErr := p.expectSymbol(")")
+	if closeErr != nil {
+		return nil, closeErr
+	}
+	return row, nil
+}
+
+// getLiteralValue returns the Go value of a constant, or the raw text of other value expressions. String constants
+// cast to a type, e.g. '2024-01-01'::timestamptz, are read as strings.
+func getLiteralValue(statement sqlStatement, start int, end int) any {
+	tokens := statement.Tokens[start:end]
+	if len(tokens) > 2 && tokens[0].Kind == sqlTokenString && isSymbolToken(tokens[1], "::") && isTypeName(tokens[2:]) {
+		return tokens[0].Text
+	}
+	sign := ""
+	if len(tokens) == 2 && isSymbolToken(tokens[0], "-") {
+		sign = "-"
+		tokens = tokens[1:]
+	}
+	if len(tokens) != 1 {
+		return statement.getRawText(start, end)
+	}
+
+	token := tokens[0]
+	switch {
+	case token.Kind == sqlTokenString && sign == "":
+		return token.Text
+	case token.Kind == sqlTokenNumber:
+		if intValue, intErr := strconv.ParseInt(sign+token.Text, 10, 64); intErr == nil {
+			return intValue
+		}
+		if floatValue, floatErr := strconv.ParseFloat(sign+token.Text, 64); floatErr == nil {
+			return floatValue
+		}
+	case isWordToken(token, "null"):
+		return nil
+	case isWordToken(token, "true"):
+		return true
+	case isWordToken(token, "false"):
+		return false
+	}
+	return statement.getRawText(start, end)
+}
+
+// isTypeName returns true if the tokens form a possibly schema qualified type name, as used in casts
+func isTypeName(tokens []sqlToken) bool {
+	for _, token := range tokens {
+		isName := token.Kind == sqlTokenWord || token.Kind == sqlTokenQuotedIdentifier
+		if !isName && !isSymbolToken(token, ".") && !isSymbolToken(token, "[") && !isSymbolToken(token, "]") {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *PSQLDefinitions) parseCopy(p *sqlParser) error {
+	schema, tableName, nameErr := p.parseQualifiedName()
+	if nameErr != nil {
+		return nameErr
+	}
+	if p.statement.CopyData == nil {
+		return nil
+	}
+	table, tableErr := d.getTable(schema, tableName)
+	if tableErr != nil {
+		return tableErr
+	}
+
+	columnNames := getColumnNames(table)
+	if p.isSymbol("(") {
+		var columnsErr error
+		columnNames, columnsErr = p.parseIdentifierList()
+		if columnsErr != nil {
+			return columnsErr
+		}
+	}
+
+	rows := [][]any{}
+	for _, dataLine := range p.statement.CopyData {
+		row := []any{}
+		for _, value := range strings.Split(dataLine, "\t") {
+			row = append(row, getCopyValue(value))
+		}
+		rows = append(rows, row)
+	}
+
+	addSeedRows(table, schema, columnNames, rows)
+	return nil
+}
+
+// getCopyValue returns the value of a field in the COPY text format, where \N is NULL
+func getCopyValue(value string) any {
+	if value == `\N` {
+		return nil
+	}
+	unescapedValue := strings.Builder{}
+	for charIdx := 0; charIdx < len(value); charIdx++ {
+		if value[charIdx] == '\\' && charIdx+1 < len(value) {
+			charIdx++
+			unescapedValue.WriteByte(unescapeSQLByte(value[charIdx]))
+			continue
+		}
+		unescapedValue.WriteByte(value[charIdx])
+	}
+	return unescapedValue.String()
+}
+
+// addSeedRows adds rows to the table's seed data, merging consecutive inserts into the same columns
+func addSeedRows(table *psqldef.Table, schema string, columnNames []string, rows [][]any) {
+	seedCount := len(table.SeedData)
+	if seedCount > 0 {
+		lastSeed := &table.SeedData[seedCount-1]
+		if lastSeed.Schema == schema && slices.Equal(lastSeed.Columns, columnNames) {
+			lastSeed.Values = append(lastSeed.Values, rows...)
+			return
+		}
+	}
+	table.SeedData = append(table.SeedData, psqldef.InsertStatement{
+		Schema:    schema,
+		TableName: table.Name,
+		Columns:   columnNames,
+		Values:    rows,
+	})
+}
+
+func (d *PSQLDefinitions) findTable(schema string, tableName string) *psqldef.Table {
+	for _, table := range d.Tables {
+		if table.Name == tableName && (schema == "" || table.Schema == "" || table.Schema == schema) {
+			return table
+		}
+	}
+	return nil
+}
+
+func (d *PSQLDefinitions) getTable(schema string, tableName string) (*psqldef.Table, error) {
+	table := d.findTable(schema, tableName)
+	if table == nil {
+		return nil, ErrUnknownTable(getQualifiedName(schema, tableName))
+	}
+	return table, nil
+}
+
+func (d *PSQLDefinitions) findView(schema string, viewName string) *psqldef.View {
+	for _, view := range d.Views {
+		if view.Name == viewName && (schema == "" || view.Schema == "" || view.Schema == schema) {
+			return view
+		}
+	}
+	return nil
+}
+
+func getTableColumn(table *psqldef.Table, columnName string) (*psqldef.TableColumn, error) {
+	for columnIdx := range table.Columns {
+		if table.Columns[columnIdx].Name == columnName {
+			return &table.Columns[columnIdx], nil
+		}
+	}
+	return nil, ErrUnknownColumn(table.Name, columnName)
+}
+
+func getColumnNames(table *psqldef.Table) []string {
+	columnNames := []string{}
+	for _, column := range table.Columns {
+		columnNames = append(columnNames, column.Name)
+	}
+	return columnNames
+}
+
+func getQualifiedName(schema string, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
@@ -0,0 +1,246 @@
+package reverse_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/internal/testutils"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/reverse"
+)
+
+type ParsePSQLTestSuite struct {
+	suite.Suite
+
+	TestDirPath string
+}
+
+func TestParsePSQLTestSuite(t *testing.T) {
+	suite.Run(t, new(ParsePSQLTestSuite))
+}
+
+func (suite *ParsePSQLTestSuite) SetupTest() {
+	suite.TestDirPath = testutils.GetTestDirPath()
+}
+
+func (suite *ParsePSQLTestSuite) TearDownTest() {
+	suite.TestDirPath = ""
+}
+
+func (suite *ParsePSQLTestSuite) getTable(definitions *reverse.PSQLDefinitions, tableName string) *psqldef.Table {
+	for _, table := range definitions.Tables {
+		if table.Name == tableName {
+			return table
+		}
+	}
+	suite.FailNow("table not found", tableName)
+	return nil
+}
+
+func (suite *ParsePSQLTestSuite) TestParsePSQLFile_PGDump() {
+	definitions, parseErr := reverse.ParsePSQLFile(filepath.Join(suite.TestDirPath, "sql", "legacy_pg_dump.sql"))
+
+	suite.Nil(parseErr)
+	suite.NotNil(definitions)
+	suite.Len(definitions.Tables, 6)
+
+	suite.Len(definitions.EnumTypes, 1)
+	accountStatusType := definitions.EnumTypes[0]
+	suite.Equal("public", accountStatusType.Schema)
+	suite.Equal("account_status", accountStatusType.Name)
+	suite.Equal([]string{"active", "suspended"}, accountStatusType.Values)
+
+	companiesTable := suite.getTable(definitions, "companies")
+	suite.Equal("public", companiesTable.Schema)
+	suite.Equal([]psqldef.TableColumn{
+		{
+			Name:       "id",
+			Type:       psqldef.PSQLTypeSerial,
+			NotNull:    true,
+			PrimaryKey: true,
+		},
+		{
+			Name: "name",
+			Type: psqldef.PSQLTypeText,
+		},
+		{
+			Name:    "tax_id",
+			Type:    psqldef.PSQLTypeText,
+			NotNull: true,
+		},
+	}, companiesTable.Columns)
+	suite.Equal([]psqldef.Trigger{
+		{
+			Schema:       "public",
+			Name:         "trg_companies_immutable",
+			TableName:    "companies",
+			Timing:       "BEFORE",
+			Events:       []string{"UPDATE"},
+			FunctionName: "fn_companies_immutable",
+			FunctionBody: []string{
+				`IF NEW."tax_id" IS DISTINCT FROM OLD."tax_id" THEN`,
+				"\tRAISE EXCEPTION 'column tax_id of table companies is immutable';",
+				"END IF;",
+				"RETURN NEW;",
+			},
+		},
+	}, companiesTable.Triggers)
+	suite.Equal([]psqldef.Index{
+		{
+			Schema:    "public",
+			Name:      "idx_companies_name",
+			TableName: "companies",
+			Columns:   []string{"name"},
+			IsUnique:  true,
+			Using:     "btree",
+		},
+	}, companiesTable.Indices)
+
+	tagsTable := suite.getTable(definitions, "tags")
+	suite.Equal(psqldef.PSQLTypeBigSerial, tagsTable.Columns[0].Type)
+	suite.Equal(psqldef.PSQLTypePrimitive{Syntax: "VARCHAR(64)"}, tagsTable.Columns[1].Type)
+	suite.Equal([]psqldef.UniqueConstraint{
+		{
+			Schema:      "public",
+			Name:        "tags_name_key",
+			TableName:   "tags",
+			ColumnNames: []string{"name"},
+		},
+	}, tagsTable.UniqueConstraints)
+
+	peopleTable := suite.getTable(definitions, "people")
+	suite.Equal(accountStatusType, peopleTable.Columns[6].Type)
+	suite.Equal("'active'::public.account_status", peopleTable.Columns[6].Default)
+	suite.Equal([]string{"lower(last_name) DESC"}, peopleTable.Indices[2].Columns)
+	suite.Len(peopleTable.ForeignKeys, 3)
+	suite.Equal(psqldef.ForeignKey{
+		Schema:            "public",
+		Name:              "fk_people_manager_id",
+		TableName:         "people",
+		ColumnNames:       []string{"manager_id"},
+		RefSchema:         "public",
+		RefTableName:      "people",
+		RefColumnNames:    []string{"id"},
+		OnDelete:          "SET NULL",
+		Deferrable:        true,
+		InitiallyDeferred: true,
+	}, peopleTable.ForeignKeys[1])
+
+	nationalitiesTable := suite.getTable(definitions, "nationalities")
+	suite.Equal([]psqldef.InsertStatement{
+		{
+			Schema:    "public",
+			TableName: "nationalities",
+			Columns:   []string{"id", "key", "value", "value_type"},
+			Values: [][]any{
+				{"1", "DE", "German", "String"},
+				{"2", "FR", "French", "String"},
+				{"3", "US", "American", "String"},
+			},
+		},
+	}, nationalitiesTable.SeedData)
+
+	suite.Len(definitions.Views, 1)
+	suite.Equal(&psqldef.View{
+		Schema: "public",
+		Name:   "person_entities",
+		Columns: []psqldef.ViewColumn{
+			{Name: "id", SourceRef: "people.id"},
+			{Name: "last_name", SourceRef: "people.last_name"},
+			{Name: "contact_email", SourceRef: "contact_infos.email"},
+		},
+		FromTable: "public.people",
+		Joins: []psqldef.JoinClause{
+			{
+				Type:  "LEFT",
+				Table: "public.contact_infos",
+				Conditions: []psqldef.JoinCondition{
+					{LeftRef: "people.id", RightRef: "contact_infos.person_id"},
+				},
+			},
+		},
+		WhereClause: "(people.is_active = true)",
+	}, definitions.Views[0])
+}
+
+func (suite *ParsePSQLTestSuite) TestParsePSQL_InlineConstraints() {
+	sql := `
+CREATE TABLE IF NOT EXISTS shop.orders (
+	id BIGSERIAL PRIMARY KEY,
+	code VARCHAR(16) NOT NULL UNIQUE,
+	total NUMERIC(10, 2) CHECK (total >= 0),
+	customer_id INTEGER REFERENCES shop.customers (id) ON DELETE RESTRICT ON UPDATE CASCADE,
+	CONSTRAINT uk_orders_code_total UNIQUE (code, total)
+);
+INSERT INTO shop.orders (code, total) VALUES ('A-1', 12.5);
+INSERT INTO shop.orders (code, total) VALUES ('B-2', -3), (E'C\'3', NULL);
+`
+
+	definitions, parseErr := reverse.ParsePSQL(sql)
+
+	suite.Nil(parseErr)
+	suite.Len(definitions.Tables, 1)
+	ordersTable := definitions.Tables[0]
+	suite.Equal("shop", ordersTable.Schema)
+	suite.Equal([]psqldef.TableColumn{
+		{Name: "id", Type: psqldef.PSQLTypeBigSerial, PrimaryKey: true},
+		{Name: "code", Type: psqldef.PSQLTypePrimitive{Syntax: "VARCHAR(16)"}, NotNull: true},
+		{Name: "total", Type: psqldef.PSQLTypePrimitive{Syntax: "NUMERIC(10, 2)"}},
+		{Name: "customer_id", Type: psqldef.PSQLTypeInteger},
+	}, ordersTable.Columns)
+	suite.Equal([]psqldef.UniqueConstraint{
+		{Schema: "shop", Name: "orders_code_key", TableName: "orders", ColumnNames: []string{"code"}},
+		{Schema: "shop", Name: "uk_orders_code_total", TableName: "orders", ColumnNames: []string{"code", "total"}},
+	}, ordersTable.UniqueConstraints)
+	suite.Equal([]psqldef.CheckConstraint{
+		{Schema: "shop", Name: "orders_check", TableName: "orders", Expression: "total >= 0"},
+	}, ordersTable.CheckConstraints)
+	suite.Equal([]psqldef.ForeignKey{
+		{
+			Schema:         "shop",
+			Name:           "orders_customer_id_fkey",
+			TableName:      "orders",
+			ColumnNames:    []string{"customer_id"},
+			RefSchema:      "shop",
+			RefTableName:   "customers",
+			RefColumnNames: []string{"id"},
+			OnDelete:       "RESTRICT",
+			OnUpdate:       "CASCADE",
+		},
+	}, ordersTable.ForeignKeys)
+	suite.Equal([]psqldef.InsertStatement{
+		{
+			Schema:    "shop",
+			TableName: "orders",
+			Columns:   []string{"code", "total"},
+			Values: [][]any{
+				{"A-1", 12.5},
+				{"B-2", int64(-3)},
+				{"C'3", nil},
+			},
+		},
+	}, ordersTable.SeedData)
+}
+
+func (suite *ParsePSQLTestSuite) TestParsePSQL_UnterminatedString() {
+	definitions, parseErr := reverse.ParsePSQL("INSERT INTO people (name) VALUES (\n'unterminated);")
+
+	suite.Nil(definitions)
+	suite.EqualError(parseErr, "line 2: unterminated string")
+}
+
+func (suite *ParsePSQLTestSuite) TestParsePSQL_UnknownTable() {
+	definitions, parseErr := reverse.ParsePSQL("ALTER TABLE ONLY public.people ADD CONSTRAINT people_pkey PRIMARY KEY (id);")
+
+	suite.Nil(definitions)
+	suite.EqualError(parseErr, "table 'public.people' is not defined")
+}
+
+func (suite *ParsePSQLTestSuite) TestParsePSQL_UnexpectedToken() {
+	definitions, parseErr := reverse.ParsePSQL("CREATE TABLE people (\n\tid INTEGER,\n\tPRIMARY KEY id\n);")
+
+	suite.Nil(definitions)
+	suite.EqualError(parseErr, "line 3: expected '(', found 'id'")
+}
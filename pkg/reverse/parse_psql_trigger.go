@@ -0,0 +1,184 @@
+package reverse
+
+import (
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// parseCreateFunction remembers the body of a function for the triggers executing it. A function refreshing a
+// materialized view becomes the refresh function of the view.
+func (d *PSQLDefinitions) parseCreateFunction(p *sqlParser) error {
+	schema, functionName, nameErr := p.parseQualifiedName()
+	if nameErr != nil {
+		return nameErr
+	}
+	skipErr := p.skipParenthesized()
+	if skipErr != nil {
+		return skipErr
+	}
+
+	for !p.isDone() {
+		if !p.acceptKeyword("as") {
+			p.pos++
+			continue
+		}
+		token, found := p.peek()
+		if !found || token.Kind != sqlTokenString {
+			continue
+		}
+		if d.functionBodies == nil {
+			d.functionBodies = map[string]string{}
+		}
+		d.functionBodies[getQualifiedName(schema, functionName)] = token.Text
+		if view := d.findRefreshedView(token.Text); view != nil {
+			view.RefreshFunctionName = functionName
+		}
+		return nil
+	}
+	return nil
+}
+
+// findRefreshedView returns the known materialized view refreshed by a function body
+func (d *PSQLDefinitions) findRefreshedView(functionBody string) *psqldef.View {
+	statements, splitErr := splitSQLStatements(functionBody)
+	if splitErr != nil {
+		return nil
+	}
+	for _, statement := range statements {
+		for tokenIdx := range statement.Tokens {
+			p := &sqlParser{statement: statement, pos: tokenIdx}
+			if !p.acceptKeyword("refresh", "materialized", "view") {
+				continue
+			}
+			p.acceptKeyword("concurrently")
+			schema, viewName, nameErr := p.parseQualifiedName()
+			if nameErr != nil {
+				return nil
+			}
+			return d.findView(schema, viewName)
+		}
+	}
+	return nil
+}
+
+func (d *PSQLDefinitions) parseCreateTrigger(p *sqlParser) error {
+	triggerName, nameErr := p.parseIdentifier()
+	if nameErr != nil {
+		return nameErr
+	}
+
+	timing := ""
+	for _, candidateTiming := range [][]string{{"before"}, {"after"}, {"instead", "of"}} {
+		if p.acceptKeyword(candidateTiming...) {
+			timing = strings.ToUpper(strings.Join(candidateTiming, " "))
+			break
+		}
+	}
+	if timing == "" {
+		return p.errUnexpected("BEFORE, AFTER or INSTEAD OF")
+	}
+
+	events := []string{}
+	for {
+		event, eventErr := parseTriggerEvent(p)
+		if eventErr != nil {
+			return eventErr
+		}
+		events = append(events, event)
+		if !p.acceptKeyword("or") {
+			break
+		}
+	}
+
+	onErr := p.expectKeyword("on")
+	if onErr != nil {
+		return onErr
+	}
+	schema, tableName, tableNameErr := p.parseQualifiedName()
+	if tableNameErr != nil {
+		return tableNameErr
+	}
+
+	// Skip the referencing, FOR EACH and WHEN clauses
+	for !p.isDone() && !p.isKeyword("execute") {
+		p.pos++
+	}
+	executeErr := p.expectKeyword("execute")
+	if executeErr != nil {
+		return executeErr
+	}
+	if !p.acceptKeyword("function") {
+		procedureErr := p.expectKeyword("procedure")
+		if procedureErr != nil {
+			return procedureErr
+		}
+	}
+	functionSchema, functionName, functionNameErr := p.parseQualifiedName()
+	if functionNameErr != nil {
+		return functionNameErr
+	}
+
+	table, tableErr := d.getTable(schema, tableName)
+	if tableErr != nil {
+		return tableErr
+	}
+	table.Triggers = append(table.Triggers, psqldef.Trigger{
+		Schema:       functionSchema,
+		Name:         triggerName,
+		TableName:    tableName,
+		Timing:       timing,
+		Events:       events,
+		FunctionName: functionName,
+		FunctionBody: getFunctionBodyLines(d.functionBodies[getQualifiedName(functionSchema, functionName)]),
+	})
+	return nil
+}
+
+// parseTriggerEvent parses a trigger event, keeping the columns of UPDATE OF events
+func parseTriggerEvent(p *sqlParser) (string, error) {
+	for _, candidateEvent := range []string{"insert", "update", "delete", "truncate"} {
+		if !p.acceptKeyword(candidateEvent) {
+			continue
+		}
+		event := strings.ToUpper(candidateEvent)
+		if !p.acceptKeyword("of") {
+			return event, nil
+		}
+		columnNames := []string{}
+		for {
+			columnName, columnNameErr := p.parseIdentifier()
+			if columnNameErr != nil {
+				return "", columnNameErr
+			}
+			columnNames = append(columnNames, columnName)
+			if !p.acceptSymbol(",") {
+				return event + " OF " + strings.Join(columnNames, ", "), nil
+			}
+		}
+	}
+	return "", p.errUnexpected("trigger event")
+}
+
+// getFunctionBodyLines returns the lines of a PL/pgSQL function body between its BEGIN and END, without the
+// indentation of the block
+func getFunctionBodyLines(functionBody string) []string {
+	lines := strings.Split(strings.ReplaceAll(functionBody, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	lineCount := len(lines)
+	if lineCount >= 2 && strings.EqualFold(strings.TrimSpace(lines[0]), "begin") &&
+		strings.EqualFold(strings.TrimSpace(lines[lineCount-1]), "end;") {
+		lines = lines[1 : lineCount-1]
+	}
+
+	bodyLines := []string{}
+	for _, line := range lines {
+		bodyLines = append(bodyLines, strings.TrimPrefix(line, "\t"))
+	}
+	return bodyLines
+}
@@ -0,0 +1,229 @@
+package reverse
+
+import (
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+func (d *PSQLDefinitions) parseCreateView(p *sqlParser, materialized bool) error {
+	p.acceptKeyword("if", "not", "exists")
+	schema, viewName, nameErr := p.parseQualifiedName()
+	if nameErr != nil {
+		return nameErr
+	}
+	skipErr := p.skipParenthesized()
+	if skipErr != nil {
+		return skipErr
+	}
+	asErr := p.expectKeyword("as")
+	if asErr != nil {
+		return asErr
+	}
+
+	view := &psqldef.View{
+		Schema:       schema,
+		Name:         viewName,
+		Materialized: materialized,
+	}
+	queryErr := parseViewQuery(p, view)
+	if queryErr != nil {
+		return queryErr
+	}
+
+	d.Views = append(d.Views, view)
+	return nil
+}
+
+// parseViewQuery parses the select list, source table, joins and where clause of a view query
+func parseViewQuery(p *sqlParser, view *psqldef.View) error {
+	selectErr := p.expectKeyword("select")
+	if selectErr != nil {
+		return selectErr
+	}
+	p.acceptKeyword("distinct")
+
+	for {
+		columnStart, columnEnd := p.skipExpression("from")
+		if columnStart == columnEnd {
+			return p.errUnexpected("view column")
+		}
+		view.Columns = append(view.Columns, getViewColumn(p.statement, columnStart, columnEnd))
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if p.acceptKeyword("from") {
+		fromErr := parseViewFrom(p, view)
+		if fromErr != nil {
+			return fromErr
+		}
+	}
+	if p.acceptKeyword("where") {
+		view.WhereClause = p.parseExpression("group", "order", "limit", "with")
+	}
+	if p.acceptKeyword("with", "no", "data") {
+		view.WithNoData = true
+	}
+	return nil
+}
+
+// getViewColumn returns the column of a select list item, named by its alias or its referenced column
+func getViewColumn(statement sqlStatement, start int, end int) psqldef.ViewColumn {
+	tokens := statement.Tokens[start:end]
+	tokenCount := len(tokens)
+	if tokenCount >= 3 && isWordToken(tokens[tokenCount-2], "as") {
+		return psqldef.ViewColumn{
+			Name:      getIdentifierFromToken(tokens[tokenCount-1]),
+			SourceRef: statement.getRawText(start, end-2),
+		}
+	}
+
+	columnName := ""
+	lastToken := tokens[tokenCount-1]
+	if lastToken.Kind == sqlTokenWord || lastToken.Kind == sqlTokenQuotedIdentifier {
+		columnName = getIdentifierFromToken(lastToken)
+	}
+	return psqldef.ViewColumn{
+		Name:      columnName,
+		SourceRef: statement.getRawText(start, end),
+	}
+}
+
+// parseViewFrom parses the source table and joins of a view query, including the parenthesized join trees
+// PostgreSQL prints for stored views
+func parseViewFrom(p *sqlParser, view *psqldef.View) error {
+	for p.acceptSymbol("(") {
+	}
+	fromTable, _, fromErr := parseViewTableReference(p)
+	if fromErr != nil {
+		return fromErr
+	}
+	view.FromTable = fromTable
+
+	for {
+		for p.acceptSymbol(")") {
+		}
+		joinType, isJoin := parseJoinType(p)
+		if !isJoin {
+			return nil
+		}
+		for p.acceptSymbol("(") {
+		}
+		joinTable, joinAlias, joinTableErr := parseViewTableReference(p)
+		if joinTableErr != nil {
+			return joinTableErr
+		}
+
+		join := psqldef.JoinClause{
+			Type:  joinType,
+			Table: joinTable,
+			Alias: joinAlias,
+		}
+		if p.acceptKeyword("on") {
+			join.Conditions = getJoinConditions(p.parseExpression(viewClauseKeywords...))
+		}
+		view.Joins = append(view.Joins, join)
+	}
+}
+
+// parseViewTableReference returns the raw table reference and the alias of a source table
+func parseViewTableReference(p *sqlParser) (string, string, error) {
+	tableStart := p.pos
+	_, _, nameErr := p.parseQualifiedName()
+	if nameErr != nil {
+		return "", "", nameErr
+	}
+	tableReference := p.statement.getRawText(tableStart, p.pos)
+
+	hasAlias := p.acceptKeyword("as")
+	token, found := p.peek()
+	isIdentifier := found && (token.Kind == sqlTokenWord || token.Kind == sqlTokenQuotedIdentifier)
+	if !hasAlias && (!isIdentifier || p.isKeyword("on") || p.isAnyKeyword(viewClauseKeywords)) {
+		return tableReference, "", nil
+	}
+	alias, aliasErr := p.parseIdentifier()
+	if aliasErr != nil {
+		return "", "", aliasErr
+	}
+	return tableReference, alias, nil
+}
+
+func parseJoinType(p *sqlParser) (string, bool) {
+	joinType := "INNER"
+	for _, candidateType := range []string{"left", "right", "full", "inner", "cross"} {
+		if p.acceptKeyword(candidateType) {
+			joinType = strings.ToUpper(candidateType)
+			break
+		}
+	}
+	p.acceptKeyword("outer")
+	if !p.acceptKeyword("join") {
+		return "", false
+	}
+	return joinType, true
+}
+
+// getJoinConditions splits a join condition into its AND-ed equality conditions
+func getJoinConditions(condition string) []psqldef.JoinCondition {
+	conditions := []psqldef.JoinCondition{}
+	for _, part := range splitTopLevel(trimWrappingParentheses(condition), " AND ") {
+		sides := splitTopLevel(trimWrappingParentheses(part), " = ")
+		if len(sides) != 2 {
+			continue
+		}
+		conditions = append(conditions, psqldef.JoinCondition{
+			LeftRef:  strings.TrimSpace(sides[0]),
+			RightRef: strings.TrimSpace(sides[1]),
+		})
+	}
+	return conditions
+}
+
+// trimWrappingParentheses removes parentheses wrapping the whole expression
+func trimWrappingParentheses(expression string) string {
+	expression = strings.TrimSpace(expression)
+	for strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") {
+		depth := 0
+		wrapsWhole := true
+		for charIdx, char := range expression {
+			if char == '(' {
+				depth++
+			}
+			if char == ')' {
+				depth--
+			}
+			if depth == 0 && charIdx < len(expression)-1 {
+				wrapsWhole = false
+				break
+			}
+		}
+		if !wrapsWhole {
+			break
+		}
+		expression = strings.TrimSpace(expression[1 : len(expression)-1])
+	}
+	return expression
+}
+
+// splitTopLevel splits an expression at separators outside parentheses, matching the separator case-insensitively
+func splitTopLevel(expression string, separator string) []string {
+	parts := []string{}
+	depth := 0
+	partStart := 0
+	for charIdx := 0; charIdx < len(expression); charIdx++ {
+		switch expression[charIdx] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 && strings.EqualFold(expression[charIdx:min(charIdx+len(separator), len(expression))], separator) {
+			parts = append(parts, expression[partStart:charIdx])
+			charIdx += len(separator) - 1
+			partStart = charIdx + 1
+		}
+	}
+	return append(parts, expression[partStart:])
+}
@@ -0,0 +1,426 @@
+package reverse
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gertd/go-pluralize"
+	"github.com/kalo-build/go-util/strcase"
+	"github.com/kalo-build/morphe-go/pkg/yaml"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/typemap"
+)
+
+// Morphe holds the models and enums reverse-engineered from PostgreSQL definitions
+type Morphe struct {
+	Models map[string]yaml.Model
+	Enums  map[string]yaml.Enum
+	// RelationAliases maps relations named differently than their related model to that model, in the shape of
+	// the models.relation_aliases compile config, which Morphe model files cannot express
+	RelationAliases map[string]map[string]string
+}
+
+var pluralizeClient = pluralize.NewClient()
+
+// psqlTypeToMorpheModelFieldType inverts typemap.MorpheModelFieldToPSQLField, widened to the PostgreSQL types
+// legacy schemas use for the same values
+var psqlTypeToMorpheModelFieldType = getPSQLTypeToMorpheModelFieldType()
+
+func getPSQLTypeToMorpheModelFieldType() map[string]yaml.ModelFieldType {
+	fieldTypes := map[string]yaml.ModelFieldType{}
+	// The first field type wins, so TEXT maps back to String rather than Protected or Sealed
+	for _, fieldType := range yaml.ModelFieldTypesPrimitive {
+		psqlType, found := typemap.MorpheModelFieldToPSQLField[fieldType]
+		if _, mapped := fieldTypes[psqlType.GetSyntax()]; found && !mapped {
+			fieldTypes[psqlType.GetSyntax()] = fieldType
+		}
+	}
+
+	fieldTypes[psqldef.PSQLTypeBigSerial.Syntax] = yaml.ModelFieldTypeAutoIncrement
+	fieldTypes[psqldef.PSQLTypeVarchar.Syntax] = yaml.ModelFieldTypeString
+	fieldTypes[psqldef.PSQLTypeChar.Syntax] = yaml.ModelFieldTypeString
+	fieldTypes[psqldef.PSQLTypeSmallInt.Syntax] = yaml.ModelFieldTypeInteger
+	fieldTypes[psqldef.PSQLTypeBigInt.Syntax] = yaml.ModelFieldTypeInteger
+	fieldTypes[psqldef.PSQLTypeReal.Syntax] = yaml.ModelFieldTypeFloat
+	fieldTypes[psqldef.PSQLTypeNumeric.Syntax] = yaml.ModelFieldTypeFloat
+	fieldTypes[psqldef.PSQLTypeTimestamp.Syntax] = yaml.ModelFieldTypeTime
+	return fieldTypes
+}
+
+// PSQLDefinitionsToMorphe maps parsed PostgreSQL definitions back to Morphe models and enums.
+//
+// Lookup tables shaped like compiled enum tables and native enum types become enums, and foreign keys to them
+// become enum fields. Tables holding only two model foreign keys, apart from a surrogate key, are junction tables
+// which become a ForMany relation on the model named first in the table name and a HasMany relation back. All other
+// tables become models, whose model foreign keys become ForOne relations with a HasMany, or HasOne for unique
+// foreign keys, relation back.
+func PSQLDefinitionsToMorphe(definitions *PSQLDefinitions) (*Morphe, error) {
+	morphe := &Morphe{
+		Models:          map[string]yaml.Model{},
+		Enums:           map[string]yaml.Enum{},
+		RelationAliases: map[string]map[string]string{},
+	}
+
+	for _, enumType := range definitions.EnumTypes {
+		enum := getEnumFromType(enumType)
+		morphe.Enums[enum.Name] = enum
+	}
+
+	enumNamesByTable := map[string]string{}
+	modelTables := []*psqldef.Table{}
+	for _, table := range definitions.Tables {
+		if !isEnumLookupTable(table) {
+			modelTables = append(modelTables, table)
+			continue
+		}
+		enum := getEnumFromLookupTable(table)
+		morphe.Enums[enum.Name] = enum
+		enumNamesByTable[table.Name] = enum.Name
+	}
+
+	modelNamesByTable := map[string]string{}
+	for _, table := range modelTables {
+		modelNamesByTable[table.Name] = getMorpheNameFromTableName(table.Name)
+	}
+
+	junctionTables := []*psqldef.Table{}
+	for _, table := range modelTables {
+		if isJunctionTable(table, modelNamesByTable) {
+			junctionTables = append(junctionTables, table)
+			delete(modelNamesByTable, table.Name)
+		}
+	}
+
+	for _, table := range modelTables {
+		modelName, isModel := modelNamesByTable[table.Name]
+		if !isModel {
+			continue
+		}
+		model, modelErr := getModelFromTable(table, modelName, enumNamesByTable, modelNamesByTable)
+		if modelErr != nil {
+			return nil, modelErr
+		}
+		morphe.Models[modelName] = model
+	}
+
+	for _, table := range modelTables {
+		if modelName, isModel := modelNamesByTable[table.Name]; isModel {
+			morphe.addForeignKeyRelations(table, modelName, modelNamesByTable)
+		}
+	}
+	for _, table := range junctionTables {
+		morphe.addJunctionRelations(table, modelNamesByTable)
+	}
+
+	return morphe, nil
+}
+
+// getMorpheNameFromTableName returns the singular PascalCase model or enum name of a table, e.g. Person for people
+func getMorpheNameFromTableName(tableName string) string {
+	return getMorpheNameFromSnakeCase(pluralizeClient.Singular(tableName))
+}
+
+// getMorpheNameFromSnakeCase returns the PascalCase name of a snake_case identifier, writing id as ID like the
+// registry does, e.g. TaxID for tax_id
+func getMorpheNameFromSnakeCase(identifier string) string {
+	words := strings.Split(identifier, "_")
+	for wordIdx, word := range words {
+		if word == "id" {
+			words[wordIdx] = "ID"
+			continue
+		}
+		words[wordIdx] = strcase.ToPascalCase(word)
+	}
+	return strings.Join(words, "")
+}
+
+func getEnumFromType(enumType psqldef.PSQLTypeEnum) yaml.Enum {
+	entries := map[string]any{}
+	for _, value := range enumType.Values {
+		entries[value] = value
+	}
+	return yaml.Enum{
+		Name:    getMorpheNameFromSnakeCase(enumType.Name),
+		Type:    yaml.EnumTypeString,
+		Entries: entries,
+	}
+}
+
+// isEnumLookupTable returns true for tables shaped like compiled enum lookup tables
+func isEnumLookupTable(table *psqldef.Table) bool {
+	if len(table.Columns) != 4 || len(table.ForeignKeys) > 0 {
+		return false
+	}
+	for _, column := range table.Columns {
+		switch column.Name {
+		case "id":
+			if _, isSerial := getSerialType(column.Type); !isSerial || !column.PrimaryKey {
+				return false
+			}
+		case "key", "value", "value_type":
+			if column.Type.GetSyntax() != psqldef.PSQLTypeText.Syntax || !column.NotNull {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// getEnumFromLookupTable returns the enum of a lookup table with the entries of its seed rows. Schema-only dumps
+// carry no rows, so their enums have no entries yet.
+func getEnumFromLookupTable(table *psqldef.Table) yaml.Enum {
+	enum := yaml.Enum{
+		Name:    getMorpheNameFromTableName(table.Name),
+		Type:    yaml.EnumTypeString,
+		Entries: map[string]any{},
+	}
+	for _, seedData := range table.SeedData {
+		keyIdx := slices.Index(seedData.Columns, "key")
+		valueIdx := slices.Index(seedData.Columns, "value")
+		valueTypeIdx := slices.Index(seedData.Columns, "value_type")
+		if keyIdx < 0 || valueIdx < 0 {
+			continue
+		}
+		for _, row := range seedData.Values {
+			if valueTypeIdx >= 0 && row[valueTypeIdx] != nil {
+				enum.Type = yaml.EnumType(fmt.Sprint(row[valueTypeIdx]))
+			}
+			enum.Entries[fmt.Sprint(row[keyIdx])] = getEnumEntryValue(enum.Type, fmt.Sprint(row[valueIdx]))
+		}
+	}
+	return enum
+}
+
+func getEnumEntryValue(enumType yaml.EnumType, value string) any {
+	switch enumType {
+	case yaml.EnumTypeInteger:
+		if intValue, intErr := strconv.Atoi(value); intErr == nil {
+			return intValue
+		}
+	case yaml.EnumTypeFloat:
+		if floatValue, floatErr := strconv.ParseFloat(value, 64); floatErr == nil {
+			return floatValue
+		}
+	}
+	return value
+}
+
+// isJunctionTable returns true for tables holding two model foreign keys and at most a surrogate primary key
+func isJunctionTable(table *psqldef.Table, modelNamesByTable map[string]string) bool {
+	if len(table.ForeignKeys) != 2 {
+		return false
+	}
+	foreignKeyColumnNames := []string{}
+	for _, foreignKey := range table.ForeignKeys {
+		if _, isModel := modelNamesByTable[foreignKey.RefTableName]; !isModel || foreignKey.RefTableName == table.Name {
+			return false
+		}
+		foreignKeyColumnNames = append(foreignKeyColumnNames, foreignKey.ColumnNames...)
+	}
+
+	surrogateKeyCount := 0
+	for _, column := range table.Columns {
+		if slices.Contains(foreignKeyColumnNames, column.Name) {
+			continue
+		}
+		if !column.PrimaryKey {
+			return false
+		}
+		surrogateKeyCount++
+	}
+	return surrogateKeyCount <= 1
+}
+
+func getModelFromTable(table *psqldef.Table, modelName string, enumNamesByTable map[string]string, modelNamesByTable map[string]string) (yaml.Model, error) {
+	model := yaml.Model{
+		Name:        modelName,
+		Fields:      map[string]yaml.ModelField{},
+		Identifiers: map[string]yaml.ModelIdentifier{},
+		Related:     map[string]yaml.ModelRelation{},
+	}
+
+	relationColumnNames := []string{}
+	enumForeignKeys := map[string]psqldef.ForeignKey{}
+	for _, foreignKey := range table.ForeignKeys {
+		if isRelationForeignKey(table, foreignKey, modelNamesByTable) {
+			relationColumnNames = append(relationColumnNames, foreignKey.ColumnNames...)
+		}
+		if _, isEnum := enumNamesByTable[foreignKey.RefTableName]; isEnum && len(foreignKey.ColumnNames) == 1 {
+			enumForeignKeys[foreignKey.ColumnNames[0]] = foreignKey
+		}
+	}
+
+	fieldNamesByColumn := map[string]string{}
+	for _, column := range table.Columns {
+		if slices.Contains(relationColumnNames, column.Name) {
+			continue
+		}
+		if enumForeignKey, isEnum := enumForeignKeys[column.Name]; isEnum {
+			// Enum fields compile to a foreign key to the enum lookup table, named like a relation
+			fieldName := getRelationName(enumForeignKey)
+			model.Fields[fieldName] = yaml.ModelField{
+				Type: yaml.ModelFieldType(enumNamesByTable[enumForeignKey.RefTableName]),
+			}
+			fieldNamesByColumn[column.Name] = fieldName
+			continue
+		}
+
+		fieldType, fieldTypeErr := getMorpheModelFieldType(table, column)
+		if fieldTypeErr != nil {
+			return yaml.Model{}, fieldTypeErr
+		}
+		field := yaml.ModelField{
+			Type: fieldType,
+		}
+		if column.NotNull || column.PrimaryKey {
+			field.Attributes = []string{"mandatory"}
+		}
+		fieldName := getMorpheNameFromSnakeCase(column.Name)
+		model.Fields[fieldName] = field
+		fieldNamesByColumn[column.Name] = fieldName
+	}
+
+	primaryFieldNames := []string{}
+	for _, column := range table.Columns {
+		if column.PrimaryKey {
+			primaryFieldNames = append(primaryFieldNames, fieldNamesByColumn[column.Name])
+		}
+	}
+	if len(primaryFieldNames) > 0 {
+		model.Identifiers["primary"] = yaml.ModelIdentifier{
+			Fields: primaryFieldNames,
+		}
+	}
+
+	for _, columnNames := range getUniqueColumnSets(table) {
+		fieldNames := []string{}
+		for _, columnName := range columnNames {
+			if fieldName, isField := fieldNamesByColumn[columnName]; isField {
+				fieldNames = append(fieldNames, fieldName)
+			}
+		}
+		if len(fieldNames) != len(columnNames) || slices.Equal(fieldNames, primaryFieldNames) {
+			continue
+		}
+		model.Identifiers[strcase.ToCamelCase(strings.Join(columnNames, "_"))] = yaml.ModelIdentifier{
+			Fields: fieldNames,
+		}
+	}
+
+	if len(model.Identifiers) == 0 {
+		return yaml.Model{}, ErrNoModelIdentifiers(table.Name)
+	}
+	return model, nil
+}
+
+func getMorpheModelFieldType(table *psqldef.Table, column psqldef.TableColumn) (yaml.ModelFieldType, error) {
+	if column.Type.IsEnum() {
+		return yaml.ModelFieldType(getMorpheNameFromSnakeCase(column.Type.GetSyntaxLocal())), nil
+	}
+	typeName, _, _ := strings.Cut(column.Type.GetSyntax(), "(")
+	fieldType, found := psqlTypeToMorpheModelFieldType[typeName]
+	if !found || !column.Type.IsPrimitive() {
+		return "", ErrUnsupportedColumnType(table.Name, column.Name, column.Type.GetSyntax())
+	}
+	return fieldType, nil
+}
+
+// isRelationForeignKey returns true for foreign keys to model tables whose columns are not part of the primary key,
+// which stay plain fields instead
+func isRelationForeignKey(table *psqldef.Table, foreignKey psqldef.ForeignKey, modelNamesByTable map[string]string) bool {
+	if _, isModel := modelNamesByTable[foreignKey.RefTableName]; !isModel {
+		return false
+	}
+	for _, column := range table.Columns {
+		if column.PrimaryKey && slices.Contains(foreignKey.ColumnNames, column.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// getUniqueColumnSets returns the column sets of the table's unique constraints and unique indices
+func getUniqueColumnSets(table *psqldef.Table) [][]string {
+	uniqueColumnSets := [][]string{}
+	for _, uniqueConstraint := range table.UniqueConstraints {
+		uniqueColumnSets = append(uniqueColumnSets, uniqueConstraint.ColumnNames)
+	}
+	for _, index := range table.Indices {
+		if index.IsUnique {
+			uniqueColumnSets = append(uniqueColumnSets, index.Columns)
+		}
+	}
+	return uniqueColumnSets
+}
+
+// getRelationName returns the relation a foreign key implements, named after its first column without the
+// referenced column suffix, e.g. Company for company_id
+func getRelationName(foreignKey psqldef.ForeignKey) string {
+	columnName := foreignKey.ColumnNames[0]
+	refColumnName := "id"
+	if len(foreignKey.RefColumnNames) > 0 {
+		refColumnName = foreignKey.RefColumnNames[0]
+	}
+	relationName := strings.TrimSuffix(columnName, "_"+refColumnName)
+	if relationName == "" {
+		relationName = columnName
+	}
+	return getMorpheNameFromSnakeCase(relationName)
+}
+
+func (m *Morphe) addForeignKeyRelations(table *psqldef.Table, modelName string, modelNamesByTable map[string]string) {
+	for _, foreignKey := range table.ForeignKeys {
+		if !isRelationForeignKey(table, foreignKey, modelNamesByTable) {
+			continue
+		}
+		relatedModelName := modelNamesByTable[foreignKey.RefTableName]
+		relationName := getRelationName(foreignKey)
+		m.addRelation(modelName, relationName, relatedModelName, "ForOne")
+		if relatedModelName == modelName {
+			continue
+		}
+
+		inverseRelationType := "HasMany"
+		if slices.ContainsFunc(getUniqueColumnSets(table), func(columnNames []string) bool {
+			return slices.Equal(columnNames, foreignKey.ColumnNames)
+		}) {
+			inverseRelationType = "HasOne"
+		}
+		m.addRelation(relatedModelName, modelName, modelName, inverseRelationType)
+	}
+}
+
+func (m *Morphe) addJunctionRelations(table *psqldef.Table, modelNamesByTable map[string]string) {
+	sourceForeignKey := table.ForeignKeys[0]
+	targetForeignKey := table.ForeignKeys[1]
+	// Junction tables are named after their source model first, e.g. person_tags
+	sourcePrefix := strcase.ToSnakeCaseLower(getRelationName(sourceForeignKey)) + "_"
+	targetPrefix := strcase.ToSnakeCaseLower(getRelationName(targetForeignKey)) + "_"
+	if !strings.HasPrefix(table.Name, sourcePrefix) && strings.HasPrefix(table.Name, targetPrefix) {
+		sourceForeignKey, targetForeignKey = targetForeignKey, sourceForeignKey
+	}
+
+	sourceModelName := modelNamesByTable[sourceForeignKey.RefTableName]
+	targetModelName := modelNamesByTable[targetForeignKey.RefTableName]
+	m.addRelation(sourceModelName, getRelationName(targetForeignKey), targetModelName, "ForMany")
+	if sourceModelName != targetModelName {
+		m.addRelation(targetModelName, sourceModelName, sourceModelName, "HasMany")
+	}
+}
+
+func (m *Morphe) addRelation(modelName string, relationName string, relatedModelName string, relationType string) {
+	m.Models[modelName].Related[relationName] = yaml.ModelRelation{
+		Type: relationType,
+	}
+	if relationName == relatedModelName {
+		return
+	}
+	if m.RelationAliases[modelName] == nil {
+		m.RelationAliases[modelName] = map[string]string{}
+	}
+	m.RelationAliases[modelName][relationName] = relatedModelName
+}
@@ -0,0 +1,114 @@
+package reverse
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+)
+
+// psqlTypesByName maps PostgreSQL type names and their aliases to the project's type definitions
+var psqlTypesByName = map[string]psqldef.PSQLTypePrimitive{
+	"text":                        psqldef.PSQLTypeText,
+	"character varying":           psqldef.PSQLTypeVarchar,
+	"varchar":                     psqldef.PSQLTypeVarchar,
+	"character":                   psqldef.PSQLTypeChar,
+	"char":                        psqldef.PSQLTypeChar,
+	"bpchar":                      psqldef.PSQLTypeChar,
+	"boolean":                     psqldef.PSQLTypeBoolean,
+	"bool":                        psqldef.PSQLTypeBoolean,
+	"smallint":                    psqldef.PSQLTypeSmallInt,
+	"int2":                        psqldef.PSQLTypeSmallInt,
+	"integer":                     psqldef.PSQLTypeInteger,
+	"int":                         psqldef.PSQLTypeInteger,
+	"int4":                        psqldef.PSQLTypeInteger,
+	"bigint":                      psqldef.PSQLTypeBigInt,
+	"int8":                        psqldef.PSQLTypeBigInt,
+	"serial":                      psqldef.PSQLTypeSerial,
+	"serial4":                     psqldef.PSQLTypeSerial,
+	"bigserial":                   psqldef.PSQLTypeBigSerial,
+	"serial8":                     psqldef.PSQLTypeBigSerial,
+	"real":                        psqldef.PSQLTypeReal,
+	"float4":                      psqldef.PSQLTypeReal,
+	"double precision":            psqldef.PSQLTypeDoublePrecision,
+	"float8":                      psqldef.PSQLTypeDoublePrecision,
+	"numeric":                     psqldef.PSQLTypeNumeric,
+	"decimal":                     psqldef.PSQLTypeNumeric,
+	"uuid":                        psqldef.PSQLTypeUUID,
+	"bytea":                       psqldef.PSQLTypeBytea,
+	"timestamp":                   psqldef.PSQLTypeTimestamp,
+	"timestamp without time zone": psqldef.PSQLTypeTimestamp,
+	"timestamptz":                 psqldef.PSQLTypeTimestampTZ,
+	"timestamp with time zone":    psqldef.PSQLTypeTimestampTZ,
+	"date":                        psqldef.PSQLTypeDate,
+	"time":                        psqldef.PSQLTypeTime,
+	"time without time zone":      psqldef.PSQLTypeTime,
+	"timetz":                      psqldef.PSQLTypeTimeTZ,
+	"time with time zone":         psqldef.PSQLTypeTimeTZ,
+	"interval":                    psqldef.PSQLTypeInterval,
+	"json":                        psqldef.PSQLTypeJSON,
+	"jsonb":                       psqldef.PSQLTypeJSONB,
+}
+
+var typeModifiersRegex = regexp.MustCompile(`\s*\([^)]*\)`)
+
+// getPSQLTypeFromSyntax returns the type definition for a column type as written in SQL.
+//
+// Type modifiers such as a VARCHAR length are kept in the syntax, types neither built in nor declared as enum
+// types are kept verbatim.
+func getPSQLTypeFromSyntax(syntax string, enumTypes []psqldef.PSQLTypeEnum) psqldef.PSQLType {
+	syntax = strings.TrimSpace(syntax)
+	if strings.HasSuffix(syntax, "[]") {
+		return psqldef.PSQLTypeArray{
+			ValueType: getPSQLTypeFromSyntax(strings.TrimSuffix(syntax, "[]"), enumTypes),
+		}
+	}
+
+	modifiers := strings.Join(typeModifiersRegex.FindAllString(syntax, -1), "")
+	typeName := strings.ToLower(strings.Join(strings.Fields(typeModifiersRegex.ReplaceAllString(syntax, " ")), " "))
+	typeName = strings.TrimPrefix(typeName, "pg_catalog.")
+	if primitiveType, found := psqlTypesByName[typeName]; found {
+		return psqldef.PSQLTypePrimitive{
+			Syntax: primitiveType.Syntax + strings.TrimSpace(modifiers),
+		}
+	}
+
+	schema, name := splitQualifiedName(syntax)
+	for _, enumType := range enumTypes {
+		if enumType.Name == name && (schema == "" || enumType.Schema == schema) {
+			return enumType
+		}
+	}
+	return psqldef.PSQLTypePrimitive{
+		Syntax: syntax,
+	}
+}
+
+// getSerialType returns the SERIAL type backing an integer column that draws its default from a sequence
+func getSerialType(columnType psqldef.PSQLType) (psqldef.PSQLType, bool) {
+	switch columnType.GetSyntax() {
+	case psqldef.PSQLTypeInteger.Syntax, psqldef.PSQLTypeSerial.Syntax:
+		return psqldef.PSQLTypeSerial, true
+	case psqldef.PSQLTypeBigInt.Syntax, psqldef.PSQLTypeBigSerial.Syntax:
+		return psqldef.PSQLTypeBigSerial, true
+	}
+	return nil, false
+}
+
+// splitQualifiedName splits a [schema.]name type reference, removing identifier quotes
+func splitQualifiedName(qualifiedName string) (string, string) {
+	schema := ""
+	name := qualifiedName
+	if dotIdx := strings.LastIndex(qualifiedName, "."); dotIdx >= 0 {
+		schema = unquoteIdentifier(qualifiedName[:dotIdx])
+		name = qualifiedName[dotIdx+1:]
+	}
+	return schema, unquoteIdentifier(name)
+}
+
+func unquoteIdentifier(identifier string) string {
+	if len(identifier) >= 2 && strings.HasPrefix(identifier, `"`) && strings.HasSuffix(identifier, `"`) {
+		return strings.ReplaceAll(identifier[1:len(identifier)-1], `""`, `"`)
+	}
+	return strings.ToLower(identifier)
+}
@@ -0,0 +1,27 @@
+package reverse
+
+// PSQLToMorphe parses the configured SQL file and writes the reverse-engineered models and enums as Morphe registry
+// files. The relation aliases of the returned definitions belong in the compile config.
+func PSQLToMorphe(config MorpheReverseConfig) (*Morphe, error) {
+	validateErr := config.Validate()
+	if validateErr != nil {
+		return nil, validateErr
+	}
+
+	definitions, parseErr := ParsePSQLFile(config.SQLFilePath)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	morphe, morpheErr := PSQLDefinitionsToMorphe(definitions)
+	if morpheErr != nil {
+		return nil, morpheErr
+	}
+
+	writeErr := WriteMorpheFiles(config.OutputDirPath, morphe)
+	if writeErr != nil {
+		return nil, writeErr
+	}
+
+	return morphe, nil
+}
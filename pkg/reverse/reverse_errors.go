@@ -0,0 +1,37 @@
+package reverse
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNoSQLFilePath = errors.New("sql file path cannot be empty")
+var ErrNoOutputDirPath = errors.New("output directory path cannot be empty")
+
+func ErrUnterminatedSQL(line int, what string) error {
+	return fmt.Errorf("line %d: unterminated %s", line, what)
+}
+
+func ErrUnexpectedSQLToken(line int, expected string, found string) error {
+	return fmt.Errorf("line %d: expected %s, found '%s'", line, expected, found)
+}
+
+func ErrUnexpectedSQLEnd(line int, expected string) error {
+	return fmt.Errorf("line %d: expected %s, found end of statement", line, expected)
+}
+
+func ErrUnknownTable(tableName string) error {
+	return fmt.Errorf("table '%s' is not defined", tableName)
+}
+
+func ErrUnknownColumn(tableName string, columnName string) error {
+	return fmt.Errorf("table '%s' has no column '%s'", tableName, columnName)
+}
+
+func ErrUnsupportedColumnType(tableName string, columnName string, typeSyntax string) error {
+	return fmt.Errorf("column '%s' of table '%s' has type '%s' without a morphe field type", columnName, tableName, typeSyntax)
+}
+
+func ErrNoModelIdentifiers(tableName string) error {
+	return fmt.Errorf("table '%s' has neither a primary key nor unique columns to identify its model", tableName)
+}
@@ -0,0 +1,148 @@
+package reverse_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kalo-build/go-util/assertfile"
+	"github.com/kalo-build/morphe-go/pkg/yaml"
+	"github.com/kalo-build/plugin-morphe-psql-types/internal/testutils"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/reverse"
+)
+
+type ReverseTestSuite struct {
+	assertfile.FileSuite
+
+	TestDirPath            string
+	TestGroundTruthDirPath string
+}
+
+func TestReverseTestSuite(t *testing.T) {
+	suite.Run(t, new(ReverseTestSuite))
+}
+
+func (suite *ReverseTestSuite) SetupTest() {
+	suite.TestDirPath = testutils.GetTestDirPath()
+	suite.TestGroundTruthDirPath = filepath.Join(suite.TestDirPath, "ground-truth", "reverse-legacy")
+}
+
+func (suite *ReverseTestSuite) TearDownTest() {
+	suite.TestDirPath = ""
+}
+
+func (suite *ReverseTestSuite) TestPSQLToMorphe() {
+	workingDirPath := suite.TestDirPath + "/working"
+	defer os.RemoveAll(workingDirPath)
+
+	config := reverse.MorpheReverseConfig{
+		SQLFilePath:   filepath.Join(suite.TestDirPath, "sql", "legacy_pg_dump.sql"),
+		OutputDirPath: workingDirPath,
+	}
+
+	morphe, reverseErr := reverse.PSQLToMorphe(config)
+
+	suite.Nil(reverseErr)
+	suite.NotNil(morphe)
+	suite.Len(morphe.Models, 4)
+	suite.Len(morphe.Enums, 2)
+	suite.Equal(map[string]map[string]string{
+		"Person": {
+			"Manager": "Person",
+		},
+	}, morphe.RelationAliases)
+
+	for _, filePath := range []string{
+		"models/company.mod",
+		"models/contact-info.mod",
+		"models/person.mod",
+		"models/tag.mod",
+		"enums/account-status.enum",
+		"enums/nationality.enum",
+	} {
+		suite.FileEquals(
+			filepath.Join(workingDirPath, filePath),
+			filepath.Join(suite.TestGroundTruthDirPath, filePath),
+		)
+	}
+}
+
+func (suite *ReverseTestSuite) TestPSQLToMorphe_NoSQLFilePath() {
+	config := reverse.MorpheReverseConfig{
+		OutputDirPath: suite.TestDirPath + "/working",
+	}
+
+	morphe, reverseErr := reverse.PSQLToMorphe(config)
+
+	suite.Nil(morphe)
+	suite.ErrorIs(reverseErr, reverse.ErrNoSQLFilePath)
+}
+
+func (suite *ReverseTestSuite) TestPSQLDefinitionsToMorphe_CompositeJunction() {
+	definitions, parseErr := reverse.ParsePSQL(`
+CREATE TABLE students (id UUID PRIMARY KEY, email TEXT NOT NULL UNIQUE);
+CREATE TABLE courses (id SERIAL PRIMARY KEY, title TEXT NOT NULL);
+CREATE TABLE student_courses (
+	student_id UUID NOT NULL REFERENCES students (id),
+	course_id INTEGER NOT NULL REFERENCES courses (id),
+	PRIMARY KEY (student_id, course_id)
+);
+`)
+	suite.Nil(parseErr)
+
+	morphe, morpheErr := reverse.PSQLDefinitionsToMorphe(definitions)
+
+	suite.Nil(morpheErr)
+	suite.Equal(map[string]yaml.Model{
+		"Student": {
+			Name: "Student",
+			Fields: map[string]yaml.ModelField{
+				"ID":    {Type: yaml.ModelFieldTypeUUID, Attributes: []string{"mandatory"}},
+				"Email": {Type: yaml.ModelFieldTypeString, Attributes: []string{"mandatory"}},
+			},
+			Identifiers: map[string]yaml.ModelIdentifier{
+				"primary": {Fields: []string{"ID"}},
+				"email":   {Fields: []string{"Email"}},
+			},
+			Related: map[string]yaml.ModelRelation{
+				"Course": {Type: "ForMany"},
+			},
+		},
+		"Course": {
+			Name: "Course",
+			Fields: map[string]yaml.ModelField{
+				"ID":    {Type: yaml.ModelFieldTypeAutoIncrement, Attributes: []string{"mandatory"}},
+				"Title": {Type: yaml.ModelFieldTypeString, Attributes: []string{"mandatory"}},
+			},
+			Identifiers: map[string]yaml.ModelIdentifier{
+				"primary": {Fields: []string{"ID"}},
+			},
+			Related: map[string]yaml.ModelRelation{
+				"Student": {Type: "HasMany"},
+			},
+		},
+	}, morphe.Models)
+	suite.Empty(morphe.RelationAliases)
+}
+
+func (suite *ReverseTestSuite) TestPSQLDefinitionsToMorphe_UnsupportedColumnType() {
+	definitions, parseErr := reverse.ParsePSQL("CREATE TABLE documents (id SERIAL PRIMARY KEY, body JSONB);")
+	suite.Nil(parseErr)
+
+	morphe, morpheErr := reverse.PSQLDefinitionsToMorphe(definitions)
+
+	suite.Nil(morphe)
+	suite.EqualError(morpheErr, "column 'body' of table 'documents' has type 'JSONB' without a morphe field type")
+}
+
+func (suite *ReverseTestSuite) TestPSQLDefinitionsToMorphe_NoModelIdentifiers() {
+	definitions, parseErr := reverse.ParsePSQL("CREATE TABLE audit_logs (message TEXT);")
+	suite.Nil(parseErr)
+
+	morphe, morpheErr := reverse.PSQLDefinitionsToMorphe(definitions)
+
+	suite.Nil(morphe)
+	suite.EqualError(morpheErr, "table 'audit_logs' has neither a primary key nor unique columns to identify its model")
+}
@@ -0,0 +1,231 @@
+package reverse_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	rcfg "github.com/kalo-build/morphe-go/pkg/registry/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/internal/testutils"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/compile/cfg"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/migrate"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/psqldef"
+	"github.com/kalo-build/plugin-morphe-psql-types/pkg/reverse"
+)
+
+type RoundTripTestSuite struct {
+	suite.Suite
+
+	TestDirPath    string
+	WorkingDirPath string
+}
+
+func TestRoundTripTestSuite(t *testing.T) {
+	suite.Run(t, new(RoundTripTestSuite))
+}
+
+func (suite *RoundTripTestSuite) SetupTest() {
+	suite.TestDirPath = testutils.GetTestDirPath()
+	suite.WorkingDirPath = filepath.Join(suite.TestDirPath, "working")
+}
+
+func (suite *RoundTripTestSuite) TearDownTest() {
+	os.RemoveAll(suite.WorkingDirPath)
+	suite.TestDirPath = ""
+	suite.WorkingDirPath = ""
+}
+
+func (suite *RoundTripTestSuite) compileSnapshot(morpheConfig cfg.MorpheConfig) migrate.Snapshot {
+	registryDirPath := filepath.Join(suite.TestDirPath, "registry", "minimal")
+	r, rErr := registry.LoadMorpheRegistry(registry.LoadMorpheRegistryHooks{}, rcfg.MorpheLoadRegistryConfig{
+		RegistryEnumsDirPath:      filepath.Join(registryDirPath, "enums"),
+		RegistryStructuresDirPath: filepath.Join(registryDirPath, "structures"),
+		RegistryModelsDirPath:     filepath.Join(registryDirPath, "models"),
+		RegistryEntitiesDirPath:   filepath.Join(registryDirPath, "entities"),
+	})
+	suite.Require().Nil(rErr)
+
+	snapshot, snapshotErr := migrate.CompileSnapshot(compile.MorpheCompileConfig{MorpheConfig: morpheConfig}, r)
+	suite.Require().Nil(snapshotErr)
+	return snapshot
+}
+
+func (suite *RoundTripTestSuite) assertTableRoundTrip(table *psqldef.Table) {
+	tableWriter := &compile.MorpheTableFileWriter{
+		TargetDirPath: suite.WorkingDirPath,
+	}
+	tableContents, writeErr := tableWriter.WriteTable(table)
+	suite.Require().Nil(writeErr)
+
+	definitions, parseErr := reverse.ParsePSQL(string(tableContents))
+	suite.Require().Nil(parseErr)
+	suite.Require().Len(definitions.Tables, 1, table.Name)
+	suite.Equal(reverse.NormalizeTable(*table), reverse.NormalizeTable(*definitions.Tables[0]))
+}
+
+func (suite *RoundTripTestSuite) assertViewRoundTrip(view *psqldef.View) {
+	viewWriter := &compile.MorpheViewFileWriter{
+		TargetDirPath: suite.WorkingDirPath,
+	}
+	viewContents, writeErr := viewWriter.WriteView(view)
+	suite.Require().Nil(writeErr)
+
+	definitions, parseErr := reverse.ParsePSQL(string(viewContents))
+	suite.Require().Nil(parseErr)
+	suite.Require().Len(definitions.Views, 1, view.Name)
+	suite.Equal(reverse.NormalizeView(*view), reverse.NormalizeView(*definitions.Views[0]))
+}
+
+func (suite *RoundTripTestSuite) TestRoundTrip_CompiledRegistry() {
+	snapshot := suite.compileSnapshot(cfg.DefaultMorpheConfig())
+
+	suite.NotEmpty(snapshot.Tables)
+	for _, table := range snapshot.Tables {
+		suite.assertTableRoundTrip(table)
+	}
+	suite.NotEmpty(snapshot.Views)
+	for _, view := range snapshot.Views {
+		suite.assertViewRoundTrip(view)
+	}
+}
+
+func (suite *RoundTripTestSuite) TestRoundTrip_CompiledRegistryNativeEnums() {
+	morpheConfig := cfg.DefaultMorpheConfig()
+	morpheConfig.MorpheEnumsConfig.Strategy = cfg.EnumStrategyNativeEnum
+	snapshot := suite.compileSnapshot(morpheConfig)
+
+	for _, table := range snapshot.Tables {
+		suite.assertTableRoundTrip(table)
+	}
+}
+
+func (suite *RoundTripTestSuite) TestRoundTrip_Table() {
+	suite.assertTableRoundTrip(&psqldef.Table{
+		Schema: "shop",
+		Name:   "order_items",
+		Columns: []psqldef.TableColumn{
+			{Name: "order_id", Type: psqldef.PSQLTypeInteger, NotNull: true, PrimaryKey: true},
+			{Name: "line", Type: psqldef.PSQLTypeInteger, NotNull: true, PrimaryKey: true},
+			{Name: "sku", Type: psqldef.PSQLTypeText, NotNull: true},
+			{Name: "quantity", Type: psqldef.PSQLTypeInteger, NotNull: true, Default: "1"},
+			{Name: "price", Type: psqldef.PSQLTypeDoublePrecision},
+			{Name: "created_at", Type: psqldef.PSQLTypeTimestampTZ, Default: "now()"},
+		},
+		Indices: []psqldef.Index{
+			{Name: "idx_order_items_sku", TableName: "order_items", Columns: []string{"sku"}, Using: "hash"},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{
+				Schema:            "shop",
+				Name:              "fk_order_items_order_id",
+				TableName:         "order_items",
+				ColumnNames:       []string{"order_id"},
+				RefSchema:         "shop",
+				RefTableName:      "orders",
+				RefColumnNames:    []string{"id"},
+				OnDelete:          "CASCADE",
+				OnUpdate:          "RESTRICT",
+				Deferrable:        true,
+				InitiallyDeferred: true,
+			},
+		},
+		UniqueConstraints: []psqldef.UniqueConstraint{
+			{Name: "uk_order_items_order_id_sku", TableName: "order_items", ColumnNames: []string{"order_id", "sku"}},
+		},
+		CheckConstraints: []psqldef.CheckConstraint{
+			{Name: "chk_order_items_quantity", TableName: "order_items", Expression: "quantity > 0"},
+		},
+		SeedData: []psqldef.InsertStatement{
+			{
+				Schema:    "shop",
+				TableName: "order_items",
+				Columns:   []string{"order_id", "line", "sku", "quantity", "price", "created_at"},
+				Values: [][]any{
+					{1, 1, "A'1", 2, 9.5, "2024-01-01T00:00:00Z"},
+					{1, 2, "B-2", 1, nil, nil},
+				},
+			},
+		},
+		Triggers: []psqldef.Trigger{
+			{
+				Schema:       "shop",
+				Name:         "trg_order_items_sku_immutable",
+				TableName:    "order_items",
+				Timing:       "BEFORE",
+				Events:       []string{"UPDATE"},
+				FunctionName: "fn_order_items_sku_immutable",
+				FunctionBody: []string{
+					`IF NEW."sku" IS DISTINCT FROM OLD."sku" THEN`,
+					"\tRAISE EXCEPTION 'column sku of table order_items is immutable';",
+					"END IF;",
+					"RETURN NEW;",
+				},
+			},
+		},
+	})
+}
+
+func (suite *RoundTripTestSuite) TestRoundTrip_Table_UnnamedConstraints() {
+	suite.assertTableRoundTrip(&psqldef.Table{
+		Schema: "shop",
+		Name:   "orders",
+		Columns: []psqldef.TableColumn{
+			{Name: "id", Type: psqldef.PSQLTypeSerial, PrimaryKey: true},
+			{Name: "code", Type: psqldef.PSQLTypeText},
+			{Name: "total", Type: psqldef.PSQLTypeInteger},
+			{Name: "customer_id", Type: psqldef.PSQLTypeInteger},
+		},
+		ForeignKeys: []psqldef.ForeignKey{
+			{TableName: "orders", ColumnNames: []string{"customer_id"}, RefTableName: "customers", RefColumnNames: []string{"id"}},
+		},
+		UniqueConstraints: []psqldef.UniqueConstraint{
+			{TableName: "orders", ColumnNames: []string{"code"}},
+		},
+		CheckConstraints: []psqldef.CheckConstraint{
+			{TableName: "orders", Expression: "total >= 0"},
+		},
+	})
+}
+
+func (suite *RoundTripTestSuite) TestRoundTrip_MaterializedView() {
+	suite.assertViewRoundTrip(&psqldef.View{
+		Schema: "public",
+		Name:   "person_entities",
+		Columns: []psqldef.ViewColumn{
+			{Name: "id", SourceRef: "people.id"},
+			{Name: "email", SourceRef: "contact_infos.email", Alias: "email"},
+			{Name: "manager_name", SourceRef: "managers.last_name"},
+		},
+		FromTable: "people",
+		Joins: []psqldef.JoinClause{
+			{
+				Type:  "LEFT",
+				Table: "contact_infos",
+				Alias: "contact_infos",
+				Conditions: []psqldef.JoinCondition{
+					{LeftRef: "people.id", RightRef: "contact_infos.person_id"},
+				},
+			},
+			{
+				Type:  "LEFT",
+				Table: "people",
+				Alias: "managers",
+				Conditions: []psqldef.JoinCondition{
+					{LeftRef: "people.manager_id", RightRef: "managers.id"},
+					{LeftRef: "managers.is_active", RightRef: "true"},
+				},
+			},
+		},
+		WhereClause:  "people.is_active = true",
+		Materialized: true,
+		WithNoData:   true,
+		Indices: []psqldef.Index{
+			{Name: "uidx_person_entities_id", TableName: "person_entities", Columns: []string{"id"}, IsUnique: true},
+		},
+		RefreshFunctionName: "fn_refresh_person_entities",
+	})
+}
@@ -0,0 +1,235 @@
+package reverse
+
+import (
+	"strings"
+)
+
+// sqlParser walks the tokens of a single statement
+type sqlParser struct {
+	statement sqlStatement
+	pos       int
+}
+
+func newSQLParser(statement sqlStatement) *sqlParser {
+	return &sqlParser{statement: statement}
+}
+
+func (p *sqlParser) isDone() bool {
+	return p.pos >= len(p.statement.Tokens)
+}
+
+func (p *sqlParser) peek() (sqlToken, bool) {
+	if p.isDone() {
+		return sqlToken{}, false
+	}
+	return p.statement.Tokens[p.pos], true
+}
+
+// getLine returns the line of the current token, or of the last token once all tokens are consumed
+func (p *sqlParser) getLine() int {
+	tokens := p.statement.Tokens
+	if len(tokens) == 0 {
+		return 0
+	}
+	if p.isDone() {
+		return tokens[len(tokens)-1].Line
+	}
+	return tokens[p.pos].Line
+}
+
+// isKeyword returns true if the next tokens are the given keywords, compared case-insensitively
+func (p *sqlParser) isKeyword(keywords ...string) bool {
+	for keywordIdx, keyword := range keywords {
+		tokenIdx := p.pos + keywordIdx
+		if tokenIdx >= len(p.statement.Tokens) || !isWordToken(p.statement.Tokens[tokenIdx], keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptKeyword consumes the given keywords if they are next
+func (p *sqlParser) acceptKeyword(keywords ...string) bool {
+	if !p.isKeyword(keywords...) {
+		return false
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+func (p *sqlParser) expectKeyword(keywords ...string) error {
+	if !p.acceptKeyword(keywords...) {
+		return p.errUnexpected(strings.ToUpper(strings.Join(keywords, " ")))
+	}
+	return nil
+}
+
+func (p *sqlParser) isSymbol(symbol string) bool {
+	token, found := p.peek()
+	return found && isSymbolToken(token, symbol)
+}
+
+func (p *sqlParser) acceptSymbol(symbol string) bool {
+	if !p.isSymbol(symbol) {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *sqlParser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.errUnexpected("'" + symbol + "'")
+	}
+	return nil
+}
+
+func (p *sqlParser) errUnexpected(expected string) error {
+	token, found := p.peek()
+	if !found {
+		return ErrUnexpectedSQLEnd(p.getLine(), expected)
+	}
+	return ErrUnexpectedSQLToken(token.Line, expected, p.statement.Source[token.Start:token.End])
+}
+
+// parseIdentifier returns the next identifier, folding unquoted identifiers to lower case like PostgreSQL does
+func (p *sqlParser) parseIdentifier() (string, error) {
+	token, found := p.peek()
+	if !found || (token.Kind != sqlTokenWord && token.Kind != sqlTokenQuotedIdentifier) {
+		return "", p.errUnexpected("identifier")
+	}
+	p.pos++
+	return getIdentifierFromToken(token), nil
+}
+
+// parseQualifiedName returns the schema and name of a [schema.]name reference
+func (p *sqlParser) parseQualifiedName() (string, string, error) {
+	name, nameErr := p.parseIdentifier()
+	if nameErr != nil {
+		return "", "", nameErr
+	}
+	if !p.acceptSymbol(".") {
+		return "", name, nil
+	}
+	qualifiedName, qualifiedNameErr := p.parseIdentifier()
+	if qualifiedNameErr != nil {
+		return "", "", qualifiedNameErr
+	}
+	return name, qualifiedName, nil
+}
+
+// parseIdentifierList parses a parenthesized, comma separated identifier list
+func (p *sqlParser) parseIdentifierList() ([]string, error) {
+	openErr := p.expectSymbol("(")
+	if openErr != nil {
+		return nil, openErr
+	}
+	identifiers := []string{}
+	for {
+		identifier, identifierErr := p.parseIdentifier()
+		if identifierErr != nil {
+			return nil, identifierErr
+		}
+		identifiers = append(identifiers, identifier)
+		if p.acceptSymbol(")") {
+			return identifiers, nil
+		}
+		commaErr := p.expectSymbol(",")
+		if commaErr != nil {
+			return nil, commaErr
+		}
+	}
+}
+
+// skipExpression consumes tokens up to a top-level comma, an unmatched closing parenthesis or one of the stop
+// keywords and returns the token index range it consumed
+func (p *sqlParser) skipExpression(stopKeywords ...string) (int, int) {
+	start := p.pos
+	depth := 0
+	for !p.isDone() {
+		token := p.statement.Tokens[p.pos]
+		if depth == 0 && isSymbolToken(token, ",") {
+			break
+		}
+		if depth == 0 && isSymbolToken(token, ")") {
+			break
+		}
+		if depth == 0 && token.Kind == sqlTokenWord && p.isAnyKeyword(stopKeywords) {
+			break
+		}
+		if isSymbolToken(token, "(") || isSymbolToken(token, "[") {
+			depth++
+		}
+		if isSymbolToken(token, ")") || isSymbolToken(token, "]") {
+			depth--
+		}
+		p.pos++
+	}
+	return start, p.pos
+}
+
+// parseExpression consumes an expression like skipExpression and returns its raw text
+func (p *sqlParser) parseExpression(stopKeywords ...string) string {
+	start, end := p.skipExpression(stopKeywords...)
+	return p.statement.getRawText(start, end)
+}
+
+// parseParenthesizedExpression consumes a parenthesized expression and returns the raw text inside the parentheses
+func (p *sqlParser) parseParenthesizedExpression() (string, error) {
+	openErr := p.expectSymbol("(")
+	if openErr != nil {
+		return "", openErr
+	}
+	start := p.pos
+	depth := 0
+	for !p.isDone() {
+		token := p.statement.Tokens[p.pos]
+		if isSymbolToken(token, ")") && depth == 0 {
+			p.pos++
+			return p.statement.getRawText(start, p.pos-1), nil
+		}
+		if isSymbolToken(token, "(") {
+			depth++
+		}
+		if isSymbolToken(token, ")") {
+			depth--
+		}
+		p.pos++
+	}
+	return "", p.errUnexpected("')'")
+}
+
+// skipParenthesized consumes a parenthesized token group if it is next
+func (p *sqlParser) skipParenthesized() error {
+	if !p.isSymbol("(") {
+		return nil
+	}
+	_, parenthesizedErr := p.parseParenthesizedExpression()
+	return parenthesizedErr
+}
+
+func (p *sqlParser) isAnyKeyword(keywords []string) bool {
+	for _, keyword := range keywords {
+		if p.isKeyword(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func getIdentifierFromToken(token sqlToken) string {
+	if token.Kind == sqlTokenQuotedIdentifier {
+		return token.Text
+	}
+	return strings.ToLower(token.Text)
+}
+
+// parseStringLiteral returns the content of the next string constant
+func (p *sqlParser) parseStringLiteral() (string, error) {
+	token, found := p.peek()
+	if !found || token.Kind != sqlTokenString {
+		return "", p.errUnexpected("string")
+	}
+	p.pos++
+	return token.Text, nil
+}
@@ -0,0 +1,361 @@
+package reverse
+
+import (
+	"strings"
+	"unicode"
+)
+
+type sqlTokenKind int
+
+const (
+	// sqlTokenWord is a keyword or an unquoted identifier
+	sqlTokenWord sqlTokenKind = iota
+	sqlTokenQuotedIdentifier
+	sqlTokenString
+	sqlTokenNumber
+	sqlTokenSymbol
+)
+
+// sqlToken is a single token of a SQL statement
+type sqlToken struct {
+	Kind sqlTokenKind
+	// Text holds the unquoted content of quoted identifiers and strings
+	Text string
+	// Start and End are the byte offsets of the raw token in the scanned SQL
+	Start int
+	End   int
+	Line  int
+}
+
+// sqlStatement holds the tokens of a single statement, without its terminating semicolon
+type sqlStatement struct {
+	Source string
+	Tokens []sqlToken
+	// CopyData holds the raw data lines following a COPY ... FROM stdin statement
+	CopyData []string
+}
+
+// getRawText returns the SQL text spanning the tokens from index start up to, excluding, index end
+func (s sqlStatement) getRawText(start int, end int) string {
+	if start >= end || start >= len(s.Tokens) {
+		return ""
+	}
+	return s.Source[s.Tokens[start].Start:s.Tokens[end-1].End]
+}
+
+// isCopyFromStdin returns true for COPY statements whose data follows the statement
+func (s sqlStatement) isCopyFromStdin() bool {
+	tokenCount := len(s.Tokens)
+	if tokenCount < 3 || !isWordToken(s.Tokens[0], "copy") {
+		return false
+	}
+	return isWordToken(s.Tokens[tokenCount-2], "from") && isWordToken(s.Tokens[tokenCount-1], "stdin")
+}
+
+// splitSQLStatements tokenizes SQL into semicolon-terminated statements, skipping comments.
+//
+// Quoted identifiers, string constants (including escape and dollar-quoted strings) and the data block of
+// COPY ... FROM stdin statements are kept intact, so semicolons inside function bodies do not split statements.
+func splitSQLStatements(sql string) ([]sqlStatement, error) {
+	scanner := sqlScanner{source: sql, line: 1}
+	statements := []sqlStatement{}
+	statement := sqlStatement{Source: sql}
+	for {
+		token, found, scanErr := scanner.nextToken()
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		if !found {
+			break
+		}
+		if token.Kind != sqlTokenSymbol || token.Text != ";" {
+			statement.Tokens = append(statement.Tokens, token)
+			continue
+		}
+		if len(statement.Tokens) == 0 {
+			continue
+		}
+		if statement.isCopyFromStdin() {
+			copyData, copyErr := scanner.readCopyData()
+			if copyErr != nil {
+				return nil, copyErr
+			}
+			statement.CopyData = copyData
+		}
+		statements = append(statements, statement)
+		statement = sqlStatement{Source: sql}
+	}
+	if len(statement.Tokens) > 0 {
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+type sqlScanner struct {
+	source string
+	pos    int
+	line   int
+}
+
+func (s *sqlScanner) nextToken() (sqlToken, bool, error) {
+	skipErr := s.skipWhitespaceAndComments()
+	if skipErr != nil {
+		return sqlToken{}, false, skipErr
+	}
+	if s.pos >= len(s.source) {
+		return sqlToken{}, false, nil
+	}
+
+	start := s.pos
+	line := s.line
+	char := s.source[s.pos]
+	switch {
+	case (char == 'E' || char == 'e') && s.peekByte(1) == '\'':
+		s.pos++
+		text, stringErr := s.readQuoted('\'', true)
+		if stringErr != nil {
+			return sqlToken{}, false, stringErr
+		}
+		return s.newToken(sqlTokenString, text, start, line), true, nil
+	case char == '\'':
+		text, stringErr := s.readQuoted('\'', false)
+		if stringErr != nil {
+			return sqlToken{}, false, stringErr
+		}
+		return s.newToken(sqlTokenString, text, start, line), true, nil
+	case char == '"':
+		text, identifierErr := s.readQuoted('"', false)
+		if identifierErr != nil {
+			return sqlToken{}, false, identifierErr
+		}
+		return s.newToken(sqlTokenQuotedIdentifier, text, start, line), true, nil
+	case char == '$' && s.isDollarQuoteStart():
+		text, dollarErr := s.readDollarQuoted()
+		if dollarErr != nil {
+			return sqlToken{}, false, dollarErr
+		}
+		return s.newToken(sqlTokenString, text, start, line), true, nil
+	case isDigit(char):
+		s.readNumber()
+		return s.newToken(sqlTokenNumber, s.source[start:s.pos], start, line), true, nil
+	case isWordStart(rune(char)):
+		s.readWord()
+		return s.newToken(sqlTokenWord, s.source[start:s.pos], start, line), true, nil
+	}
+
+	for _, symbol := range []string{"::", "<>", "<=", ">=", "!=", "||"} {
+		if strings.HasPrefix(s.source[s.pos:], symbol) {
+			s.pos += len(symbol)
+			return s.newToken(sqlTokenSymbol, symbol, start, line), true, nil
+		}
+	}
+	s.pos++
+	return s.newToken(sqlTokenSymbol, string(char), start, line), true, nil
+}
+
+func (s *sqlScanner) newToken(kind sqlTokenKind, text string, start int, line int) sqlToken {
+	return sqlToken{
+		Kind:  kind,
+		Text:  text,
+		Start: start,
+		End:   s.pos,
+		Line:  line,
+	}
+}
+
+func (s *sqlScanner) peekByte(offset int) byte {
+	if s.pos+offset >= len(s.source) {
+		return 0
+	}
+	return s.source[s.pos+offset]
+}
+
+func (s *sqlScanner) advance() {
+	if s.source[s.pos] == '\n' {
+		s.line++
+	}
+	s.pos++
+}
+
+func (s *sqlScanner) skipWhitespaceAndComments() error {
+	for s.pos < len(s.source) {
+		char := s.source[s.pos]
+		switch {
+		case unicode.IsSpace(rune(char)):
+			s.advance()
+		case char == '-' && s.peekByte(1) == '-':
+			for s.pos < len(s.source) && s.source[s.pos] != '\n' {
+				s.pos++
+			}
+		case char == '/' && s.peekByte(1) == '*':
+			commentErr := s.skipBlockComment()
+			if commentErr != nil {
+				return commentErr
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// skipBlockComment skips a possibly nested /* */ comment
+func (s *sqlScanner) skipBlockComment() error {
+	line := s.line
+	depth := 0
+	for s.pos < len(s.source) {
+		switch {
+		case s.source[s.pos] == '/' && s.peekByte(1) == '*':
+			depth++
+			s.pos += 2
+		case s.source[s.pos] == '*' && s.peekByte(1) == '/':
+			depth--
+			s.pos += 2
+			if depth == 0 {
+				return nil
+			}
+		default:
+			s.advance()
+		}
+	}
+	return ErrUnterminatedSQL(line, "comment")
+}
+
+// readQuoted reads a quoted string or identifier, where a doubled quote escapes the quote
+func (s *sqlScanner) readQuoted(quote byte, backslashEscapes bool) (string, error) {
+	line := s.line
+	s.pos++
+	text := strings.Builder{}
+	for s.pos < len(s.source) {
+		char := s.source[s.pos]
+		switch {
+		case backslashEscapes && char == '\\' && s.pos+1 < len(s.source):
+			text.WriteByte(unescapeSQLByte(s.source[s.pos+1]))
+			s.pos += 2
+		case char == quote && s.peekByte(1) == quote:
+			text.WriteByte(quote)
+			s.pos += 2
+		case char == quote:
+			s.pos++
+			return text.String(), nil
+		default:
+			text.WriteByte(char)
+			s.advance()
+		}
+	}
+	if quote == '"' {
+		return "", ErrUnterminatedSQL(line, "quoted identifier")
+	}
+	return "", ErrUnterminatedSQL(line, "string")
+}
+
+func (s *sqlScanner) isDollarQuoteStart() bool {
+	for offset := 1; s.pos+offset < len(s.source); offset++ {
+		char := rune(s.source[s.pos+offset])
+		if char == '$' {
+			return true
+		}
+		if !isWordStart(char) && !(offset > 1 && unicode.IsDigit(char)) {
+			return false
+		}
+	}
+	return false
+}
+
+// readDollarQuoted reads a $tag$ ... $tag$ string, as used for function bodies
+func (s *sqlScanner) readDollarQuoted() (string, error) {
+	line := s.line
+	tagEnd := strings.IndexByte(s.source[s.pos+1:], '$') + s.pos + 2
+	tag := s.source[s.pos:tagEnd]
+	bodyEnd := strings.Index(s.source[tagEnd:], tag)
+	if bodyEnd < 0 {
+		return "", ErrUnterminatedSQL(line, "dollar-quoted string")
+	}
+	text := s.source[tagEnd : tagEnd+bodyEnd]
+	for s.pos < tagEnd+bodyEnd+len(tag) {
+		s.advance()
+	}
+	return text, nil
+}
+
+func (s *sqlScanner) readNumber() {
+	for s.pos < len(s.source) {
+		char := s.source[s.pos]
+		isExponent := (char == 'e' || char == 'E') && (isDigit(s.peekByte(1)) || s.peekByte(1) == '-' || s.peekByte(1) == '+')
+		if !isDigit(char) && char != '.' && !isExponent {
+			return
+		}
+		if isExponent {
+			s.pos++
+		}
+		s.pos++
+	}
+}
+
+func (s *sqlScanner) readWord() {
+	for s.pos < len(s.source) {
+		char := rune(s.source[s.pos])
+		if !isWordStart(char) && !unicode.IsDigit(char) && char != '$' {
+			return
+		}
+		s.pos++
+	}
+}
+
+// readCopyData reads the data lines of a COPY ... FROM stdin statement up to its terminating \. line
+func (s *sqlScanner) readCopyData() ([]string, error) {
+	line := s.line
+	// The data starts on the line following the statement
+	for s.pos < len(s.source) && s.source[s.pos] != '\n' {
+		s.pos++
+	}
+	if s.pos < len(s.source) {
+		s.advance()
+	}
+
+	dataLines := []string{}
+	for s.pos < len(s.source) {
+		lineEnd := strings.IndexByte(s.source[s.pos:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(s.source) - s.pos
+		}
+		dataLine := strings.TrimSuffix(s.source[s.pos:s.pos+lineEnd], "\r")
+		s.pos += lineEnd
+		if s.pos < len(s.source) {
+			s.advance()
+		}
+		if dataLine == `\.` {
+			return dataLines, nil
+		}
+		dataLines = append(dataLines, dataLine)
+	}
+	return nil, ErrUnterminatedSQL(line, "COPY data")
+}
+
+func isWordToken(token sqlToken, word string) bool {
+	return token.Kind == sqlTokenWord && strings.EqualFold(token.Text, word)
+}
+
+func isSymbolToken(token sqlToken, symbol string) bool {
+	return token.Kind == sqlTokenSymbol && token.Text == symbol
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isWordStart(char rune) bool {
+	return char == '_' || unicode.IsLetter(char)
+}
+
+func unescapeSQLByte(char byte) byte {
+	switch char {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	}
+	return char
+}
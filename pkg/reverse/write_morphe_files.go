@@ -0,0 +1,157 @@
+package reverse
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/kalo-build/go-util/core"
+	"github.com/kalo-build/go-util/strcase"
+	"github.com/kalo-build/morphe-go/pkg/registry"
+	"github.com/kalo-build/morphe-go/pkg/yaml"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// WriteMorpheFiles writes the models and enums as registry files into the models/ and enums/ directories of dirPath
+func WriteMorpheFiles(dirPath string, morphe *Morphe) error {
+	for _, modelName := range core.MapKeysSorted(morphe.Models) {
+		modelNode := getModelNode(morphe.Models[modelName])
+		writeErr := writeMorpheFile(filepath.Join(dirPath, "models"), modelName, registry.ModelFileSuffix, modelNode)
+		if writeErr != nil {
+			return writeErr
+		}
+	}
+	for _, enumName := range core.MapKeysSorted(morphe.Enums) {
+		enumNode, enumNodeErr := getEnumNode(morphe.Enums[enumName])
+		if enumNodeErr != nil {
+			return enumNodeErr
+		}
+		writeErr := writeMorpheFile(filepath.Join(dirPath, "enums"), enumName, registry.EnumFileSuffix, enumNode)
+		if writeErr != nil {
+			return writeErr
+		}
+	}
+	return nil
+}
+
+func writeMorpheFile(dirPath string, definitionName string, fileSuffix string, node *yamlv3.Node) error {
+	fileContents := bytes.Buffer{}
+	encoder := yamlv3.NewEncoder(&fileContents)
+	encoder.SetIndent(2)
+	encodeErr := encoder.Encode(node)
+	if encodeErr != nil {
+		return encodeErr
+	}
+	closeErr := encoder.Close()
+	if closeErr != nil {
+		return closeErr
+	}
+
+	mkDirErr := os.MkdirAll(dirPath, 0755)
+	if mkDirErr != nil {
+		return mkDirErr
+	}
+	filePath := filepath.Join(dirPath, strcase.ToKebabCaseLower(definitionName)+fileSuffix)
+	return os.WriteFile(filePath, fileContents.Bytes(), 0644)
+}
+
+// getModelNode returns the YAML of a model, listing the primary identifier fields first and the other fields,
+// identifiers and relations by name
+func getModelNode(model yaml.Model) *yamlv3.Node {
+	primaryFieldNames := model.Identifiers["primary"].Fields
+	fieldNames := slices.Clone(primaryFieldNames)
+	for _, fieldName := range core.MapKeysSorted(model.Fields) {
+		if !slices.Contains(primaryFieldNames, fieldName) {
+			fieldNames = append(fieldNames, fieldName)
+		}
+	}
+
+	fieldsNode := newMappingNode()
+	for _, fieldName := range fieldNames {
+		field := model.Fields[fieldName]
+		fieldNode := newMappingNode()
+		addMappingEntry(fieldNode, "type", newScalarNode(string(field.Type)))
+		if len(field.Attributes) > 0 {
+			addMappingEntry(fieldNode, "attributes", newSequenceNode(field.Attributes))
+		}
+		addMappingEntry(fieldsNode, fieldName, fieldNode)
+	}
+
+	identifierNames := core.MapKeysSorted(model.Identifiers)
+	if slices.Contains(identifierNames, "primary") {
+		identifierNames = append([]string{"primary"}, slices.DeleteFunc(identifierNames, func(identifierName string) bool {
+			return identifierName == "primary"
+		})...)
+	}
+	identifiersNode := newMappingNode()
+	for _, identifierName := range identifierNames {
+		identifierFieldNames := model.Identifiers[identifierName].Fields
+		if len(identifierFieldNames) == 1 {
+			addMappingEntry(identifiersNode, identifierName, newScalarNode(identifierFieldNames[0]))
+			continue
+		}
+		addMappingEntry(identifiersNode, identifierName, newSequenceNode(identifierFieldNames))
+	}
+
+	modelNode := newMappingNode()
+	addMappingEntry(modelNode, "name", newScalarNode(model.Name))
+	addMappingEntry(modelNode, "fields", fieldsNode)
+	addMappingEntry(modelNode, "identifiers", identifiersNode)
+	if len(model.Related) > 0 {
+		relatedNode := newMappingNode()
+		for _, relationName := range core.MapKeysSorted(model.Related) {
+			relationNode := newMappingNode()
+			addMappingEntry(relationNode, "type", newScalarNode(model.Related[relationName].Type))
+			addMappingEntry(relatedNode, relationName, relationNode)
+		}
+		addMappingEntry(modelNode, "related", relatedNode)
+	}
+	return modelNode
+}
+
+func getEnumNode(enum yaml.Enum) (*yamlv3.Node, error) {
+	entriesNode := newMappingNode()
+	for _, entryName := range core.MapKeysSorted(enum.Entries) {
+		entryNode := &yamlv3.Node{}
+		encodeErr := entryNode.Encode(enum.Entries[entryName])
+		if encodeErr != nil {
+			return nil, encodeErr
+		}
+		addMappingEntry(entriesNode, entryName, entryNode)
+	}
+
+	enumNode := newMappingNode()
+	addMappingEntry(enumNode, "name", newScalarNode(enum.Name))
+	addMappingEntry(enumNode, "type", newScalarNode(string(enum.Type)))
+	addMappingEntry(enumNode, "entries", entriesNode)
+	return enumNode, nil
+}
+
+func newMappingNode() *yamlv3.Node {
+	return &yamlv3.Node{
+		Kind: yamlv3.MappingNode,
+	}
+}
+
+func newScalarNode(value string) *yamlv3.Node {
+	return &yamlv3.Node{
+		Kind:  yamlv3.ScalarNode,
+		Tag:   "!!str",
+		Value: value,
+	}
+}
+
+func newSequenceNode(values []string) *yamlv3.Node {
+	sequenceNode := &yamlv3.Node{
+		Kind: yamlv3.SequenceNode,
+	}
+	for _, value := range values {
+		sequenceNode.Content = append(sequenceNode.Content, newScalarNode(value))
+	}
+	return sequenceNode
+}
+
+func addMappingEntry(mappingNode *yamlv3.Node, key string, valueNode *yamlv3.Node) {
+	mappingNode.Content = append(mappingNode.Content, newScalarNode(key), valueNode)
+}
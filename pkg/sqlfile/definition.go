@@ -11,7 +11,7 @@ func WriteSQLDefinitionFile(dirPath string, definitionName string, psqlFileConte
 	definitionFileName := strcase.ToSnakeCaseLower(definitionName)
 	definitionFilePath := filepath.Join(dirPath, definitionFileName+".sql")
 	if _, readErr := os.ReadDir(dirPath); readErr != nil && os.IsNotExist(readErr) {
-		mkDirErr := os.MkdirAll(dirPath, 0644)
+		mkDirErr := os.MkdirAll(dirPath, 0755)
 		if mkDirErr != nil {
 			return nil, mkDirErr
 		}
@@ -10,3 +10,9 @@ var MorpheEnumEntryToPSQLEntryType = map[yaml.EnumType]psqldef.PSQLType{
 	yaml.EnumTypeInteger: psqldef.PSQLTypeInteger,
 	yaml.EnumTypeFloat:   psqldef.PSQLTypeDoublePrecision,
 }
+
+var MorpheEnumEntryToJSONType = map[yaml.EnumType]string{
+	yaml.EnumTypeString:  "string",
+	yaml.EnumTypeInteger: "number",
+	yaml.EnumTypeFloat:   "number",
+}
@@ -108,3 +108,16 @@ var MorpheStructureFieldToPSQLFieldBigSerialForeign = map[yaml.StructureFieldTyp
 	yaml.StructureFieldTypeProtected:     psqldef.PSQLTypeText,
 	yaml.StructureFieldTypeSealed:        psqldef.PSQLTypeText,
 }
+
+var MorpheStructureFieldToJSONType = map[yaml.StructureFieldType]string{
+	yaml.StructureFieldTypeUUID:          "string",
+	yaml.StructureFieldTypeAutoIncrement: "number",
+	yaml.StructureFieldTypeString:        "string",
+	yaml.StructureFieldTypeInteger:       "number",
+	yaml.StructureFieldTypeFloat:         "number",
+	yaml.StructureFieldTypeBoolean:       "boolean",
+	yaml.StructureFieldTypeTime:          "string",
+	yaml.StructureFieldTypeDate:          "string",
+	yaml.StructureFieldTypeProtected:     "string",
+	yaml.StructureFieldTypeSealed:        "string",
+}
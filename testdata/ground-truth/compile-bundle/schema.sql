@@ -0,0 +1,108 @@
+-- Schema bundle generated from Morphe definitions
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+-- Table definition for companies
+CREATE TABLE IF NOT EXISTS public.companies (
+	id SERIAL PRIMARY KEY,
+	name TEXT,
+	tax_id TEXT
+);
+
+-- Indices
+CREATE UNIQUE INDEX IF NOT EXISTS idx_companies_name ON public.companies ("name");
+
+-- Table definition for morphe_structures
+CREATE TABLE IF NOT EXISTS public.morphe_structures (
+	id SERIAL PRIMARY KEY,
+	"type" TEXT NOT NULL,
+	"data" JSONB NOT NULL,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	updated_at TIMESTAMPTZ DEFAULT NOW(),
+	CONSTRAINT chk_morphe_structures_address CHECK ("type" <> 'Address' OR ("data" ?& ARRAY['City', 'HouseNr', 'Street', 'ZipCode'] AND jsonb_typeof("data"->'City') IN ('string', 'null') AND jsonb_typeof("data"->'HouseNr') IN ('string', 'null') AND jsonb_typeof("data"->'Street') IN ('string', 'null') AND jsonb_typeof("data"->'ZipCode') IN ('string', 'null')))
+);
+
+-- Indices
+CREATE INDEX IF NOT EXISTS idx_morphe_structures_type ON public.morphe_structures ("type");
+CREATE INDEX IF NOT EXISTS idx_morphe_structures_data ON public.morphe_structures USING GIN ("data");
+
+-- Table definition for nationalities
+CREATE TABLE IF NOT EXISTS public.nationalities (
+	id SERIAL PRIMARY KEY,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	value_type TEXT NOT NULL,
+	CONSTRAINT uk_nationalities_key UNIQUE (key)
+);
+
+-- Seed Data
+INSERT INTO public.nationalities (key, value, value_type) VALUES ('DE', 'German', 'String');
+INSERT INTO public.nationalities (key, value, value_type) VALUES ('FR', 'French', 'String');
+INSERT INTO public.nationalities (key, value, value_type) VALUES ('US', 'American', 'String');
+
+-- Table definition for people
+CREATE TABLE IF NOT EXISTS public.people (
+	first_name TEXT,
+	id SERIAL PRIMARY KEY,
+	last_name TEXT,
+	nationality_id INTEGER NOT NULL,
+	company_id INTEGER NOT NULL,
+	CONSTRAINT fk_people_nationality_id FOREIGN KEY (nationality_id)
+		REFERENCES public.nationalities(id)
+		ON DELETE CASCADE,
+	CONSTRAINT fk_people_company_id FOREIGN KEY (company_id)
+		REFERENCES public.companies(id)
+		ON DELETE CASCADE
+);
+
+-- Indices
+CREATE INDEX IF NOT EXISTS idx_people_nationality_id ON public.people (nationality_id);
+CREATE INDEX IF NOT EXISTS idx_people_company_id ON public.people (company_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_people_first_name_last_name ON public.people (first_name, last_name);
+
+-- Table definition for contact_infos
+CREATE TABLE IF NOT EXISTS public.contact_infos (
+	email TEXT,
+	id SERIAL PRIMARY KEY,
+	person_id INTEGER NOT NULL,
+	CONSTRAINT fk_contact_infos_person_id FOREIGN KEY (person_id)
+		REFERENCES public.people(id)
+		ON DELETE CASCADE
+);
+
+-- Indices
+CREATE INDEX IF NOT EXISTS idx_contact_infos_person_id ON public.contact_infos (person_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contact_infos_email ON public.contact_infos (email);
+
+-- Table definition for universal_numbers
+CREATE TABLE IF NOT EXISTS public.universal_numbers (
+	id SERIAL PRIMARY KEY,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	value_type TEXT NOT NULL,
+	CONSTRAINT uk_universal_numbers_key UNIQUE (key)
+);
+
+-- Seed Data
+INSERT INTO public.universal_numbers (key, value, value_type) VALUES ('Euler', '2.7182818285', 'Float');
+INSERT INTO public.universal_numbers (key, value, value_type) VALUES ('Pi', '3.1415926535', 'Float');
+
+-- View definition for company_entities
+CREATE OR REPLACE VIEW public.company_entities AS
+SELECT
+	companies.id,
+	companies.name,
+	companies.tax_id
+FROM companies;
+
+-- View definition for person_entities
+CREATE OR REPLACE VIEW public.person_entities AS
+SELECT
+	contact_infos.email,
+	people.id,
+	people.last_name,
+	people.nationality
+FROM people
+LEFT JOIN contact_infos
+	ON people.id = contact_infos.person_id;
+
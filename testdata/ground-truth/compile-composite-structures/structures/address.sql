@@ -0,0 +1,11 @@
+-- Type definition for address
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+CREATE TYPE public.address AS (
+	city TEXT,
+	house_nr TEXT,
+	street TEXT,
+	zip_code TEXT
+);
+
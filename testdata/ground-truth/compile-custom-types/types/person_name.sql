@@ -0,0 +1,6 @@
+-- Type definition for person_name
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+CREATE DOMAIN public.person_name AS TEXT;
+
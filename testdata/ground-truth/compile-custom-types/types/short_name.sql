@@ -0,0 +1,6 @@
+-- Type definition for short_name
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+CREATE DOMAIN public.short_name AS public.person_name;
+
@@ -0,0 +1,33 @@
+-- Table definition for contracts
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+CREATE TABLE IF NOT EXISTS public.contracts (
+	id SERIAL PRIMARY KEY,
+	number TEXT NOT NULL,
+	signed_at TIMESTAMPTZ,
+	title TEXT NOT NULL
+);
+
+-- Indices
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contracts_number ON public.contracts (number);
+
+-- Triggers
+CREATE OR REPLACE FUNCTION public.fn_contracts_immutable()
+RETURNS TRIGGER AS $$
+BEGIN
+	IF NEW."number" IS DISTINCT FROM OLD."number" THEN
+		RAISE EXCEPTION 'column number of table contracts is immutable';
+	END IF;
+	IF NEW."signed_at" IS DISTINCT FROM OLD."signed_at" THEN
+		RAISE EXCEPTION 'column signed_at of table contracts is immutable';
+	END IF;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_contracts_immutable ON public.contracts;
+CREATE TRIGGER trg_contracts_immutable
+	BEFORE UPDATE ON public.contracts
+	FOR EACH ROW EXECUTE FUNCTION public.fn_contracts_immutable();
+
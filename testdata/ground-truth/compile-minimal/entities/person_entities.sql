@@ -10,5 +10,5 @@ SELECT
 	people.nationality
 FROM people
 LEFT JOIN contact_infos
-	ON people.id = contact_infos.id;
+	ON people.id = contact_infos.person_id;
 
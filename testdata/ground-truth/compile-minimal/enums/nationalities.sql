@@ -7,7 +7,7 @@ CREATE TABLE IF NOT EXISTS public.nationalities (
 	key TEXT NOT NULL,
 	value TEXT NOT NULL,
 	value_type TEXT NOT NULL,
-	UNIQUE (key)
+	CONSTRAINT uk_nationalities_key UNIQUE (key)
 );
 
 -- Seed Data
@@ -7,7 +7,7 @@ CREATE TABLE IF NOT EXISTS public.universal_numbers (
 	key TEXT NOT NULL,
 	value TEXT NOT NULL,
 	value_type TEXT NOT NULL,
-	UNIQUE (key)
+	CONSTRAINT uk_universal_numbers_key UNIQUE (key)
 );
 
 -- Seed Data
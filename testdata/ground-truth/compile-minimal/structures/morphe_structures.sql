@@ -7,7 +7,8 @@ CREATE TABLE IF NOT EXISTS public.morphe_structures (
 	"type" TEXT NOT NULL,
 	"data" JSONB NOT NULL,
 	created_at TIMESTAMPTZ DEFAULT NOW(),
-	updated_at TIMESTAMPTZ DEFAULT NOW()
+	updated_at TIMESTAMPTZ DEFAULT NOW(),
+	CONSTRAINT chk_morphe_structures_address CHECK ("type" <> 'Address' OR ("data" ?& ARRAY['City', 'HouseNr', 'Street', 'ZipCode'] AND jsonb_typeof("data"->'City') IN ('string', 'null') AND jsonb_typeof("data"->'HouseNr') IN ('string', 'null') AND jsonb_typeof("data"->'Street') IN ('string', 'null') AND jsonb_typeof("data"->'ZipCode') IN ('string', 'null')))
 );
 
 -- Indices
@@ -0,0 +1,10 @@
+-- Type definition for nationality
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+CREATE TYPE public.nationality AS ENUM (
+	'DE',
+	'FR',
+	'US'
+);
+
@@ -0,0 +1,9 @@
+-- Type definition for universal_number
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+CREATE TYPE public.universal_number AS ENUM (
+	'Euler',
+	'Pi'
+);
+
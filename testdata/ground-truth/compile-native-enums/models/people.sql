@@ -0,0 +1,19 @@
+-- Table definition for people
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+CREATE TABLE IF NOT EXISTS public.people (
+	first_name TEXT,
+	id SERIAL PRIMARY KEY,
+	last_name TEXT,
+	nationality public.nationality NOT NULL,
+	company_id INTEGER NOT NULL,
+	CONSTRAINT fk_people_company_id FOREIGN KEY (company_id)
+		REFERENCES public.companies(id)
+		ON DELETE CASCADE
+);
+
+-- Indices
+CREATE INDEX IF NOT EXISTS idx_people_company_id ON public.people (company_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_people_first_name_last_name ON public.people (first_name, last_name);
+
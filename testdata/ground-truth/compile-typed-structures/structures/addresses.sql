@@ -0,0 +1,14 @@
+-- Table definition for addresses
+
+CREATE SCHEMA IF NOT EXISTS public;
+
+CREATE TABLE IF NOT EXISTS public.addresses (
+	id SERIAL PRIMARY KEY,
+	city TEXT,
+	house_nr TEXT,
+	street TEXT,
+	zip_code TEXT,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
@@ -0,0 +1,41 @@
+-- Migration 0001 (down): Minimal v2
+
+DROP VIEW IF EXISTS public.company_entities;
+
+DROP VIEW IF EXISTS public.person_entities;
+
+ALTER TABLE public.companies ADD COLUMN IF NOT EXISTS name TEXT;
+
+ALTER TABLE public.people ALTER COLUMN last_name DROP NOT NULL;
+
+ALTER TABLE public.people DROP COLUMN IF EXISTS nickname;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_companies_name ON public.companies ("name");
+
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM public.nationalities WHERE key = 'IT' AND value = 'Italian' AND value_type = 'String' AND (EXISTS (SELECT 1 FROM public.people referencing WHERE referencing.nationality_id = nationalities.id))) THEN
+		RAISE EXCEPTION 'removed seed data of table public.nationalities is still referenced by public.people';
+	END IF;
+END $$;
+
+DELETE FROM public.nationalities WHERE key = 'IT' AND value = 'Italian' AND value_type = 'String';
+
+INSERT INTO public.nationalities (key, value, value_type) VALUES ('FR', 'French', 'String');
+
+CREATE OR REPLACE VIEW public.company_entities AS
+SELECT
+	companies.id,
+	companies.name,
+	companies.tax_id
+FROM companies;
+
+CREATE OR REPLACE VIEW public.person_entities AS
+SELECT
+	contact_infos.email,
+	people.id,
+	people.last_name,
+	people.nationality
+FROM people
+LEFT JOIN contact_infos
+	ON people.id = contact_infos.person_id;
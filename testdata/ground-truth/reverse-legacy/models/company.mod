@@ -0,0 +1,18 @@
+name: Company
+fields:
+  ID:
+    type: AutoIncrement
+    attributes:
+      - mandatory
+  Name:
+    type: String
+  TaxID:
+    type: String
+    attributes:
+      - mandatory
+identifiers:
+  primary: ID
+  name: Name
+related:
+  Person:
+    type: HasMany
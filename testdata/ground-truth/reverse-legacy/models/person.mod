@@ -0,0 +1,44 @@
+name: Person
+fields:
+  ID:
+    type: AutoIncrement
+    attributes:
+      - mandatory
+  BirthDate:
+    type: Date
+  CreatedAt:
+    type: Time
+    attributes:
+      - mandatory
+  ExternalID:
+    type: UUID
+  FirstName:
+    type: String
+  IsActive:
+    type: Boolean
+    attributes:
+      - mandatory
+  LastName:
+    type: String
+  Nationality:
+    type: Nationality
+  Score:
+    type: Float
+  Status:
+    type: AccountStatus
+    attributes:
+      - mandatory
+identifiers:
+  primary: ID
+  firstNameLastName:
+    - FirstName
+    - LastName
+related:
+  Company:
+    type: ForOne
+  ContactInfo:
+    type: HasOne
+  Manager:
+    type: ForOne
+  Tag:
+    type: ForMany
@@ -0,0 +1,16 @@
+name: Tag
+fields:
+  ID:
+    type: AutoIncrement
+    attributes:
+      - mandatory
+  Name:
+    type: String
+    attributes:
+      - mandatory
+identifiers:
+  primary: ID
+  name: Name
+related:
+  Person:
+    type: HasMany
@@ -0,0 +1,22 @@
+name: Contract
+fields:
+  ID:
+    type: AutoIncrement
+    attributes:
+      - mandatory
+  Number:
+    type: String
+    attributes:
+      - mandatory
+      - immutable
+  Title:
+    type: String
+    attributes:
+      - mandatory
+  SignedAt:
+    type: Time
+    attributes:
+      - immutable
+identifiers:
+  primary: ID
+  number: Number
@@ -0,0 +1,13 @@
+name: Company
+fields:
+  ID:
+    type: AutoIncrement
+    attributes:
+      - mandatory
+  TaxID:
+    type: String
+identifiers:
+  primary: ID
+related:
+  Person:
+    type: HasMany
\ No newline at end of file
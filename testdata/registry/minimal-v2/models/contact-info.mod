@@ -0,0 +1,14 @@
+name: ContactInfo
+fields:
+  ID:
+    type: AutoIncrement
+    attributes:
+      - mandatory
+  Email:
+    type: String
+identifiers:
+  primary: ID
+  email: Email
+related:
+  Person:
+    type: ForOne
\ No newline at end of file
@@ -0,0 +1,26 @@
+name: Person
+fields:
+  ID:
+    type: AutoIncrement
+    attributes:
+      - mandatory
+  FirstName:
+    type: String
+  LastName:
+    type: String
+    attributes:
+      - mandatory
+  Nickname:
+    type: String
+  Nationality:
+    type: Nationality
+identifiers:
+  primary: ID
+  name:
+    - FirstName
+    - LastName
+related:
+  ContactInfo:
+    type: HasOne
+  Company:
+    type: ForOne
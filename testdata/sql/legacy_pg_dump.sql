@@ -0,0 +1,244 @@
+--
+-- PostgreSQL database dump
+--
+
+-- Dumped from database version 16.2
+-- Dumped by pg_dump version 16.2
+
+SET statement_timeout = 0;
+SET lock_timeout = 0;
+SET client_encoding = 'UTF8';
+SET standard_conforming_strings = on;
+SELECT pg_catalog.set_config('search_path', '', false);
+SET check_function_bodies = false;
+
+--
+-- Name: account_status; Type: TYPE; Schema: public; Owner: legacy
+--
+
+CREATE TYPE public.account_status AS ENUM (
+    'active',
+    'suspended'
+);
+
+
+ALTER TYPE public.account_status OWNER TO legacy;
+
+--
+-- Name: fn_companies_immutable(); Type: FUNCTION; Schema: public; Owner: legacy
+--
+
+CREATE FUNCTION public.fn_companies_immutable() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+	IF NEW."tax_id" IS DISTINCT FROM OLD."tax_id" THEN
+		RAISE EXCEPTION 'column tax_id of table companies is immutable';
+	END IF;
+	RETURN NEW;
+END;
+$$;
+
+
+SET default_tablespace = '';
+
+SET default_table_access_method = heap;
+
+--
+-- Name: companies; Type: TABLE; Schema: public; Owner: legacy
+--
+
+CREATE TABLE public.companies (
+    id integer NOT NULL,
+    name text,
+    tax_id text NOT NULL
+);
+
+
+ALTER TABLE public.companies OWNER TO legacy;
+
+--
+-- Name: companies_id_seq; Type: SEQUENCE; Schema: public; Owner: legacy
+--
+
+CREATE SEQUENCE public.companies_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER SEQUENCE public.companies_id_seq OWNED BY public.companies.id;
+
+--
+-- Name: contact_infos; Type: TABLE; Schema: public; Owner: legacy
+--
+
+CREATE TABLE public.contact_infos (
+    id integer NOT NULL,
+    email character varying(255),
+    person_id integer NOT NULL
+);
+
+
+--
+-- Name: nationalities; Type: TABLE; Schema: public; Owner: legacy
+--
+
+CREATE TABLE public.nationalities (
+    id integer NOT NULL,
+    key text NOT NULL,
+    value text NOT NULL,
+    value_type text NOT NULL
+);
+
+
+--
+-- Name: people; Type: TABLE; Schema: public; Owner: legacy
+--
+
+CREATE TABLE public.people (
+    id integer NOT NULL,
+    first_name text,
+    last_name text,
+    nationality_id integer NOT NULL,
+    company_id integer NOT NULL,
+    manager_id integer,
+    status public.account_status DEFAULT 'active'::public.account_status NOT NULL,
+    score double precision,
+    is_active boolean DEFAULT true NOT NULL,
+    birth_date date,
+    external_id uuid,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: person_tags; Type: TABLE; Schema: public; Owner: legacy
+--
+
+CREATE TABLE public.person_tags (
+    id integer NOT NULL,
+    person_id integer NOT NULL,
+    tag_id bigint NOT NULL
+);
+
+
+--
+-- Name: tags; Type: TABLE; Schema: public; Owner: legacy
+--
+
+CREATE TABLE public.tags (
+    id bigint NOT NULL,
+    name character varying(64) NOT NULL
+);
+
+
+ALTER TABLE public.tags ALTER COLUMN id ADD GENERATED ALWAYS AS IDENTITY (
+    SEQUENCE NAME public.tags_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+
+--
+-- Name: person_entities; Type: VIEW; Schema: public; Owner: legacy
+--
+
+CREATE VIEW public.person_entities AS
+ SELECT people.id,
+    people.last_name,
+    contact_infos.email AS contact_email
+   FROM (public.people
+     LEFT JOIN public.contact_infos ON ((people.id = contact_infos.person_id)))
+  WHERE (people.is_active = true);
+
+
+ALTER TABLE public.person_entities OWNER TO legacy;
+
+--
+-- Name: companies id; Type: DEFAULT; Schema: public; Owner: legacy
+--
+
+ALTER TABLE ONLY public.companies ALTER COLUMN id SET DEFAULT nextval('public.companies_id_seq'::regclass);
+ALTER TABLE ONLY public.contact_infos ALTER COLUMN id SET DEFAULT nextval('public.contact_infos_id_seq'::regclass);
+ALTER TABLE ONLY public.nationalities ALTER COLUMN id SET DEFAULT nextval('public.nationalities_id_seq'::regclass);
+ALTER TABLE ONLY public.people ALTER COLUMN id SET DEFAULT nextval('public.people_id_seq'::regclass);
+ALTER TABLE ONLY public.person_tags ALTER COLUMN id SET DEFAULT nextval('public.person_tags_id_seq'::regclass);
+
+--
+-- Data for Name: nationalities; Type: TABLE DATA; Schema: public; Owner: legacy
+--
+
+COPY public.nationalities (id, key, value, value_type) FROM stdin;
+1	DE	German	String
+2	FR	French	String
+3	US	American	String
+\.
+
+
+--
+-- Name: companies companies_pkey; Type: CONSTRAINT; Schema: public; Owner: legacy
+--
+
+ALTER TABLE ONLY public.companies
+    ADD CONSTRAINT companies_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.contact_infos
+    ADD CONSTRAINT contact_infos_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.contact_infos
+    ADD CONSTRAINT contact_infos_person_id_key UNIQUE (person_id);
+ALTER TABLE ONLY public.nationalities
+    ADD CONSTRAINT nationalities_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.nationalities
+    ADD CONSTRAINT uk_nationalities_key UNIQUE (key);
+ALTER TABLE ONLY public.people
+    ADD CONSTRAINT people_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.person_tags
+    ADD CONSTRAINT person_tags_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.tags
+    ADD CONSTRAINT tags_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.tags
+    ADD CONSTRAINT tags_name_key UNIQUE (name);
+
+--
+-- Name: idx_companies_name; Type: INDEX; Schema: public; Owner: legacy
+--
+
+CREATE UNIQUE INDEX idx_companies_name ON public.companies USING btree (name);
+CREATE UNIQUE INDEX idx_contact_infos_email ON public.contact_infos USING btree (email);
+CREATE INDEX idx_people_company_id ON public.people USING btree (company_id);
+CREATE UNIQUE INDEX idx_people_first_name_last_name ON public.people USING btree (first_name, last_name);
+CREATE INDEX idx_people_lower_last_name ON public.people USING btree (lower(last_name) DESC);
+
+--
+-- Name: companies trg_companies_immutable; Type: TRIGGER; Schema: public; Owner: legacy
+--
+
+CREATE TRIGGER trg_companies_immutable BEFORE UPDATE ON public.companies FOR EACH ROW EXECUTE FUNCTION public.fn_companies_immutable();
+
+--
+-- Name: contact_infos fk_contact_infos_person_id; Type: FK CONSTRAINT; Schema: public; Owner: legacy
+--
+
+ALTER TABLE ONLY public.contact_infos
+    ADD CONSTRAINT fk_contact_infos_person_id FOREIGN KEY (person_id) REFERENCES public.people(id) ON DELETE CASCADE;
+ALTER TABLE ONLY public.people
+    ADD CONSTRAINT fk_people_company_id FOREIGN KEY (company_id) REFERENCES public.companies(id) ON DELETE CASCADE;
+ALTER TABLE ONLY public.people
+    ADD CONSTRAINT fk_people_manager_id FOREIGN KEY (manager_id) REFERENCES public.people(id) ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED;
+ALTER TABLE ONLY public.people
+    ADD CONSTRAINT fk_people_nationality_id FOREIGN KEY (nationality_id) REFERENCES public.nationalities(id) ON DELETE CASCADE;
+ALTER TABLE ONLY public.person_tags
+    ADD CONSTRAINT fk_person_tags_person_id FOREIGN KEY (person_id) REFERENCES public.people(id) ON DELETE CASCADE;
+ALTER TABLE ONLY public.person_tags
+    ADD CONSTRAINT fk_person_tags_tag_id FOREIGN KEY (tag_id) REFERENCES public.tags(id) ON DELETE CASCADE;
+
+--
+-- PostgreSQL database dump complete
+--
+